@@ -0,0 +1,220 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MCPCatalogSpec defines which MCP servers are aggregated into the catalog
+type MCPCatalogSpec struct {
+	// NamespaceSelector selects the namespaces whose MCPServers are included.
+	// When omitted, servers from all namespaces are included.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ServerSelector selects MCPServers by label.
+	// When omitted, all MCPServers in the selected namespaces are included.
+	// +optional
+	ServerSelector *metav1.LabelSelector `json:"serverSelector,omitempty"`
+
+	// RefreshInterval is how often the tool, resource and prompt listings are refreshed.
+	// Servers are also re-listed as soon as they are re-validated.
+	// Values below 30s are raised to 30s.
+	// +kubebuilder:default="5m"
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// MCPCatalogStatus defines the observed state of MCPCatalog
+type MCPCatalogStatus struct {
+	// Servers lists the validated MCP servers selected by the catalog, sorted by namespace and name
+	// +optional
+	Servers []MCPCatalogServer `json:"servers,omitempty"`
+
+	// ServerCount is the number of servers in the catalog
+	// +optional
+	ServerCount int32 `json:"serverCount"`
+
+	// ToolCount is the number of tools across all servers in the catalog
+	// +optional
+	ToolCount int32 `json:"toolCount"`
+
+	// LastRefreshTime is when the catalog was last rebuilt
+	// +optional
+	LastRefreshTime *metav1.Time `json:"lastRefreshTime,omitempty"`
+
+	// ObservedGeneration is the generation of the MCPCatalog the status was built from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the catalog
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// MCPCatalogServer describes one validated MCP server and what it offers
+type MCPCatalogServer struct {
+	// Name of the MCPServer
+	Name string `json:"name"`
+
+	// Namespace of the MCPServer
+	Namespace string `json:"namespace"`
+
+	// Endpoint is the in-cluster URL clients connect to
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Protocol is the MCP transport variant, "streamable-http" or "sse"
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// ProtocolVersion is the MCP specification version the server negotiated
+	// +optional
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+
+	// Capabilities lists the capabilities the server advertises
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// ServerInfo is the server name and version reported during initialization
+	// +optional
+	ServerInfo *ValidationServerInfo `json:"serverInfo,omitempty"`
+
+	// Tools lists the tools returned by tools/list.
+	// The status keeps at most 100 tools across all servers of a catalog; the full
+	// listing of a truncated server is in the ConfigMap named by toolsConfigMapName
+	// and is served by the catalog HTTP endpoint.
+	// +optional
+	Tools []MCPCatalogTool `json:"tools,omitempty"`
+
+	// ToolCount is the number of tools the server lists, including those left out of tools
+	// +optional
+	ToolCount int32 `json:"toolCount,omitempty"`
+
+	// ToolsConfigMapName is the ConfigMap in the server's namespace holding the
+	// full tool listing when tools is truncated
+	// +optional
+	ToolsConfigMapName string `json:"toolsConfigMapName,omitempty"`
+
+	// Resources lists the resources returned by resources/list
+	// +optional
+	Resources []MCPCatalogResource `json:"resources,omitempty"`
+
+	// Prompts lists the prompts returned by prompts/list
+	// +optional
+	Prompts []MCPCatalogPrompt `json:"prompts,omitempty"`
+
+	// LastListedTime is when the listings were last fetched from the server
+	// +optional
+	LastListedTime *metav1.Time `json:"lastListedTime,omitempty"`
+
+	// ListingError is set when the listings could not be fetched from the server.
+	// The names recorded during validation are used instead, without descriptions.
+	// +optional
+	ListingError string `json:"listingError,omitempty"`
+}
+
+// MCPCatalogTool describes a tool offered by a server
+type MCPCatalogTool struct {
+	// Name of the tool
+	Name string `json:"name"`
+
+	// Description of the tool, truncated to 256 characters
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// MCPCatalogResource describes a resource offered by a server
+type MCPCatalogResource struct {
+	// URI of the resource
+	URI string `json:"uri"`
+
+	// Name of the resource
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Description of the resource, truncated to 256 characters
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// MimeType of the resource
+	// +optional
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// MCPCatalogPrompt describes a prompt offered by a server
+type MCPCatalogPrompt struct {
+	// Name of the prompt
+	Name string `json:"name"`
+
+	// Description of the prompt, truncated to 256 characters
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// MCPCatalog condition types
+const (
+	// MCPCatalogConditionReady indicates the catalog was rebuilt successfully
+	MCPCatalogConditionReady = "Ready"
+)
+
+// MCPCatalogToolsConfigMapKey is the key of the JSON tool listing in the
+// ConfigMap named by toolsConfigMapName
+const MCPCatalogToolsConfigMapKey = "tools.json"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=mcpcat,categories={mcp-operator}
+// +kubebuilder:printcolumn:name="Servers",type=integer,JSONPath=`.status.serverCount`
+// +kubebuilder:printcolumn:name="Tools",type=integer,JSONPath=`.status.toolCount`
+// +kubebuilder:printcolumn:name="Refreshed",type=date,JSONPath=`.status.lastRefreshTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// MCPCatalog aggregates the validated MCPServers of the cluster and the tools,
+// resources and prompts they offer, so clients can discover servers without
+// connecting to each of them.
+type MCPCatalog struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec defines the servers included in the catalog
+	// +optional
+	Spec MCPCatalogSpec `json:"spec,omitempty"`
+
+	// status defines the observed state of MCPCatalog
+	// +optional
+	Status MCPCatalogStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPCatalogList contains a list of MCPCatalog
+type MCPCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPCatalog{}, &MCPCatalogList{})
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Default values for MCPGateway
+const (
+	// DefaultGatewayPort is the port the gateway serves MCP traffic on
+	DefaultGatewayPort int32 = 8080
+
+	// DefaultGatewayMetricsPort is the port the gateway exposes metrics and health endpoints on
+	DefaultGatewayMetricsPort int32 = 9090
+
+	// DefaultGatewayPath is the HTTP path the gateway serves MCP traffic on
+	DefaultGatewayPath = "/mcp"
+
+	// DefaultGatewayToolSeparator joins a backend prefix and a tool name
+	DefaultGatewayToolSeparator = "__"
+)
+
+// MCPGatewaySpec defines the desired state of MCPGateway
+type MCPGatewaySpec struct {
+	// ServerSelector selects the MCPServers in the gateway's namespace to put behind the gateway.
+	// Only validated Streamable HTTP servers are used as backends.
+	// +required
+	ServerSelector metav1.LabelSelector `json:"serverSelector"`
+
+	// ToolPrefix configures how tool names are prefixed with the name of their server
+	// +optional
+	ToolPrefix *MCPGatewayToolPrefix `json:"toolPrefix,omitempty"`
+
+	// Replicas is the number of gateway pods
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Image is the gateway container image. The gateway runs the mcp-proxy binary in gateway mode.
+	// Default: the metrics sidecar image
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Port is the port the gateway serves MCP traffic on
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=8080
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// MetricsPort is the port the gateway exposes Prometheus metrics and health endpoints on
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default=9090
+	// +optional
+	MetricsPort int32 `json:"metricsPort,omitempty"`
+
+	// Path is the HTTP path the gateway serves MCP traffic on
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:default="/mcp"
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Resources are the resource requirements of the gateway container.
+	// Default: the metrics sidecar defaults
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// MCPGatewayToolPrefix configures tool name prefixing
+type MCPGatewayToolPrefix struct {
+	// Enabled exposes each tool as "<server><separator><tool>" so tools with the same
+	// name on different servers do not collide. Without prefixing, the server whose
+	// name sorts first keeps a duplicated tool name.
+	// +kubebuilder:default=true
+	// +optional
+	Enabled bool `json:"enabled"`
+
+	// Separator joins the server name and the tool name
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]{1,8}$`
+	// +kubebuilder:default="__"
+	// +optional
+	Separator string `json:"separator,omitempty"`
+}
+
+// MCPGatewayStatus defines the observed state of MCPGateway
+type MCPGatewayStatus struct {
+	// Endpoint is the in-cluster URL of the gateway
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Backends lists the MCPServers behind the gateway, sorted by name
+	// +optional
+	Backends []MCPGatewayBackend `json:"backends,omitempty"`
+
+	// SkippedServers lists selected MCPServers that cannot be used as backends
+	// +optional
+	SkippedServers []MCPGatewaySkippedServer `json:"skippedServers,omitempty"`
+
+	// BackendCount is the number of servers behind the gateway
+	// +optional
+	BackendCount int32 `json:"backendCount"`
+
+	// ReadyReplicas is the number of ready gateway pods
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// ObservedGeneration is the generation of the MCPGateway the status was built from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the gateway
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// MCPGatewayBackend is an MCPServer behind the gateway
+type MCPGatewayBackend struct {
+	// Name of the MCPServer
+	Name string `json:"name"`
+
+	// Endpoint is the validated Streamable HTTP endpoint the gateway forwards to
+	Endpoint string `json:"endpoint"`
+}
+
+// MCPGatewaySkippedServer is a selected MCPServer that is not behind the gateway
+type MCPGatewaySkippedServer struct {
+	// Name of the MCPServer
+	Name string `json:"name"`
+
+	// Reason explains why the server is not used as a backend
+	Reason string `json:"reason"`
+}
+
+// MCPGateway condition types
+const (
+	// MCPGatewayConditionReady indicates the gateway is serving at least one backend
+	MCPGatewayConditionReady = "Ready"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=mcpgw,categories={mcp-operator}
+// +kubebuilder:printcolumn:name="Backends",type=integer,JSONPath=`.status.backendCount`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`
+// +kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=`.status.endpoint`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// MCPGateway fronts several MCPServers behind one Streamable HTTP endpoint.
+// The gateway merges their tools and routes each tool call to the server that owns it.
+type MCPGateway struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec defines the desired state of MCPGateway
+	// +required
+	Spec MCPGatewaySpec `json:"spec"`
+
+	// status defines the observed state of MCPGateway
+	// +optional
+	Status MCPGatewayStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// MCPGatewayList contains a list of MCPGateway
+type MCPGatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MCPGateway `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MCPGateway{}, &MCPGatewayList{})
+}
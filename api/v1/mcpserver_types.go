@@ -18,6 +18,8 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -88,6 +90,41 @@ type MCPServerSpec struct {
 	// Only applicable when metrics.enabled is true.
 	// +optional
 	Sidecar *SidecarConfig `json:"sidecar,omitempty"`
+
+	// Policy restricts which tools and resources clients may use.
+	// Policies are enforced by the sidecar proxy, so they require metrics.enabled.
+	// +optional
+	Policy *PolicySpec `json:"policy,omitempty"`
+
+	// Rollout defines how changes to the pod template reach the running server.
+	// By default the Deployment is updated in place with a rolling update.
+	// +optional
+	Rollout *RolloutConfig `json:"rollout,omitempty"`
+
+	// Exposure publishes the server outside the cluster through a Gateway API
+	// HTTPRoute or an Ingress named after the MCPServer.
+	// +optional
+	Exposure *ExposureConfig `json:"exposure,omitempty"`
+
+	// NetworkPolicy restricts the traffic of the server pods with a
+	// NetworkPolicy named after the MCPServer. Only the clients it lists,
+	// Prometheus and the operator reach the server.
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
+	// Disruption configures the PodDisruptionBudget limiting how many server
+	// pods voluntary evictions, such as node drains, take down at once.
+	// A budget allowing one unavailable pod is created by default when
+	// replicas is greater than 1 or the HPA is enabled.
+	// +optional
+	Disruption *DisruptionConfig `json:"disruption,omitempty"`
+
+	// ScaleToZero scales the server Deployment to zero replicas once it has
+	// served no request for idleTimeout. An activator receives the traffic of
+	// the server meanwhile, and scales it up again on the first request.
+	// Requires metrics.enabled: idleness is read from the sidecar metrics.
+	// +optional
+	ScaleToZero *ScaleToZeroConfig `json:"scaleToZero,omitempty"`
 }
 
 // MCPServerSecurity defines security settings for the MCP server
@@ -163,6 +200,56 @@ type MCPServerHealthCheck struct {
 	// Port specifies the port for health checks
 	// +optional
 	Port *intstr.IntOrString `json:"port,omitempty"`
+
+	// Type selects how health is checked. http probes Path on the server.
+	// mcp has the sidecar send MCP requests to the server, and points the
+	// probes of the server container at the sidecar, so a server that
+	// accepts connections but no longer answers is restarted.
+	// mcp requires metrics.enabled
+	// +kubebuilder:default=http
+	// +optional
+	Type HealthCheckType `json:"type,omitempty"`
+
+	// MCP configures the health check of type mcp
+	// +optional
+	MCP *MCPHealthCheckConfig `json:"mcp,omitempty"`
+}
+
+// HealthCheckType is how the health of the MCP server is checked
+// +kubebuilder:validation:Enum=http;mcp
+type HealthCheckType string
+
+const (
+	// HealthCheckTypeHTTP probes an HTTP path of the server
+	HealthCheckTypeHTTP HealthCheckType = "http"
+
+	// HealthCheckTypeMCP sends MCP requests to the server from the sidecar
+	HealthCheckTypeMCP HealthCheckType = "mcp"
+)
+
+// MCPHealthCheckConfig configures the MCP requests of the health check
+type MCPHealthCheckConfig struct {
+	// Method is the MCP request sent on every check. ping pings a session
+	// started once; initialize starts and ends a session every time, for
+	// servers that do not answer ping
+	// +kubebuilder:validation:Enum=initialize;ping
+	// +kubebuilder:default=ping
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// TimeoutSeconds bounds every check
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	// +kubebuilder:default=5
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed checks after
+	// which the server is reported unhealthy
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
 }
 
 // MCPServerPodTemplate defines additional pod template specifications
@@ -234,6 +321,16 @@ type MCPServerStatus struct {
 	// +optional
 	ServiceEndpoint string `json:"serviceEndpoint,omitempty"`
 
+	// SessionRouterEndpoint is the endpoint of the session router, which clients
+	// should use instead of ServiceEndpoint when session routing is enabled
+	// +optional
+	SessionRouterEndpoint string `json:"sessionRouterEndpoint,omitempty"`
+
+	// ExternalEndpoint is the URL clients outside the cluster reach the server
+	// on, once the HTTPRoute or Ingress of spec.exposure is created
+	// +optional
+	ExternalEndpoint string `json:"externalEndpoint,omitempty"`
+
 	// TransportType represents the active transport type
 	// +optional
 	TransportType MCPTransportType `json:"transportType,omitempty"`
@@ -256,6 +353,14 @@ type MCPServerStatus struct {
 	// Validation represents the MCP protocol validation status
 	// +optional
 	Validation *ValidationStatus `json:"validation,omitempty"`
+
+	// Rollout reports the progress of the latest canary rollout
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// ScaleToZero reports the activity the scale to zero of the server is based on
+	// +optional
+	ScaleToZero *ScaleToZeroStatus `json:"scaleToZero,omitempty"`
 }
 
 // ResolvedTransportStatus tracks the resolved transport protocol after auto-detection.
@@ -282,7 +387,7 @@ type ResolvedTransportStatus struct {
 }
 
 // MCPServerPhase represents the current phase of an MCP server deployment
-// +kubebuilder:validation:Enum=Pending;Creating;Running;Scaling;Updating;Failed;ValidationFailed;Terminating
+// +kubebuilder:validation:Enum=Pending;Creating;Running;Scaling;Updating;Idle;Failed;ValidationFailed;Terminating
 type MCPServerPhase string
 
 const (
@@ -296,6 +401,8 @@ const (
 	MCPServerPhaseScaling MCPServerPhase = "Scaling"
 	// MCPServerPhaseUpdating indicates the MCP server is being updated
 	MCPServerPhaseUpdating MCPServerPhase = "Updating"
+	// MCPServerPhaseIdle indicates the MCP server is scaled to zero until the next request
+	MCPServerPhaseIdle MCPServerPhase = "Idle"
 	// MCPServerPhaseFailed indicates the MCP server deployment failed
 	MCPServerPhaseFailed MCPServerPhase = "Failed"
 	// MCPServerPhaseValidationFailed indicates validation failed in strict mode
@@ -326,7 +433,7 @@ type MCPServerCondition struct {
 }
 
 // MCPServerConditionType represents the type of condition
-// +kubebuilder:validation:Enum=Ready;Available;Progressing;Degraded;Reconciled
+// +kubebuilder:validation:Enum=Ready;Available;Progressing;Degraded;Reconciled;CapabilityDrift;RolloutHealthy;Exposed;DisruptionAllowed
 type MCPServerConditionType string
 
 const (
@@ -340,6 +447,18 @@ const (
 	MCPServerConditionDegraded MCPServerConditionType = "Degraded"
 	// MCPServerConditionReconciled indicates the MCP server has been successfully reconciled
 	MCPServerConditionReconciled MCPServerConditionType = "Reconciled"
+	// MCPServerConditionCapabilityDrift indicates periodic re-validation found that the server's
+	// capabilities, protocol version or server info changed without a spec change
+	MCPServerConditionCapabilityDrift MCPServerConditionType = "CapabilityDrift"
+	// MCPServerConditionRolloutHealthy indicates whether the latest canary rollout
+	// progressed or was rolled back
+	MCPServerConditionRolloutHealthy MCPServerConditionType = "RolloutHealthy"
+	// MCPServerConditionExposed indicates whether the HTTPRoute or Ingress of
+	// spec.exposure is in place
+	MCPServerConditionExposed MCPServerConditionType = "Exposed"
+	// MCPServerConditionDisruptionAllowed indicates whether the PodDisruptionBudget
+	// of the server currently allows voluntary evictions
+	MCPServerConditionDisruptionAllowed MCPServerConditionType = "DisruptionAllowed"
 )
 
 // MCPServerHPA defines Horizontal Pod Autoscaler configuration
@@ -373,6 +492,16 @@ type MCPServerHPA struct {
 	// +optional
 	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
 
+	// Metrics are MCP traffic metrics recorded by the sidecar to scale on,
+	// alongside or instead of CPU and memory. They require spec.metrics.enabled
+	// and a custom metrics adapter serving them: prometheus-adapter, or the
+	// adapter built into the operator
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=3
+	// +optional
+	Metrics []MCPServerHPAMetric `json:"metrics,omitempty"`
+
 	// ScaleUpBehavior configures scaling up behavior
 	// +optional
 	ScaleUpBehavior *MCPServerHPABehavior `json:"scaleUpBehavior,omitempty"`
@@ -382,6 +511,48 @@ type MCPServerHPA struct {
 	ScaleDownBehavior *MCPServerHPABehavior `json:"scaleDownBehavior,omitempty"`
 }
 
+// MCPServerHPAMetricType is an MCP traffic metric the HPA can scale on
+// +kubebuilder:validation:Enum=ActiveSSEConnections;RequestsPerSecond;ToolCallLatencyP95
+type MCPServerHPAMetricType string
+
+const (
+	// HPAMetricActiveSSEConnections scales on the average number of open SSE
+	// connections per pod
+	HPAMetricActiveSSEConnections MCPServerHPAMetricType = "ActiveSSEConnections"
+	// HPAMetricRequestsPerSecond scales on the average number of requests per
+	// second per pod
+	HPAMetricRequestsPerSecond MCPServerHPAMetricType = "RequestsPerSecond"
+	// HPAMetricToolCallLatencyP95 scales on the 95th percentile latency of the
+	// tool calls answered by all pods
+	HPAMetricToolCallLatencyP95 MCPServerHPAMetricType = "ToolCallLatencyP95"
+)
+
+// Names of the MCP traffic metrics in the custom and external metrics APIs.
+// Adapters must serve them under these names for the HPA to find them.
+const (
+	// HPAMetricNameActiveSSEConnections is the Pods metric of ActiveSSEConnections
+	HPAMetricNameActiveSSEConnections = "mcp_sse_connections_active"
+	// HPAMetricNameRequestsPerSecond is the Pods metric of RequestsPerSecond
+	HPAMetricNameRequestsPerSecond = "mcp_requests_per_second"
+	// HPAMetricNameToolCallLatencyP95 is the External metric of ToolCallLatencyP95
+	HPAMetricNameToolCallLatencyP95 = "mcp_tool_call_duration_seconds_p95"
+	// HPAMetricServerLabel is the label selecting the MCPServer of an External metric
+	HPAMetricServerLabel = "mcpserver"
+)
+
+// MCPServerHPAMetric defines an MCP traffic metric target
+type MCPServerHPAMetric struct {
+	// Type is the metric to scale on
+	// +kubebuilder:validation:Required
+	Type MCPServerHPAMetricType `json:"type"`
+
+	// Target is the value the HPA keeps the metric at: an average per pod for
+	// ActiveSSEConnections and RequestsPerSecond, and a latency in seconds
+	// for ToolCallLatencyP95 (e.g. "500m" for 500ms)
+	// +kubebuilder:validation:Required
+	Target resource.Quantity `json:"target"`
+}
+
 // MCPServerHPABehavior defines scaling behavior policies
 type MCPServerHPABehavior struct {
 	// StabilizationWindowSeconds is the number of seconds for which past recommendations should be considered
@@ -444,6 +615,55 @@ type MetricsConfig struct {
 	// +kubebuilder:default=9090
 	// +optional
 	Port int32 `json:"port,omitempty"`
+
+	// MaxToolLabels is the number of tools given their own tool_name label in the
+	// per-tool metrics. Only the most called tools, recomputed every 5 minutes, get
+	// their own label and the others are recorded as "other", bounding the series
+	// of servers with dynamic tools.
+	// Default: 100
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxToolLabels *int32 `json:"maxToolLabels,omitempty"`
+
+	// Tracing makes the sidecar export an OpenTelemetry span for every request,
+	// continuing the trace of the W3C traceparent header sent by the client.
+	// +optional
+	Tracing *TracingConfig `json:"tracing,omitempty"`
+}
+
+// TracingExporter selects where the sidecar sends spans
+// +kubebuilder:validation:Enum=otlp;stdout
+type TracingExporter string
+
+const (
+	// TracingExporterOTLP sends spans to an OTLP/HTTP collector
+	TracingExporterOTLP TracingExporter = "otlp"
+
+	// TracingExporterStdout writes spans as JSON lines to the sidecar log
+	TracingExporterStdout TracingExporter = "stdout"
+)
+
+// TracingConfig configures distributed tracing in the sidecar
+type TracingConfig struct {
+	// Exporter selects where spans are sent.
+	// "stdout" writes them to the sidecar log, for debugging without a collector.
+	// +kubebuilder:default=otlp
+	// +optional
+	Exporter TracingExporter `json:"exporter,omitempty"`
+
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g. http://otel-collector.observability:4318.
+	// Spans are sent without TLS to http URLs. Required when exporter is "otlp".
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// SamplingPercentage is the percentage of new traces sampled.
+	// Requests carrying a traceparent follow the sampling decision of the caller.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=100
+	// +optional
+	SamplingPercentage *int32 `json:"samplingPercentage,omitempty"`
 }
 
 // SidecarConfig allows advanced customization of the metrics sidecar proxy
@@ -470,6 +690,195 @@ type SidecarConfig struct {
 	// When enabled, the sidecar accepts HTTPS and forwards HTTP to the MCP server.
 	// +optional
 	TLS *SidecarTLSConfig `json:"tls,omitempty"`
+
+	// Auth requires clients to authenticate at the sidecar with an API key or a JWT.
+	// Requests without valid credentials are rejected with 401 before reaching the server.
+	// The verified identity is passed to the server in the X-Forwarded-User header,
+	// or spec.policy.identityHeader when set, and is used by policy identities.
+	// +optional
+	Auth *SidecarAuthConfig `json:"auth,omitempty"`
+
+	// RateLimit throttles clients and tools with token buckets.
+	// Requests over a limit are rejected with HTTP 429 and a JSON-RPC error.
+	// +optional
+	RateLimit *SidecarRateLimitConfig `json:"rateLimit,omitempty"`
+
+	// Audit writes a record of every tools/call and resources/read request:
+	// the client identity, session, tool or resource, arguments, result status and duration.
+	// +optional
+	Audit *SidecarAuditConfig `json:"audit,omitempty"`
+}
+
+// AuditSink is where the sidecar writes audit records
+// +kubebuilder:validation:Enum=stdout;file
+type AuditSink string
+
+const (
+	// AuditSinkStdout writes audit records to the sidecar's standard output,
+	// next to its logs
+	AuditSinkStdout AuditSink = "stdout"
+
+	// AuditSinkFile appends audit records to a JSON-lines file
+	AuditSinkFile AuditSink = "file"
+)
+
+// SidecarAuditConfig configures the audit records written by the sidecar
+type SidecarAuditConfig struct {
+	// Sink is where records are written
+	// +kubebuilder:default=stdout
+	// +optional
+	Sink AuditSink `json:"sink,omitempty"`
+
+	// File configures the file records are appended to when sink is "file"
+	// +optional
+	File *AuditFileConfig `json:"file,omitempty"`
+
+	// Redact lists JSONPath expressions evaluated against the arguments of every
+	// tool call. Matching values are recorded as "[REDACTED]".
+	// Supported: $.name, $['name'], $.list[0], $.list[*].name and $..name.
+	// +optional
+	Redact []string `json:"redact,omitempty"`
+
+	// MaxArgumentBytes is the size of the encoded arguments above which they are
+	// truncated. Truncated arguments are recorded as a string holding their start.
+	// +kubebuilder:default=4096
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxArgumentBytes int32 `json:"maxArgumentBytes,omitempty"`
+}
+
+// AuditFileConfig configures the file audit sink
+type AuditFileConfig struct {
+	// VolumeName is the volume from spec.podTemplate.volumes the file is written to,
+	// for example a PersistentVolumeClaim read by a log shipper.
+	// Default: an emptyDir volume
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+
+	// FileName is the name of the file in the volume
+	// +kubebuilder:default=audit.jsonl
+	// +kubebuilder:validation:Pattern=`^[^/]+$`
+	// +optional
+	FileName string `json:"fileName,omitempty"`
+}
+
+// SidecarRateLimitConfig configures rate limits enforced by the sidecar.
+// A request must pass every rule that applies to it.
+type SidecarRateLimitConfig struct {
+	// Rules are the rate limits
+	// +kubebuilder:validation:MinItems=1
+	Rules []RateLimitRule `json:"rules"`
+
+	// TrustForwardedFor takes the client IP of ip keys from the X-Real-IP and
+	// X-Forwarded-For headers instead of the connection. Only enable it when all
+	// clients reach the server through a proxy that overwrites these headers,
+	// since clients can otherwise send any address to escape their limit.
+	// +optional
+	TrustForwardedFor bool `json:"trustForwardedFor,omitempty"`
+}
+
+// RateLimitKey is what a rate limit counts requests by
+// +kubebuilder:validation:Enum=ip;identity;tool
+type RateLimitKey string
+
+const (
+	// RateLimitKeyIP counts requests by client IP address
+	RateLimitKeyIP RateLimitKey = "ip"
+
+	// RateLimitKeyIdentity counts requests by the identity verified by sidecar.auth
+	RateLimitKeyIdentity RateLimitKey = "identity"
+
+	// RateLimitKeyTool counts tools/call requests by tool name
+	RateLimitKeyTool RateLimitKey = "tool"
+)
+
+// RateLimitRule is a token bucket allowing requestsPerMinute on average and
+// bursts of up to burst requests, kept separately for each combination of the by keys.
+// Rules counting by tool, or with tools patterns, only count tools/call requests.
+type RateLimitRule struct {
+	// Name identifies the rule in the rule label of mcp_ratelimit_rejections_total.
+	// Defaults to the by keys joined with "+", e.g. "identity+tool".
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// By lists the keys requests are counted by.
+	// For example [identity, tool] gives every client its own limit for every tool.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	By []RateLimitKey `json:"by"`
+
+	// RequestsPerMinute is the sustained rate allowed
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerMinute int32 `json:"requestsPerMinute"`
+
+	// Burst is the number of requests allowed at once.
+	// Default: requestsPerMinute
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+
+	// Tools restricts the rule to calls of tools matching these glob patterns,
+	// where "*" matches any sequence of characters and "?" matches one character.
+	// +optional
+	Tools []string `json:"tools,omitempty"`
+}
+
+// SidecarAuthConfig configures client authentication at the sidecar.
+// At least one of apiKeys or jwt must be set. When both are set, either credential is accepted.
+type SidecarAuthConfig struct {
+	// APIKeys accepts static API keys sent in the X-API-Key header or as a bearer token.
+	// +optional
+	APIKeys *SidecarAPIKeyAuth `json:"apiKeys,omitempty"`
+
+	// JWT accepts bearer tokens signed by a key of a JSON Web Key Set.
+	// +optional
+	JWT *SidecarJWTAuth `json:"jwt,omitempty"`
+}
+
+// SidecarAPIKeyAuth configures API key authentication
+type SidecarAPIKeyAuth struct {
+	// SecretRef references the Secret holding the API keys.
+	// Each key of the Secret is a client identity and its value is that client's API key.
+	// Keys rotated in the Secret are picked up without restarting the pod.
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+}
+
+// SidecarJWTAuth configures JWT authentication.
+// Exactly one of jwksURL or jwksConfigMapRef must be set.
+type SidecarJWTAuth struct {
+	// JWKSURL is the HTTPS URL of the JSON Web Key Set of the token issuer.
+	// +kubebuilder:validation:Pattern=`^https://`
+	// +optional
+	JWKSURL string `json:"jwksURL,omitempty"`
+
+	// JWKSConfigMapRef selects a ConfigMap key holding the JSON Web Key Set.
+	// +optional
+	JWKSConfigMapRef *corev1.ConfigMapKeySelector `json:"jwksConfigMapRef,omitempty"`
+
+	// Issuer is the required iss claim of tokens.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// Audiences are the accepted aud claims of tokens. A token must carry one of them,
+	// so tokens the issuer grants for other services are rejected.
+	// +kubebuilder:validation:MinItems=1
+	Audiences []string `json:"audiences"`
+
+	// IdentityClaim is the token claim used as the client identity.
+	// +kubebuilder:default="sub"
+	// +optional
+	IdentityClaim string `json:"identityClaim,omitempty"`
+
+	// AuthorizationServers are the OAuth 2.1 authorization servers issuing tokens for this server.
+	// When set, the sidecar serves OAuth protected resource metadata at
+	// /.well-known/oauth-protected-resource so MCP clients can discover where to obtain tokens.
+	// +optional
+	AuthorizationServers []string `json:"authorizationServers,omitempty"`
+
+	// Resource is the resource identifier published in the protected resource metadata,
+	// usually the public URL of the MCP endpoint. Derived from the request URL when omitted.
+	// +optional
+	Resource string `json:"resource,omitempty"`
 }
 
 // SidecarTLSConfig configures TLS termination for the sidecar
@@ -489,10 +898,377 @@ type SidecarTLSConfig struct {
 	MinVersion string `json:"minVersion,omitempty"`
 }
 
+// PolicySpec restricts which tools and resources clients may use.
+// A name is permitted when it matches no deny pattern and, if allow patterns
+// are given, matches at least one of them. Patterns are globs where "*" matches
+// any sequence of characters, including "/", and "?" matches one character.
+type PolicySpec struct {
+	// Tools allows or denies tools by name.
+	// Denied tools are removed from tools/list and calling them returns a JSON-RPC error.
+	// +optional
+	Tools *PolicyRules `json:"tools,omitempty"`
+
+	// Resources allows or denies resources by URI.
+	// Denied resources are removed from resources/list and reading them returns a JSON-RPC error.
+	// +optional
+	Resources *PolicyRules `json:"resources,omitempty"`
+
+	// IdentityHeader is the request header the identity verified by sidecar.auth
+	// is forwarded to the server in.
+	// Default: X-Forwarded-User
+	// +optional
+	IdentityHeader string `json:"identityHeader,omitempty"`
+
+	// Identities holds rules for specific clients. The rules of the first entry
+	// matching the identity verified by sidecar.auth replace the top-level tools
+	// or resources rules. Requires sidecar.auth.
+	// +optional
+	Identities []IdentityPolicy `json:"identities,omitempty"`
+}
+
+// PolicyRules are allow and deny lists of glob patterns
+type PolicyRules struct {
+	// Allow lists the permitted patterns. When empty, everything not denied is permitted.
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+
+	// Deny lists the forbidden patterns. Deny takes precedence over allow.
+	// +optional
+	Deny []string `json:"deny,omitempty"`
+}
+
+// IdentityPolicy holds the policy rules for a set of client identities
+type IdentityPolicy struct {
+	// Names are glob patterns matched against the client identity
+	// +kubebuilder:validation:MinItems=1
+	Names []string `json:"names"`
+
+	// Tools replaces the top-level tools rules for these clients
+	// +optional
+	Tools *PolicyRules `json:"tools,omitempty"`
+
+	// Resources replaces the top-level resources rules for these clients
+	// +optional
+	Resources *PolicyRules `json:"resources,omitempty"`
+}
+
+// RolloutStrategy selects how pod template changes are rolled out
+// +kubebuilder:validation:Enum=rollingUpdate;canary
+type RolloutStrategy string
+
+const (
+	// RolloutStrategyRollingUpdate updates the Deployment in place
+	RolloutStrategyRollingUpdate RolloutStrategy = "rollingUpdate"
+
+	// RolloutStrategyCanary runs the new pod template in a separate canary
+	// Deployment, validates it and shifts traffic to it step by step
+	RolloutStrategyCanary RolloutStrategy = "canary"
+)
+
+// RolloutConfig defines how pod template changes are rolled out
+type RolloutConfig struct {
+	// Strategy selects how pod template changes are rolled out
+	// +kubebuilder:default=rollingUpdate
+	// +optional
+	Strategy RolloutStrategy `json:"strategy,omitempty"`
+
+	// Canary configures the canary strategy
+	// +optional
+	Canary *CanaryConfig `json:"canary,omitempty"`
+}
+
+// CanaryConfig configures canary rollouts
+type CanaryConfig struct {
+	// Steps are the traffic weights the canary goes through before it is promoted.
+	// Weights must increase from one step to the next.
+	// Default: 25% for 5 minutes, then 50% for 5 minutes
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Steps []CanaryStep `json:"steps,omitempty"`
+
+	// MaxErrorRatePercent rolls the canary back when more than this percentage of
+	// the requests its sidecars served failed. Failures are responses with a 5xx
+	// status and JSON-RPC error responses. Requires metrics.enabled.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxErrorRatePercent *int32 `json:"maxErrorRatePercent,omitempty"`
+
+	// AllowToolRemoval promotes a canary that no longer offers some of the tools
+	// of the stable server. By default such a canary is rolled back.
+	// +optional
+	AllowToolRemoval bool `json:"allowToolRemoval,omitempty"`
+}
+
+// CanaryStep is a traffic weight the canary holds for a while
+type CanaryStep struct {
+	// Weight is the percentage of server pods running the canary
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	Weight int32 `json:"weight"`
+
+	// Pause is how long the step lasts before the next one starts
+	// +optional
+	Pause *metav1.Duration `json:"pause,omitempty"`
+}
+
+// RolloutPhase represents the phase of a canary rollout
+// +kubebuilder:validation:Enum=Progressing;Promoting;Succeeded;RolledBack;Aborted
+type RolloutPhase string
+
+const (
+	// RolloutPhaseProgressing indicates the canary is being validated or is going through its steps
+	RolloutPhaseProgressing RolloutPhase = "Progressing"
+	// RolloutPhasePromoting indicates the stable Deployment is being updated to the canary revision
+	RolloutPhasePromoting RolloutPhase = "Promoting"
+	// RolloutPhaseSucceeded indicates the canary revision was promoted
+	RolloutPhaseSucceeded RolloutPhase = "Succeeded"
+	// RolloutPhaseRolledBack indicates the canary failed and was removed.
+	// The revision is not tried again until the spec changes.
+	RolloutPhaseRolledBack RolloutPhase = "RolledBack"
+	// RolloutPhaseAborted indicates the spec returned to the stable revision during the rollout
+	RolloutPhaseAborted RolloutPhase = "Aborted"
+)
+
+// RolloutStatus reports the progress of a canary rollout
+type RolloutStatus struct {
+	// Phase is the phase of the rollout
+	// +optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+
+	// StableRevision is the pod template hash of the stable Deployment
+	// +optional
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// CanaryRevision is the pod template hash being rolled out
+	// +optional
+	CanaryRevision string `json:"canaryRevision,omitempty"`
+
+	// Step is the index of the current canary step
+	// +optional
+	Step int32 `json:"step"`
+
+	// CanaryWeight is the percentage of ready server pods running the canary
+	// +optional
+	CanaryWeight int32 `json:"canaryWeight"`
+
+	// CanaryValidated indicates the canary passed protocol validation and receives traffic
+	// +optional
+	CanaryValidated bool `json:"canaryValidated,omitempty"`
+
+	// ToolsAdded lists the tools the canary offers that the stable server does not
+	// +optional
+	ToolsAdded []string `json:"toolsAdded,omitempty"`
+
+	// ToolsRemoved lists the tools of the stable server the canary no longer offers
+	// +optional
+	ToolsRemoved []string `json:"toolsRemoved,omitempty"`
+
+	// StartTime is when the rollout of the canary revision started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// StepStartTime is when the current step started
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+
+	// Message describes the current state of the rollout
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ExposureType selects the kind of resource that exposes the server
+// +kubebuilder:validation:Enum=httpRoute;ingress
+type ExposureType string
+
+const (
+	// ExposureTypeHTTPRoute generates a Gateway API HTTPRoute
+	ExposureTypeHTTPRoute ExposureType = "httpRoute"
+
+	// ExposureTypeIngress generates an Ingress
+	ExposureTypeIngress ExposureType = "ingress"
+)
+
+// ExposureConfig defines how the server is reached from outside the cluster.
+// The request timeout and, for Ingress, the proxy buffering annotations are
+// derived from the resolved transport, so event streams are neither buffered
+// nor cut off by the proxy in front of the server.
+type ExposureConfig struct {
+	// Type selects an HTTPRoute or an Ingress
+	// +kubebuilder:default=httpRoute
+	// +optional
+	Type ExposureType `json:"type,omitempty"`
+
+	// Host is the hostname clients reach the server on
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	// +kubebuilder:validation:MaxLength=253
+	Host string `json:"host"`
+
+	// Path is the path prefix routed to the server. Requests are forwarded
+	// unchanged, so the server must serve its endpoint under this prefix.
+	// +kubebuilder:default="/"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// TLS serves the host over HTTPS
+	// +optional
+	TLS *ExposureTLSConfig `json:"tls,omitempty"`
+
+	// Timeout overrides the request timeout derived from the transport:
+	// 1 hour for SSE, 5 minutes for Streamable HTTP
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// Gateway is the Gateway the HTTPRoute attaches to. Required for type httpRoute.
+	// +optional
+	Gateway *ExposureGatewayRef `json:"gateway,omitempty"`
+
+	// IngressClassName is the class of the Ingress. Only for type ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Annotations are set on the generated resource and take precedence
+	// over the annotations derived from the transport
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ExposureTLSConfig configures HTTPS for the exposed host
+type ExposureTLSConfig struct {
+	// SecretName is the kubernetes.io/tls Secret holding the certificate of
+	// the host. Required for type ingress. HTTPRoutes cannot reference
+	// certificates, TLS is terminated by the Gateway listener, so it must be
+	// empty for type httpRoute.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// ExposureGatewayRef references the Gateway an HTTPRoute attaches to
+type ExposureGatewayRef struct {
+	// Name of the Gateway
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Default: the namespace of the MCPServer
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName selects a listener of the Gateway
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// NetworkPolicyConfig defines the traffic allowed to and from the server pods.
+// Traffic from the session router and the operator, which validates the
+// server, is always allowed.
+type NetworkPolicyConfig struct {
+	// Ingress selects the clients allowed to connect to the server
+	// +optional
+	Ingress *NetworkPolicyIngress `json:"ingress,omitempty"`
+
+	// MetricsFrom selects the pods allowed to scrape metrics.port when
+	// metrics are enabled. Default: pods labeled
+	// app.kubernetes.io/name=prometheus in the monitoring namespace. The
+	// operator, which reads the metrics, is always allowed
+	// +optional
+	MetricsFrom []networkingv1.NetworkPolicyPeer `json:"metricsFrom,omitempty"`
+
+	// Egress restricts the connections the server pods open. Without it,
+	// egress is not restricted.
+	// +optional
+	Egress *NetworkPolicyEgress `json:"egress,omitempty"`
+}
+
+// NetworkPolicyIngress defines the clients allowed to connect to the server
+type NetworkPolicyIngress struct {
+	// From selects the namespaces and pods allowed to connect to the server
+	// port, or to the sidecar port when metrics are enabled.
+	// Default: the pods of the MCPServer's namespace
+	// +optional
+	From []networkingv1.NetworkPolicyPeer `json:"from,omitempty"`
+}
+
+// NetworkPolicyEgress defines the destinations the server pods may connect to.
+// DNS queries to kube-dns are always allowed.
+type NetworkPolicyEgress struct {
+	// CIDRs the server pods may connect to
+	// +optional
+	CIDRs []string `json:"cidrs,omitempty"`
+
+	// DNSNames the server pods may connect to. NetworkPolicies only match
+	// addresses, so the names are resolved by the operator and their addresses
+	// refreshed every 5 minutes.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+}
+
+// DisruptionConfig defines the PodDisruptionBudget of the server pods.
+// At most one of minAvailable and maxUnavailable may be set.
+type DisruptionConfig struct {
+	// Enabled creates the PodDisruptionBudget. Set to false to manage
+	// budgets yourself.
+	// Default: true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MinAvailable is the number or percentage of pods that must stay
+	// available during voluntary evictions
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods voluntary evictions
+	// may take down at once.
+	// Default: 1 when minAvailable is not set
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ScaleToZeroConfig defines when the server is scaled to zero
+type ScaleToZeroConfig struct {
+	// Enabled scales the server to zero once it is idle
+	// +optional
+	Enabled bool `json:"enabled"`
+
+	// IdleTimeout is how long the server must serve no request, and hold no
+	// SSE connection, before it is scaled to zero.
+	// Default: 15m
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+
+	// ActivationTimeout is how long the activator holds a request while the
+	// server scales up, before answering 503.
+	// Default: 2m
+	// +optional
+	ActivationTimeout *metav1.Duration `json:"activationTimeout,omitempty"`
+}
+
+// ScaleToZeroStatus reports the activity of a server that scales to zero
+type ScaleToZeroStatus struct {
+	// Idle is true while the server is scaled to zero and the activator
+	// receives its traffic
+	// +optional
+	Idle bool `json:"idle,omitempty"`
+
+	// LastActivityTime is when the sidecars last reported a new request or
+	// an open SSE connection
+	// +optional
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// ObservedRequests is the number of requests the sidecars reported at LastActivityTime
+	// +optional
+	ObservedRequests int64 `json:"observedRequests,omitempty"`
+}
+
 // MCPServerTransport defines transport configuration for the MCP server
 type MCPServerTransport struct {
 	// Type specifies the transport type
-	// +kubebuilder:validation:Enum=http;custom
+	// Valid values:
+	// - "http": The server listens on HTTP itself
+	// - "stdio": The server speaks MCP over stdin/stdout and is exposed through
+	//   an in-pod bridge that serves Streamable HTTP. Requires command to be set.
+	// +kubebuilder:validation:Enum=http;stdio;custom
 	// +kubebuilder:default=http
 	// +optional
 	Type MCPTransportType `json:"type,omitempty"`
@@ -513,12 +1289,14 @@ type MCPServerTransport struct {
 }
 
 // MCPTransportType represents the type of transport
-// +kubebuilder:validation:Enum=http
+// +kubebuilder:validation:Enum=http;stdio
 type MCPTransportType string
 
 const (
 	// MCPTransportHTTP indicates HTTP transport (supports both SSE and standard HTTP)
 	MCPTransportHTTP MCPTransportType = "http"
+	// MCPTransportStdio indicates a stdio server bridged to Streamable HTTP
+	MCPTransportStdio MCPTransportType = "stdio"
 )
 
 // MCPTransportProtocol represents the MCP protocol variant
@@ -536,7 +1314,8 @@ const (
 
 // MCPTransportConfigDetails contains transport-specific configuration options
 type MCPTransportConfigDetails struct {
-	// HTTP configuration for HTTP transport (supports SSE and standard HTTP)
+	// HTTP configuration for HTTP transport (supports SSE and standard HTTP).
+	// For stdio transport, port and path configure the bridge's HTTP endpoint.
 	// +optional
 	HTTP *MCPHTTPTransportConfig `json:"http,omitempty"`
 }
@@ -566,6 +1345,35 @@ type MCPHTTPTransportConfig struct {
 	// or when SSE is auto-detected and protocol is "auto".
 	// +optional
 	SSE *SSEConfig `json:"sse,omitempty"`
+
+	// SessionRouting puts a session router in front of the server pods. The router
+	// sends every request carrying an Mcp-Session-Id to the pod that created the
+	// session, so Streamable HTTP servers keeping sessions in memory can run
+	// several replicas. Not supported for SSE.
+	// +optional
+	SessionRouting *SessionRoutingConfig `json:"sessionRouting,omitempty"`
+}
+
+// SessionRoutingConfig configures the session router of a Streamable HTTP server.
+// The router runs as a separate Deployment named "<name>-router" with a Service
+// of the same name, discovers the ready server pods from their EndpointSlices
+// and pins each session to the pod that created it.
+type SessionRoutingConfig struct {
+	// Enabled deploys the session router
+	// +optional
+	Enabled bool `json:"enabled"`
+
+	// Replicas is the number of router pods. Router pods share no state: a
+	// session a router has not seen is found by asking the server pods.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources are the resource requirements of the router container.
+	// Default: the metrics sidecar defaults
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // SSEConfig defines SSE-specific configuration for deployments using SSE transport.
@@ -630,7 +1438,8 @@ const (
 //   - StrictMode is false (deployment continues even if validation fails)
 //   - Validation results populate status.validation fields
 //
-// Validation occurs on deployment (create/update), not periodically.
+// Validation occurs on deployment (create/update). Set interval to also
+// re-validate running servers periodically and detect capability drift.
 type ValidationSpec struct {
 	// Enabled indicates if protocol validation should be performed.
 	// Default: true (validation runs even when this spec is omitted)
@@ -662,6 +1471,89 @@ type ValidationSpec struct {
 	// Valid values: "tools", "resources", "prompts"
 	// +optional
 	RequiredCapabilities []string `json:"requiredCapabilities,omitempty"`
+
+	// RequiredTools lists tool names the server must return from tools/list.
+	// Missing tools fail validation when strictMode is true and are reported
+	// as warnings otherwise.
+	// +listType=set
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	RequiredTools []string `json:"requiredTools,omitempty"`
+
+	// RequiredResources lists resource URIs the server must return from resources/list.
+	// Resources are matched by URI, since resource names are display labels.
+	// Missing resources fail validation when strictMode is true and are reported
+	// as warnings otherwise.
+	// +listType=set
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	RequiredResources []string `json:"requiredResources,omitempty"`
+
+	// RequiredPrompts lists prompt names the server must return from prompts/list.
+	// Missing prompts fail validation when strictMode is true and are reported
+	// as warnings otherwise.
+	// +listType=set
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	RequiredPrompts []string `json:"requiredPrompts,omitempty"`
+
+	// Interval enables periodic re-validation of running servers.
+	// After validation succeeds, it is re-run at this interval and the discovered
+	// capabilities, protocol version and server info are compared with the
+	// previous result. Changes emit CapabilityDrift events and set the
+	// CapabilityDrift condition, which stays True until the spec changes.
+	// This catches images whose behaviour changed under a mutable tag.
+	// Values below 1m are raised to 1m. When omitted, no periodic validation runs.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Auth provides credentials for validating servers that require authentication.
+	// Without it, protected servers stop at the AuthRequired state.
+	// +optional
+	Auth *ValidationAuth `json:"auth,omitempty"`
+}
+
+// ValidationAuth configures the credentials the validator presents to the MCP server.
+// All values are read from a Secret in the MCPServer namespace.
+// At least one of bearerTokenKey, headers or oauth2 must be set.
+type ValidationAuth struct {
+	// SecretRef references the Secret holding the credentials
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+
+	// BearerTokenKey is the Secret key holding a token sent as "Authorization: Bearer <token>"
+	// +optional
+	BearerTokenKey string `json:"bearerTokenKey,omitempty"`
+
+	// Headers maps HTTP header names to the Secret keys holding their values.
+	// Use this for API keys (e.g., X-API-Key: api-key).
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// OAuth2 obtains an access token using the OAuth2 client credentials grant.
+	// The token takes precedence over bearerTokenKey.
+	// +optional
+	OAuth2 *ValidationOAuth2 `json:"oauth2,omitempty"`
+}
+
+// ValidationOAuth2 configures the OAuth2 client credentials grant
+type ValidationOAuth2 struct {
+	// TokenURL is the token endpoint of the authorization server
+	// +kubebuilder:validation:Pattern=`^https?://`
+	TokenURL string `json:"tokenURL"`
+
+	// Scopes are the scopes requested with the token
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// ClientIDKey is the Secret key holding the client ID
+	// +kubebuilder:default="client_id"
+	// +optional
+	ClientIDKey string `json:"clientIDKey,omitempty"`
+
+	// ClientSecretKey is the Secret key holding the client secret
+	// +kubebuilder:default="client_secret"
+	// +optional
+	ClientSecretKey string `json:"clientSecretKey,omitempty"`
 }
 
 // ValidationStatus represents the MCP protocol validation status
@@ -697,6 +1589,15 @@ type ValidationStatus struct {
 	// +optional
 	Capabilities []string `json:"capabilities,omitempty"`
 
+	// ServerInfo is the server name and version reported during initialization
+	// +optional
+	ServerInfo *ValidationServerInfo `json:"serverInfo,omitempty"`
+
+	// Inventory lists the tools, resources and prompts the server exposed
+	// during the last validation that reached it
+	// +optional
+	Inventory *ValidationInventory `json:"inventory,omitempty"`
+
 	// Compliant indicates if the server is protocol compliant
 	// +optional
 	Compliant bool `json:"compliant"`
@@ -719,6 +1620,52 @@ type ValidationStatus struct {
 	ValidatedGeneration int64 `json:"validatedGeneration,omitempty"`
 }
 
+// ValidationServerInfo identifies the MCP server implementation
+type ValidationServerInfo struct {
+	// Name is the server implementation name
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Version is the server implementation version
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// ValidationInventory lists what an MCP server exposes.
+// Small inventories are stored inline. Larger ones are written to a ConfigMap
+// to keep the status small; the counts are always set.
+type ValidationInventory struct {
+	// Tools lists the tool names returned by tools/list
+	// +optional
+	Tools []string `json:"tools,omitempty"`
+
+	// Resources lists the resource URIs returned by resources/list
+	// +optional
+	Resources []string `json:"resources,omitempty"`
+
+	// Prompts lists the prompt names returned by prompts/list
+	// +optional
+	Prompts []string `json:"prompts,omitempty"`
+
+	// ToolCount is the number of tools
+	// +optional
+	ToolCount int32 `json:"toolCount"`
+
+	// ResourceCount is the number of resources
+	// +optional
+	ResourceCount int32 `json:"resourceCount"`
+
+	// PromptCount is the number of prompts
+	// +optional
+	PromptCount int32 `json:"promptCount"`
+
+	// ConfigMapName is the ConfigMap holding the inventory when it is too large
+	// to store inline. It has the keys "tools", "resources" and "prompts", each
+	// with one entry per line.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
 // ValidationIssue represents a validation problem found
 type ValidationIssue struct {
 	// Level indicates the severity of the issue
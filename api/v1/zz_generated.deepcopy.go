@@ -22,219 +22,259 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPHTTPTransportConfig) DeepCopyInto(out *MCPHTTPTransportConfig) {
+func (in *AuditFileConfig) DeepCopyInto(out *AuditFileConfig) {
 	*out = *in
-	if in.SessionManagement != nil {
-		in, out := &in.SessionManagement, &out.SessionManagement
-		*out = new(bool)
-		**out = **in
-	}
-	if in.SSE != nil {
-		in, out := &in.SSE, &out.SSE
-		*out = new(SSEConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPHTTPTransportConfig.
-func (in *MCPHTTPTransportConfig) DeepCopy() *MCPHTTPTransportConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditFileConfig.
+func (in *AuditFileConfig) DeepCopy() *AuditFileConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPHTTPTransportConfig)
+	out := new(AuditFileConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServer) DeepCopyInto(out *MCPServer) {
+func (in *CanaryConfig) DeepCopyInto(out *CanaryConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaxErrorRatePercent != nil {
+		in, out := &in.MaxErrorRatePercent, &out.MaxErrorRatePercent
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServer.
-func (in *MCPServer) DeepCopy() *MCPServer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryConfig.
+func (in *CanaryConfig) DeepCopy() *CanaryConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServer)
+	out := new(CanaryConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MCPServer) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerCondition) DeepCopyInto(out *MCPServerCondition) {
+func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerCondition.
-func (in *MCPServerCondition) DeepCopy() *MCPServerCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStep.
+func (in *CanaryStep) DeepCopy() *CanaryStep {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerCondition)
+	out := new(CanaryStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerHPA) DeepCopyInto(out *MCPServerHPA) {
+func (in *DisruptionConfig) DeepCopyInto(out *DisruptionConfig) {
 	*out = *in
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MinReplicas != nil {
-		in, out := &in.MinReplicas, &out.MinReplicas
-		*out = new(int32)
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
 		**out = **in
 	}
-	if in.MaxReplicas != nil {
-		in, out := &in.MaxReplicas, &out.MaxReplicas
-		*out = new(int32)
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
 		**out = **in
 	}
-	if in.TargetCPUUtilizationPercentage != nil {
-		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
-		*out = new(int32)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionConfig.
+func (in *DisruptionConfig) DeepCopy() *DisruptionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposureConfig) DeepCopyInto(out *ExposureConfig) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(ExposureTLSConfig)
 		**out = **in
 	}
-	if in.TargetMemoryUtilizationPercentage != nil {
-		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
-		*out = new(int32)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.ScaleUpBehavior != nil {
-		in, out := &in.ScaleUpBehavior, &out.ScaleUpBehavior
-		*out = new(MCPServerHPABehavior)
-		(*in).DeepCopyInto(*out)
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(ExposureGatewayRef)
+		**out = **in
 	}
-	if in.ScaleDownBehavior != nil {
-		in, out := &in.ScaleDownBehavior, &out.ScaleDownBehavior
-		*out = new(MCPServerHPABehavior)
-		(*in).DeepCopyInto(*out)
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHPA.
-func (in *MCPServerHPA) DeepCopy() *MCPServerHPA {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposureConfig.
+func (in *ExposureConfig) DeepCopy() *ExposureConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerHPA)
+	out := new(ExposureConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerHPABehavior) DeepCopyInto(out *MCPServerHPABehavior) {
+func (in *ExposureGatewayRef) DeepCopyInto(out *ExposureGatewayRef) {
 	*out = *in
-	if in.StabilizationWindowSeconds != nil {
-		in, out := &in.StabilizationWindowSeconds, &out.StabilizationWindowSeconds
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Policies != nil {
-		in, out := &in.Policies, &out.Policies
-		*out = make([]MCPServerHPAPolicy, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHPABehavior.
-func (in *MCPServerHPABehavior) DeepCopy() *MCPServerHPABehavior {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposureGatewayRef.
+func (in *ExposureGatewayRef) DeepCopy() *ExposureGatewayRef {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerHPABehavior)
+	out := new(ExposureGatewayRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerHPAPolicy) DeepCopyInto(out *MCPServerHPAPolicy) {
+func (in *ExposureTLSConfig) DeepCopyInto(out *ExposureTLSConfig) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHPAPolicy.
-func (in *MCPServerHPAPolicy) DeepCopy() *MCPServerHPAPolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposureTLSConfig.
+func (in *ExposureTLSConfig) DeepCopy() *ExposureTLSConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerHPAPolicy)
+	out := new(ExposureTLSConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerHealthCheck) DeepCopyInto(out *MCPServerHealthCheck) {
+func (in *IdentityPolicy) DeepCopyInto(out *IdentityPolicy) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = new(bool)
-		**out = **in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Port != nil {
-		in, out := &in.Port, &out.Port
-		*out = new(intstr.IntOrString)
-		**out = **in
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = new(PolicyRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(PolicyRules)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHealthCheck.
-func (in *MCPServerHealthCheck) DeepCopy() *MCPServerHealthCheck {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityPolicy.
+func (in *IdentityPolicy) DeepCopy() *IdentityPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerHealthCheck)
+	out := new(IdentityPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
+func (in *MCPCatalog) DeepCopyInto(out *MCPCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalog.
+func (in *MCPCatalog) DeepCopy() *MCPCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPCatalogList) DeepCopyInto(out *MCPCatalogList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MCPServer, len(*in))
+		*out = make([]MCPCatalog, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerList.
-func (in *MCPServerList) DeepCopy() *MCPServerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalogList.
+func (in *MCPCatalogList) DeepCopy() *MCPCatalogList {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerList)
+	out := new(MCPCatalogList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MCPServerList) DeepCopyObject() runtime.Object {
+func (in *MCPCatalogList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -242,418 +282,1585 @@ func (in *MCPServerList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerPodTemplate) DeepCopyInto(out *MCPServerPodTemplate) {
+func (in *MCPCatalogPrompt) DeepCopyInto(out *MCPCatalogPrompt) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalogPrompt.
+func (in *MCPCatalogPrompt) DeepCopy() *MCPCatalogPrompt {
+	if in == nil {
+		return nil
 	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out := new(MCPCatalogPrompt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPCatalogResource) DeepCopyInto(out *MCPCatalogResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalogResource.
+func (in *MCPCatalogResource) DeepCopy() *MCPCatalogResource {
+	if in == nil {
+		return nil
 	}
-	if in.NodeSelector != nil {
-		in, out := &in.NodeSelector, &out.NodeSelector
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	out := new(MCPCatalogResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPCatalogServer) DeepCopyInto(out *MCPCatalogServer) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Tolerations != nil {
-		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]corev1.Toleration, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ServerInfo != nil {
+		in, out := &in.ServerInfo, &out.ServerInfo
+		*out = new(ValidationServerInfo)
+		**out = **in
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(corev1.Affinity)
-		(*in).DeepCopyInto(*out)
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]MCPCatalogTool, len(*in))
+		copy(*out, *in)
 	}
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]corev1.LocalObjectReference, len(*in))
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]MCPCatalogResource, len(*in))
 		copy(*out, *in)
 	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]corev1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Prompts != nil {
+		in, out := &in.Prompts, &out.Prompts
+		*out = make([]MCPCatalogPrompt, len(*in))
+		copy(*out, *in)
 	}
-	if in.VolumeMounts != nil {
-		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]corev1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.LastListedTime != nil {
+		in, out := &in.LastListedTime, &out.LastListedTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerPodTemplate.
-func (in *MCPServerPodTemplate) DeepCopy() *MCPServerPodTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalogServer.
+func (in *MCPCatalogServer) DeepCopy() *MCPCatalogServer {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerPodTemplate)
+	out := new(MCPCatalogServer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerSecurity) DeepCopyInto(out *MCPServerSecurity) {
+func (in *MCPCatalogSpec) DeepCopyInto(out *MCPCatalogSpec) {
 	*out = *in
-	if in.RunAsUser != nil {
-		in, out := &in.RunAsUser, &out.RunAsUser
-		*out = new(int64)
-		**out = **in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.RunAsGroup != nil {
-		in, out := &in.RunAsGroup, &out.RunAsGroup
-		*out = new(int64)
-		**out = **in
+	if in.ServerSelector != nil {
+		in, out := &in.ServerSelector, &out.ServerSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.ReadOnlyRootFilesystem != nil {
-		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
-		*out = new(bool)
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.RunAsNonRoot != nil {
-		in, out := &in.RunAsNonRoot, &out.RunAsNonRoot
-		*out = new(bool)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalogSpec.
+func (in *MCPCatalogSpec) DeepCopy() *MCPCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPCatalogStatus) DeepCopyInto(out *MCPCatalogStatus) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]MCPCatalogServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastRefreshTime != nil {
+		in, out := &in.LastRefreshTime, &out.LastRefreshTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalogStatus.
+func (in *MCPCatalogStatus) DeepCopy() *MCPCatalogStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPCatalogStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPCatalogTool) DeepCopyInto(out *MCPCatalogTool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPCatalogTool.
+func (in *MCPCatalogTool) DeepCopy() *MCPCatalogTool {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPCatalogTool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPGateway) DeepCopyInto(out *MCPGateway) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPGateway.
+func (in *MCPGateway) DeepCopy() *MCPGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPGateway) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPGatewayBackend) DeepCopyInto(out *MCPGatewayBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPGatewayBackend.
+func (in *MCPGatewayBackend) DeepCopy() *MCPGatewayBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPGatewayBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPGatewayList) DeepCopyInto(out *MCPGatewayList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MCPGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPGatewayList.
+func (in *MCPGatewayList) DeepCopy() *MCPGatewayList {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPGatewayList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPGatewayList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPGatewaySkippedServer) DeepCopyInto(out *MCPGatewaySkippedServer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPGatewaySkippedServer.
+func (in *MCPGatewaySkippedServer) DeepCopy() *MCPGatewaySkippedServer {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPGatewaySkippedServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPGatewaySpec) DeepCopyInto(out *MCPGatewaySpec) {
+	*out = *in
+	in.ServerSelector.DeepCopyInto(&out.ServerSelector)
+	if in.ToolPrefix != nil {
+		in, out := &in.ToolPrefix, &out.ToolPrefix
+		*out = new(MCPGatewayToolPrefix)
+		**out = **in
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPGatewaySpec.
+func (in *MCPGatewaySpec) DeepCopy() *MCPGatewaySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPGatewaySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPGatewayStatus) DeepCopyInto(out *MCPGatewayStatus) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]MCPGatewayBackend, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkippedServers != nil {
+		in, out := &in.SkippedServers, &out.SkippedServers
+		*out = make([]MCPGatewaySkippedServer, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPGatewayStatus.
+func (in *MCPGatewayStatus) DeepCopy() *MCPGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPGatewayToolPrefix) DeepCopyInto(out *MCPGatewayToolPrefix) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPGatewayToolPrefix.
+func (in *MCPGatewayToolPrefix) DeepCopy() *MCPGatewayToolPrefix {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPGatewayToolPrefix)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPHTTPTransportConfig) DeepCopyInto(out *MCPHTTPTransportConfig) {
+	*out = *in
+	if in.SessionManagement != nil {
+		in, out := &in.SessionManagement, &out.SessionManagement
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SSE != nil {
+		in, out := &in.SSE, &out.SSE
+		*out = new(SSEConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SessionRouting != nil {
+		in, out := &in.SessionRouting, &out.SessionRouting
+		*out = new(SessionRoutingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPHTTPTransportConfig.
+func (in *MCPHTTPTransportConfig) DeepCopy() *MCPHTTPTransportConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPHTTPTransportConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPHealthCheckConfig) DeepCopyInto(out *MCPHealthCheckConfig) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPHealthCheckConfig.
+func (in *MCPHealthCheckConfig) DeepCopy() *MCPHealthCheckConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPHealthCheckConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServer) DeepCopyInto(out *MCPServer) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServer.
+func (in *MCPServer) DeepCopy() *MCPServer {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPServer) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerCondition) DeepCopyInto(out *MCPServerCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerCondition.
+func (in *MCPServerCondition) DeepCopy() *MCPServerCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerHPA) DeepCopyInto(out *MCPServerHPA) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMemoryUtilizationPercentage != nil {
+		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]MCPServerHPAMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScaleUpBehavior != nil {
+		in, out := &in.ScaleUpBehavior, &out.ScaleUpBehavior
+		*out = new(MCPServerHPABehavior)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleDownBehavior != nil {
+		in, out := &in.ScaleDownBehavior, &out.ScaleDownBehavior
+		*out = new(MCPServerHPABehavior)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHPA.
+func (in *MCPServerHPA) DeepCopy() *MCPServerHPA {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerHPA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerHPABehavior) DeepCopyInto(out *MCPServerHPABehavior) {
+	*out = *in
+	if in.StabilizationWindowSeconds != nil {
+		in, out := &in.StabilizationWindowSeconds, &out.StabilizationWindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]MCPServerHPAPolicy, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHPABehavior.
+func (in *MCPServerHPABehavior) DeepCopy() *MCPServerHPABehavior {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerHPABehavior)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerHPAMetric) DeepCopyInto(out *MCPServerHPAMetric) {
+	*out = *in
+	out.Target = in.Target.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHPAMetric.
+func (in *MCPServerHPAMetric) DeepCopy() *MCPServerHPAMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerHPAMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerHPAPolicy) DeepCopyInto(out *MCPServerHPAPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHPAPolicy.
+func (in *MCPServerHPAPolicy) DeepCopy() *MCPServerHPAPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerHPAPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerHealthCheck) DeepCopyInto(out *MCPServerHealthCheck) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MCP != nil {
+		in, out := &in.MCP, &out.MCP
+		*out = new(MCPHealthCheckConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerHealthCheck.
+func (in *MCPServerHealthCheck) DeepCopy() *MCPServerHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MCPServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerList.
+func (in *MCPServerList) DeepCopy() *MCPServerList {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MCPServerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerPodTemplate) DeepCopyInto(out *MCPServerPodTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerPodTemplate.
+func (in *MCPServerPodTemplate) DeepCopy() *MCPServerPodTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerPodTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerSecurity) DeepCopyInto(out *MCPServerSecurity) {
+	*out = *in
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RunAsGroup != nil {
+		in, out := &in.RunAsGroup, &out.RunAsGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReadOnlyRootFilesystem != nil {
+		in, out := &in.ReadOnlyRootFilesystem, &out.ReadOnlyRootFilesystem
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RunAsNonRoot != nil {
+		in, out := &in.RunAsNonRoot, &out.RunAsNonRoot
+		*out = new(bool)
+		**out = **in
 	}
 	if in.AllowPrivilegeEscalation != nil {
 		in, out := &in.AllowPrivilegeEscalation, &out.AllowPrivilegeEscalation
 		*out = new(bool)
 		**out = **in
 	}
-	if in.FSGroup != nil {
-		in, out := &in.FSGroup, &out.FSGroup
-		*out = new(int64)
-		**out = **in
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSecurity.
+func (in *MCPServerSecurity) DeepCopy() *MCPServerSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerService) DeepCopyInto(out *MCPServerService) {
+	*out = *in
+	if in.TargetPort != nil {
+		in, out := &in.TargetPort, &out.TargetPort
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerService.
+func (in *MCPServerService) DeepCopy() *MCPServerService {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(MCPServerSecurity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(MCPServerService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(MCPServerHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Environment != nil {
+		in, out := &in.Environment, &out.Environment
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodTemplate != nil {
+		in, out := &in.PodTemplate, &out.PodTemplate
+		*out = new(MCPServerPodTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HPA != nil {
+		in, out := &in.HPA, &out.HPA
+		*out = new(MCPServerHPA)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transport != nil {
+		in, out := &in.Transport, &out.Transport
+		*out = new(MCPServerTransport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ValidationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecar != nil {
+		in, out := &in.Sidecar, &out.Sidecar
+		*out = new(SidecarConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(PolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exposure != nil {
+		in, out := &in.Exposure, &out.Exposure
+		*out = new(ExposureConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Disruption != nil {
+		in, out := &in.Disruption, &out.Disruption
+		*out = new(DisruptionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleToZero != nil {
+		in, out := &in.ScaleToZero, &out.ScaleToZero
+		*out = new(ScaleToZeroConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
+func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]MCPServerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResolvedTransport != nil {
+		in, out := &in.ResolvedTransport, &out.ResolvedTransport
+		*out = new(ResolvedTransportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ValidationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleToZero != nil {
+		in, out := &in.ScaleToZero, &out.ScaleToZero
+		*out = new(ScaleToZeroStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerStatus.
+func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerTransport) DeepCopyInto(out *MCPServerTransport) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(MCPTransportConfigDetails)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerTransport.
+func (in *MCPServerTransport) DeepCopy() *MCPServerTransport {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerTransport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPTransportConfigDetails) DeepCopyInto(out *MCPTransportConfigDetails) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(MCPHTTPTransportConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPTransportConfigDetails.
+func (in *MCPTransportConfigDetails) DeepCopy() *MCPTransportConfigDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPTransportConfigDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
+	*out = *in
+	if in.MaxToolLabels != nil {
+		in, out := &in.MaxToolLabels, &out.MaxToolLabels
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(TracingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfig.
+func (in *MetricsConfig) DeepCopy() *MetricsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(NetworkPolicyIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricsFrom != nil {
+		in, out := &in.MetricsFrom, &out.MetricsFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(NetworkPolicyEgress)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
+func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyEgress) DeepCopyInto(out *NetworkPolicyEgress) {
+	*out = *in
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyEgress.
+func (in *NetworkPolicyEgress) DeepCopy() *NetworkPolicyEgress {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyEgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyIngress) DeepCopyInto(out *NetworkPolicyIngress) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyIngress.
+func (in *NetworkPolicyIngress) DeepCopy() *NetworkPolicyIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyRules) DeepCopyInto(out *PolicyRules) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyRules.
+func (in *PolicyRules) DeepCopy() *PolicyRules {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicySpec) DeepCopyInto(out *PolicySpec) {
+	*out = *in
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = new(PolicyRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(PolicyRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Identities != nil {
+		in, out := &in.Identities, &out.Identities
+		*out = make([]IdentityPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicySpec.
+func (in *PolicySpec) DeepCopy() *PolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitRule) DeepCopyInto(out *RateLimitRule) {
+	*out = *in
+	if in.By != nil {
+		in, out := &in.By, &out.By
+		*out = make([]RateLimitKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitRule.
+func (in *RateLimitRule) DeepCopy() *RateLimitRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedTransportStatus) DeepCopyInto(out *ResolvedTransportStatus) {
+	*out = *in
+	if in.LastResolvedTime != nil {
+		in, out := &in.LastResolvedTime, &out.LastResolvedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedTransportStatus.
+func (in *ResolvedTransportStatus) DeepCopy() *ResolvedTransportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedTransportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutConfig) DeepCopyInto(out *RolloutConfig) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutConfig.
+func (in *RolloutConfig) DeepCopy() *RolloutConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.ToolsAdded != nil {
+		in, out := &in.ToolsAdded, &out.ToolsAdded
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ToolsRemoved != nil {
+		in, out := &in.ToolsRemoved, &out.ToolsRemoved
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSEConfig) DeepCopyInto(out *SSEConfig) {
+	*out = *in
+	if in.EnableSessionAffinity != nil {
+		in, out := &in.EnableSessionAffinity, &out.EnableSessionAffinity
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSEConfig.
+func (in *SSEConfig) DeepCopy() *SSEConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SSEConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleToZeroConfig) DeepCopyInto(out *ScaleToZeroConfig) {
+	*out = *in
+	if in.IdleTimeout != nil {
+		in, out := &in.IdleTimeout, &out.IdleTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ActivationTimeout != nil {
+		in, out := &in.ActivationTimeout, &out.ActivationTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleToZeroConfig.
+func (in *ScaleToZeroConfig) DeepCopy() *ScaleToZeroConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleToZeroConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleToZeroStatus) DeepCopyInto(out *ScaleToZeroStatus) {
+	*out = *in
+	if in.LastActivityTime != nil {
+		in, out := &in.LastActivityTime, &out.LastActivityTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleToZeroStatus.
+func (in *ScaleToZeroStatus) DeepCopy() *ScaleToZeroStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleToZeroStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionRoutingConfig) DeepCopyInto(out *SessionRoutingConfig) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSecurity.
-func (in *MCPServerSecurity) DeepCopy() *MCPServerSecurity {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionRoutingConfig.
+func (in *SessionRoutingConfig) DeepCopy() *SessionRoutingConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerSecurity)
+	out := new(SessionRoutingConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerService) DeepCopyInto(out *MCPServerService) {
+func (in *SidecarAPIKeyAuth) DeepCopyInto(out *SidecarAPIKeyAuth) {
 	*out = *in
-	if in.TargetPort != nil {
-		in, out := &in.TargetPort, &out.TargetPort
-		*out = new(intstr.IntOrString)
-		**out = **in
-	}
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.SecretRef = in.SecretRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerService.
-func (in *MCPServerService) DeepCopy() *MCPServerService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarAPIKeyAuth.
+func (in *SidecarAPIKeyAuth) DeepCopy() *SidecarAPIKeyAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerService)
+	out := new(SidecarAPIKeyAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
+func (in *SidecarAuditConfig) DeepCopyInto(out *SidecarAuditConfig) {
 	*out = *in
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(AuditFileConfig)
+		**out = **in
 	}
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
+	if in.Redact != nil {
+		in, out := &in.Redact, &out.Redact
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int32)
-		**out = **in
-	}
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = new(corev1.ResourceRequirements)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Security != nil {
-		in, out := &in.Security, &out.Security
-		*out = new(MCPServerSecurity)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarAuditConfig.
+func (in *SidecarAuditConfig) DeepCopy() *SidecarAuditConfig {
+	if in == nil {
+		return nil
 	}
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(MCPServerService)
-		(*in).DeepCopyInto(*out)
+	out := new(SidecarAuditConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarAuthConfig) DeepCopyInto(out *SidecarAuthConfig) {
+	*out = *in
+	if in.APIKeys != nil {
+		in, out := &in.APIKeys, &out.APIKeys
+		*out = new(SidecarAPIKeyAuth)
+		**out = **in
 	}
-	if in.HealthCheck != nil {
-		in, out := &in.HealthCheck, &out.HealthCheck
-		*out = new(MCPServerHealthCheck)
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(SidecarJWTAuth)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Environment != nil {
-		in, out := &in.Environment, &out.Environment
-		*out = make([]corev1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.PodTemplate != nil {
-		in, out := &in.PodTemplate, &out.PodTemplate
-		*out = new(MCPServerPodTemplate)
-		(*in).DeepCopyInto(*out)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarAuthConfig.
+func (in *SidecarAuthConfig) DeepCopy() *SidecarAuthConfig {
+	if in == nil {
+		return nil
 	}
-	if in.HPA != nil {
-		in, out := &in.HPA, &out.HPA
-		*out = new(MCPServerHPA)
-		(*in).DeepCopyInto(*out)
+	out := new(SidecarAuthConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfig) DeepCopyInto(out *SidecarConfig) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(SidecarTLSConfig)
+		**out = **in
 	}
-	if in.Transport != nil {
-		in, out := &in.Transport, &out.Transport
-		*out = new(MCPServerTransport)
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(SidecarAuthConfig)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Validation != nil {
-		in, out := &in.Validation, &out.Validation
-		*out = new(ValidationSpec)
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(SidecarRateLimitConfig)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Metrics != nil {
-		in, out := &in.Metrics, &out.Metrics
-		*out = new(MetricsConfig)
-		**out = **in
-	}
-	if in.Sidecar != nil {
-		in, out := &in.Sidecar, &out.Sidecar
-		*out = new(SidecarConfig)
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(SidecarAuditConfig)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
-func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfig.
+func (in *SidecarConfig) DeepCopy() *SidecarConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerSpec)
+	out := new(SidecarConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
+func (in *SidecarJWTAuth) DeepCopyInto(out *SidecarJWTAuth) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]MCPServerCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.ResolvedTransport != nil {
-		in, out := &in.ResolvedTransport, &out.ResolvedTransport
-		*out = new(ResolvedTransportStatus)
+	if in.JWKSConfigMapRef != nil {
+		in, out := &in.JWKSConfigMapRef, &out.JWKSConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.LastReconcileTime != nil {
-		in, out := &in.LastReconcileTime, &out.LastReconcileTime
-		*out = (*in).DeepCopy()
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Validation != nil {
-		in, out := &in.Validation, &out.Validation
-		*out = new(ValidationStatus)
-		(*in).DeepCopyInto(*out)
+	if in.AuthorizationServers != nil {
+		in, out := &in.AuthorizationServers, &out.AuthorizationServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerStatus.
-func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarJWTAuth.
+func (in *SidecarJWTAuth) DeepCopy() *SidecarJWTAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerStatus)
+	out := new(SidecarJWTAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerTransport) DeepCopyInto(out *MCPServerTransport) {
+func (in *SidecarRateLimitConfig) DeepCopyInto(out *SidecarRateLimitConfig) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = new(MCPTransportConfigDetails)
-		(*in).DeepCopyInto(*out)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RateLimitRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerTransport.
-func (in *MCPServerTransport) DeepCopy() *MCPServerTransport {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarRateLimitConfig.
+func (in *SidecarRateLimitConfig) DeepCopy() *SidecarRateLimitConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerTransport)
+	out := new(SidecarRateLimitConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPTransportConfigDetails) DeepCopyInto(out *MCPTransportConfigDetails) {
+func (in *SidecarTLSConfig) DeepCopyInto(out *SidecarTLSConfig) {
 	*out = *in
-	if in.HTTP != nil {
-		in, out := &in.HTTP, &out.HTTP
-		*out = new(MCPHTTPTransportConfig)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPTransportConfigDetails.
-func (in *MCPTransportConfigDetails) DeepCopy() *MCPTransportConfigDetails {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarTLSConfig.
+func (in *SidecarTLSConfig) DeepCopy() *SidecarTLSConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPTransportConfigDetails)
+	out := new(SidecarTLSConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
+func (in *TracingConfig) DeepCopyInto(out *TracingConfig) {
 	*out = *in
+	if in.SamplingPercentage != nil {
+		in, out := &in.SamplingPercentage, &out.SamplingPercentage
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfig.
-func (in *MetricsConfig) DeepCopy() *MetricsConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingConfig.
+func (in *TracingConfig) DeepCopy() *TracingConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricsConfig)
+	out := new(TracingConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResolvedTransportStatus) DeepCopyInto(out *ResolvedTransportStatus) {
+func (in *ValidationAuth) DeepCopyInto(out *ValidationAuth) {
 	*out = *in
-	if in.LastResolvedTime != nil {
-		in, out := &in.LastResolvedTime, &out.LastResolvedTime
-		*out = (*in).DeepCopy()
+	out.SecretRef = in.SecretRef
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.OAuth2 != nil {
+		in, out := &in.OAuth2, &out.OAuth2
+		*out = new(ValidationOAuth2)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedTransportStatus.
-func (in *ResolvedTransportStatus) DeepCopy() *ResolvedTransportStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationAuth.
+func (in *ValidationAuth) DeepCopy() *ValidationAuth {
 	if in == nil {
 		return nil
 	}
-	out := new(ResolvedTransportStatus)
+	out := new(ValidationAuth)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SSEConfig) DeepCopyInto(out *SSEConfig) {
+func (in *ValidationInventory) DeepCopyInto(out *ValidationInventory) {
 	*out = *in
-	if in.EnableSessionAffinity != nil {
-		in, out := &in.EnableSessionAffinity, &out.EnableSessionAffinity
-		*out = new(bool)
-		**out = **in
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.TerminationGracePeriodSeconds != nil {
-		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
-		*out = new(int64)
-		**out = **in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.MaxSurge != nil {
-		in, out := &in.MaxSurge, &out.MaxSurge
-		*out = new(intstr.IntOrString)
-		**out = **in
+	if in.Prompts != nil {
+		in, out := &in.Prompts, &out.Prompts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSEConfig.
-func (in *SSEConfig) DeepCopy() *SSEConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationInventory.
+func (in *ValidationInventory) DeepCopy() *ValidationInventory {
 	if in == nil {
 		return nil
 	}
-	out := new(SSEConfig)
+	out := new(ValidationInventory)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SidecarConfig) DeepCopyInto(out *SidecarConfig) {
+func (in *ValidationIssue) DeepCopyInto(out *ValidationIssue) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.TLS != nil {
-		in, out := &in.TLS, &out.TLS
-		*out = new(SidecarTLSConfig)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfig.
-func (in *SidecarConfig) DeepCopy() *SidecarConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationIssue.
+func (in *ValidationIssue) DeepCopy() *ValidationIssue {
 	if in == nil {
 		return nil
 	}
-	out := new(SidecarConfig)
+	out := new(ValidationIssue)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SidecarTLSConfig) DeepCopyInto(out *SidecarTLSConfig) {
+func (in *ValidationOAuth2) DeepCopyInto(out *ValidationOAuth2) {
 	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarTLSConfig.
-func (in *SidecarTLSConfig) DeepCopy() *SidecarTLSConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationOAuth2.
+func (in *ValidationOAuth2) DeepCopy() *ValidationOAuth2 {
 	if in == nil {
 		return nil
 	}
-	out := new(SidecarTLSConfig)
+	out := new(ValidationOAuth2)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ValidationIssue) DeepCopyInto(out *ValidationIssue) {
+func (in *ValidationServerInfo) DeepCopyInto(out *ValidationServerInfo) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationIssue.
-func (in *ValidationIssue) DeepCopy() *ValidationIssue {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationServerInfo.
+func (in *ValidationServerInfo) DeepCopy() *ValidationServerInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(ValidationIssue)
+	out := new(ValidationServerInfo)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -676,6 +1883,31 @@ func (in *ValidationSpec) DeepCopyInto(out *ValidationSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RequiredTools != nil {
+		in, out := &in.RequiredTools, &out.RequiredTools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredResources != nil {
+		in, out := &in.RequiredResources, &out.RequiredResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredPrompts != nil {
+		in, out := &in.RequiredPrompts, &out.RequiredPrompts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ValidationAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationSpec.
@@ -700,6 +1932,16 @@ func (in *ValidationStatus) DeepCopyInto(out *ValidationStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServerInfo != nil {
+		in, out := &in.ServerInfo, &out.ServerInfo
+		*out = new(ValidationServerInfo)
+		**out = **in
+	}
+	if in.Inventory != nil {
+		in, out := &in.Inventory, &out.Inventory
+		*out = new(ValidationInventory)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LastValidated != nil {
 		in, out := &in.LastValidated, &out.LastValidated
 		*out = (*in).DeepCopy()
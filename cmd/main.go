@@ -17,10 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -40,8 +42,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/catalog"
 	"github.com/vitorbari/mcp-operator/internal/controller"
+	"github.com/vitorbari/mcp-operator/internal/metricsadapter"
 	"github.com/vitorbari/mcp-operator/internal/transport"
+	webhookv1 "github.com/vitorbari/mcp-operator/internal/webhook/v1"
 	"github.com/vitorbari/mcp-operator/pkg/validator"
 	// +kubebuilder:scaffold:imports
 )
@@ -66,12 +71,29 @@ func main() {
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
+	var catalogAddr string
+	var secureCatalog bool
+	var metricsAdapterAddr string
+	var metricsAdapterCertPath, metricsAdapterCertName, metricsAdapterCertKey string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&catalogAddr, "catalog-bind-address", "0", "The address the MCPCatalog JSON endpoint binds to. "+
+		"Use :8090 to serve catalogs, or leave as 0 to disable it.")
+	flag.BoolVar(&secureCatalog, "catalog-secure", true,
+		"If set, the catalog endpoint is served via HTTPS to clients authorized to get /catalogs. "+
+			"Use --catalog-secure=false to serve any client via HTTP instead.")
+	flag.StringVar(&metricsAdapterAddr, "metrics-adapter-bind-address", "0", "The address the custom and external "+
+		"metrics APIs bind to. Use :6443 to serve MCP traffic metrics to HPAs, or leave as 0 to disable it.")
+	flag.StringVar(&metricsAdapterCertPath, "metrics-adapter-cert-path", "",
+		"The directory that contains the metrics adapter certificate.")
+	flag.StringVar(&metricsAdapterCertName, "metrics-adapter-cert-name", "tls.crt",
+		"The name of the metrics adapter certificate file.")
+	flag.StringVar(&metricsAdapterCertKey, "metrics-adapter-cert-key", "tls.key",
+		"The name of the metrics adapter key file.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -223,12 +245,107 @@ func main() {
 		Scheme:           mgr.GetScheme(),
 		TransportFactory: transportFactory,
 		Recorder:         mgr.GetEventRecorderFor("mcpserver-controller"),
+
+		OperatorNamespace: operatorNamespace(),
+		APIReader:         mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MCPServer")
 		os.Exit(1)
 	}
+	if err := (&controller.MCPCatalogReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MCPCatalog")
+		os.Exit(1)
+	}
+	if err := (&controller.MCPGatewayReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MCPGateway")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1.SetupMCPServerWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "MCPServer")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	if catalogAddr != "0" {
+		setupLog.Info("Adding catalog server to manager", "address", catalogAddr, "secure", secureCatalog)
+		catalogServer := &catalog.Server{Reader: mgr.GetClient(), BindAddress: catalogAddr}
+		if secureCatalog {
+			// Catalogs are protected like the metrics endpoint: clients are authenticated
+			// with TokenReviews and authorized with SubjectAccessReviews for the
+			// /catalogs non-resource URLs
+			filter, err := filters.WithAuthenticationAndAuthorization(mgr.GetConfig(), mgr.GetHTTPClient())
+			if err != nil {
+				setupLog.Error(err, "unable to create the catalog server filter")
+				os.Exit(1)
+			}
+			catalogServer.SecureServing = true
+			catalogServer.TLSOpts = tlsOpts
+			catalogServer.Filter = filter
+		}
+		if err := mgr.Add(catalogServer); err != nil {
+			setupLog.Error(err, "unable to add catalog server to manager")
+			os.Exit(1)
+		}
+	}
+
+	if metricsAdapterAddr != "0" {
+		if len(metricsAdapterCertPath) == 0 {
+			setupLog.Error(nil, "--metrics-adapter-cert-path is required to serve the metrics APIs")
+			os.Exit(1)
+		}
+
+		setupLog.Info("Initializing metrics adapter certificate watcher using provided certificates",
+			"metrics-adapter-cert-path", metricsAdapterCertPath, "metrics-adapter-cert-name", metricsAdapterCertName,
+			"metrics-adapter-cert-key", metricsAdapterCertKey)
+		metricsAdapterCertWatcher, err := certwatcher.New(
+			filepath.Join(metricsAdapterCertPath, metricsAdapterCertName),
+			filepath.Join(metricsAdapterCertPath, metricsAdapterCertKey),
+		)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize metrics adapter certificate watcher")
+			os.Exit(1)
+		}
+		if err := mgr.Add(metricsAdapterCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add metrics adapter certificate watcher to manager")
+			os.Exit(1)
+		}
+
+		// Only the Kubernetes API server, proxying the requests of the HPA
+		// controller, may read the metrics
+		clientCAs, err := metricsadapter.RequestHeaderClientCAs(context.Background(), mgr.GetAPIReader())
+		if err != nil {
+			setupLog.Error(err, "unable to read the client CA of the Kubernetes API server")
+			os.Exit(1)
+		}
+
+		setupLog.Info("Adding metrics adapter to manager", "address", metricsAdapterAddr)
+		collector := &metricsadapter.Collector{Reader: mgr.GetClient()}
+		if err := mgr.Add(collector); err != nil {
+			setupLog.Error(err, "unable to add metrics collector to manager")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&metricsadapter.Server{
+			Collector:   collector,
+			BindAddress: metricsAdapterAddr,
+			TLSOpts: append(tlsOpts, func(config *tls.Config) {
+				config.GetCertificate = metricsAdapterCertWatcher.GetCertificate
+			}),
+			ClientCAs: clientCAs,
+		}); err != nil {
+			setupLog.Error(err, "unable to add metrics adapter to manager")
+			os.Exit(1)
+		}
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -260,3 +377,13 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// operatorNamespace returns the namespace the operator runs in, read from its
+// service account, or "" when running outside a cluster
+func operatorNamespace() string {
+	namespace, err := os.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/namespace")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(namespace))
+}
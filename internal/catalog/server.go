@@ -0,0 +1,230 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package catalog serves MCPCatalog contents as JSON over HTTP, so clients can
+// discover MCP servers without access to the Kubernetes API.
+package catalog
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Catalog is the JSON representation of an MCPCatalog
+type Catalog struct {
+	Name   string                 `json:"name"`
+	Status mcpv1.MCPCatalogStatus `json:"status"`
+}
+
+// Server serves the catalogs read from the manager's cache.
+// It implements manager.Runnable and runs on every replica, not just the leader.
+type Server struct {
+	// Reader is used to read MCPCatalogs, normally the manager's cached client
+	Reader client.Reader
+
+	// BindAddress is the address the server listens on, e.g. ":8090"
+	BindAddress string
+
+	// SecureServing serves the catalogs over HTTPS. When TLSOpts provide no
+	// certificate, a self-signed one is generated, as for the metrics endpoint.
+	SecureServing bool
+
+	// TLSOpts configure the listener when SecureServing is set
+	TLSOpts []func(*tls.Config)
+
+	// Filter wraps the handler, normally with filters.WithAuthenticationAndAuthorization
+	// so that only clients allowed to get the /catalogs non-resource URLs are served.
+	// When nil, any client is served.
+	Filter metricsserver.Filter
+}
+
+// Handler returns the HTTP handler serving the catalogs:
+//
+//	GET /catalogs         all catalogs
+//	GET /catalogs/{name}  a single catalog
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /catalogs", s.listCatalogs)
+	mux.HandleFunc("GET /catalogs/{name}", s.getCatalog)
+	return mux
+}
+
+// Start serves the catalogs until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("catalog-server")
+
+	handler := s.Handler()
+	if s.Filter != nil {
+		var err error
+		handler, err = s.Filter(log, handler)
+		if err != nil {
+			return fmt.Errorf("failed to apply the catalog server filter: %w", err)
+		}
+	}
+
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Info("Serving catalogs", "address", listener.Addr().String())
+		errChan <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// listen opens the listener, with TLS when SecureServing is set
+func (s *Server) listen() (net.Listener, error) {
+	if !s.SecureServing {
+		return net.Listen("tcp", s.BindAddress)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	for _, opt := range s.TLSOpts {
+		opt(tlsConfig)
+	}
+	if tlsConfig.GetCertificate == nil && len(tlsConfig.Certificates) == 0 {
+		cert, key, err := certutil.GenerateSelfSignedCertKeyWithFixtures("localhost", []net.IP{{127, 0, 0, 1}}, nil, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate self-signed certificate for catalog server: %w", err)
+		}
+		keyPair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create self-signed key pair for catalog server: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+
+	return tls.Listen("tcp", s.BindAddress, tlsConfig)
+}
+
+// NeedLeaderElection returns false so every replica serves the catalogs
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) listCatalogs(w http.ResponseWriter, r *http.Request) {
+	catalogList := &mcpv1.MCPCatalogList{}
+	if err := s.Reader.List(r.Context(), catalogList); err != nil {
+		logf.FromContext(r.Context()).Error(err, "Failed to list MCPCatalogs")
+		writeError(w, http.StatusInternalServerError, "failed to list catalogs")
+		return
+	}
+
+	catalogs := make([]Catalog, 0, len(catalogList.Items))
+	for _, item := range catalogList.Items {
+		catalogs = append(catalogs, Catalog{Name: item.Name, Status: s.fullStatus(r.Context(), &item)})
+	}
+	writeJSON(w, http.StatusOK, map[string][]Catalog{"catalogs": catalogs})
+}
+
+func (s *Server) getCatalog(w http.ResponseWriter, r *http.Request) {
+	catalog := &mcpv1.MCPCatalog{}
+	if err := s.Reader.Get(r.Context(), client.ObjectKey{Name: r.PathValue("name")}, catalog); err != nil {
+		if apierrors.IsNotFound(err) {
+			writeError(w, http.StatusNotFound, "catalog not found")
+			return
+		}
+		logf.FromContext(r.Context()).Error(err, "Failed to get MCPCatalog")
+		writeError(w, http.StatusInternalServerError, "failed to get catalog")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Catalog{Name: catalog.Name, Status: s.fullStatus(r.Context(), catalog)})
+}
+
+// fullStatus returns the status of a catalog with the full tool listing of each
+// server. The status only keeps part of the tools of large catalogs; the rest is
+// read from the ConfigMap the entry points at. When it cannot be read, the
+// entry is served as stored in the status.
+func (s *Server) fullStatus(ctx context.Context, catalog *mcpv1.MCPCatalog) mcpv1.MCPCatalogStatus {
+	status := *catalog.Status.DeepCopy()
+	for i := range status.Servers {
+		server := &status.Servers[i]
+		if server.ToolsConfigMapName == "" {
+			continue
+		}
+
+		tools, err := s.readTools(ctx, server.Namespace, server.ToolsConfigMapName)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to read catalog tools",
+				"catalog", catalog.Name, "configMap", server.Namespace+"/"+server.ToolsConfigMapName)
+			continue
+		}
+		server.Tools = tools
+		server.ToolsConfigMapName = ""
+	}
+	return status
+}
+
+// readTools reads a tool listing written by the MCPCatalog controller
+func (s *Server) readTools(ctx context.Context, namespace, name string) ([]mcpv1.MCPCatalogTool, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := s.Reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap); err != nil {
+		return nil, err
+	}
+
+	var tools []mcpv1.MCPCatalogTool
+	if err := json.Unmarshal([]byte(configMap.Data[mcpv1.MCPCatalogToolsConfigMapKey]), &tools); err != nil {
+		return nil, fmt.Errorf("invalid tool listing: %w", err)
+	}
+	return tools, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+func TestCatalog(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Catalog Suite")
+}
+
+var _ = Describe("Catalog Server", func() {
+	var (
+		reader  client.Reader
+		handler http.Handler
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(mcpv1.AddToScheme(scheme)).To(Succeed())
+
+		catalog := &mcpv1.MCPCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: "all"},
+			Status: mcpv1.MCPCatalogStatus{
+				ServerCount: 1,
+				ToolCount:   1,
+				Servers: []mcpv1.MCPCatalogServer{{
+					Name:      "weather",
+					Namespace: "default",
+					Tools:     []mcpv1.MCPCatalogTool{{Name: "forecast"}},
+				}},
+			},
+		}
+
+		reader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(catalog).Build()
+		server := &Server{Reader: reader}
+		handler = server.Handler()
+	})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	It("should list all catalogs as JSON", func() {
+		recorder := serve(http.MethodGet, "/catalogs")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var body struct {
+			Catalogs []Catalog `json:"catalogs"`
+		}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+		Expect(body.Catalogs).To(HaveLen(1))
+		Expect(body.Catalogs[0].Name).To(Equal("all"))
+		Expect(body.Catalogs[0].Status.Servers).To(HaveLen(1))
+		Expect(body.Catalogs[0].Status.Servers[0].Tools).To(ConsistOf(mcpv1.MCPCatalogTool{Name: "forecast"}))
+	})
+
+	It("should return a single catalog by name", func() {
+		recorder := serve(http.MethodGet, "/catalogs/all")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var catalog Catalog
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &catalog)).To(Succeed())
+		Expect(catalog.Status.ServerCount).To(Equal(int32(1)))
+	})
+
+	It("should serve the full tool listing of truncated servers", func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(scheme)).To(Succeed())
+
+		catalog := &mcpv1.MCPCatalog{
+			ObjectMeta: metav1.ObjectMeta{Name: "large"},
+			Status: mcpv1.MCPCatalogStatus{
+				ServerCount: 1,
+				ToolCount:   2,
+				Servers: []mcpv1.MCPCatalogServer{{
+					Name:               "weather",
+					Namespace:          "default",
+					Tools:              []mcpv1.MCPCatalogTool{{Name: "alerts"}},
+					ToolCount:          2,
+					ToolsConfigMapName: "weather-catalog-tools",
+				}},
+			},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather-catalog-tools", Namespace: "default"},
+			Data: map[string]string{
+				mcpv1.MCPCatalogToolsConfigMapKey: `[{"name":"alerts"},{"name":"forecast","description":"Forecast"}]`,
+			},
+		}
+		server := &Server{Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(catalog, configMap).Build()}
+
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/catalogs/large", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var body Catalog
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &body)).To(Succeed())
+		Expect(body.Status.Servers).To(HaveLen(1))
+		Expect(body.Status.Servers[0].ToolsConfigMapName).To(BeEmpty())
+		Expect(body.Status.Servers[0].Tools).To(Equal([]mcpv1.MCPCatalogTool{
+			{Name: "alerts"},
+			{Name: "forecast", Description: "Forecast"},
+		}))
+	})
+
+	It("should return 404 for an unknown catalog", func() {
+		recorder := serve(http.MethodGet, "/catalogs/missing")
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		Expect(recorder.Body.String()).To(ContainSubstring("catalog not found"))
+	})
+
+	It("should reject other methods", func() {
+		recorder := serve(http.MethodPost, "/catalogs")
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should serve over HTTPS behind the filter", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		address := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		server := &Server{
+			Reader:        reader,
+			BindAddress:   address,
+			SecureServing: true,
+			Filter: func(_ logr.Logger, next http.Handler) (http.Handler, error) {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("Authorization") != "Bearer reader" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					next.ServeHTTP(w, r)
+				}), nil
+			},
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- server.Start(ctx) }()
+		DeferCleanup(func() {
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		httpClient := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		}}
+		get := func(token string) (int, error) {
+			request, err := http.NewRequest(http.MethodGet, "https://"+address+"/catalogs", nil)
+			if err != nil {
+				return 0, err
+			}
+			if token != "" {
+				request.Header.Set("Authorization", "Bearer "+token)
+			}
+			response, err := httpClient.Do(request)
+			if err != nil {
+				return 0, err
+			}
+			defer func() { _ = response.Body.Close() }()
+			return response.StatusCode, nil
+		}
+
+		Eventually(get).WithArguments("").Should(Equal(http.StatusUnauthorized))
+		Expect(get("reader")).To(Equal(http.StatusOK))
+	})
+})
@@ -0,0 +1,204 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/utils"
+)
+
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+
+// disruptionBudgetEnabled reports whether the MCPServer gets a
+// PodDisruptionBudget: when spec.disruption asks for one, or by default when
+// the server runs several replicas or is autoscaled
+func disruptionBudgetEnabled(mcpServer *mcpv1.MCPServer) bool {
+	if disruption := mcpServer.Spec.Disruption; disruption != nil {
+		return disruption.Enabled == nil || *disruption.Enabled
+	}
+	hpa := mcpServer.Spec.HPA
+	if hpa != nil && hpa.Enabled != nil && *hpa.Enabled {
+		return true
+	}
+	return mcpServer.Spec.Replicas != nil && *mcpServer.Spec.Replicas > 1
+}
+
+// reconcileDisruptionBudget creates the PodDisruptionBudget of the MCPServer,
+// removes it when no longer wanted, and reports in the DisruptionAllowed
+// condition whether it currently allows voluntary evictions
+func (r *MCPServerReconciler) reconcileDisruptionBudget(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	log := logf.FromContext(ctx)
+	previousStatus := mcpServer.Status.DeepCopy()
+
+	foreign, err := r.foreignDisruptionBudget(ctx, mcpServer)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !disruptionBudgetEnabled(mcpServer):
+		if err := r.deleteDisruptionBudget(ctx, mcpServer); err != nil {
+			return err
+		}
+		r.removeCondition(mcpServer, mcpv1.MCPServerConditionDisruptionAllowed)
+	case foreign != "":
+		// Evictions fail for pods selected by more than one budget, so a
+		// budget maintained by hand takes precedence over the generated one
+		log.Info("PodDisruptionBudget not managed by the operator selects the server pods", "pdb", foreign)
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "ForeignDisruptionBudget",
+			fmt.Sprintf("PodDisruptionBudget %s already selects the server pods, not creating another", foreign))
+		if err := r.deleteDisruptionBudget(ctx, mcpServer); err != nil {
+			return err
+		}
+		r.removeCondition(mcpServer, mcpv1.MCPServerConditionDisruptionAllowed)
+	default:
+		pdb, err := r.applyDisruptionBudget(ctx, mcpServer)
+		if err != nil {
+			return err
+		}
+		r.setDisruptionAllowedCondition(mcpServer, pdb)
+	}
+
+	if reflect.DeepEqual(previousStatus, &mcpServer.Status) {
+		return nil
+	}
+	return r.updateStatus(ctx, mcpServer)
+}
+
+// applyDisruptionBudget creates or updates the PodDisruptionBudget of the MCPServer
+func (r *MCPServerReconciler) applyDisruptionBudget(ctx context.Context, mcpServer *mcpv1.MCPServer) (*policyv1.PodDisruptionBudget, error) {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+	}
+	desired := buildDisruptionBudget(mcpServer)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+			if err := controllerutil.SetControllerReference(mcpServer, pdb, r.Scheme); err != nil {
+				return err
+			}
+			pdb.Labels = desired.Labels
+			pdb.Spec = desired.Spec
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconcile PodDisruptionBudget: %w", err)
+	}
+	return pdb, nil
+}
+
+// buildDisruptionBudget builds the PodDisruptionBudget of the MCPServer.
+// It selects canary pods too, as they serve part of the traffic.
+func buildDisruptionBudget(mcpServer *mcpv1.MCPServer) *policyv1.PodDisruptionBudget {
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": mcpServer.Name}},
+	}
+
+	disruption := mcpServer.Spec.Disruption
+	switch {
+	case disruption != nil && disruption.MinAvailable != nil:
+		spec.MinAvailable = disruption.MinAvailable
+	case disruption != nil && disruption.MaxUnavailable != nil:
+		spec.MaxUnavailable = disruption.MaxUnavailable
+	default:
+		maxUnavailable := intstr.FromInt32(1)
+		spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServer.Name,
+			Namespace: mcpServer.Namespace,
+			Labels:    utils.BuildStandardLabels(mcpServer),
+		},
+		Spec: spec,
+	}
+}
+
+// foreignDisruptionBudget returns the name of a PodDisruptionBudget the
+// MCPServer does not control that selects its pods, or "" if there is none
+func (r *MCPServerReconciler) foreignDisruptionBudget(ctx context.Context, mcpServer *mcpv1.MCPServer) (string, error) {
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := r.List(ctx, pdbs, client.InNamespace(mcpServer.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list PodDisruptionBudgets: %w", err)
+	}
+
+	podLabels := labels.Set(utils.BuildStandardLabels(mcpServer))
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		if metav1.IsControlledBy(pdb, mcpServer) || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		if selector.Matches(podLabels) {
+			return pdb.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// setDisruptionAllowedCondition reports whether the PodDisruptionBudget
+// allows voluntary evictions, once the disruption controller observed it
+func (r *MCPServerReconciler) setDisruptionAllowedCondition(mcpServer *mcpv1.MCPServer, pdb *policyv1.PodDisruptionBudget) {
+	if pdb.Status.ObservedGeneration == 0 || pdb.Status.ObservedGeneration < pdb.Generation {
+		return
+	}
+
+	condition := mcpv1.MCPServerCondition{
+		Type:               mcpv1.MCPServerConditionDisruptionAllowed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "EvictionsAllowed",
+		Message: fmt.Sprintf("PodDisruptionBudget %s allows %d voluntary evictions",
+			pdb.Name, pdb.Status.DisruptionsAllowed),
+	}
+	if pdb.Status.DisruptionsAllowed == 0 {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "EvictionsBlocked"
+		condition.Message = fmt.Sprintf(
+			"PodDisruptionBudget %s blocks voluntary evictions: %d of %d pods healthy, %d must stay healthy",
+			pdb.Name, pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods, pdb.Status.DesiredHealthy)
+	}
+	r.setCondition(mcpServer, condition)
+}
+
+// deleteDisruptionBudget removes the PodDisruptionBudget of the MCPServer, if any
+func (r *MCPServerReconciler) deleteDisruptionBudget(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+	}
+	return client.IgnoreNotFound(r.deleteIfOwned(ctx, mcpServer, pdb))
+}
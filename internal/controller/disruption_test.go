@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+var _ = Describe("Disruption Budget", func() {
+	var (
+		ctx        context.Context
+		reconciler *MCPServerReconciler
+		mcpServer  *mcpv1.MCPServer
+	)
+
+	key := types.NamespacedName{Name: "weather", Namespace: "default"}
+
+	// disruptionAllowed returns the DisruptionAllowed condition, if set
+	disruptionAllowed := func() *mcpv1.MCPServerCondition {
+		for i := range mcpServer.Status.Conditions {
+			if mcpServer.Status.Conditions[i].Type == mcpv1.MCPServerConditionDisruptionAllowed {
+				return &mcpServer.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "weather-uid"},
+			Spec: mcpv1.MCPServerSpec{
+				Image:    "weather-server:latest",
+				Replicas: ptr(int32(3)),
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		reconciler = &MCPServerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(runtimeScheme).
+				WithObjects(mcpServer).
+				WithStatusSubresource(mcpServer, &policyv1.PodDisruptionBudget{}).
+				Build(),
+			Scheme:   runtimeScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("should create a budget allowing one unavailable pod for several replicas", func() {
+		Expect(reconciler.reconcileDisruptionBudget(ctx, mcpServer)).To(Succeed())
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(reconciler.Get(ctx, key, pdb)).To(Succeed())
+		Expect(pdb.OwnerReferences).To(HaveLen(1))
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": "weather"}))
+		Expect(pdb.Spec.MinAvailable).To(BeNil())
+		Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt32(1)))
+
+		// Not observed by the disruption controller yet
+		Expect(disruptionAllowed()).To(BeNil())
+	})
+
+	It("should only create a default budget for several replicas or the HPA", func() {
+		mcpServer.Spec.Replicas = ptr(int32(1))
+		Expect(disruptionBudgetEnabled(mcpServer)).To(BeFalse())
+
+		mcpServer.Spec.HPA = &mcpv1.MCPServerHPA{Enabled: ptr(true)}
+		Expect(disruptionBudgetEnabled(mcpServer)).To(BeTrue())
+
+		mcpServer.Spec.Disruption = &mcpv1.DisruptionConfig{Enabled: ptr(false)}
+		Expect(disruptionBudgetEnabled(mcpServer)).To(BeFalse())
+
+		mcpServer.Spec.HPA = nil
+		mcpServer.Spec.Disruption = &mcpv1.DisruptionConfig{}
+		Expect(disruptionBudgetEnabled(mcpServer)).To(BeTrue())
+	})
+
+	It("should report when the budget blocks evictions", func() {
+		minAvailable := intstr.FromString("100%")
+		mcpServer.Spec.Disruption = &mcpv1.DisruptionConfig{MinAvailable: &minAvailable}
+		Expect(reconciler.reconcileDisruptionBudget(ctx, mcpServer)).To(Succeed())
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(reconciler.Get(ctx, key, pdb)).To(Succeed())
+		Expect(*pdb.Spec.MinAvailable).To(Equal(minAvailable))
+		Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+
+		pdb.Status = policyv1.PodDisruptionBudgetStatus{
+			ObservedGeneration: pdb.Generation + 1,
+			CurrentHealthy:     3,
+			DesiredHealthy:     3,
+			ExpectedPods:       3,
+		}
+		pdb.Generation = pdb.Status.ObservedGeneration
+		Expect(reconciler.Status().Update(ctx, pdb)).To(Succeed())
+
+		Expect(reconciler.reconcileDisruptionBudget(ctx, mcpServer)).To(Succeed())
+		condition := disruptionAllowed()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("EvictionsBlocked"))
+		Expect(condition.Message).To(ContainSubstring("3 of 3 pods healthy, 3 must stay healthy"))
+
+		stored := &mcpv1.MCPServer{}
+		Expect(reconciler.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Status.Conditions).To(ContainElement(HaveField("Type", mcpv1.MCPServerConditionDisruptionAllowed)))
+
+		By("Dropping to a single replica")
+		mcpServer.Spec.Replicas = ptr(int32(1))
+		mcpServer.Spec.Disruption = nil
+		Expect(reconciler.reconcileDisruptionBudget(ctx, mcpServer)).To(Succeed())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, key, &policyv1.PodDisruptionBudget{}))).To(BeTrue())
+		Expect(disruptionAllowed()).To(BeNil())
+	})
+
+	It("should not create a budget when another one selects the server pods", func() {
+		Expect(reconciler.Create(ctx, &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather-pdb", Namespace: "default"},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+					"app": "weather", "app.kubernetes.io/name": "mcpserver",
+				}},
+			},
+		})).To(Succeed())
+
+		Expect(reconciler.reconcileDisruptionBudget(ctx, mcpServer)).To(Succeed())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, key, &policyv1.PodDisruptionBudget{}))).To(BeTrue())
+		Expect(reconciler.Recorder.(*record.FakeRecorder).Events).To(Receive(ContainSubstring("ForeignDisruptionBudget")))
+	})
+})
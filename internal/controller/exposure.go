@@ -0,0 +1,400 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// sseExposureTimeout is the request timeout of exposed SSE servers, whose
+	// event streams stay open for the whole session
+	sseExposureTimeout = time.Hour
+
+	// streamableHTTPExposureTimeout is the request timeout of exposed
+	// Streamable HTTP servers, long enough for slow tool calls streamed as events
+	streamableHTTPExposureTimeout = 5 * time.Minute
+
+	// Annotations of ingress-nginx set on generated Ingresses, so event
+	// streams are passed through as they are written and not timed out
+	nginxProxyReadTimeoutAnnotation = "nginx.ingress.kubernetes.io/proxy-read-timeout"
+	nginxProxySendTimeoutAnnotation = "nginx.ingress.kubernetes.io/proxy-send-timeout"
+	nginxProxyBufferingAnnotation   = "nginx.ingress.kubernetes.io/proxy-buffering"
+)
+
+// httpRouteGVK is the Gateway API HTTPRoute kind. Gateway API types are
+// handled as unstructured objects, as the CRDs are not installed everywhere.
+var httpRouteGVK = schema.GroupVersionKind{
+	Group:   "gateway.networking.k8s.io",
+	Version: "v1",
+	Kind:    "HTTPRoute",
+}
+
+// newHTTPRoute returns an empty HTTPRoute object
+func newHTTPRoute(name, namespace string) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	route.SetName(name)
+	route.SetNamespace(namespace)
+	return route
+}
+
+// exposureType returns the kind of resource the MCPServer is exposed with
+func exposureType(exposure *mcpv1.ExposureConfig) mcpv1.ExposureType {
+	if exposure.Type == "" {
+		return mcpv1.ExposureTypeHTTPRoute
+	}
+	return exposure.Type
+}
+
+// exposurePath returns the path prefix routed to the server
+func exposurePath(exposure *mcpv1.ExposureConfig) string {
+	if exposure.Path == "" {
+		return "/"
+	}
+	return exposure.Path
+}
+
+// exposureTimeout returns the request timeout of the exposed server:
+// the configured one, or the one suited to its transport
+func exposureTimeout(mcpServer *mcpv1.MCPServer) time.Duration {
+	if timeout := mcpServer.Spec.Exposure.Timeout; timeout != nil {
+		return timeout.Duration
+	}
+	if transport.IsSSEActive(mcpServer) {
+		return sseExposureTimeout
+	}
+	return streamableHTTPExposureTimeout
+}
+
+// exposureBackend returns the Service and port the exposed traffic is sent
+// to: the session router when enabled, the server Service otherwise
+func exposureBackend(mcpServer *mcpv1.MCPServer) (string, int32) {
+	if sessionRoutingEnabled(mcpServer) {
+		return sessionRouterName(mcpServer), transport.GetServicePort(mcpServer)
+	}
+	return mcpServer.Name, transport.GetServicePort(mcpServer)
+}
+
+// externalEndpoint returns the URL clients outside the cluster reach the server on
+func externalEndpoint(mcpServer *mcpv1.MCPServer) string {
+	exposure := mcpServer.Spec.Exposure
+	scheme := "http"
+	if exposure.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, exposure.Host, transport.GetHTTPPath(mcpServer))
+}
+
+// exposureLabels are the labels set on the HTTPRoute or Ingress of an MCPServer
+func exposureLabels(mcpServer *mcpv1.MCPServer) map[string]string {
+	return map[string]string{
+		"app":                          mcpServer.Name,
+		"app.kubernetes.io/name":       "mcpserver",
+		"app.kubernetes.io/instance":   mcpServer.Name,
+		"app.kubernetes.io/component":  "mcp-server",
+		"app.kubernetes.io/managed-by": "mcp-operator",
+	}
+}
+
+// reconcileExposure creates the HTTPRoute or Ingress of spec.exposure, removes
+// the ones no longer wanted, and reports the external endpoint in the status
+func (r *MCPServerReconciler) reconcileExposure(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	log := logf.FromContext(ctx)
+	exposure := mcpServer.Spec.Exposure
+	previousStatus := mcpServer.Status.DeepCopy()
+
+	wantIngress := exposure != nil && exposureType(exposure) == mcpv1.ExposureTypeIngress
+	wantHTTPRoute := exposure != nil && exposureType(exposure) == mcpv1.ExposureTypeHTTPRoute
+
+	if !wantIngress {
+		if err := r.deleteIngress(ctx, mcpServer); err != nil {
+			return err
+		}
+	}
+	if !wantHTTPRoute {
+		if err := r.deleteHTTPRoute(ctx, mcpServer); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case exposure == nil:
+		mcpServer.Status.ExternalEndpoint = ""
+		r.removeCondition(mcpServer, mcpv1.MCPServerConditionExposed)
+	case wantIngress:
+		if err := r.reconcileIngress(ctx, mcpServer); err != nil {
+			return err
+		}
+		r.setExposedCondition(mcpServer, corev1.ConditionTrue, "IngressReconciled",
+			fmt.Sprintf("Ingress %s routes %s to the server", mcpServer.Name, exposure.Host))
+		mcpServer.Status.ExternalEndpoint = externalEndpoint(mcpServer)
+	case wantHTTPRoute:
+		created, err := r.reconcileHTTPRoute(ctx, mcpServer)
+		if err != nil {
+			return err
+		}
+		if created {
+			r.setExposedCondition(mcpServer, corev1.ConditionTrue, "HTTPRouteReconciled",
+				fmt.Sprintf("HTTPRoute %s routes %s to the server", mcpServer.Name, exposure.Host))
+			mcpServer.Status.ExternalEndpoint = externalEndpoint(mcpServer)
+		} else {
+			log.Info("Gateway API is not installed, not exposing the server")
+			r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "GatewayAPINotInstalled",
+				"spec.exposure asks for an HTTPRoute, but the Gateway API CRDs are not installed")
+			r.setExposedCondition(mcpServer, corev1.ConditionFalse, "GatewayAPINotInstalled",
+				"The HTTPRoute CRD of the Gateway API is not installed in the cluster")
+			mcpServer.Status.ExternalEndpoint = ""
+		}
+	}
+
+	if reflect.DeepEqual(previousStatus, &mcpServer.Status) {
+		return nil
+	}
+	return r.updateStatus(ctx, mcpServer)
+}
+
+// reconcileIngress creates or updates the Ingress of the MCPServer
+func (r *MCPServerReconciler) reconcileIngress(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+	}
+	desired := buildIngress(mcpServer)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, ingress, func() error {
+			if err := controllerutil.SetControllerReference(mcpServer, ingress, r.Scheme); err != nil {
+				return err
+			}
+			ingress.Labels = desired.Labels
+			ingress.Annotations = desired.Annotations
+			ingress.Spec = desired.Spec
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile Ingress: %w", err)
+		}
+		return nil
+	})
+}
+
+// buildIngress builds the Ingress of the MCPServer
+func buildIngress(mcpServer *mcpv1.MCPServer) *networkingv1.Ingress {
+	exposure := mcpServer.Spec.Exposure
+	serviceName, servicePort := exposureBackend(mcpServer)
+	timeout := strconv.Itoa(int(exposureTimeout(mcpServer).Seconds()))
+
+	annotations := map[string]string{
+		nginxProxyReadTimeoutAnnotation: timeout,
+		nginxProxySendTimeoutAnnotation: timeout,
+		nginxProxyBufferingAnnotation:   "off",
+	}
+	maps.Copy(annotations, exposure.Annotations)
+
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        mcpServer.Name,
+			Namespace:   mcpServer.Namespace,
+			Labels:      exposureLabels(mcpServer),
+			Annotations: annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: exposure.IngressClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: exposure.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     exposurePath(exposure),
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: serviceName,
+									Port: networkingv1.ServiceBackendPort{Number: servicePort},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	if exposure.TLS != nil {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{
+			Hosts:      []string{exposure.Host},
+			SecretName: exposure.TLS.SecretName,
+		}}
+	}
+
+	return ingress
+}
+
+// reconcileHTTPRoute creates or updates the HTTPRoute of the MCPServer. It
+// reports false when the Gateway API CRDs are not installed.
+func (r *MCPServerReconciler) reconcileHTTPRoute(ctx context.Context, mcpServer *mcpv1.MCPServer) (bool, error) {
+	route := newHTTPRoute(mcpServer.Name, mcpServer.Namespace)
+	desired := buildHTTPRoute(mcpServer)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
+			if err := controllerutil.SetControllerReference(mcpServer, route, r.Scheme); err != nil {
+				return err
+			}
+			route.SetLabels(desired.GetLabels())
+			route.SetAnnotations(desired.GetAnnotations())
+			route.Object["spec"] = desired.Object["spec"]
+			return nil
+		})
+		return err
+	})
+	if meta.IsNoMatchError(err) || isNoMatchError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to reconcile HTTPRoute: %w", err)
+	}
+	return true, nil
+}
+
+// buildHTTPRoute builds the HTTPRoute of the MCPServer
+func buildHTTPRoute(mcpServer *mcpv1.MCPServer) *unstructured.Unstructured {
+	exposure := mcpServer.Spec.Exposure
+	serviceName, servicePort := exposureBackend(mcpServer)
+
+	parentRef := map[string]interface{}{}
+	if gateway := exposure.Gateway; gateway != nil {
+		parentRef["name"] = gateway.Name
+		if gateway.Namespace != "" {
+			parentRef["namespace"] = gateway.Namespace
+		}
+		if gateway.SectionName != "" {
+			parentRef["sectionName"] = gateway.SectionName
+		}
+	}
+
+	route := newHTTPRoute(mcpServer.Name, mcpServer.Namespace)
+	route.SetLabels(exposureLabels(mcpServer))
+	if len(exposure.Annotations) > 0 {
+		route.SetAnnotations(maps.Clone(exposure.Annotations))
+	}
+	route.Object["spec"] = map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"hostnames":  []interface{}{exposure.Host},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": []interface{}{
+					map[string]interface{}{
+						"path": map[string]interface{}{
+							"type":  "PathPrefix",
+							"value": exposurePath(exposure),
+						},
+					},
+				},
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": serviceName,
+						"port": int64(servicePort),
+					},
+				},
+				"timeouts": map[string]interface{}{
+					"request": fmt.Sprintf("%ds", int64(exposureTimeout(mcpServer).Seconds())),
+				},
+			},
+		},
+	}
+	return route
+}
+
+// deleteIngress removes the Ingress of the MCPServer, if any
+func (r *MCPServerReconciler) deleteIngress(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+	}
+	return client.IgnoreNotFound(r.deleteIfOwned(ctx, mcpServer, ingress))
+}
+
+// deleteHTTPRoute removes the HTTPRoute of the MCPServer, if any
+func (r *MCPServerReconciler) deleteHTTPRoute(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	err := r.deleteIfOwned(ctx, mcpServer, newHTTPRoute(mcpServer.Name, mcpServer.Namespace))
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) || isNoMatchError(err) {
+		return nil
+	}
+	return err
+}
+
+// deleteIfOwned deletes an object the MCPServer controls. Objects of the same
+// name created by someone else are left alone.
+func (r *MCPServerReconciler) deleteIfOwned(ctx context.Context, mcpServer *mcpv1.MCPServer, object client.Object) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(object), object); err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(object, mcpServer) {
+		return nil
+	}
+	return r.Delete(ctx, object)
+}
+
+// setExposedCondition sets the Exposed condition
+func (r *MCPServerReconciler) setExposedCondition(
+	mcpServer *mcpv1.MCPServer,
+	status corev1.ConditionStatus,
+	reason, message string,
+) {
+	r.setCondition(mcpServer, mcpv1.MCPServerCondition{
+		Type:               mcpv1.MCPServerConditionExposed,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// removeCondition removes a condition from the MCPServer status
+func (r *MCPServerReconciler) removeCondition(mcpServer *mcpv1.MCPServer, conditionType mcpv1.MCPServerConditionType) {
+	conditions := mcpServer.Status.Conditions[:0]
+	for _, condition := range mcpServer.Status.Conditions {
+		if condition.Type != conditionType {
+			conditions = append(conditions, condition)
+		}
+	}
+	mcpServer.Status.Conditions = conditions
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+var _ = Describe("Exposure", func() {
+	var (
+		ctx        context.Context
+		reconciler *MCPServerReconciler
+		mcpServer  *mcpv1.MCPServer
+	)
+
+	key := types.NamespacedName{Name: "weather", Namespace: "default"}
+
+	// newReconciler returns a reconciler whose client knows the HTTPRoute
+	// kind only when the Gateway API is installed
+	newReconciler := func(gatewayAPI bool) *MCPServerReconciler {
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		builder := fake.NewClientBuilder().
+			WithScheme(runtimeScheme).
+			WithObjects(mcpServer).
+			WithStatusSubresource(mcpServer)
+		if !gatewayAPI {
+			noMatch := &meta.NoKindMatchError{GroupKind: httpRouteGVK.GroupKind(), SearchedVersions: []string{"v1"}}
+			builder = builder.WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if _, ok := obj.(*unstructured.Unstructured); ok {
+						return noMatch
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			})
+		}
+
+		return &MCPServerReconciler{
+			Client:   builder.Build(),
+			Scheme:   runtimeScheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "weather-uid"},
+			Spec: mcpv1.MCPServerSpec{
+				Image: "weather-server:latest",
+				Transport: &mcpv1.MCPServerTransport{
+					Type:     mcpv1.MCPTransportHTTP,
+					Protocol: mcpv1.MCPProtocolSSE,
+					Config: &mcpv1.MCPTransportConfigDetails{
+						HTTP: &mcpv1.MCPHTTPTransportConfig{Port: 3001, Path: "/sse"},
+					},
+				},
+				Exposure: &mcpv1.ExposureConfig{
+					Type:             mcpv1.ExposureTypeIngress,
+					Host:             "weather.example.com",
+					IngressClassName: ptr("nginx"),
+					TLS:              &mcpv1.ExposureTLSConfig{SecretName: "weather-tls"},
+					Annotations:      map[string]string{nginxProxySendTimeoutAnnotation: "60"},
+				},
+			},
+		}
+		reconciler = newReconciler(true)
+	})
+
+	It("should create an Ingress suited to the SSE transport", func() {
+		Expect(reconciler.reconcileExposure(ctx, mcpServer)).To(Succeed())
+
+		ingress := &networkingv1.Ingress{}
+		Expect(reconciler.Get(ctx, key, ingress)).To(Succeed())
+		Expect(*ingress.Spec.IngressClassName).To(Equal("nginx"))
+		Expect(ingress.Annotations).To(HaveKeyWithValue(nginxProxyReadTimeoutAnnotation, "3600"))
+		Expect(ingress.Annotations).To(HaveKeyWithValue(nginxProxyBufferingAnnotation, "off"))
+		Expect(ingress.Annotations).To(HaveKeyWithValue(nginxProxySendTimeoutAnnotation, "60"))
+		Expect(ingress.OwnerReferences).To(HaveLen(1))
+
+		rule := ingress.Spec.Rules[0]
+		Expect(rule.Host).To(Equal("weather.example.com"))
+		path := rule.HTTP.Paths[0]
+		Expect(path.Path).To(Equal("/"))
+		Expect(*path.PathType).To(Equal(networkingv1.PathTypePrefix))
+		Expect(path.Backend.Service.Name).To(Equal("weather"))
+		Expect(path.Backend.Service.Port.Number).To(Equal(int32(3001)))
+		Expect(ingress.Spec.TLS).To(ConsistOf(networkingv1.IngressTLS{
+			Hosts: []string{"weather.example.com"}, SecretName: "weather-tls",
+		}))
+
+		Expect(mcpServer.Status.ExternalEndpoint).To(Equal("https://weather.example.com/sse"))
+		stored := &mcpv1.MCPServer{}
+		Expect(reconciler.Get(ctx, key, stored)).To(Succeed())
+		Expect(stored.Status.ExternalEndpoint).To(Equal("https://weather.example.com/sse"))
+	})
+
+	It("should replace the Ingress with an HTTPRoute and remove it when exposure is dropped", func() {
+		Expect(reconciler.reconcileExposure(ctx, mcpServer)).To(Succeed())
+
+		mcpServer.Spec.Transport.Protocol = mcpv1.MCPProtocolStreamableHTTP
+		mcpServer.Spec.Transport.Config.HTTP.Path = "/mcp"
+		mcpServer.Spec.Transport.Config.HTTP.SessionRouting = &mcpv1.SessionRoutingConfig{Enabled: true}
+		mcpServer.Spec.Exposure = &mcpv1.ExposureConfig{
+			Type:    mcpv1.ExposureTypeHTTPRoute,
+			Host:    "weather.example.com",
+			Path:    "/weather",
+			Gateway: &mcpv1.ExposureGatewayRef{Name: "public", Namespace: "gateways", SectionName: "https"},
+			TLS:     &mcpv1.ExposureTLSConfig{},
+		}
+		Expect(reconciler.reconcileExposure(ctx, mcpServer)).To(Succeed())
+
+		Expect(errors.IsNotFound(reconciler.Get(ctx, key, &networkingv1.Ingress{}))).To(BeTrue())
+
+		route := newHTTPRoute(key.Name, key.Namespace)
+		Expect(reconciler.Get(ctx, key, route)).To(Succeed())
+		Expect(route.GetOwnerReferences()).To(HaveLen(1))
+
+		parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+		Expect(parentRefs).To(ConsistOf(map[string]interface{}{
+			"name": "public", "namespace": "gateways", "sectionName": "https",
+		}))
+		hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+		Expect(hostnames).To(Equal([]string{"weather.example.com"}))
+
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		Expect(rules).To(HaveLen(1))
+		rule := rules[0].(map[string]interface{})
+		timeout, _, _ := unstructured.NestedString(rule, "timeouts", "request")
+		Expect(timeout).To(Equal("300s"))
+		backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+		Expect(backendRefs).To(ConsistOf(map[string]interface{}{"name": "weather-router", "port": int64(3001)}))
+		matches, _, _ := unstructured.NestedSlice(rule, "matches")
+		path, _, _ := unstructured.NestedString(matches[0].(map[string]interface{}), "path", "value")
+		Expect(path).To(Equal("/weather"))
+
+		Expect(mcpServer.Status.ExternalEndpoint).To(Equal("https://weather.example.com/mcp"))
+
+		By("Dropping the exposure")
+		mcpServer.Spec.Exposure = nil
+		Expect(reconciler.reconcileExposure(ctx, mcpServer)).To(Succeed())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, key, newHTTPRoute(key.Name, key.Namespace)))).To(BeTrue())
+		Expect(mcpServer.Status.ExternalEndpoint).To(BeEmpty())
+		for _, condition := range mcpServer.Status.Conditions {
+			Expect(condition.Type).NotTo(Equal(mcpv1.MCPServerConditionExposed))
+		}
+	})
+
+	It("should use a configured timeout", func() {
+		mcpServer.Spec.Exposure.Timeout = &metav1.Duration{Duration: 90 * time.Second}
+
+		ingress := buildIngress(mcpServer)
+		Expect(ingress.Annotations).To(HaveKeyWithValue(nginxProxyReadTimeoutAnnotation, "90"))
+	})
+
+	It("should report when the Gateway API is not installed", func() {
+		reconciler = newReconciler(false)
+		mcpServer.Spec.Exposure = &mcpv1.ExposureConfig{
+			Type:    mcpv1.ExposureTypeHTTPRoute,
+			Host:    "weather.example.com",
+			Gateway: &mcpv1.ExposureGatewayRef{Name: "public"},
+		}
+
+		Expect(reconciler.reconcileExposure(ctx, mcpServer)).To(Succeed())
+		Expect(mcpServer.Status.ExternalEndpoint).To(BeEmpty())
+
+		var exposed *mcpv1.MCPServerCondition
+		for i := range mcpServer.Status.Conditions {
+			if mcpServer.Status.Conditions[i].Type == mcpv1.MCPServerConditionExposed {
+				exposed = &mcpServer.Status.Conditions[i]
+			}
+		}
+		Expect(exposed).NotTo(BeNil())
+		Expect(exposed.Status).To(Equal(corev1.ConditionFalse))
+		Expect(exposed.Reason).To(Equal("GatewayAPINotInstalled"))
+	})
+})
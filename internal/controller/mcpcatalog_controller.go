@@ -0,0 +1,630 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/mcp"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+const (
+	// defaultCatalogRefreshInterval is used when spec.refreshInterval is not set
+	defaultCatalogRefreshInterval = 5 * time.Minute
+
+	// minCatalogRefreshInterval keeps catalogs from polling servers too often
+	minCatalogRefreshInterval = 30 * time.Second
+
+	// catalogListTimeout bounds the list calls made to a single server
+	catalogListTimeout = 10 * time.Second
+
+	// maxCatalogDescriptionLength keeps long descriptions from bloating the status
+	maxCatalogDescriptionLength = 256
+
+	// maxCatalogStatusTools is the largest number of tools kept in the status of a
+	// catalog, across all its servers. Servers whose tools do not fit have their full
+	// listing written to a ConfigMap so the cluster-scoped catalog object stays small.
+	maxCatalogStatusTools = 100
+)
+
+// catalogToolsConfigMapName returns the name of the ConfigMap holding the full
+// tool listing of the named server
+func catalogToolsConfigMapName(serverName string) string {
+	return serverName + "-catalog-tools"
+}
+
+// catalogListing is a cached listing of one server, shared by all catalogs
+type catalogListing struct {
+	// lastValidated is the validation the listing was taken after
+	lastValidated time.Time
+	server        mcpv1.MCPCatalogServer
+	// toolsConfigMap is set once a tools ConfigMap was written for the server, and
+	// toolsWritten once it holds this listing
+	toolsConfigMap bool
+	toolsWritten   bool
+	// catalogs are the keys of the catalogs selecting the server. The listing
+	// is evicted once no catalog selects it.
+	catalogs map[string]bool
+}
+
+// MCPCatalogReconciler reconciles a MCPCatalog object
+type MCPCatalogReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// listings caches server listings by namespace/name, so several catalogs
+	// selecting the same server do not list it more than once per interval
+	listings   map[string]catalogListing
+	listingsMu sync.Mutex
+}
+
+// +kubebuilder:rbac:groups=mcp.mcp-operator.io,resources=mcpcatalogs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mcp.mcp-operator.io,resources=mcpcatalogs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mcp.mcp-operator.io,resources=mcpcatalogs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile rebuilds the catalog from the validated MCPServers it selects
+func (r *MCPCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	catalog := &mcpv1.MCPCatalog{}
+	if err := r.Get(ctx, req.NamespacedName, catalog); err != nil {
+		if errors.IsNotFound(err) {
+			r.deleteToolsConfigMaps(ctx, r.releaseListings(req.String(), nil))
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get MCPCatalog")
+		return ctrl.Result{}, err
+	}
+
+	refreshInterval := catalogRefreshInterval(catalog)
+
+	servers, err := r.selectServers(ctx, catalog)
+	if err != nil {
+		log.Error(err, "Failed to select MCPServers")
+		meta.SetStatusCondition(&catalog.Status.Conditions, metav1.Condition{
+			Type:               mcpv1.MCPCatalogConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "SelectionFailed",
+			Message:            err.Error(),
+			ObservedGeneration: catalog.Generation,
+		})
+		if statusErr := r.Status().Update(ctx, catalog); statusErr != nil {
+			log.Error(statusErr, "Failed to update MCPCatalog status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	entries := make([]mcpv1.MCPCatalogServer, 0, len(servers))
+	selected := make(map[string]bool, len(servers))
+	var toolCount int32
+	toolBudget := maxCatalogStatusTools
+	for i := range servers {
+		entry := r.catalogEntry(ctx, req.String(), &servers[i], refreshInterval)
+		toolCount += entry.ToolCount
+		if len(entry.Tools) > toolBudget {
+			entry = r.truncateTools(ctx, &servers[i], entry, toolBudget)
+		}
+		toolBudget -= len(entry.Tools)
+		entries = append(entries, entry)
+		selected[listingKey(&servers[i])] = true
+	}
+	r.deleteToolsConfigMaps(ctx, r.releaseListings(req.String(), selected))
+
+	now := metav1.Now()
+	catalog.Status.Servers = entries
+	catalog.Status.ServerCount = int32(len(entries))
+	catalog.Status.ToolCount = toolCount
+	catalog.Status.LastRefreshTime = &now
+	catalog.Status.ObservedGeneration = catalog.Generation
+	meta.SetStatusCondition(&catalog.Status.Conditions, metav1.Condition{
+		Type:               mcpv1.MCPCatalogConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CatalogRefreshed",
+		Message:            fmt.Sprintf("Catalog lists %d servers with %d tools", len(entries), toolCount),
+		ObservedGeneration: catalog.Generation,
+	})
+
+	if err := r.Status().Update(ctx, catalog); err != nil {
+		if errors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "Failed to update MCPCatalog status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: refreshInterval}, nil
+}
+
+// catalogRefreshInterval returns the effective refresh interval of a catalog
+func catalogRefreshInterval(catalog *mcpv1.MCPCatalog) time.Duration {
+	if catalog.Spec.RefreshInterval == nil {
+		return defaultCatalogRefreshInterval
+	}
+	if catalog.Spec.RefreshInterval.Duration < minCatalogRefreshInterval {
+		return minCatalogRefreshInterval
+	}
+	return catalog.Spec.RefreshInterval.Duration
+}
+
+// selectServers returns the validated MCPServers matching the catalog selectors,
+// sorted by namespace and name
+func (r *MCPCatalogReconciler) selectServers(ctx context.Context, catalog *mcpv1.MCPCatalog) ([]mcpv1.MCPServer, error) {
+	listOpts := []client.ListOption{}
+	if catalog.Spec.ServerSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(catalog.Spec.ServerSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid serverSelector: %w", err)
+		}
+		listOpts = append(listOpts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	var namespaces map[string]bool
+	if catalog.Spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(catalog.Spec.NamespaceSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+		namespaces, err = r.selectNamespaces(ctx, selector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	serverList := &mcpv1.MCPServerList{}
+	if err := r.List(ctx, serverList, listOpts...); err != nil {
+		return nil, fmt.Errorf("failed to list MCPServers: %w", err)
+	}
+
+	var servers []mcpv1.MCPServer
+	for _, server := range serverList.Items {
+		if namespaces != nil && !namespaces[server.Namespace] {
+			continue
+		}
+		if server.Status.Validation == nil || server.Status.Validation.State != mcpv1.ValidationStateValidated {
+			continue
+		}
+		servers = append(servers, server)
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].Namespace != servers[j].Namespace {
+			return servers[i].Namespace < servers[j].Namespace
+		}
+		return servers[i].Name < servers[j].Name
+	})
+	return servers, nil
+}
+
+// selectNamespaces returns the names of the namespaces matching a selector
+func (r *MCPCatalogReconciler) selectNamespaces(ctx context.Context, selector labels.Selector) (map[string]bool, error) {
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	namespaces := make(map[string]bool, len(namespaceList.Items))
+	for _, namespace := range namespaceList.Items {
+		namespaces[namespace.Name] = true
+	}
+	return namespaces, nil
+}
+
+// listingKey returns the key of a server in the listings cache
+func listingKey(mcpServer *mcpv1.MCPServer) string {
+	return mcpServer.Namespace + "/" + mcpServer.Name
+}
+
+// catalogEntry returns the catalog entry for a server, listing it again when the
+// cached listing predates the last validation or is older than the refresh interval.
+// The listing is recorded as selected by the catalog with the given key.
+func (r *MCPCatalogReconciler) catalogEntry(
+	ctx context.Context,
+	catalogKey string,
+	mcpServer *mcpv1.MCPServer,
+	refreshInterval time.Duration,
+) mcpv1.MCPCatalogServer {
+	key := listingKey(mcpServer)
+	var lastValidated time.Time
+	if mcpServer.Status.Validation.LastValidated != nil {
+		lastValidated = mcpServer.Status.Validation.LastValidated.Time
+	}
+
+	r.listingsMu.Lock()
+	cached, ok := r.listings[key]
+	if ok {
+		cached.catalogs[catalogKey] = true
+	}
+	r.listingsMu.Unlock()
+
+	if ok && cached.lastValidated.Equal(lastValidated) &&
+		cached.server.LastListedTime != nil &&
+		time.Since(cached.server.LastListedTime.Time) < refreshInterval {
+		return cached.server
+	}
+
+	entry := r.listServer(ctx, mcpServer)
+
+	r.listingsMu.Lock()
+	if r.listings == nil {
+		r.listings = make(map[string]catalogListing)
+	}
+	catalogs := map[string]bool{catalogKey: true}
+	var toolsConfigMap bool
+	if current, ok := r.listings[key]; ok {
+		for other := range current.catalogs {
+			catalogs[other] = true
+		}
+		toolsConfigMap = current.toolsConfigMap
+	}
+	r.listings[key] = catalogListing{
+		lastValidated:  lastValidated,
+		server:         entry,
+		toolsConfigMap: toolsConfigMap,
+		catalogs:       catalogs,
+	}
+	r.listingsMu.Unlock()
+
+	return entry
+}
+
+// releaseListings drops the catalog with the given key from the listings of the
+// servers it no longer selects, and evicts listings that no catalog selects.
+// Deleted servers drop out of every selection, and a nil selection releases
+// all listings of a deleted catalog.
+// It returns the tools ConfigMaps of the evicted listings, which are no longer needed.
+func (r *MCPCatalogReconciler) releaseListings(catalogKey string, selected map[string]bool) []types.NamespacedName {
+	r.listingsMu.Lock()
+	defer r.listingsMu.Unlock()
+
+	var evicted []types.NamespacedName
+	for key, listing := range r.listings {
+		if selected[key] {
+			continue
+		}
+		delete(listing.catalogs, catalogKey)
+		if len(listing.catalogs) == 0 {
+			delete(r.listings, key)
+			if listing.toolsConfigMap {
+				evicted = append(evicted, types.NamespacedName{
+					Name:      catalogToolsConfigMapName(listing.server.Name),
+					Namespace: listing.server.Namespace,
+				})
+			}
+		}
+	}
+	return evicted
+}
+
+// truncateTools keeps the first limit tools of a catalog entry and points the entry
+// at a ConfigMap holding the full tool listing. The ConfigMap is shared by all
+// catalogs selecting the server and is written once per listing.
+// When it cannot be written, the truncated entry is returned without it.
+func (r *MCPCatalogReconciler) truncateTools(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	entry mcpv1.MCPCatalogServer,
+	limit int,
+) mcpv1.MCPCatalogServer {
+	key := listingKey(mcpServer)
+	tools := entry.Tools
+	entry.Tools = tools[:limit:limit]
+
+	r.listingsMu.Lock()
+	listing, ok := r.listings[key]
+	written := ok && listing.toolsWritten
+	r.listingsMu.Unlock()
+
+	if !written {
+		if err := r.writeToolsConfigMap(ctx, mcpServer, tools); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to write catalog tools ConfigMap", "mcpserver", key)
+			return entry
+		}
+
+		r.listingsMu.Lock()
+		if listing, ok := r.listings[key]; ok {
+			listing.toolsConfigMap = true
+			listing.toolsWritten = true
+			r.listings[key] = listing
+		}
+		r.listingsMu.Unlock()
+	}
+
+	entry.ToolsConfigMapName = catalogToolsConfigMapName(mcpServer.Name)
+	return entry
+}
+
+// writeToolsConfigMap creates or updates the ConfigMap holding the full tool
+// listing of a server. It is owned by the MCPServer so it is removed with it.
+func (r *MCPCatalogReconciler) writeToolsConfigMap(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	tools []mcpv1.MCPCatalogTool,
+) error {
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      catalogToolsConfigMapName(mcpServer.Name),
+				Namespace: mcpServer.Namespace,
+			},
+		}
+
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+			if err := controllerutil.SetControllerReference(mcpServer, configMap, r.Scheme); err != nil {
+				return err
+			}
+
+			configMap.Labels = map[string]string{
+				"app":                          mcpServer.Name,
+				"app.kubernetes.io/name":       "mcpserver",
+				"app.kubernetes.io/instance":   mcpServer.Name,
+				"app.kubernetes.io/component":  "catalog-tools",
+				"app.kubernetes.io/managed-by": "mcp-operator",
+			}
+			configMap.Data = map[string]string{mcpv1.MCPCatalogToolsConfigMapKey: string(data)}
+			return nil
+		})
+		return err
+	})
+}
+
+// deleteToolsConfigMaps removes the catalog tools ConfigMaps of evicted listings.
+// Failures are only logged; the ConfigMaps are removed with their MCPServer anyway.
+func (r *MCPCatalogReconciler) deleteToolsConfigMaps(ctx context.Context, keys []types.NamespacedName) {
+	for _, key := range keys {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+		if err := r.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+			logf.FromContext(ctx).Error(err, "Failed to delete catalog tools ConfigMap", "configMap", key.String())
+		}
+	}
+}
+
+// listServer builds a catalog entry from the validation status and, where the
+// operator can reach the server with the Streamable HTTP client, from its list calls.
+// When the server cannot be listed, the names recorded during validation are used.
+func (r *MCPCatalogReconciler) listServer(ctx context.Context, mcpServer *mcpv1.MCPServer) mcpv1.MCPCatalogServer {
+	log := logf.FromContext(ctx).WithValues("mcpserver", mcpServer.Namespace+"/"+mcpServer.Name)
+	validation := mcpServer.Status.Validation
+
+	now := metav1.Now()
+	entry := mcpv1.MCPCatalogServer{
+		Name:            mcpServer.Name,
+		Namespace:       mcpServer.Namespace,
+		Endpoint:        validation.Endpoint,
+		Protocol:        validation.Protocol,
+		ProtocolVersion: validation.ProtocolVersion,
+		Capabilities:    validation.Capabilities,
+		LastListedTime:  &now,
+	}
+	if validation.ServerInfo != nil {
+		serverInfo := *validation.ServerInfo
+		entry.ServerInfo = &serverInfo
+	}
+
+	var err error
+	switch {
+	case validation.Endpoint == "":
+		err = fmt.Errorf("server has no validated endpoint")
+	case validation.Protocol != string(validator.TransportStreamableHTTP):
+		err = fmt.Errorf("listing is not supported over the %s transport", validation.Protocol)
+	case mcpServer.Spec.Validation != nil && mcpServer.Spec.Validation.Auth != nil:
+		err = fmt.Errorf("listing servers that require authentication is not supported")
+	default:
+		err = listCapabilities(ctx, validation.Endpoint, &entry)
+	}
+
+	if err != nil {
+		log.V(1).Info("Using validated inventory for catalog", "reason", err.Error())
+		entry.ListingError = err.Error()
+		applyInventoryNames(validation.Inventory, &entry)
+	}
+	entry.ToolCount = int32(len(entry.Tools))
+	return entry
+}
+
+// listCapabilities fills a catalog entry from the server's tools, resources and
+// prompts list calls
+func listCapabilities(ctx context.Context, endpoint string, entry *mcpv1.MCPCatalogServer) error {
+	ctx, cancel := context.WithTimeout(ctx, catalogListTimeout)
+	defer cancel()
+
+	mcpClient := mcp.NewClient(endpoint,
+		mcp.WithTimeout(catalogListTimeout),
+		mcp.WithClientInfo("mcp-operator-catalog", "1.0.0"),
+	)
+
+	initResult, err := mcpClient.Initialize(ctx)
+	if err != nil {
+		return fmt.Errorf("initialize failed: %w", err)
+	}
+	entry.ProtocolVersion = initResult.ProtocolVersion
+	entry.ServerInfo = &mcpv1.ValidationServerInfo{
+		Name:    initResult.ServerInfo.Name,
+		Version: initResult.ServerInfo.Version,
+	}
+
+	var tools []mcpv1.MCPCatalogTool
+	if initResult.Capabilities.Tools != nil {
+		result, err := mcpClient.ListTools(ctx)
+		if err != nil {
+			return fmt.Errorf("tools/list failed: %w", err)
+		}
+		for _, tool := range result.Tools {
+			tools = append(tools, mcpv1.MCPCatalogTool{
+				Name:        tool.Name,
+				Description: truncateDescription(tool.Description),
+			})
+		}
+	}
+
+	var resources []mcpv1.MCPCatalogResource
+	if initResult.Capabilities.Resources != nil {
+		result, err := mcpClient.ListResources(ctx)
+		if err != nil {
+			return fmt.Errorf("resources/list failed: %w", err)
+		}
+		for _, resource := range result.Resources {
+			resources = append(resources, mcpv1.MCPCatalogResource{
+				URI:         resource.URI,
+				Name:        resource.Name,
+				Description: truncateDescription(resource.Description),
+				MimeType:    resource.MimeType,
+			})
+		}
+	}
+
+	var prompts []mcpv1.MCPCatalogPrompt
+	if initResult.Capabilities.Prompts != nil {
+		result, err := mcpClient.ListPrompts(ctx)
+		if err != nil {
+			return fmt.Errorf("prompts/list failed: %w", err)
+		}
+		for _, prompt := range result.Prompts {
+			prompts = append(prompts, mcpv1.MCPCatalogPrompt{
+				Name:        prompt.Name,
+				Description: truncateDescription(prompt.Description),
+			})
+		}
+	}
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+
+	entry.Tools = tools
+	entry.Resources = resources
+	entry.Prompts = prompts
+	return nil
+}
+
+// applyInventoryNames fills a catalog entry from the names recorded during validation.
+// Inventories stored in a ConfigMap only contribute what is inline in the status.
+func applyInventoryNames(inventory *mcpv1.ValidationInventory, entry *mcpv1.MCPCatalogServer) {
+	if inventory == nil {
+		return
+	}
+	for _, name := range inventory.Tools {
+		entry.Tools = append(entry.Tools, mcpv1.MCPCatalogTool{Name: name})
+	}
+	for _, uri := range inventory.Resources {
+		entry.Resources = append(entry.Resources, mcpv1.MCPCatalogResource{URI: uri})
+	}
+	for _, name := range inventory.Prompts {
+		entry.Prompts = append(entry.Prompts, mcpv1.MCPCatalogPrompt{Name: name})
+	}
+}
+
+// truncateDescription shortens a description to maxCatalogDescriptionLength runes
+func truncateDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= maxCatalogDescriptionLength {
+		return description
+	}
+	return string(runes[:maxCatalogDescriptionLength-3]) + "..."
+}
+
+// mapServerToCatalogs enqueues every catalog when a server changes, since each
+// catalog may select it
+func (r *MCPCatalogReconciler) mapServerToCatalogs(ctx context.Context, _ client.Object) []reconcile.Request {
+	catalogList := &mcpv1.MCPCatalogList{}
+	if err := r.List(ctx, catalogList); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list MCPCatalogs")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(catalogList.Items))
+	for _, catalog := range catalogList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&catalog)})
+	}
+	return requests
+}
+
+// validatedServerChangedPredicate passes server updates that can change which
+// servers are selected and how they are reached: label changes and validation
+// results. Other status updates are ignored.
+func validatedServerChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldServer, ok := e.ObjectOld.(*mcpv1.MCPServer)
+			if !ok {
+				return false
+			}
+			newServer, ok := e.ObjectNew.(*mcpv1.MCPServer)
+			if !ok {
+				return false
+			}
+
+			if !reflect.DeepEqual(oldServer.Labels, newServer.Labels) {
+				return true
+			}
+			oldValidation, newValidation := oldServer.Status.Validation, newServer.Status.Validation
+			if (oldValidation == nil) != (newValidation == nil) {
+				return true
+			}
+			if oldValidation == nil {
+				return false
+			}
+			return oldValidation.State != newValidation.State ||
+				!oldValidation.LastValidated.Equal(newValidation.LastValidated)
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MCPCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mcpv1.MCPCatalog{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&mcpv1.MCPServer{},
+			handler.EnqueueRequestsFromMapFunc(r.mapServerToCatalogs),
+			builder.WithPredicates(validatedServerChangedPredicate()),
+		).
+		Named("mcpcatalog").
+		Complete(r)
+}
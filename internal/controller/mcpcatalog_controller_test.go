@@ -0,0 +1,328 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/mcp"
+)
+
+// newCatalogMCPServer starts a Streamable HTTP MCP server offering two tools on
+// two pages and one prompt, and counts the tools/list calls for the first page
+func newCatalogMCPServer(toolsListCalls *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request mcp.JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(request.Method, "notifications/") {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		var result any
+		switch request.Method {
+		case "initialize":
+			result = map[string]any{
+				"protocolVersion": "2025-03-26",
+				"capabilities":    map[string]any{"tools": map[string]any{}, "prompts": map[string]any{}},
+				"serverInfo":      map[string]any{"name": "weather-server", "version": "2.0.0"},
+			}
+		case "tools/list":
+			if params, ok := request.Params.(map[string]any); ok && params["cursor"] == "page-2" {
+				result = map[string]any{"tools": []map[string]any{
+					{"name": "alerts", "description": "Weather alerts", "inputSchema": map[string]any{"type": "object"}},
+				}}
+				break
+			}
+			toolsListCalls.Add(1)
+			result = map[string]any{
+				"tools": []map[string]any{
+					{"name": "forecast", "description": strings.Repeat("d", 300), "inputSchema": map[string]any{"type": "object"}},
+				},
+				"nextCursor": "page-2",
+			}
+		case "prompts/list":
+			result = map[string]any{"prompts": []map[string]any{{"name": "daily-summary"}}}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+}
+
+var _ = Describe("MCPCatalog Controller", func() {
+	var (
+		ctx            context.Context
+		reconciler     *MCPCatalogReconciler
+		mcpHTTPServer  *httptest.Server
+		toolsListCalls atomic.Int32
+		lastValidated  metav1.Time
+	)
+
+	validatedServer := func(name, namespace string, labels map[string]string, validation *mcpv1.ValidationStatus) *mcpv1.MCPServer {
+		return &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec:       mcpv1.MCPServerSpec{Image: "test-server:latest"},
+			Status:     mcpv1.MCPServerStatus{Validation: validation},
+		}
+	}
+
+	reconcileCatalog := func(name string) *mcpv1.MCPCatalog {
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(defaultCatalogRefreshInterval))
+
+		catalog := &mcpv1.MCPCatalog{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: name}, catalog)).To(Succeed())
+		return catalog
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		toolsListCalls.Store(0)
+		mcpHTTPServer = newCatalogMCPServer(&toolsListCalls)
+		lastValidated = metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+
+		objects := []client.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "weather"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+			validatedServer("weather", "default", map[string]string{"catalog": "public"}, &mcpv1.ValidationStatus{
+				State:           mcpv1.ValidationStateValidated,
+				Protocol:        "streamable-http",
+				ProtocolVersion: "2025-03-26",
+				Endpoint:        mcpHTTPServer.URL + "/mcp",
+				Capabilities:    []string{"tools", "prompts"},
+				LastValidated:   &lastValidated,
+			}),
+			validatedServer("legacy", "other", map[string]string{"catalog": "public"}, &mcpv1.ValidationStatus{
+				State:           mcpv1.ValidationStateValidated,
+				Protocol:        "sse",
+				ProtocolVersion: "2024-11-05",
+				Endpoint:        "http://legacy.other.svc.cluster.local:8080/sse",
+				Capabilities:    []string{"tools"},
+				ServerInfo:      &mcpv1.ValidationServerInfo{Name: "legacy-server", Version: "0.1.0"},
+				Inventory:       &mcpv1.ValidationInventory{Tools: []string{"lookup"}, ToolCount: 1},
+				LastValidated:   &lastValidated,
+			}),
+			validatedServer("broken", "default", map[string]string{"catalog": "public"}, &mcpv1.ValidationStatus{
+				State: mcpv1.ValidationStateFailed,
+			}),
+			validatedServer("internal", "default", map[string]string{"catalog": "private"}, &mcpv1.ValidationStatus{
+				State:         mcpv1.ValidationStateValidated,
+				Protocol:      "sse",
+				Endpoint:      "http://internal.default.svc.cluster.local:8080/sse",
+				LastValidated: &lastValidated,
+			}),
+			&mcpv1.MCPCatalog{ObjectMeta: metav1.ObjectMeta{Name: "all"}},
+			&mcpv1.MCPCatalog{
+				ObjectMeta: metav1.ObjectMeta{Name: "public-weather"},
+				Spec: mcpv1.MCPCatalogSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "weather"}},
+					ServerSelector:    &metav1.LabelSelector{MatchLabels: map[string]string{"catalog": "public"}},
+				},
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		reconciler = &MCPCatalogReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(runtimeScheme).
+				WithObjects(objects...).
+				WithStatusSubresource(&mcpv1.MCPCatalog{}, &mcpv1.MCPServer{}).
+				Build(),
+			Scheme: runtimeScheme,
+		}
+	})
+
+	AfterEach(func() {
+		mcpHTTPServer.Close()
+	})
+
+	It("should aggregate validated servers with their listings", func() {
+		catalog := reconcileCatalog("all")
+
+		Expect(catalog.Status.ServerCount).To(Equal(int32(3)))
+		Expect(catalog.Status.ToolCount).To(Equal(int32(3)))
+		Expect(catalog.Status.LastRefreshTime).NotTo(BeNil())
+		Expect(meta.IsStatusConditionTrue(catalog.Status.Conditions, mcpv1.MCPCatalogConditionReady)).To(BeTrue())
+
+		servers := catalog.Status.Servers
+		Expect(servers).To(HaveLen(3))
+		Expect(servers[0].Name).To(Equal("internal"))
+		Expect(servers[1].Name).To(Equal("weather"))
+		Expect(servers[2].Name).To(Equal("legacy"))
+
+		weather := servers[1]
+		Expect(weather.ListingError).To(BeEmpty())
+		Expect(weather.ServerInfo).To(Equal(&mcpv1.ValidationServerInfo{Name: "weather-server", Version: "2.0.0"}))
+		Expect(weather.Tools).To(HaveLen(2))
+		Expect(weather.Tools[0].Name).To(Equal("alerts"))
+		Expect(weather.Tools[1].Name).To(Equal("forecast"))
+		Expect(weather.Tools[1].Description).To(HaveLen(maxCatalogDescriptionLength))
+		Expect(weather.Tools[1].Description).To(HaveSuffix("..."))
+		Expect(weather.Prompts).To(ConsistOf(mcpv1.MCPCatalogPrompt{Name: "daily-summary"}))
+		Expect(weather.Resources).To(BeEmpty())
+	})
+
+	It("should fall back to the validated inventory for SSE servers", func() {
+		catalog := reconcileCatalog("all")
+
+		legacy := catalog.Status.Servers[2]
+		Expect(legacy.ListingError).To(ContainSubstring("sse transport"))
+		Expect(legacy.ProtocolVersion).To(Equal("2024-11-05"))
+		Expect(legacy.ServerInfo.Name).To(Equal("legacy-server"))
+		Expect(legacy.Tools).To(ConsistOf(mcpv1.MCPCatalogTool{Name: "lookup"}))
+	})
+
+	It("should apply the namespace and server selectors", func() {
+		catalog := reconcileCatalog("public-weather")
+
+		Expect(catalog.Status.Servers).To(HaveLen(1))
+		Expect(catalog.Status.Servers[0].Name).To(Equal("weather"))
+	})
+
+	It("should reuse listings until the server is re-validated", func() {
+		reconcileCatalog("all")
+		reconcileCatalog("public-weather")
+		Expect(toolsListCalls.Load()).To(Equal(int32(1)))
+
+		server := &mcpv1.MCPServer{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "weather", Namespace: "default"}, server)).To(Succeed())
+		revalidated := metav1.NewTime(lastValidated.Add(time.Minute))
+		server.Status.Validation.LastValidated = &revalidated
+		Expect(reconciler.Status().Update(ctx, server)).To(Succeed())
+
+		reconcileCatalog("all")
+		Expect(toolsListCalls.Load()).To(Equal(int32(2)))
+	})
+
+	It("should evict listings no catalog selects", func() {
+		reconcileCatalog("all")
+		reconcileCatalog("public-weather")
+		Expect(reconciler.listings).To(HaveKey("default/weather"))
+
+		By("Keeping listings another catalog still selects")
+		Expect(reconciler.Delete(ctx, &mcpv1.MCPCatalog{ObjectMeta: metav1.ObjectMeta{Name: "all"}})).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "all"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.listings).To(HaveLen(1))
+		Expect(reconciler.listings).To(HaveKey("default/weather"))
+
+		By("Evicting the listing of a deleted server")
+		Expect(reconciler.Delete(ctx, &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default"},
+		})).To(Succeed())
+		catalog := reconcileCatalog("public-weather")
+		Expect(catalog.Status.Servers).To(BeEmpty())
+		Expect(reconciler.listings).To(BeEmpty())
+	})
+
+	It("should keep large tool listings out of the status", func() {
+		toolNames := make([]string, 150)
+		for i := range toolNames {
+			toolNames[i] = fmt.Sprintf("tool-%03d", i)
+		}
+		large := validatedServer("zoo", "other", nil, nil)
+		Expect(reconciler.Create(ctx, large)).To(Succeed())
+		large.Status.Validation = &mcpv1.ValidationStatus{
+			State:         mcpv1.ValidationStateValidated,
+			Protocol:      "sse",
+			Endpoint:      "http://zoo.other.svc.cluster.local:8080/sse",
+			Inventory:     &mcpv1.ValidationInventory{Tools: toolNames, ToolCount: 150},
+			LastValidated: &lastValidated,
+		}
+		Expect(reconciler.Status().Update(ctx, large)).To(Succeed())
+
+		catalog := reconcileCatalog("all")
+		Expect(catalog.Status.ToolCount).To(Equal(int32(153)))
+
+		zoo := catalog.Status.Servers[3]
+		Expect(zoo.Name).To(Equal("zoo"))
+		Expect(zoo.ToolCount).To(Equal(int32(150)))
+		Expect(zoo.Tools).To(HaveLen(maxCatalogStatusTools - 3))
+		Expect(zoo.ToolsConfigMapName).To(Equal("zoo-catalog-tools"))
+
+		configMap := &corev1.ConfigMap{}
+		configMapKey := types.NamespacedName{Name: "zoo-catalog-tools", Namespace: "other"}
+		Expect(reconciler.Get(ctx, configMapKey, configMap)).To(Succeed())
+		var tools []mcpv1.MCPCatalogTool
+		Expect(json.Unmarshal([]byte(configMap.Data[mcpv1.MCPCatalogToolsConfigMapKey]), &tools)).To(Succeed())
+		Expect(tools).To(HaveLen(150))
+
+		By("Deleting the ConfigMap once no catalog selects the server")
+		Expect(reconciler.Delete(ctx, &mcpv1.MCPCatalog{ObjectMeta: metav1.ObjectMeta{Name: "all"}})).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "all"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, configMapKey, configMap))).To(BeTrue())
+	})
+
+	It("should report invalid selectors on the Ready condition", func() {
+		catalog := &mcpv1.MCPCatalog{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "all"}, catalog)).To(Succeed())
+		catalog.Spec.ServerSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "catalog", Operator: "Bogus"}},
+		}
+		Expect(reconciler.Update(ctx, catalog)).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "all"}})
+		Expect(err).To(HaveOccurred())
+
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "all"}, catalog)).To(Succeed())
+		condition := meta.FindStatusCondition(catalog.Status.Conditions, mcpv1.MCPCatalogConditionReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("SelectionFailed"))
+	})
+
+	It("should clamp the refresh interval", func() {
+		catalog := &mcpv1.MCPCatalog{}
+		Expect(catalogRefreshInterval(catalog)).To(Equal(defaultCatalogRefreshInterval))
+
+		catalog.Spec.RefreshInterval = &metav1.Duration{Duration: time.Second}
+		Expect(catalogRefreshInterval(catalog)).To(Equal(minCatalogRefreshInterval))
+
+		catalog.Spec.RefreshInterval = &metav1.Duration{Duration: time.Hour}
+		Expect(catalogRefreshInterval(catalog)).To(Equal(time.Hour))
+	})
+})
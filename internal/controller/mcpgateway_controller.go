@@ -0,0 +1,512 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+const (
+	// gatewayConfigKey is the ConfigMap key holding the gateway configuration
+	gatewayConfigKey = "gateway.json"
+
+	// gatewayConfigDir is where the gateway configuration is mounted
+	gatewayConfigDir = "/etc/mcp-gateway"
+
+	// gatewayConfigHashAnnotation rolls the gateway pods when the configuration changes
+	gatewayConfigHashAnnotation = "mcp.mcp-operator.io/config-hash"
+)
+
+// gatewayConfig mirrors the configuration file read by the mcp-proxy in gateway mode
+type gatewayConfig struct {
+	Path            string           `json:"path"`
+	PrefixToolNames bool             `json:"prefixToolNames"`
+	Separator       string           `json:"separator"`
+	Backends        []gatewayBackend `json:"backends"`
+}
+
+// gatewayBackend is one server in the gateway configuration
+type gatewayBackend struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// MCPGatewayReconciler reconciles a MCPGateway object
+type MCPGatewayReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=mcp.mcp-operator.io,resources=mcpgateways,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=mcp.mcp-operator.io,resources=mcpgateways/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=mcp.mcp-operator.io,resources=mcpgateways/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile selects the gateway's backends and keeps its ConfigMap, Deployment and Service in sync
+func (r *MCPGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	gateway := &mcpv1.MCPGateway{}
+	if err := r.Get(ctx, req.NamespacedName, gateway); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get MCPGateway")
+		return ctrl.Result{}, err
+	}
+
+	backends, skipped, err := r.selectBackends(ctx, gateway)
+	if err != nil {
+		log.Error(err, "Failed to select MCPServers")
+		r.setReadyCondition(gateway, metav1.ConditionFalse, "SelectionFailed", err.Error())
+		if statusErr := r.Status().Update(ctx, gateway); statusErr != nil {
+			log.Error(statusErr, "Failed to update MCPGateway status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	config, err := json.Marshal(buildGatewayConfig(gateway, backends))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileConfigMap(ctx, gateway, config); err != nil {
+		log.Error(err, "Failed to reconcile gateway ConfigMap")
+		return ctrl.Result{}, err
+	}
+
+	deployment, err := r.reconcileDeployment(ctx, gateway, config)
+	if err != nil {
+		log.Error(err, "Failed to reconcile gateway Deployment")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileService(ctx, gateway); err != nil {
+		log.Error(err, "Failed to reconcile gateway Service")
+		return ctrl.Result{}, err
+	}
+
+	gateway.Status.Endpoint = fmt.Sprintf("http://%s.%s.svc.cluster.local:%d%s",
+		gatewayResourceName(gateway), gateway.Namespace, gatewayPort(gateway), gatewayPath(gateway))
+	gateway.Status.Backends = backends
+	gateway.Status.BackendCount = int32(len(backends))
+	gateway.Status.SkippedServers = skipped
+	gateway.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+	gateway.Status.ObservedGeneration = gateway.Generation
+
+	switch {
+	case len(backends) == 0:
+		r.setReadyCondition(gateway, metav1.ConditionFalse, "NoBackends",
+			"No selected MCPServer is a validated Streamable HTTP server")
+	case deployment.Status.ReadyReplicas == 0:
+		r.setReadyCondition(gateway, metav1.ConditionFalse, "DeploymentNotReady", "No gateway pod is ready")
+	default:
+		r.setReadyCondition(gateway, metav1.ConditionTrue, "GatewayReady",
+			fmt.Sprintf("Gateway serves %d backends", len(backends)))
+	}
+
+	if err := r.Status().Update(ctx, gateway); err != nil {
+		if errors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "Failed to update MCPGateway status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// selectBackends lists the MCPServers selected by the gateway and splits them
+// into usable backends and skipped servers, both sorted by name
+func (r *MCPGatewayReconciler) selectBackends(
+	ctx context.Context, gateway *mcpv1.MCPGateway,
+) ([]mcpv1.MCPGatewayBackend, []mcpv1.MCPGatewaySkippedServer, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&gateway.Spec.ServerSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid serverSelector: %w", err)
+	}
+
+	serverList := &mcpv1.MCPServerList{}
+	if err := r.List(ctx, serverList,
+		client.InNamespace(gateway.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(serverList.Items, func(i, j int) bool {
+		return serverList.Items[i].Name < serverList.Items[j].Name
+	})
+
+	var backends []mcpv1.MCPGatewayBackend
+	var skipped []mcpv1.MCPGatewaySkippedServer
+	for _, server := range serverList.Items {
+		if reason := gatewaySkipReason(&server); reason != "" {
+			skipped = append(skipped, mcpv1.MCPGatewaySkippedServer{Name: server.Name, Reason: reason})
+			continue
+		}
+		backends = append(backends, mcpv1.MCPGatewayBackend{
+			Name:     server.Name,
+			Endpoint: server.Status.Validation.Endpoint,
+		})
+	}
+	return backends, skipped, nil
+}
+
+// gatewaySkipReason explains why a server cannot be a gateway backend, or
+// returns an empty string if it can
+func gatewaySkipReason(server *mcpv1.MCPServer) string {
+	validation := server.Status.Validation
+	switch {
+	case validation == nil || validation.State != mcpv1.ValidationStateValidated:
+		return "Server is not validated"
+	case validation.Protocol != string(validator.TransportStreamableHTTP):
+		return fmt.Sprintf("Gateway only supports Streamable HTTP servers, server uses %s", validation.Protocol)
+	case validation.Endpoint == "":
+		return "Server has no validated endpoint"
+	}
+	return ""
+}
+
+// buildGatewayConfig builds the configuration file read by the gateway
+func buildGatewayConfig(gateway *mcpv1.MCPGateway, backends []mcpv1.MCPGatewayBackend) gatewayConfig {
+	config := gatewayConfig{
+		Path:            gatewayPath(gateway),
+		PrefixToolNames: true,
+		Separator:       mcpv1.DefaultGatewayToolSeparator,
+		Backends:        make([]gatewayBackend, 0, len(backends)),
+	}
+	if prefix := gateway.Spec.ToolPrefix; prefix != nil {
+		config.PrefixToolNames = prefix.Enabled
+		if prefix.Separator != "" {
+			config.Separator = prefix.Separator
+		}
+	}
+	for _, backend := range backends {
+		config.Backends = append(config.Backends, gatewayBackend{Name: backend.Name, URL: backend.Endpoint})
+	}
+	return config
+}
+
+// reconcileConfigMap writes the gateway configuration
+func (r *MCPGatewayReconciler) reconcileConfigMap(ctx context.Context, gateway *mcpv1.MCPGateway, config []byte) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      gatewayResourceName(gateway),
+				Namespace: gateway.Namespace,
+			},
+		}
+
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+			if err := controllerutil.SetControllerReference(gateway, configMap, r.Scheme); err != nil {
+				return err
+			}
+			configMap.Labels = gatewayLabels(gateway)
+			configMap.Data = map[string]string{gatewayConfigKey: string(config)}
+			return nil
+		})
+		return err
+	})
+}
+
+// reconcileDeployment creates or updates the gateway Deployment and returns it
+func (r *MCPGatewayReconciler) reconcileDeployment(
+	ctx context.Context, gateway *mcpv1.MCPGateway, config []byte,
+) (*appsv1.Deployment, error) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gatewayResourceName(gateway),
+			Namespace: gateway.Namespace,
+		},
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+			if err := controllerutil.SetControllerReference(gateway, deployment, r.Scheme); err != nil {
+				return err
+			}
+			deployment.Labels = gatewayLabels(gateway)
+			deployment.Spec = r.buildDeploymentSpec(gateway, config)
+			return nil
+		})
+		return err
+	})
+	return deployment, err
+}
+
+// buildDeploymentSpec builds the gateway Deployment spec
+func (r *MCPGatewayReconciler) buildDeploymentSpec(gateway *mcpv1.MCPGateway, config []byte) appsv1.DeploymentSpec {
+	replicas := int32(1)
+	if gateway.Spec.Replicas != nil {
+		replicas = *gateway.Spec.Replicas
+	}
+
+	image := mcpv1.DefaultSidecarImage
+	if gateway.Spec.Image != "" {
+		image = gateway.Spec.Image
+	}
+
+	resources := gateway.Spec.Resources
+	if resources.Requests == nil {
+		resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPURequest),
+				corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryRequest),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPULimit),
+				corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryLimit),
+			},
+		}
+	}
+
+	port := gatewayPort(gateway)
+	metricsPort := gatewayMetricsPort(gateway)
+	hash := sha256.Sum256(config)
+	runAsNonRoot, readOnlyRootFilesystem, allowPrivilegeEscalation := true, true, false
+
+	container := corev1.Container{
+		Name:  "mcp-gateway",
+		Image: image,
+		Args: []string{
+			"--mode=gateway",
+			fmt.Sprintf("--listen-addr=:%d", port),
+			fmt.Sprintf("--metrics-addr=:%d", metricsPort),
+			fmt.Sprintf("--gateway-config=%s/%s", gatewayConfigDir, gatewayConfigKey),
+			"--log-level=info",
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "mcp", ContainerPort: port, Protocol: corev1.ProtocolTCP},
+			{Name: "metrics", ContainerPort: metricsPort, Protocol: corev1.ProtocolTCP},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "gateway-config", MountPath: gatewayConfigDir, ReadOnly: true},
+		},
+		LivenessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(int(metricsPort))},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+			FailureThreshold:    3,
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/readyz", Port: intstr.FromInt(int(metricsPort))},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       5,
+			TimeoutSeconds:      3,
+			FailureThreshold:    3,
+		},
+		Resources: resources,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot:             &runAsNonRoot,
+			ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+
+	return appsv1.DeploymentSpec{
+		Replicas: &replicas,
+		Selector: &metav1.LabelSelector{MatchLabels: gatewaySelectorLabels(gateway)},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: gatewayLabels(gateway),
+				Annotations: map[string]string{
+					gatewayConfigHashAnnotation: hex.EncodeToString(hash[:]),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{container},
+				Volumes: []corev1.Volume{
+					{
+						Name: "gateway-config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: gatewayResourceName(gateway)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// reconcileService creates or updates the gateway Service
+func (r *MCPGatewayReconciler) reconcileService(ctx context.Context, gateway *mcpv1.MCPGateway) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      gatewayResourceName(gateway),
+				Namespace: gateway.Namespace,
+			},
+		}
+
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+			if err := controllerutil.SetControllerReference(gateway, service, r.Scheme); err != nil {
+				return err
+			}
+			service.Labels = gatewayLabels(gateway)
+			// Only set the fields we own so the allocated ClusterIP is kept
+			service.Spec.Type = corev1.ServiceTypeClusterIP
+			service.Spec.Selector = gatewaySelectorLabels(gateway)
+			service.Spec.Ports = []corev1.ServicePort{
+				{
+					Name:       "mcp",
+					Port:       gatewayPort(gateway),
+					TargetPort: intstr.FromString("mcp"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+				{
+					Name:       "metrics",
+					Port:       gatewayMetricsPort(gateway),
+					TargetPort: intstr.FromString("metrics"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			}
+			return nil
+		})
+		return err
+	})
+}
+
+// setReadyCondition sets the Ready condition of the gateway
+func (r *MCPGatewayReconciler) setReadyCondition(
+	gateway *mcpv1.MCPGateway, status metav1.ConditionStatus, reason, message string,
+) {
+	meta.SetStatusCondition(&gateway.Status.Conditions, metav1.Condition{
+		Type:               mcpv1.MCPGatewayConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: gateway.Generation,
+	})
+}
+
+// gatewayResourceName is the name of the ConfigMap, Deployment and Service of a gateway.
+// The suffix keeps them apart from the resources of an MCPServer with the same name.
+func gatewayResourceName(gateway *mcpv1.MCPGateway) string {
+	return gateway.Name + "-gateway"
+}
+
+// gatewaySelectorLabels selects the gateway pods. Gateway pods carry no "app"
+// label, so MCPServer selectors never match them.
+func gatewaySelectorLabels(gateway *mcpv1.MCPGateway) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     "mcpgateway",
+		"app.kubernetes.io/instance": gateway.Name,
+	}
+}
+
+// gatewayLabels are the labels set on every resource of a gateway
+func gatewayLabels(gateway *mcpv1.MCPGateway) map[string]string {
+	labels := gatewaySelectorLabels(gateway)
+	labels["app.kubernetes.io/component"] = "gateway"
+	labels["app.kubernetes.io/managed-by"] = "mcp-operator"
+	return labels
+}
+
+// gatewayPort returns the port the gateway serves MCP traffic on
+func gatewayPort(gateway *mcpv1.MCPGateway) int32 {
+	if gateway.Spec.Port != 0 {
+		return gateway.Spec.Port
+	}
+	return mcpv1.DefaultGatewayPort
+}
+
+// gatewayMetricsPort returns the port the gateway serves metrics and health endpoints on
+func gatewayMetricsPort(gateway *mcpv1.MCPGateway) int32 {
+	if gateway.Spec.MetricsPort != 0 {
+		return gateway.Spec.MetricsPort
+	}
+	return mcpv1.DefaultGatewayMetricsPort
+}
+
+// gatewayPath returns the path the gateway serves MCP traffic on
+func gatewayPath(gateway *mcpv1.MCPGateway) string {
+	if gateway.Spec.Path != "" {
+		return gateway.Spec.Path
+	}
+	return mcpv1.DefaultGatewayPath
+}
+
+// mapServerToGateways enqueues the gateways in the server's namespace, since
+// each of them may select it
+func (r *MCPGatewayReconciler) mapServerToGateways(ctx context.Context, obj client.Object) []reconcile.Request {
+	gatewayList := &mcpv1.MCPGatewayList{}
+	if err := r.List(ctx, gatewayList, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list MCPGateways")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(gatewayList.Items))
+	for _, gateway := range gatewayList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gateway)})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MCPGatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&mcpv1.MCPGateway{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Watches(
+			&mcpv1.MCPServer{},
+			handler.EnqueueRequestsFromMapFunc(r.mapServerToGateways),
+			builder.WithPredicates(validatedServerChangedPredicate()),
+		).
+		Named("mcpgateway").
+		Complete(r)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+var _ = Describe("MCPGateway Controller", func() {
+	var (
+		ctx        context.Context
+		reconciler *MCPGatewayReconciler
+	)
+
+	gatewayKey := types.NamespacedName{Name: "tools", Namespace: "default"}
+	resourceKey := types.NamespacedName{Name: "tools-gateway", Namespace: "default"}
+
+	server := func(name string, labels map[string]string, validation *mcpv1.ValidationStatus) *mcpv1.MCPServer {
+		return &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Spec:       mcpv1.MCPServerSpec{Image: "test-server:latest"},
+			Status:     mcpv1.MCPServerStatus{Validation: validation},
+		}
+	}
+
+	reconcileGateway := func() *mcpv1.MCPGateway {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: gatewayKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		gateway := &mcpv1.MCPGateway{}
+		Expect(reconciler.Get(ctx, gatewayKey, gateway)).To(Succeed())
+		return gateway
+	}
+
+	readConfig := func() gatewayConfig {
+		configMap := &corev1.ConfigMap{}
+		Expect(reconciler.Get(ctx, resourceKey, configMap)).To(Succeed())
+
+		var config gatewayConfig
+		Expect(json.Unmarshal([]byte(configMap.Data[gatewayConfigKey]), &config)).To(Succeed())
+		return config
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		gatewayLabels := map[string]string{"gateway": "tools"}
+
+		objects := []client.Object{
+			server("weather", gatewayLabels, &mcpv1.ValidationStatus{
+				State:    mcpv1.ValidationStateValidated,
+				Protocol: "streamable-http",
+				Endpoint: "http://weather.default.svc.cluster.local:8080/mcp",
+			}),
+			server("calendar", gatewayLabels, &mcpv1.ValidationStatus{
+				State:    mcpv1.ValidationStateValidated,
+				Protocol: "streamable-http",
+				Endpoint: "http://calendar.default.svc.cluster.local:8080/mcp",
+			}),
+			server("legacy", gatewayLabels, &mcpv1.ValidationStatus{
+				State:    mcpv1.ValidationStateValidated,
+				Protocol: "sse",
+				Endpoint: "http://legacy.default.svc.cluster.local:8080/sse",
+			}),
+			server("pending", gatewayLabels, nil),
+			server("unrelated", nil, &mcpv1.ValidationStatus{
+				State:    mcpv1.ValidationStateValidated,
+				Protocol: "streamable-http",
+				Endpoint: "http://unrelated.default.svc.cluster.local:8080/mcp",
+			}),
+			&mcpv1.MCPGateway{
+				ObjectMeta: metav1.ObjectMeta{Name: gatewayKey.Name, Namespace: gatewayKey.Namespace},
+				Spec: mcpv1.MCPGatewaySpec{
+					ServerSelector: metav1.LabelSelector{MatchLabels: gatewayLabels},
+				},
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		reconciler = &MCPGatewayReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(runtimeScheme).
+				WithObjects(objects...).
+				WithStatusSubresource(&mcpv1.MCPGateway{}, &mcpv1.MCPServer{}).
+				Build(),
+			Scheme: runtimeScheme,
+		}
+	})
+
+	It("should select validated Streamable HTTP servers as backends", func() {
+		gateway := reconcileGateway()
+
+		Expect(gateway.Status.BackendCount).To(Equal(int32(2)))
+		Expect(gateway.Status.Backends).To(Equal([]mcpv1.MCPGatewayBackend{
+			{Name: "calendar", Endpoint: "http://calendar.default.svc.cluster.local:8080/mcp"},
+			{Name: "weather", Endpoint: "http://weather.default.svc.cluster.local:8080/mcp"},
+		}))
+		Expect(gateway.Status.SkippedServers).To(HaveLen(2))
+		Expect(gateway.Status.SkippedServers[0].Name).To(Equal("legacy"))
+		Expect(gateway.Status.SkippedServers[0].Reason).To(ContainSubstring("sse"))
+		Expect(gateway.Status.SkippedServers[1].Name).To(Equal("pending"))
+		Expect(gateway.Status.Endpoint).To(Equal("http://tools-gateway.default.svc.cluster.local:8080/mcp"))
+
+		config := readConfig()
+		Expect(config.Path).To(Equal("/mcp"))
+		Expect(config.PrefixToolNames).To(BeTrue())
+		Expect(config.Separator).To(Equal("__"))
+		Expect(config.Backends).To(HaveLen(2))
+		Expect(config.Backends[0]).To(Equal(gatewayBackend{
+			Name: "calendar", URL: "http://calendar.default.svc.cluster.local:8080/mcp",
+		}))
+	})
+
+	It("should create a gateway Deployment and Service", func() {
+		reconcileGateway()
+
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, resourceKey, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Labels).NotTo(HaveKey("app"))
+		Expect(deployment.Spec.Template.Annotations).To(HaveKey(gatewayConfigHashAnnotation))
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal(mcpv1.DefaultSidecarImage))
+		Expect(container.Args).To(ContainElements(
+			"--mode=gateway",
+			"--listen-addr=:8080",
+			"--metrics-addr=:9090",
+			"--gateway-config=/etc/mcp-gateway/gateway.json",
+		))
+		Expect(container.VolumeMounts[0].MountPath).To(Equal(gatewayConfigDir))
+		Expect(deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("tools-gateway"))
+
+		service := &corev1.Service{}
+		Expect(reconciler.Get(ctx, resourceKey, service)).To(Succeed())
+		Expect(service.Spec.Selector).To(Equal(deployment.Spec.Selector.MatchLabels))
+		Expect(service.Spec.Ports).To(HaveLen(2))
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32(8080)))
+	})
+
+	It("should roll the gateway pods when the backends change", func() {
+		reconcileGateway()
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, resourceKey, deployment)).To(Succeed())
+		firstHash := deployment.Spec.Template.Annotations[gatewayConfigHashAnnotation]
+
+		legacy := &mcpv1.MCPServer{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "legacy", Namespace: "default"}, legacy)).To(Succeed())
+		legacy.Status.Validation.Protocol = "streamable-http"
+		legacy.Status.Validation.Endpoint = "http://legacy.default.svc.cluster.local:8080/mcp"
+		Expect(reconciler.Status().Update(ctx, legacy)).To(Succeed())
+
+		gateway := reconcileGateway()
+		Expect(gateway.Status.BackendCount).To(Equal(int32(3)))
+		Expect(readConfig().Backends).To(HaveLen(3))
+
+		Expect(reconciler.Get(ctx, resourceKey, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Annotations[gatewayConfigHashAnnotation]).NotTo(Equal(firstHash))
+	})
+
+	It("should apply the tool prefix settings", func() {
+		gateway := &mcpv1.MCPGateway{}
+		Expect(reconciler.Get(ctx, gatewayKey, gateway)).To(Succeed())
+		gateway.Spec.ToolPrefix = &mcpv1.MCPGatewayToolPrefix{Enabled: false, Separator: "."}
+		gateway.Spec.Path = "/gateway"
+		Expect(reconciler.Update(ctx, gateway)).To(Succeed())
+
+		gateway = reconcileGateway()
+		Expect(gateway.Status.Endpoint).To(HaveSuffix("/gateway"))
+
+		config := readConfig()
+		Expect(config.PrefixToolNames).To(BeFalse())
+		Expect(config.Separator).To(Equal("."))
+		Expect(config.Path).To(Equal("/gateway"))
+	})
+
+	It("should not be ready until a gateway pod is ready", func() {
+		gateway := reconcileGateway()
+		condition := meta.FindStatusCondition(gateway.Status.Conditions, mcpv1.MCPGatewayConditionReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("DeploymentNotReady"))
+
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, resourceKey, deployment)).To(Succeed())
+		deployment.Status.ReadyReplicas = 1
+		Expect(reconciler.Status().Update(ctx, deployment)).To(Succeed())
+
+		gateway = reconcileGateway()
+		Expect(gateway.Status.ReadyReplicas).To(Equal(int32(1)))
+		Expect(meta.IsStatusConditionTrue(gateway.Status.Conditions, mcpv1.MCPGatewayConditionReady)).To(BeTrue())
+	})
+
+	It("should report when no server can be a backend", func() {
+		gateway := &mcpv1.MCPGateway{}
+		Expect(reconciler.Get(ctx, gatewayKey, gateway)).To(Succeed())
+		gateway.Spec.ServerSelector = metav1.LabelSelector{MatchLabels: map[string]string{"gateway": "none"}}
+		Expect(reconciler.Update(ctx, gateway)).To(Succeed())
+
+		gateway = reconcileGateway()
+		Expect(gateway.Status.BackendCount).To(BeZero())
+		condition := meta.FindStatusCondition(gateway.Status.Conditions, mcpv1.MCPGatewayConditionReady)
+		Expect(condition.Reason).To(Equal("NoBackends"))
+		Expect(readConfig().Backends).To(BeEmpty())
+	})
+})
@@ -22,12 +22,15 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -55,6 +58,19 @@ type MCPServerReconciler struct {
 	TransportFactory *transport.ManagerFactory
 	Recorder         record.EventRecorder
 
+	// OperatorNamespace is the namespace the operator runs in. Generated
+	// NetworkPolicies admit the operator pods of this namespace only.
+	OperatorNamespace string
+
+	// APIReader reads objects that are not watched, such as the Secrets
+	// holding validation credentials, without caching them in the manager.
+	// Defaults to the client.
+	APIReader client.Reader
+
+	// lookupHost resolves the egress DNS names of NetworkPolicies.
+	// Defaults to net.DefaultResolver.LookupHost.
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+
 	// ServiceMonitor CRD availability cache
 	// This cache prevents excessive API calls when checking if Prometheus Operator is installed
 	crdAvailabilityCache bool
@@ -73,6 +89,8 @@ type MCPServerReconciler struct {
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
 const (
 	mcpServerFinalizer = "mcp.mcp-operator.io/finalizer"
@@ -286,6 +304,44 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// is disabled) and the config is now applied to resources.
 	r.maybeMarkSSEConfigApplied(ctx, mcpServer)
 
+	// Reconcile the session router if enabled
+	if err := r.reconcileSessionRouter(ctx, mcpServer); err != nil {
+		log.Error(err, "Failed to reconcile session router")
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "SessionRouterFailed", fmt.Sprintf("Failed to reconcile session router: %v", err))
+		return r.updateStatusWithError(ctx, mcpServer, err)
+	}
+
+	// Expose the server outside the cluster if requested
+	if err := r.reconcileExposure(ctx, mcpServer); err != nil {
+		log.Error(err, "Failed to reconcile exposure")
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "ExposureFailed", fmt.Sprintf("Failed to reconcile exposure: %v", err))
+		return r.updateStatusWithError(ctx, mcpServer, err)
+	}
+
+	// Restrict the traffic of the server pods if requested
+	networkPolicyRequeue, err := r.reconcileNetworkPolicy(ctx, mcpServer)
+	if err != nil {
+		log.Error(err, "Failed to reconcile NetworkPolicy")
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "NetworkPolicyFailed", fmt.Sprintf("Failed to reconcile NetworkPolicy: %v", err))
+		return r.updateStatusWithError(ctx, mcpServer, err)
+	}
+
+	// Drive the canary rollout of pod template changes
+	rolloutRequeue, err := r.reconcileRollout(ctx, mcpServer)
+	if err != nil {
+		log.Error(err, "Failed to reconcile rollout")
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "RolloutFailed", fmt.Sprintf("Failed to reconcile rollout: %v", err))
+		return r.updateStatusWithError(ctx, mcpServer, err)
+	}
+
+	// Scale the server to zero once idle, and back once the activator woke it up
+	scaleToZeroRequeue, err := r.reconcileScaleToZero(ctx, mcpServer)
+	if err != nil {
+		log.Error(err, "Failed to reconcile scale-to-zero")
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "ScaleToZeroFailed", fmt.Sprintf("Failed to reconcile scale-to-zero: %v", err))
+		return r.updateStatusWithError(ctx, mcpServer, err)
+	}
+
 	// Reconcile HPA if enabled
 	if err := r.reconcileHPA(ctx, mcpServer); err != nil {
 		log.Error(err, "Failed to reconcile HPA")
@@ -293,6 +349,13 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return r.updateStatusWithError(ctx, mcpServer, err)
 	}
 
+	// Limit voluntary evictions of the server pods
+	if err := r.reconcileDisruptionBudget(ctx, mcpServer); err != nil {
+		log.Error(err, "Failed to reconcile PodDisruptionBudget")
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "DisruptionBudgetFailed", fmt.Sprintf("Failed to reconcile PodDisruptionBudget: %v", err))
+		return r.updateStatusWithError(ctx, mcpServer, err)
+	}
+
 	// Reconcile ServiceMonitor if metrics are enabled and Prometheus Operator is installed
 	if err := r.reconcileServiceMonitor(ctx, mcpServer); err != nil {
 		log.Error(err, "Failed to reconcile ServiceMonitor")
@@ -314,7 +377,8 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	// Perform protocol validation if enabled and server is running
-	// Validation occurs on deployment and retries with backoff if it fails
+	// Validation occurs on deployment and retries with backoff if it fails,
+	// and is repeated periodically when spec.validation.interval is set
 	// Strict mode enforcement (deployment deletion) is handled in updateValidationStatus
 	if r.shouldValidate(ctx, mcpServer) {
 		validationResult := r.validateServer(ctx, mcpServer)
@@ -388,8 +452,10 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 					log.Error(err, "Failed to update validation status to Disabled")
 				}
 			}
-		} else if mcpServer.Status.Phase != mcpv1.MCPServerPhaseRunning || !r.arePodsReady(ctx, mcpServer) {
-			// Server not ready for validation yet - set state to Pending if not in a terminal state
+		} else if mcpServer.Status.Phase != mcpv1.MCPServerPhaseIdle &&
+			(mcpServer.Status.Phase != mcpv1.MCPServerPhaseRunning || !r.arePodsReady(ctx, mcpServer)) {
+			// Server not ready for validation yet - set state to Pending if not in a terminal state.
+			// A server scaled to zero is not pending: it keeps the result of its last validation
 			if mcpServer.Status.Validation == nil ||
 				(mcpServer.Status.Validation.State != mcpv1.ValidationStateValidated &&
 					mcpServer.Status.Validation.State != mcpv1.ValidationStateAuthRequired &&
@@ -411,6 +477,13 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Calculate retry interval for failed validations (returns 0 if validation succeeded)
 	requeueAfter := r.getValidationRetryInterval(mcpServer)
 
+	// Check back sooner while a canary rollout is in progress, to resolve the
+	// egress DNS names of the NetworkPolicy again in time, and to check the
+	// sidecars for activity before the idle timeout passes
+	requeueAfter = soonerRequeue(requeueAfter, rolloutRequeue)
+	requeueAfter = soonerRequeue(requeueAfter, networkPolicyRequeue)
+	requeueAfter = soonerRequeue(requeueAfter, scaleToZeroRequeue)
+
 	// Record reconciliation metrics
 	metrics.RecordReconcileMetrics("mcpserver", time.Since(startTime).Seconds(), "success")
 
@@ -428,6 +501,14 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{}, nil
 }
 
+// soonerRequeue returns the earlier of two requeue delays, where 0 means no requeue
+func soonerRequeue(current, next time.Duration) time.Duration {
+	if next > 0 && (current == 0 || next < current) {
+		return next
+	}
+	return current
+}
+
 // handleDeletion handles the deletion of MCPServer resources
 func (r *MCPServerReconciler) handleDeletion(ctx context.Context, mcpServer *mcpv1.MCPServer) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
@@ -604,6 +685,11 @@ func (r *MCPServerReconciler) buildHPA(mcpServer *mcpv1.MCPServer) *autoscalingv
 		})
 	}
 
+	// MCP traffic metrics of the sidecar
+	for _, metric := range mcpServer.Spec.HPA.Metrics {
+		metricSpecs = append(metricSpecs, buildHPAMetricSpec(mcpServer, metric))
+	}
+
 	hpaSpec := autoscalingv2.HorizontalPodAutoscalerSpec{
 		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
 			APIVersion: "apps/v1",
@@ -642,6 +728,46 @@ func (r *MCPServerReconciler) buildHPA(mcpServer *mcpv1.MCPServer) *autoscalingv
 	return hpa
 }
 
+// buildHPAMetricSpec converts an MCP traffic metric target to the metric the
+// adapters serve it as: a Pods metric averaged over the pods, or, for the tool
+// call latency computed across all pods, an External metric of the server
+func buildHPAMetricSpec(mcpServer *mcpv1.MCPServer, metric mcpv1.MCPServerHPAMetric) autoscalingv2.MetricSpec {
+	target := metric.Target.DeepCopy()
+
+	if metric.Type == mcpv1.HPAMetricToolCallLatencyP95 {
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: mcpv1.HPAMetricNameToolCallLatencyP95,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{mcpv1.HPAMetricServerLabel: mcpServer.Name},
+					},
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:  autoscalingv2.ValueMetricType,
+					Value: &target,
+				},
+			},
+		}
+	}
+
+	name := mcpv1.HPAMetricNameActiveSSEConnections
+	if metric.Type == mcpv1.HPAMetricRequestsPerSecond {
+		name = mcpv1.HPAMetricNameRequestsPerSecond
+	}
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.PodsMetricSourceType,
+		Pods: &autoscalingv2.PodsMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{Name: name},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &target,
+			},
+		},
+	}
+}
+
 // buildHPAScalingRules converts MCPServerHPABehavior to autoscalingv2.HPAScalingRules
 func (r *MCPServerReconciler) buildHPAScalingRules(behavior *mcpv1.MCPServerHPABehavior) *autoscalingv2.HPAScalingRules {
 	rules := &autoscalingv2.HPAScalingRules{}
@@ -736,7 +862,10 @@ func (r *MCPServerReconciler) updateMCPServerStatus(ctx context.Context, mcpServ
 
 	// Determine phase based on deployment status
 	previousPhase := mcpServer.Status.Phase
-	if deployment.Status.Replicas == 0 {
+	if transport.IsScaledToZero(mcpServer) {
+		mcpServer.Status.Phase = mcpv1.MCPServerPhaseIdle
+		mcpServer.Status.Message = "Scaled to zero; the activator scales the server up on the next request"
+	} else if deployment.Status.Replicas == 0 {
 		mcpServer.Status.Phase = mcpv1.MCPServerPhaseCreating
 		mcpServer.Status.Message = "Creating deployment"
 	} else if deployment.Status.ReadyReplicas == 0 {
@@ -787,6 +916,13 @@ func (r *MCPServerReconciler) updateMCPServerStatus(ctx context.Context, mcpServ
 		}
 	}
 
+	// Set session router endpoint
+	mcpServer.Status.SessionRouterEndpoint = ""
+	if sessionRoutingEnabled(mcpServer) {
+		mcpServer.Status.SessionRouterEndpoint = fmt.Sprintf("%s.%s.svc.cluster.local:%d",
+			sessionRouterName(mcpServer), mcpServer.Namespace, transport.GetServicePort(mcpServer))
+	}
+
 	// Capture the current status for comparison
 	originalStatus := mcpServer.Status.DeepCopy()
 
@@ -813,7 +949,13 @@ func (r *MCPServerReconciler) updateConditions(mcpServer *mcpv1.MCPServer, deplo
 		Type:               mcpv1.MCPServerConditionReady,
 		LastTransitionTime: now,
 	}
-	if deployment.Status.ReadyReplicas > 0 {
+	idle := transport.IsScaledToZero(mcpServer)
+	if idle {
+		// The activator accepts requests on behalf of the server
+		readyCondition.Status = corev1.ConditionTrue
+		readyCondition.Reason = "ScaledToZero"
+		readyCondition.Message = "MCPServer is scaled to zero and activated on the next request"
+	} else if deployment.Status.ReadyReplicas > 0 {
 		readyCondition.Status = corev1.ConditionTrue
 		readyCondition.Reason = "DeploymentReady"
 		readyCondition.Message = "MCPServer deployment has ready replicas"
@@ -829,7 +971,11 @@ func (r *MCPServerReconciler) updateConditions(mcpServer *mcpv1.MCPServer, deplo
 		Type:               mcpv1.MCPServerConditionAvailable,
 		LastTransitionTime: now,
 	}
-	if deployment.Status.AvailableReplicas > 0 {
+	if idle {
+		availableCondition.Status = corev1.ConditionFalse
+		availableCondition.Reason = "ScaledToZero"
+		availableCondition.Message = "MCPServer is scaled to zero"
+	} else if deployment.Status.AvailableReplicas > 0 {
 		availableCondition.Status = corev1.ConditionTrue
 		availableCondition.Reason = "DeploymentAvailable"
 		availableCondition.Message = "MCPServer deployment has available replicas"
@@ -997,10 +1143,10 @@ func (r *MCPServerReconciler) shouldValidate(ctx context.Context, mcpServer *mcp
 		return true
 	}
 
-	// Don't re-validate if validation passed (no periodic validation)
+	// Re-validate successful servers only when periodic validation is due
 	if mcpServer.Status.Validation.State == mcpv1.ValidationStateValidated ||
 		mcpServer.Status.Validation.State == mcpv1.ValidationStateAuthRequired {
-		return false
+		return isRevalidationDue(mcpServer)
 	}
 
 	// Don't re-validate if validation failed permanently
@@ -1060,9 +1206,15 @@ func (r *MCPServerReconciler) isPermanentError(result *validator.ValidationResul
 		case validator.CodeAuthRequired:
 			// Auth required - user needs to provide credentials
 			return true
+		case validator.CodeAuthFailed:
+			// Credentials rejected or not loadable - user needs to fix them
+			return true
 		case validator.CodeInvalidProtocolVersion:
 			// Invalid protocol version - server incompatibility
 			return true
+		case validator.CodeToolInvalidSchema, validator.CodeToolInvalidName, validator.CodeToolDuplicateName:
+			// Invalid tool definitions - the server needs fixing
+			return true
 		case validator.CodeMissingServerInfo:
 			// Missing server info after successful connection - protocol issue
 			if result.Success {
@@ -1072,11 +1224,18 @@ func (r *MCPServerReconciler) isPermanentError(result *validator.ValidationResul
 	}
 
 	// If validation succeeded but IsCompliant is false due to missing capabilities,
-	// this is a permanent error (server doesn't support required features)
+	// tools, resources or prompts, this is a permanent error (server doesn't support required features)
 	if !result.Success && result.ProtocolVersion != "" && len(result.Capabilities) > 0 {
 		// Server responded successfully but doesn't meet requirements
 		for _, issue := range result.Issues {
-			if issue.Code == validator.CodeMissingCapability {
+			if issue.Level != validator.LevelError {
+				continue
+			}
+			switch issue.Code {
+			case validator.CodeMissingCapability,
+				validator.CodeMissingTool,
+				validator.CodeMissingResource,
+				validator.CodeMissingPrompt:
 				return true
 			}
 		}
@@ -1139,9 +1298,16 @@ func (r *MCPServerReconciler) validateServer(ctx context.Context, mcpServer *mcp
 		return nil
 	}
 
+	// Load credentials for servers that require authentication
+	creds, err := r.loadValidationCredentials(ctx, mcpServer)
+	if err != nil {
+		log.Error(err, "Failed to load validation credentials")
+		return newCredentialsFailureResult(err)
+	}
+
 	// Create validator with a fixed timeout
 	timeout := 30 * time.Second
-	v := validator.NewValidatorWithTimeout(endpoint, timeout)
+	v := validator.NewValidator(endpoint, validator.WithTimeout(timeout), validator.WithCredentials(creds))
 
 	// Prepare validation options
 	opts := validator.ValidationOptions{
@@ -1161,6 +1327,13 @@ func (r *MCPServerReconciler) validateServer(ctx context.Context, mcpServer *mcp
 		opts.RequiredCapabilities = mcpServer.Spec.Validation.RequiredCapabilities
 	}
 
+	// Add the expected inventory if specified
+	if mcpServer.Spec.Validation != nil {
+		opts.RequiredTools = mcpServer.Spec.Validation.RequiredTools
+		opts.RequiredResources = mcpServer.Spec.Validation.RequiredResources
+		opts.RequiredPrompts = mcpServer.Spec.Validation.RequiredPrompts
+	}
+
 	// Add strict mode if specified
 	if r.isStrictModeEnabled(mcpServer) {
 		opts.StrictMode = true
@@ -1284,6 +1457,20 @@ func (r *MCPServerReconciler) updateValidationStatus(ctx context.Context, mcpSer
 
 	now := metav1.Now()
 
+	// Compare with the previous result before it is modified or replaced
+	isRevalidation := isPeriodicRevalidation(mcpServer)
+	specChanged := mcpServer.Status.Validation == nil ||
+		mcpServer.Status.Validation.ValidatedGeneration != mcpServer.Generation
+	var drift []string
+	if isRevalidation {
+		drift = detectValidationDrift(mcpServer.Status.Validation, result)
+	}
+
+	// A server already validated for its spec is not failed over a re-validation
+	if isRevalidation && !result.IsCompliant() {
+		return r.recordRevalidationFailure(ctx, mcpServer, result, drift)
+	}
+
 	// Check for protocol mismatch and preserve any mismatch issues
 	hasMismatch := r.checkProtocolMismatch(ctx, mcpServer, result)
 	var mismatchIssues []mcpv1.ValidationIssue
@@ -1297,8 +1484,9 @@ func (r *MCPServerReconciler) updateValidationStatus(ctx context.Context, mcpSer
 	}
 
 	// Track validation attempts
+	// Periodic re-validation starts a fresh series of attempts
 	attempts := int32(1)
-	if mcpServer.Status.Validation != nil {
+	if mcpServer.Status.Validation != nil && !isRevalidation {
 		attempts = mcpServer.Status.Validation.Attempts + 1
 	}
 
@@ -1346,6 +1534,8 @@ func (r *MCPServerReconciler) updateValidationStatus(ctx context.Context, mcpSer
 		Protocol:            string(result.DetectedTransport),
 		Endpoint:            result.Endpoint,
 		Capabilities:        result.Capabilities,
+		ServerInfo:          toValidationServerInfo(result.ServerInfo),
+		Inventory:           r.reconcileInventory(ctx, mcpServer, result),
 		Compliant:           isCompliant,
 		RequiresAuth:        result.RequiresAuth,
 		LastValidated:       &now,
@@ -1370,6 +1560,7 @@ func (r *MCPServerReconciler) updateValidationStatus(ctx context.Context, mcpSer
 
 	// Update conditions based on validation result
 	r.updateValidationConditions(mcpServer, result, hasMismatch)
+	r.updateDriftCondition(mcpServer, specChanged, drift)
 
 	// Update status with retry logic
 	if err := r.updateStatus(ctx, mcpServer); err != nil {
@@ -1377,15 +1568,23 @@ func (r *MCPServerReconciler) updateValidationStatus(ctx context.Context, mcpSer
 		return err
 	}
 
+	if len(drift) > 0 {
+		log.Info("Capability drift detected", "changes", drift)
+		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "CapabilityDrift",
+			fmt.Sprintf("MCP server changed since last validation: %s", strings.Join(drift, "; ")))
+	}
+
 	// Emit events based on validation state and attempts
-	switch state {
-	case mcpv1.ValidationStateValidated:
+	// Successful periodic re-validations are not reported to avoid an event per interval
+	switch {
+	case isRevalidation && (state == mcpv1.ValidationStateValidated || state == mcpv1.ValidationStateAuthRequired):
+	case state == mcpv1.ValidationStateValidated:
 		r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "ValidationPassed",
 			fmt.Sprintf("MCP protocol validation succeeded (transport: %s)", result.DetectedTransport))
-	case mcpv1.ValidationStateAuthRequired:
+	case state == mcpv1.ValidationStateAuthRequired:
 		r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "ValidationAuthRequired",
 			fmt.Sprintf("MCP server requires authentication (transport: %s)", result.DetectedTransport))
-	case mcpv1.ValidationStateValidating:
+	case state == mcpv1.ValidationStateValidating:
 		// Validation is still in progress (transient error or first permanent error attempt)
 		isPermanent := r.isPermanentError(result, hasMismatch)
 		errorType := "transient"
@@ -1398,7 +1597,7 @@ func (r *MCPServerReconciler) updateValidationStatus(ctx context.Context, mcpSer
 		r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "ValidationRetry",
 			fmt.Sprintf("Validation failed (attempt %d/%d, %s error), will retry: %s",
 				attempts, maxAttempts, errorType, firstErrorMsg))
-	case mcpv1.ValidationStateFailed:
+	case state == mcpv1.ValidationStateFailed:
 		// Validation failed permanently
 		isPermanent := r.isPermanentError(result, hasMismatch)
 		maxAttempts := maxValidationAttempts
@@ -1777,11 +1976,11 @@ func (r *MCPServerReconciler) maybeMarkSSEConfigApplied(ctx context.Context, mcp
 // getValidationRetryInterval calculates retry interval for failed validations using progressive backoff
 // Returns 0 if validation should not be retried (terminal states: Validated, AuthRequired, or Failed)
 func (r *MCPServerReconciler) getValidationRetryInterval(mcpServer *mcpv1.MCPServer) time.Duration {
-	// If validation passed, don't retry (no periodic validation)
+	// If validation passed, only requeue for periodic re-validation (0 when disabled)
 	if mcpServer.Status.Validation != nil &&
 		(mcpServer.Status.Validation.State == mcpv1.ValidationStateValidated ||
 			mcpServer.Status.Validation.State == mcpv1.ValidationStateAuthRequired) {
-		return 0
+		return timeUntilRevalidation(mcpServer)
 	}
 
 	// If validation failed permanently, don't retry
@@ -1838,6 +2037,9 @@ func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&networkingv1.Ingress{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Named("mcpserver").
 		Complete(r)
 }
@@ -1777,6 +1777,48 @@ var _ = Describe("MCPServer Controller", func() {
 	})
 })
 
+var _ = Describe("HPA Metrics", func() {
+	It("should emit MCP traffic metrics as Pods and External metrics", func() {
+		mcpServer := &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default"},
+			Spec: mcpv1.MCPServerSpec{
+				Image: "weather:1.0",
+				HPA: &mcpv1.MCPServerHPA{
+					Enabled:                        ptr(true),
+					TargetCPUUtilizationPercentage: ptr(int32(70)),
+					Metrics: []mcpv1.MCPServerHPAMetric{
+						{Type: mcpv1.HPAMetricActiveSSEConnections, Target: resource.MustParse("50")},
+						{Type: mcpv1.HPAMetricRequestsPerSecond, Target: resource.MustParse("20")},
+						{Type: mcpv1.HPAMetricToolCallLatencyP95, Target: resource.MustParse("500m")},
+					},
+				},
+			},
+		}
+
+		hpa := (&MCPServerReconciler{}).buildHPA(mcpServer)
+		Expect(hpa.Spec.Metrics).To(HaveLen(4))
+		Expect(hpa.Spec.Metrics[0].Type).To(Equal(autoscalingv2.ResourceMetricSourceType))
+
+		sse := hpa.Spec.Metrics[1]
+		Expect(sse.Type).To(Equal(autoscalingv2.PodsMetricSourceType))
+		Expect(sse.Pods.Metric.Name).To(Equal("mcp_sse_connections_active"))
+		Expect(sse.Pods.Target.Type).To(Equal(autoscalingv2.AverageValueMetricType))
+		Expect(sse.Pods.Target.AverageValue.String()).To(Equal("50"))
+
+		requests := hpa.Spec.Metrics[2]
+		Expect(requests.Type).To(Equal(autoscalingv2.PodsMetricSourceType))
+		Expect(requests.Pods.Metric.Name).To(Equal("mcp_requests_per_second"))
+		Expect(requests.Pods.Target.AverageValue.String()).To(Equal("20"))
+
+		latency := hpa.Spec.Metrics[3]
+		Expect(latency.Type).To(Equal(autoscalingv2.ExternalMetricSourceType))
+		Expect(latency.External.Metric.Name).To(Equal("mcp_tool_call_duration_seconds_p95"))
+		Expect(latency.External.Metric.Selector.MatchLabels).To(Equal(map[string]string{"mcpserver": "weather"}))
+		Expect(latency.External.Target.Type).To(Equal(autoscalingv2.ValueMetricType))
+		Expect(latency.External.Target.Value.String()).To(Equal("500m"))
+	})
+})
+
 // findCondition finds a condition by type in the conditions list
 //
 //nolint:unparam // condType is designed to be flexible for future test cases with other condition types
@@ -0,0 +1,264 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+	"github.com/vitorbari/mcp-operator/internal/utils"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// networkPolicyDNSRefreshInterval is how often the addresses of
+	// egress.dnsNames are resolved again
+	networkPolicyDNSRefreshInterval = 5 * time.Minute
+
+	// namespaceNameLabel is set by Kubernetes on every namespace to its name
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+)
+
+// defaultMetricsPeer selects the Prometheus pods allowed to scrape metrics
+// when metricsFrom is not set
+var defaultMetricsPeer = networkingv1.NetworkPolicyPeer{
+	NamespaceSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{namespaceNameLabel: "monitoring"},
+	},
+	PodSelector: &metav1.LabelSelector{
+		MatchLabels: map[string]string{"app.kubernetes.io/name": "prometheus"},
+	},
+}
+
+// reconcileNetworkPolicy creates the NetworkPolicy of spec.networkPolicy, or
+// removes it when no longer wanted. It returns when the addresses of the
+// egress DNS names should be resolved again.
+func (r *MCPServerReconciler) reconcileNetworkPolicy(ctx context.Context, mcpServer *mcpv1.MCPServer) (time.Duration, error) {
+	config := mcpServer.Spec.NetworkPolicy
+	if config == nil {
+		return 0, r.deleteNetworkPolicy(ctx, mcpServer)
+	}
+
+	var addresses []string
+	if config.Egress != nil {
+		var err error
+		if addresses, err = r.resolveEgressAddresses(ctx, mcpServer); err != nil {
+			return 0, err
+		}
+	}
+
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+	}
+	desired := r.buildNetworkPolicy(mcpServer, addresses)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, networkPolicy, func() error {
+			if err := controllerutil.SetControllerReference(mcpServer, networkPolicy, r.Scheme); err != nil {
+				return err
+			}
+			networkPolicy.Labels = desired.Labels
+			networkPolicy.Spec = desired.Spec
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to reconcile NetworkPolicy: %w", err)
+	}
+
+	if config.Egress != nil && len(config.Egress.DNSNames) > 0 {
+		return networkPolicyDNSRefreshInterval, nil
+	}
+	return 0, nil
+}
+
+// resolveEgressAddresses returns the sorted addresses of egress.dnsNames.
+// Names that do not exist are skipped with a warning event, other lookup
+// failures are returned, so a transient DNS error does not cut the server off.
+func (r *MCPServerReconciler) resolveEgressAddresses(ctx context.Context, mcpServer *mcpv1.MCPServer) ([]string, error) {
+	lookupHost := r.lookupHost
+	if lookupHost == nil {
+		lookupHost = net.DefaultResolver.LookupHost
+	}
+
+	var addresses []string
+	for _, name := range mcpServer.Spec.NetworkPolicy.Egress.DNSNames {
+		resolved, err := lookupHost(ctx, name)
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "EgressDNSNameNotFound",
+				fmt.Sprintf("Egress DNS name %s does not resolve, no traffic to it is allowed", name))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve egress DNS name %s: %w", name, err)
+		}
+		addresses = append(addresses, resolved...)
+	}
+
+	// Sort so that resolvers answering in a different order do not update the policy
+	slices.Sort(addresses)
+	return slices.Compact(addresses), nil
+}
+
+// buildNetworkPolicy builds the NetworkPolicy of the MCPServer, allowing egress
+// to the given resolved addresses besides the configured CIDRs
+func (r *MCPServerReconciler) buildNetworkPolicy(mcpServer *mcpv1.MCPServer, addresses []string) *networkingv1.NetworkPolicy {
+	config := mcpServer.Spec.NetworkPolicy
+	tcp := corev1.ProtocolTCP
+	servicePort := intstr.FromInt32(transport.GetServicePort(mcpServer))
+
+	clients := []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+	if config.Ingress != nil && len(config.Ingress.From) > 0 {
+		clients = config.Ingress.From
+	}
+	clients = append(slices.Clone(clients),
+		networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{MatchLabels: sessionRouterSelectorLabels(mcpServer)},
+		},
+		r.operatorPeer(),
+	)
+	if scaleToZeroEnabled(mcpServer) {
+		// The activator forwards the requests that wake the server up
+		clients = append(clients, networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{MatchLabels: transport.ActivatorSelectorLabels(mcpServer)},
+		})
+	}
+
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": mcpServer.Name}},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{
+			From:  clients,
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &servicePort}},
+		}},
+	}
+
+	if mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.Enabled {
+		metricsPort := intstr.FromInt32(mcpv1.DefaultMetricsPort)
+		if mcpServer.Spec.Metrics.Port != 0 {
+			metricsPort = intstr.FromInt32(mcpServer.Spec.Metrics.Port)
+		}
+		scrapers := config.MetricsFrom
+		if len(scrapers) == 0 {
+			scrapers = []networkingv1.NetworkPolicyPeer{defaultMetricsPeer}
+		}
+		// The operator reads the sidecar metrics for canary analysis, the metrics
+		// adapter and scale-to-zero
+		spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  append(slices.Clone(scrapers), r.operatorPeer()),
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &metricsPort}},
+		})
+	}
+
+	if config.Egress != nil {
+		spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		spec.Egress = []networkingv1.NetworkPolicyEgressRule{dnsEgressRule()}
+
+		var destinations []networkingv1.NetworkPolicyPeer
+		for _, cidr := range config.Egress.CIDRs {
+			destinations = append(destinations, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+		for _, address := range addresses {
+			destinations = append(destinations, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: hostCIDR(address)},
+			})
+		}
+		if len(destinations) > 0 {
+			spec.Egress = append(spec.Egress, networkingv1.NetworkPolicyEgressRule{To: destinations})
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mcpServer.Name,
+			Namespace: mcpServer.Namespace,
+			Labels:    utils.BuildStandardLabels(mcpServer),
+		},
+		Spec: spec,
+	}
+}
+
+// operatorPeer selects the operator pods, which connect to the server to
+// validate it. Without a known operator namespace, operator pods of any
+// namespace are selected.
+func (r *MCPServerReconciler) operatorPeer() networkingv1.NetworkPolicyPeer {
+	namespaces := &metav1.LabelSelector{}
+	if r.OperatorNamespace != "" {
+		namespaces.MatchLabels = map[string]string{namespaceNameLabel: r.OperatorNamespace}
+	}
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: namespaces,
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"control-plane": "controller-manager"},
+		},
+	}
+}
+
+// dnsEgressRule allows DNS queries to kube-dns
+func dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	port := intstr.FromInt32(53)
+	return networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{namespaceNameLabel: "kube-system"},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"k8s-app": "kube-dns"},
+			},
+		}},
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &udp, Port: &port},
+			{Protocol: &tcp, Port: &port},
+		},
+	}
+}
+
+// hostCIDR returns the CIDR matching a single IPv4 or IPv6 address
+func hostCIDR(address string) string {
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		return address + "/128"
+	}
+	return address + "/32"
+}
+
+// deleteNetworkPolicy removes the NetworkPolicy of the MCPServer, if any
+func (r *MCPServerReconciler) deleteNetworkPolicy(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	networkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: mcpServer.Name, Namespace: mcpServer.Namespace},
+	}
+	return client.IgnoreNotFound(r.deleteIfOwned(ctx, mcpServer, networkPolicy))
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+var _ = Describe("NetworkPolicy", func() {
+	var (
+		ctx        context.Context
+		reconciler *MCPServerReconciler
+		recorder   *record.FakeRecorder
+		mcpServer  *mcpv1.MCPServer
+		addresses  map[string][]string
+	)
+
+	key := types.NamespacedName{Name: "weather", Namespace: "default"}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "weather-uid"},
+			Spec: mcpv1.MCPServerSpec{
+				Image: "weather-server:latest",
+				Transport: &mcpv1.MCPServerTransport{
+					Type: mcpv1.MCPTransportHTTP,
+					Config: &mcpv1.MCPTransportConfigDetails{
+						HTTP: &mcpv1.MCPHTTPTransportConfig{Port: 3001},
+					},
+				},
+				NetworkPolicy: &mcpv1.NetworkPolicyConfig{},
+			},
+		}
+		addresses = map[string][]string{
+			"api.example.com": {"203.0.113.20", "203.0.113.10", "2001:db8::10"},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &MCPServerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(runtimeScheme).
+				WithObjects(mcpServer).
+				Build(),
+			Scheme:            runtimeScheme,
+			Recorder:          recorder,
+			OperatorNamespace: "mcp-operator-system",
+			lookupHost: func(_ context.Context, host string) ([]string, error) {
+				if resolved, ok := addresses[host]; ok {
+					return resolved, nil
+				}
+				return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+			},
+		}
+	})
+
+	It("should admit only the namespace, the router and the operator by default", func() {
+		requeue, err := reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeue).To(BeZero())
+
+		networkPolicy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, key, networkPolicy)).To(Succeed())
+		Expect(networkPolicy.OwnerReferences).To(HaveLen(1))
+		Expect(networkPolicy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{"app": "weather"}))
+		Expect(networkPolicy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+		Expect(networkPolicy.Spec.Egress).To(BeEmpty())
+
+		Expect(networkPolicy.Spec.Ingress).To(HaveLen(1))
+		rule := networkPolicy.Spec.Ingress[0]
+		Expect(rule.Ports).To(HaveLen(1))
+		Expect(*rule.Ports[0].Port).To(Equal(intstr.FromInt32(3001)))
+		Expect(rule.From).To(HaveLen(3))
+		Expect(rule.From[0]).To(Equal(networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}}))
+		Expect(rule.From[1].PodSelector.MatchLabels).To(Equal(sessionRouterSelectorLabels(mcpServer)))
+		Expect(rule.From[2].NamespaceSelector.MatchLabels).To(Equal(map[string]string{
+			namespaceNameLabel: "mcp-operator-system",
+		}))
+	})
+
+	It("should admit the selected clients on the sidecar port and Prometheus and the operator on the metrics port", func() {
+		clients := networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "agents"}},
+		}
+		mcpServer.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true, Port: 9191}
+		mcpServer.Spec.NetworkPolicy.Ingress = &mcpv1.NetworkPolicyIngress{From: []networkingv1.NetworkPolicyPeer{clients}}
+
+		_, err := reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+
+		networkPolicy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, key, networkPolicy)).To(Succeed())
+		Expect(networkPolicy.Spec.Ingress).To(HaveLen(2))
+
+		rule := networkPolicy.Spec.Ingress[0]
+		Expect(*rule.Ports[0].Port).To(Equal(intstr.FromInt32(mcpv1.DefaultSidecarPort)))
+		Expect(rule.From).To(HaveLen(3))
+		Expect(rule.From[0]).To(Equal(clients))
+
+		metrics := networkPolicy.Spec.Ingress[1]
+		Expect(*metrics.Ports[0].Port).To(Equal(intstr.FromInt32(9191)))
+		Expect(metrics.From).To(Equal([]networkingv1.NetworkPolicyPeer{defaultMetricsPeer, reconciler.operatorPeer()}))
+	})
+
+	It("should restrict egress to the listed CIDRs and resolved DNS names", func() {
+		mcpServer.Spec.NetworkPolicy.Egress = &mcpv1.NetworkPolicyEgress{
+			CIDRs:    []string{"10.20.0.0/16"},
+			DNSNames: []string{"api.example.com", "gone.example.com"},
+		}
+
+		requeue, err := reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeue).To(Equal(networkPolicyDNSRefreshInterval))
+		Expect(recorder.Events).To(Receive(ContainSubstring("EgressDNSNameNotFound")))
+
+		networkPolicy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, key, networkPolicy)).To(Succeed())
+		Expect(networkPolicy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+		Expect(networkPolicy.Spec.Egress).To(HaveLen(2))
+		Expect(networkPolicy.Spec.Egress[0]).To(Equal(dnsEgressRule()))
+
+		var cidrs []string
+		for _, peer := range networkPolicy.Spec.Egress[1].To {
+			cidrs = append(cidrs, peer.IPBlock.CIDR)
+		}
+		Expect(cidrs).To(Equal([]string{"10.20.0.0/16", "2001:db8::10/128", "203.0.113.10/32", "203.0.113.20/32"}))
+	})
+
+	It("should keep the policy when DNS resolution fails", func() {
+		mcpServer.Spec.NetworkPolicy.Egress = &mcpv1.NetworkPolicyEgress{DNSNames: []string{"api.example.com"}}
+		_, err := reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+
+		reconciler.lookupHost = func(_ context.Context, host string) ([]string, error) {
+			return nil, &net.DNSError{Err: "i/o timeout", Name: host, IsTimeout: true}
+		}
+		_, err = reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).To(MatchError(ContainSubstring("failed to resolve egress DNS name api.example.com")))
+
+		networkPolicy := &networkingv1.NetworkPolicy{}
+		Expect(reconciler.Get(ctx, key, networkPolicy)).To(Succeed())
+		Expect(networkPolicy.Spec.Egress).To(HaveLen(2))
+	})
+
+	It("should remove the policy when networkPolicy is dropped", func() {
+		_, err := reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+
+		mcpServer.Spec.NetworkPolicy = nil
+		_, err = reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, key, &networkingv1.NetworkPolicy{}))).To(BeTrue())
+
+		By("Leaving a policy the operator does not own alone")
+		Expect(reconciler.Create(ctx, &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		})).To(Succeed())
+		_, err = reconciler.reconcileNetworkPolicy(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.Get(ctx, key, &networkingv1.NetworkPolicy{})).To(Succeed())
+	})
+})
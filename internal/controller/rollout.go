@@ -0,0 +1,660 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+	"github.com/vitorbari/mcp-operator/internal/utils"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+// Canary pods only join the server Service once the operator sets their
+// readiness gate, which needs write access to the pod status.
+// +kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;update;patch
+
+const (
+	// canaryTrackLabel tells canary pods apart from stable ones. Canary pods
+	// also carry the "app" label, so the server Service selects them once ready,
+	// while the stable Deployment's selector excludes them.
+	canaryTrackLabel = utils.CanaryTrackLabel
+
+	// canaryReadinessGate keeps canary pods out of the server Service until
+	// they passed validation
+	canaryReadinessGate corev1.PodConditionType = "mcp.mcp-operator.io/canary-validated"
+
+	// canaryPollInterval is how often a rollout in progress is checked
+	canaryPollInterval = 15 * time.Second
+
+	// canaryProgressDeadline is how long the canary pods may take to become
+	// ready or to answer validation before the canary is rolled back
+	canaryProgressDeadline = 10 * time.Minute
+
+	// canaryMinRequests is the number of requests the canary must have served
+	// before its error rate is compared with the threshold
+	canaryMinRequests = 20
+)
+
+// defaultCanarySteps are the steps of a canary rollout without explicit steps
+var defaultCanarySteps = []mcpv1.CanaryStep{
+	{Weight: 25, Pause: &metav1.Duration{Duration: 5 * time.Minute}},
+	{Weight: 50, Pause: &metav1.Duration{Duration: 5 * time.Minute}},
+}
+
+// canaryName is the name of the canary Deployment
+func canaryName(mcpServer *mcpv1.MCPServer) string {
+	return mcpServer.Name + "-canary"
+}
+
+// canaryConfig returns the canary configuration, which may be omitted
+func canaryConfig(mcpServer *mcpv1.MCPServer) *mcpv1.CanaryConfig {
+	if mcpServer.Spec.Rollout.Canary != nil {
+		return mcpServer.Spec.Rollout.Canary
+	}
+	return &mcpv1.CanaryConfig{}
+}
+
+// canarySteps returns the configured canary steps, or the defaults
+func canarySteps(mcpServer *mcpv1.MCPServer) []mcpv1.CanaryStep {
+	if steps := canaryConfig(mcpServer).Steps; len(steps) > 0 {
+		return steps
+	}
+	return defaultCanarySteps
+}
+
+// canaryReplicas returns the number of canary pods that makes up the given
+// percentage of the pods next to the stable ones
+func canaryReplicas(stableReplicas, weight int32) int32 {
+	if stableReplicas < 1 {
+		return 1
+	}
+	replicas := (stableReplicas*weight + (100 - weight) - 1) / (100 - weight)
+	return max(replicas, 1)
+}
+
+// reconcileRollout drives a canary rollout of pod template changes. The
+// transport managers keep the stable Deployment on its revision meanwhile, and
+// move it to the canary revision once the rollout is promoting. It returns how
+// soon the rollout needs another look, or zero when no rollout is in progress.
+func (r *MCPServerReconciler) reconcileRollout(ctx context.Context, mcpServer *mcpv1.MCPServer) (time.Duration, error) {
+	if !transport.IsCanaryRollout(mcpServer) {
+		if err := r.deleteCanary(ctx, mcpServer); err != nil {
+			return 0, err
+		}
+		if mcpServer.Status.Rollout != nil {
+			mcpServer.Status.Rollout = nil
+			return 0, r.updateStatus(ctx, mcpServer)
+		}
+		return 0, nil
+	}
+
+	stable := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, stable); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	manager, err := r.TransportFactory.GetManagerForMCPServer(mcpServer)
+	if err != nil {
+		return 0, err
+	}
+	desired := manager.DesiredDeployment(mcpServer)
+
+	previous := mcpServer.Status.Rollout.DeepCopy()
+	if mcpServer.Status.Rollout == nil {
+		mcpServer.Status.Rollout = &mcpv1.RolloutStatus{}
+	}
+
+	requeueAfter, err := r.progressRollout(ctx, mcpServer, stable, desired)
+	if err != nil {
+		return 0, err
+	}
+
+	if !reflect.DeepEqual(previous, mcpServer.Status.Rollout) {
+		if err := r.updateStatus(ctx, mcpServer); err != nil {
+			return 0, err
+		}
+	}
+	return requeueAfter, nil
+}
+
+// progressRollout moves the rollout status one step forward
+//
+//nolint:gocyclo // The rollout state machine reads best in one place
+func (r *MCPServerReconciler) progressRollout(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	stable, desired *appsv1.Deployment,
+) (time.Duration, error) {
+	rollout := mcpServer.Status.Rollout
+	desiredRevision := transport.TemplateHash(&desired.Spec.Template)
+	rollout.StableRevision = stable.Annotations[transport.TemplateHashAnnotation]
+
+	// Nothing to roll out, or a promoted revision reached the stable Deployment
+	if desiredRevision == rollout.StableRevision {
+		switch {
+		case rollout.Phase == mcpv1.RolloutPhasePromoting && rollout.CanaryRevision == desiredRevision:
+			if !deploymentRolledOut(stable) {
+				rollout.Message = "Updating the stable Deployment to the canary revision"
+				return canaryPollInterval, nil
+			}
+			rollout.Phase = mcpv1.RolloutPhaseSucceeded
+			rollout.CanaryWeight = 0
+			rollout.Message = fmt.Sprintf("Revision %s was promoted", desiredRevision)
+			r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "RolloutSucceeded", rollout.Message)
+			r.setRolloutCondition(mcpServer, corev1.ConditionTrue, "Promoted", rollout.Message)
+		case rollout.Phase == mcpv1.RolloutPhaseProgressing:
+			rollout.Phase = mcpv1.RolloutPhaseAborted
+			rollout.CanaryWeight = 0
+			rollout.Message = "The spec returned to the stable revision"
+			r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "RolloutAborted",
+				fmt.Sprintf("Canary of revision %s removed: %s", rollout.CanaryRevision, rollout.Message))
+		}
+		return 0, r.deleteCanary(ctx, mcpServer)
+	}
+
+	// A rolled back revision is not tried again until the spec changes
+	if rollout.CanaryRevision == desiredRevision && rollout.Phase == mcpv1.RolloutPhaseRolledBack {
+		return 0, r.deleteCanary(ctx, mcpServer)
+	}
+
+	if rollout.CanaryRevision != desiredRevision ||
+		(rollout.Phase != mcpv1.RolloutPhaseProgressing && rollout.Phase != mcpv1.RolloutPhasePromoting) {
+		now := metav1.Now()
+		*rollout = mcpv1.RolloutStatus{
+			Phase:          mcpv1.RolloutPhaseProgressing,
+			StableRevision: rollout.StableRevision,
+			CanaryRevision: desiredRevision,
+			StartTime:      &now,
+			StepStartTime:  &now,
+			Message:        "Waiting for the canary pods",
+		}
+		r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "RolloutStarted",
+			fmt.Sprintf("Rolling out revision %s through a canary", desiredRevision))
+		r.setRolloutCondition(mcpServer, corev1.ConditionTrue, "CanaryProgressing",
+			fmt.Sprintf("Rolling out revision %s", desiredRevision))
+	}
+
+	if rollout.Phase == mcpv1.RolloutPhasePromoting {
+		// The transport manager updates the stable Deployment; keep the canary
+		// serving until it is rolled out
+		return canaryPollInterval, nil
+	}
+
+	steps := canarySteps(mcpServer)
+	step := steps[min(int(rollout.Step), len(steps)-1)]
+
+	stableReplicas := int32(1)
+	if stable.Spec.Replicas != nil {
+		stableReplicas = *stable.Spec.Replicas
+	}
+
+	// A single canary pod is validated before the canary takes any traffic
+	replicas := int32(1)
+	if rollout.CanaryValidated {
+		replicas = canaryReplicas(stableReplicas, step.Weight)
+	}
+	if err := r.reconcileCanaryDeployment(ctx, mcpServer, desired, desiredRevision, replicas); err != nil {
+		return 0, err
+	}
+
+	pods, err := r.listCanaryPods(ctx, mcpServer, desiredRevision)
+	if err != nil {
+		return 0, err
+	}
+
+	if !rollout.CanaryValidated {
+		var candidate *corev1.Pod
+		for i := range pods {
+			if podConditionTrue(&pods[i], corev1.ContainersReady) && pods[i].Status.PodIP != "" {
+				candidate = &pods[i]
+				break
+			}
+		}
+		if candidate == nil {
+			if time.Since(rollout.StartTime.Time) > canaryProgressDeadline {
+				return 0, r.rollBack(ctx, mcpServer, "ProgressDeadlineExceeded",
+					fmt.Sprintf("The canary pods did not become ready within %s", canaryProgressDeadline))
+			}
+			rollout.Message = "Waiting for the canary pods"
+			return canaryPollInterval, nil
+		}
+
+		result := r.validateCanary(ctx, mcpServer, candidate)
+		if result.ProtocolVersion == "" && !result.IsCompliant() {
+			// The canary was never reached; retry until the deadline
+			message := fmt.Sprintf("The canary could not be validated: %s", strings.Join(result.ErrorMessages(), "; "))
+			if time.Since(rollout.StartTime.Time) > canaryProgressDeadline {
+				return 0, r.rollBack(ctx, mcpServer, "ValidationFailed", message)
+			}
+			rollout.Message = message
+			return canaryPollInterval, nil
+		}
+		if !result.IsCompliant() {
+			return 0, r.rollBack(ctx, mcpServer, "ValidationFailed",
+				fmt.Sprintf("The canary failed validation: %s", strings.Join(result.ErrorMessages(), "; ")))
+		}
+
+		stableTools, known, err := r.stableTools(ctx, mcpServer)
+		if err != nil {
+			return 0, err
+		}
+		if known {
+			rollout.ToolsAdded, rollout.ToolsRemoved = diffStringSets(stableTools, result.Tools)
+			if len(rollout.ToolsRemoved) > 0 && !canaryConfig(mcpServer).AllowToolRemoval {
+				return 0, r.rollBack(ctx, mcpServer, "ToolsRemoved",
+					fmt.Sprintf("The canary no longer offers tools: %s", strings.Join(rollout.ToolsRemoved, ", ")))
+			}
+		}
+
+		now := metav1.Now()
+		rollout.CanaryValidated = true
+		rollout.StepStartTime = &now
+		r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "CanaryValidated",
+			fmt.Sprintf("Canary of revision %s passed validation", desiredRevision))
+
+		// Scale the canary to the weight of the first step
+		replicas = canaryReplicas(stableReplicas, step.Weight)
+		if err := r.reconcileCanaryDeployment(ctx, mcpServer, desired, desiredRevision, replicas); err != nil {
+			return 0, err
+		}
+	}
+
+	// Let the validated canary pods join the server Service
+	readyCanaries := int32(0)
+	for i := range pods {
+		if !podConditionTrue(&pods[i], corev1.ContainersReady) {
+			continue
+		}
+		if err := r.openCanaryReadinessGate(ctx, &pods[i]); err != nil {
+			return 0, err
+		}
+		readyCanaries++
+	}
+	if total := stable.Status.ReadyReplicas + readyCanaries; total > 0 {
+		rollout.CanaryWeight = readyCanaries * 100 / total
+	}
+
+	if maxErrorRate := canaryConfig(mcpServer).MaxErrorRatePercent; maxErrorRate != nil {
+		failed, total := r.canaryErrorRate(ctx, mcpServer, pods)
+		if total >= canaryMinRequests && failed*100 > total*float64(*maxErrorRate) {
+			return 0, r.rollBack(ctx, mcpServer, "ErrorRateExceeded",
+				fmt.Sprintf("%.1f%% of the %.0f requests served by the canary failed, above the %d%% threshold",
+					failed*100/total, total, *maxErrorRate))
+		}
+	}
+
+	if readyCanaries < replicas {
+		if time.Since(rollout.StepStartTime.Time) > canaryProgressDeadline {
+			return 0, r.rollBack(ctx, mcpServer, "ProgressDeadlineExceeded",
+				fmt.Sprintf("The canary did not scale to %d ready pods within %s", replicas, canaryProgressDeadline))
+		}
+		rollout.Message = fmt.Sprintf("Scaling the canary to %d pods", replicas)
+		return canaryPollInterval, nil
+	}
+
+	if step.Pause != nil {
+		if remaining := step.Pause.Duration - time.Since(rollout.StepStartTime.Time); remaining > 0 {
+			rollout.Message = fmt.Sprintf("Step %d of %d: %d%% of the pods run the canary",
+				rollout.Step+1, len(steps), rollout.CanaryWeight)
+			return min(remaining, canaryPollInterval), nil
+		}
+	}
+
+	now := metav1.Now()
+	rollout.Step++
+	rollout.StepStartTime = &now
+	if int(rollout.Step) < len(steps) {
+		rollout.Message = fmt.Sprintf("Starting step %d of %d", rollout.Step+1, len(steps))
+		return time.Second, nil
+	}
+
+	return r.promote(ctx, mcpServer)
+}
+
+// promote moves the stable Deployment to the canary revision. The canary
+// keeps serving until the stable Deployment is rolled out.
+func (r *MCPServerReconciler) promote(ctx context.Context, mcpServer *mcpv1.MCPServer) (time.Duration, error) {
+	rollout := mcpServer.Status.Rollout
+	rollout.Step = int32(len(canarySteps(mcpServer)))
+	rollout.Phase = mcpv1.RolloutPhasePromoting
+	rollout.Message = "Updating the stable Deployment to the canary revision"
+	r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "CanaryPromoted",
+		fmt.Sprintf("Promoting revision %s", rollout.CanaryRevision))
+
+	// Record the promotion first: the transport managers only update the
+	// stable Deployment for a promoting revision
+	if err := r.updateStatus(ctx, mcpServer); err != nil {
+		return 0, err
+	}
+	if err := r.reconcileTransportResources(ctx, mcpServer); err != nil {
+		return 0, err
+	}
+	return canaryPollInterval, nil
+}
+
+// rollBack removes the canary and records why
+func (r *MCPServerReconciler) rollBack(ctx context.Context, mcpServer *mcpv1.MCPServer, reason, message string) error {
+	logf.FromContext(ctx).Info("Rolling back canary", "revision", mcpServer.Status.Rollout.CanaryRevision, "reason", reason)
+
+	rollout := mcpServer.Status.Rollout
+	rollout.Phase = mcpv1.RolloutPhaseRolledBack
+	rollout.CanaryWeight = 0
+	rollout.Message = message
+	r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "RolloutRolledBack",
+		fmt.Sprintf("Revision %s rolled back: %s", rollout.CanaryRevision, message))
+	r.setRolloutCondition(mcpServer, corev1.ConditionFalse, reason, message)
+
+	return r.deleteCanary(ctx, mcpServer)
+}
+
+// setRolloutCondition sets the RolloutHealthy condition
+func (r *MCPServerReconciler) setRolloutCondition(
+	mcpServer *mcpv1.MCPServer,
+	status corev1.ConditionStatus,
+	reason, message string,
+) {
+	r.setCondition(mcpServer, mcpv1.MCPServerCondition{
+		Type:               mcpv1.MCPServerConditionRolloutHealthy,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// reconcileCanaryDeployment creates or updates the canary Deployment running
+// the desired pod template
+func (r *MCPServerReconciler) reconcileCanaryDeployment(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	desired *appsv1.Deployment,
+	revision string,
+	replicas int32,
+) error {
+	canary := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: canaryName(mcpServer), Namespace: mcpServer.Namespace},
+	}
+
+	template := desired.Spec.Template.DeepCopy()
+	template.Labels[canaryTrackLabel] = "canary"
+	metav1.SetMetaDataAnnotation(&template.ObjectMeta, transport.TemplateHashAnnotation, revision)
+	template.Spec.ReadinessGates = append(template.Spec.ReadinessGates,
+		corev1.PodReadinessGate{ConditionType: canaryReadinessGate})
+
+	labels := maps.Clone(desired.Labels)
+	labels[canaryTrackLabel] = "canary"
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, canary, func() error {
+			if err := controllerutil.SetControllerReference(mcpServer, canary, r.Scheme); err != nil {
+				return err
+			}
+			canary.Labels = labels
+			canary.Spec.Replicas = &replicas
+			canary.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{
+				"app":            mcpServer.Name,
+				canaryTrackLabel: "canary",
+			}}
+			canary.Spec.Strategy = desired.Spec.Strategy
+			canary.Spec.Template = *template
+			return nil
+		})
+		return err
+	})
+}
+
+// deleteCanary removes the canary Deployment if it exists
+func (r *MCPServerReconciler) deleteCanary(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	canary := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: canaryName(mcpServer), Namespace: mcpServer.Namespace},
+	}
+	if err := r.Delete(ctx, canary); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// listCanaryPods lists the canary pods running the given revision
+func (r *MCPServerReconciler) listCanaryPods(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	revision string,
+) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(mcpServer.Namespace), client.MatchingLabels{
+		"app":            mcpServer.Name,
+		canaryTrackLabel: "canary",
+	}); err != nil {
+		return nil, err
+	}
+
+	pods := make([]corev1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp == nil && pod.Annotations[transport.TemplateHashAnnotation] == revision {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+// openCanaryReadinessGate marks a canary pod as validated so it can become ready
+func (r *MCPServerReconciler) openCanaryReadinessGate(ctx context.Context, pod *corev1.Pod) error {
+	if podConditionTrue(pod, canaryReadinessGate) {
+		return nil
+	}
+
+	patch := client.StrategicMergeFrom(pod.DeepCopy())
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+		Type:               canaryReadinessGate,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "CanaryValidated",
+	})
+	return r.Status().Patch(ctx, pod, patch)
+}
+
+// podConditionTrue reports whether a pod condition is true
+func podConditionTrue(pod *corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// deploymentRolledOut reports whether every replica of a Deployment runs its current template
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// validateCanary validates a canary pod directly, since canary pods are not
+// behind the server Service before they pass
+func (r *MCPServerReconciler) validateCanary(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	pod *corev1.Pod,
+) *validator.ValidationResult {
+	creds, err := r.loadValidationCredentials(ctx, mcpServer)
+	if err != nil {
+		return newCredentialsFailureResult(err)
+	}
+
+	timeout := 30 * time.Second
+	endpoint := "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(transport.GetServicePort(mcpServer))))
+	v := validator.NewValidator(endpoint, validator.WithTimeout(timeout), validator.WithCredentials(creds))
+
+	opts := validator.ValidationOptions{
+		Timeout:    timeout,
+		StrictMode: r.isStrictModeEnabled(mcpServer),
+	}
+	if mcpServer.Spec.Transport != nil &&
+		mcpServer.Spec.Transport.Config != nil &&
+		mcpServer.Spec.Transport.Config.HTTP != nil {
+		opts.ConfiguredPath = mcpServer.Spec.Transport.Config.HTTP.Path
+	}
+	if mcpServer.Spec.Validation != nil {
+		opts.RequiredCapabilities = mcpServer.Spec.Validation.RequiredCapabilities
+		opts.RequiredTools = mcpServer.Spec.Validation.RequiredTools
+		opts.RequiredResources = mcpServer.Spec.Validation.RequiredResources
+		opts.RequiredPrompts = mcpServer.Spec.Validation.RequiredPrompts
+	}
+
+	result, err := v.Validate(ctx, opts)
+	if err != nil {
+		return &validator.ValidationResult{
+			Issues: []validator.ValidationIssue{{
+				Level:   validator.LevelError,
+				Message: fmt.Sprintf("Validation call failed: %v", err),
+			}},
+		}
+	}
+	return result
+}
+
+// stableTools returns the tools of the stable server from its last
+// validation, and whether they are known
+func (r *MCPServerReconciler) stableTools(ctx context.Context, mcpServer *mcpv1.MCPServer) ([]string, bool, error) {
+	if mcpServer.Status.Validation == nil || mcpServer.Status.Validation.Inventory == nil {
+		return nil, false, nil
+	}
+	inventory := mcpServer.Status.Validation.Inventory
+	if inventory.ConfigMapName == "" {
+		return inventory.Tools, true, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: inventory.ConfigMapName, Namespace: mcpServer.Namespace}, configMap)
+	if errors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	if configMap.Data["tools"] == "" {
+		return nil, true, nil
+	}
+	return strings.Split(configMap.Data["tools"], "\n"), true, nil
+}
+
+// canaryErrorRate sums the failed and total requests the sidecars of the
+// canary pods served. Pods whose metrics cannot be read are skipped.
+func (r *MCPServerReconciler) canaryErrorRate(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	pods []corev1.Pod,
+) (failed, total float64) {
+	log := logf.FromContext(ctx)
+
+	metricsPort := mcpv1.DefaultMetricsPort
+	if mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.Port != 0 {
+		metricsPort = mcpServer.Spec.Metrics.Port
+	}
+
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(metricsPort))))
+		podFailed, podTotal, err := scrapeRequestCounts(ctx, httpClient, url)
+		if err != nil {
+			log.V(1).Info("Failed to read canary metrics", "pod", pod.Name, "error", err)
+			continue
+		}
+		failed += podFailed
+		total += podTotal
+	}
+	return failed, total
+}
+
+// scrapeRequestCounts reads the request counters of a sidecar. Failed
+// requests are those answered with a 5xx status plus JSON-RPC errors.
+func scrapeRequestCounts(ctx context.Context, httpClient *http.Client, url string) (failed, total float64, err error) {
+	families, err := scrapeSidecarMetrics(ctx, httpClient, url)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if family := families["mcp_requests_total"]; family != nil {
+		for _, metric := range family.GetMetric() {
+			value := metric.GetCounter().GetValue()
+			total += value
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "status" && strings.HasPrefix(label.GetValue(), "5") {
+					failed += value
+				}
+			}
+		}
+	}
+	if family := families["mcp_request_errors_total"]; family != nil {
+		for _, metric := range family.GetMetric() {
+			failed += metric.GetCounter().GetValue()
+		}
+	}
+	return failed, total, nil
+}
+
+// scrapeSidecarMetrics reads the metrics a sidecar exposes in the Prometheus text format
+func scrapeSidecarMetrics(ctx context.Context, httpClient *http.Client, url string) (map[string]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+)
+
+var _ = Describe("Canary Rollout", func() {
+	var (
+		ctx           context.Context
+		reconciler    *MCPServerReconciler
+		mcpServer     *mcpv1.MCPServer
+		mcpHTTPServer *httptest.Server
+		toolsCalls    atomic.Int32
+	)
+
+	stableKey := types.NamespacedName{Name: "weather", Namespace: "default"}
+	canaryKey := types.NamespacedName{Name: "weather-canary", Namespace: "default"}
+
+	// startCanaryPod creates a canary pod of the current revision whose
+	// containers are ready and that answers on the test MCP server
+	startCanaryPod := func() *corev1.Pod {
+		manager, err := reconciler.TransportFactory.GetManagerForMCPServer(mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		desired := manager.DesiredDeployment(mcpServer)
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "weather-canary-abc12",
+				Namespace:   "default",
+				Labels:      map[string]string{"app": "weather", canaryTrackLabel: "canary"},
+				Annotations: map[string]string{transport.TemplateHashAnnotation: transport.TemplateHash(&desired.Spec.Template)},
+			},
+			Spec: desired.Spec.Template.Spec,
+		}
+		Expect(reconciler.Create(ctx, pod)).To(Succeed())
+		pod.Status = corev1.PodStatus{
+			PodIP:      "127.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.ContainersReady, Status: corev1.ConditionTrue}},
+		}
+		Expect(reconciler.Status().Update(ctx, pod)).To(Succeed())
+		return pod
+	}
+
+	// changeImage updates the image and reconciles the transport resources the
+	// way the main reconcile loop does
+	changeImage := func() {
+		mcpServer.Spec.Image = "weather-server:v2"
+		Expect(reconciler.reconcileTransportResources(ctx, mcpServer)).To(Succeed())
+	}
+
+	stableImage := func() string {
+		stable := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, stableKey, stable)).To(Succeed())
+		return stable.Spec.Template.Spec.Containers[0].Image
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		toolsCalls.Store(0)
+		mcpHTTPServer = newCatalogMCPServer(&toolsCalls)
+
+		serverURL, err := url.Parse(mcpHTTPServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+		port, err := strconv.Atoi(serverURL.Port())
+		Expect(err).NotTo(HaveOccurred())
+
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "weather-uid"},
+			Spec: mcpv1.MCPServerSpec{
+				Image:    "weather-server:v1",
+				Replicas: ptr(int32(3)),
+				Transport: &mcpv1.MCPServerTransport{
+					Type:     mcpv1.MCPTransportHTTP,
+					Protocol: mcpv1.MCPProtocolStreamableHTTP,
+					Config: &mcpv1.MCPTransportConfigDetails{
+						HTTP: &mcpv1.MCPHTTPTransportConfig{Port: int32(port), Path: "/mcp"},
+					},
+				},
+				Rollout: &mcpv1.RolloutConfig{
+					Strategy: mcpv1.RolloutStrategyCanary,
+					Canary: &mcpv1.CanaryConfig{
+						Steps: []mcpv1.CanaryStep{{Weight: 25}},
+					},
+				},
+			},
+			Status: mcpv1.MCPServerStatus{
+				Validation: &mcpv1.ValidationStatus{
+					State:     mcpv1.ValidationStateValidated,
+					Inventory: &mcpv1.ValidationInventory{Tools: []string{"forecast"}, ToolCount: 1},
+				},
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(runtimeScheme).
+			WithObjects(mcpServer).
+			WithStatusSubresource(&mcpv1.MCPServer{}, &appsv1.Deployment{}, &corev1.Pod{}).
+			Build()
+		reconciler = &MCPServerReconciler{
+			Client:           k8sClient,
+			Scheme:           runtimeScheme,
+			TransportFactory: transport.NewManagerFactory(k8sClient, runtimeScheme),
+			Recorder:         record.NewFakeRecorder(100),
+		}
+
+		Expect(reconciler.reconcileTransportResources(ctx, mcpServer)).To(Succeed())
+		_, err = reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		mcpHTTPServer.Close()
+	})
+
+	It("should run a new image in a canary Deployment and keep the stable one", func() {
+		Expect(errors.IsNotFound(reconciler.Get(ctx, canaryKey, &appsv1.Deployment{}))).To(BeTrue())
+
+		changeImage()
+		Expect(stableImage()).To(Equal("weather-server:v1"))
+
+		requeueAfter, err := reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(Equal(canaryPollInterval))
+
+		canary := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, canaryKey, canary)).To(Succeed())
+		Expect(*canary.Spec.Replicas).To(Equal(int32(1)))
+		Expect(canary.Spec.Template.Spec.Containers[0].Image).To(Equal("weather-server:v2"))
+		Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue("app", "weather"))
+		Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue(canaryTrackLabel, "canary"))
+		Expect(canary.Spec.Template.Spec.ReadinessGates).To(ContainElement(
+			corev1.PodReadinessGate{ConditionType: canaryReadinessGate}))
+
+		Expect(mcpServer.Status.Rollout.Phase).To(Equal(mcpv1.RolloutPhaseProgressing))
+		Expect(mcpServer.Status.Rollout.CanaryRevision).NotTo(Equal(mcpServer.Status.Rollout.StableRevision))
+		Expect(mcpServer.Status.Rollout.CanaryValidated).To(BeFalse())
+	})
+
+	It("should let a validated canary take traffic and promote it", func() {
+		changeImage()
+		_, err := reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		pod := startCanaryPod()
+
+		By("Validating the canary and opening its readiness gate")
+		_, err = reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(toolsCalls.Load()).To(BeNumerically(">", 0))
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(pod), pod)).To(Succeed())
+		Expect(podConditionTrue(pod, canaryReadinessGate)).To(BeTrue())
+
+		rollout := mcpServer.Status.Rollout
+		Expect(rollout.CanaryValidated).To(BeTrue())
+		Expect(rollout.ToolsAdded).To(Equal([]string{"alerts"}))
+		Expect(rollout.ToolsRemoved).To(BeEmpty())
+
+		By("Promoting once the last step is done")
+		_, err = reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcpServer.Status.Rollout.Phase).To(Equal(mcpv1.RolloutPhasePromoting))
+		Expect(stableImage()).To(Equal("weather-server:v2"))
+		Expect(reconciler.Get(ctx, canaryKey, &appsv1.Deployment{})).To(Succeed())
+
+		By("Removing the canary once the stable Deployment is rolled out")
+		stable := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, stableKey, stable)).To(Succeed())
+		stable.Status = appsv1.DeploymentStatus{
+			ObservedGeneration: stable.Generation,
+			Replicas:           3,
+			UpdatedReplicas:    3,
+			AvailableReplicas:  3,
+		}
+		Expect(reconciler.Status().Update(ctx, stable)).To(Succeed())
+
+		_, err = reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcpServer.Status.Rollout.Phase).To(Equal(mcpv1.RolloutPhaseSucceeded))
+		Expect(errors.IsNotFound(reconciler.Get(ctx, canaryKey, &appsv1.Deployment{}))).To(BeTrue())
+	})
+
+	It("should roll back a canary that no longer offers a stable tool", func() {
+		mcpServer.Status.Validation.Inventory = &mcpv1.ValidationInventory{
+			Tools: []string{"alerts", "forecast", "radar"}, ToolCount: 3,
+		}
+
+		changeImage()
+		_, err := reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		startCanaryPod()
+
+		_, err = reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+
+		rollout := mcpServer.Status.Rollout
+		Expect(rollout.Phase).To(Equal(mcpv1.RolloutPhaseRolledBack))
+		Expect(rollout.ToolsRemoved).To(Equal([]string{"radar"}))
+		Expect(rollout.Message).To(ContainSubstring("radar"))
+		Expect(errors.IsNotFound(reconciler.Get(ctx, canaryKey, &appsv1.Deployment{}))).To(BeTrue())
+
+		var condition *mcpv1.MCPServerCondition
+		for i := range mcpServer.Status.Conditions {
+			if mcpServer.Status.Conditions[i].Type == mcpv1.MCPServerConditionRolloutHealthy {
+				condition = &mcpServer.Status.Conditions[i]
+			}
+		}
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("ToolsRemoved"))
+
+		By("Keeping the stable Deployment and not retrying the revision")
+		Expect(reconciler.reconcileTransportResources(ctx, mcpServer)).To(Succeed())
+		Expect(stableImage()).To(Equal("weather-server:v1"))
+		_, err = reconciler.reconcileRollout(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, canaryKey, &appsv1.Deployment{}))).To(BeTrue())
+	})
+
+	It("should count 5xx responses and JSON-RPC errors as failed requests", func() {
+		metricsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`# TYPE mcp_requests_total counter
+mcp_requests_total{method="tools/call",status="200"} 40
+mcp_requests_total{method="tools/call",status="502"} 6
+# TYPE mcp_request_errors_total counter
+mcp_request_errors_total{code="-32603",method="tools/call"} 4
+`))
+		}))
+		defer metricsServer.Close()
+
+		failed, total, err := scrapeRequestCounts(ctx, metricsServer.Client(), metricsServer.URL+"/metrics")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(failed).To(Equal(float64(10)))
+		Expect(total).To(Equal(float64(46)))
+	})
+
+	It("should size the canary after the step weight", func() {
+		Expect(canaryReplicas(3, 25)).To(Equal(int32(1)))
+		Expect(canaryReplicas(3, 50)).To(Equal(int32(3)))
+		Expect(canaryReplicas(10, 10)).To(Equal(int32(2)))
+		Expect(canaryReplicas(0, 50)).To(Equal(int32(1)))
+	})
+})
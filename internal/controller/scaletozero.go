@@ -0,0 +1,492 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+	"github.com/vitorbari/mcp-operator/internal/utils"
+)
+
+// The activator scales the server Deployment through its scale subresource,
+// and the operator can only grant permissions it holds itself.
+// +kubebuilder:rbac:groups=apps,resources=deployments/scale,verbs=get;patch
+
+const (
+	// defaultScaleToZeroIdleTimeout is how long a server serves no request before it is scaled to zero
+	defaultScaleToZeroIdleTimeout = 15 * time.Minute
+
+	// defaultActivationTimeout is how long the activator holds a request while the server scales up
+	defaultActivationTimeout = 2 * time.Minute
+
+	// scaleToZeroPollInterval is how often the sidecars of a running server are checked for activity
+	scaleToZeroPollInterval = time.Minute
+)
+
+// scaleToZeroEnabled reports whether the MCPServer is scaled to zero once idle
+func scaleToZeroEnabled(mcpServer *mcpv1.MCPServer) bool {
+	return mcpServer.Spec.ScaleToZero != nil && mcpServer.Spec.ScaleToZero.Enabled
+}
+
+// scaleToZeroIdleTimeout returns how long the server must be idle before it is scaled to zero
+func scaleToZeroIdleTimeout(mcpServer *mcpv1.MCPServer) time.Duration {
+	if timeout := mcpServer.Spec.ScaleToZero.IdleTimeout; timeout != nil && timeout.Duration > 0 {
+		return timeout.Duration
+	}
+	return defaultScaleToZeroIdleTimeout
+}
+
+// activationReplicas is the number of replicas the activator scales the server
+// up to: the HPA minimum when autoscaled, spec.replicas otherwise
+func activationReplicas(mcpServer *mcpv1.MCPServer) int32 {
+	hpa := mcpServer.Spec.HPA
+	if hpa != nil && hpa.Enabled != nil && *hpa.Enabled {
+		if hpa.MinReplicas != nil && *hpa.MinReplicas > 0 {
+			return *hpa.MinReplicas
+		}
+		return 1
+	}
+	return max(utils.GetReplicaCount(mcpServer), 1)
+}
+
+// activatorName is the name of the Deployment, NetworkPolicy and RBAC resources of the activator
+func activatorName(mcpServer *mcpv1.MCPServer) string {
+	return mcpServer.Name + "-activator"
+}
+
+// activatorLabels are the labels set on every resource of an activator
+func activatorLabels(mcpServer *mcpv1.MCPServer) map[string]string {
+	labels := transport.ActivatorSelectorLabels(mcpServer)
+	labels["app.kubernetes.io/component"] = "activator"
+	labels["app.kubernetes.io/managed-by"] = "mcp-operator"
+	return labels
+}
+
+// reconcileScaleToZero scales the server to zero once its sidecars reported
+// no activity for the idle timeout, pointing the server Service at the
+// activator meanwhile, and points it back once the activator scaled the
+// server up. It returns when the sidecars should be checked again.
+func (r *MCPServerReconciler) reconcileScaleToZero(ctx context.Context, mcpServer *mcpv1.MCPServer) (time.Duration, error) {
+	previousStatus := mcpServer.Status.ScaleToZero.DeepCopy()
+
+	requeue, err := r.trackActivity(ctx, mcpServer)
+	if err != nil {
+		return 0, err
+	}
+
+	if reflect.DeepEqual(previousStatus, mcpServer.Status.ScaleToZero) {
+		return requeue, nil
+	}
+	return requeue, r.updateStatus(ctx, mcpServer)
+}
+
+// trackActivity records the activity of the server in its status and scales it accordingly
+func (r *MCPServerReconciler) trackActivity(ctx context.Context, mcpServer *mcpv1.MCPServer) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+
+	if !scaleToZeroEnabled(mcpServer) {
+		// Bring a server scaled to zero back before its activator goes away
+		if transport.IsScaledToZero(mcpServer) {
+			if err := r.scaleServer(ctx, mcpServer, activationReplicas(mcpServer)); err != nil {
+				return 0, err
+			}
+			if err := r.setServiceSelector(ctx, mcpServer, map[string]string{"app": mcpServer.Name}); err != nil {
+				return 0, err
+			}
+		}
+		mcpServer.Status.ScaleToZero = nil
+		return 0, r.deleteActivator(ctx, mcpServer)
+	}
+
+	if err := r.reconcileActivator(ctx, mcpServer); err != nil {
+		return 0, err
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, deployment); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+
+	if mcpServer.Status.ScaleToZero == nil {
+		mcpServer.Status.ScaleToZero = &mcpv1.ScaleToZeroStatus{}
+	}
+	status := mcpServer.Status.ScaleToZero
+	now := metav1.Now()
+
+	if status.Idle {
+		// Deployment changes trigger a reconcile, so there is nothing to poll
+		// until the activator scaled the server up and one of its pods is ready
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 || deployment.Status.ReadyReplicas == 0 {
+			return 0, nil
+		}
+
+		if err := r.setServiceSelector(ctx, mcpServer, map[string]string{"app": mcpServer.Name}); err != nil {
+			return 0, err
+		}
+		status.Idle = false
+		status.LastActivityTime = &now
+		status.ObservedRequests = 0
+
+		log.Info("MCPServer activated", "readyReplicas", deployment.Status.ReadyReplicas)
+		r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "Activated", "MCPServer scaled up from zero on a request")
+		return scaleToZeroPollInterval, nil
+	}
+
+	// A server that is still starting is not idle
+	if deployment.Status.ReadyReplicas == 0 {
+		if status.LastActivityTime == nil {
+			status.LastActivityTime = &now
+		}
+		return scaleToZeroPollInterval, nil
+	}
+
+	requests, connections, err := r.sidecarActivity(ctx, mcpServer)
+	if err != nil {
+		// Without the metrics of every pod the server cannot be known to be idle
+		log.V(1).Info("Failed to read sidecar activity", "error", err)
+		return scaleToZeroPollInterval, nil
+	}
+
+	if status.LastActivityTime == nil || int64(requests) != status.ObservedRequests || connections > 0 {
+		status.LastActivityTime = &now
+		status.ObservedRequests = int64(requests)
+	}
+
+	idleTimeout := scaleToZeroIdleTimeout(mcpServer)
+	if idle := now.Sub(status.LastActivityTime.Time); idle < idleTimeout {
+		return min(scaleToZeroPollInterval, idleTimeout-idle), nil
+	}
+
+	// The activator takes over the traffic, so it must be ready first
+	activator := &appsv1.Deployment{}
+	err = r.Get(ctx, types.NamespacedName{Name: activatorName(mcpServer), Namespace: mcpServer.Namespace}, activator)
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	}
+	if err != nil || activator.Status.ReadyReplicas == 0 {
+		log.Info("MCPServer is idle but its activator is not ready yet")
+		return scaleToZeroPollInterval, nil
+	}
+
+	// The status is saved first: until it is, reconciles would scale the server back up
+	status.Idle = true
+	if err := r.updateStatus(ctx, mcpServer); err != nil {
+		return 0, err
+	}
+	if err := r.setServiceSelector(ctx, mcpServer, transport.ActivatorSelectorLabels(mcpServer)); err != nil {
+		return 0, err
+	}
+	if err := r.scaleServer(ctx, mcpServer, 0); err != nil {
+		return 0, err
+	}
+
+	log.Info("MCPServer scaled to zero", "idleTimeout", idleTimeout)
+	r.Recorder.Event(mcpServer, corev1.EventTypeNormal, "ScaledToZero",
+		fmt.Sprintf("No requests for %s, scaled to zero", idleTimeout))
+	return 0, nil
+}
+
+// sidecarActivity sums the requests the sidecars of the server pods served
+// and the SSE connections they hold. It fails when a pod cannot be read.
+func (r *MCPServerReconciler) sidecarActivity(ctx context.Context, mcpServer *mcpv1.MCPServer) (requests, connections float64, err error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(mcpServer.Namespace),
+		client.MatchingLabels{"app": mcpServer.Name}); err != nil {
+		return 0, 0, err
+	}
+
+	metricsPort := mcpv1.DefaultMetricsPort
+	if mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.Port != 0 {
+		metricsPort = mcpServer.Spec.Metrics.Port
+	}
+
+	httpClient := &http.Client{Timeout: 5 * time.Second}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+			continue
+		}
+		url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(metricsPort))))
+		families, err := scrapeSidecarMetrics(ctx, httpClient, url)
+		if err != nil {
+			return 0, 0, fmt.Errorf("pod %s: %w", pod.Name, err)
+		}
+		if family := families["mcp_requests_total"]; family != nil {
+			for _, metric := range family.GetMetric() {
+				requests += metric.GetCounter().GetValue()
+			}
+		}
+		if family := families["mcp_sse_connections_active"]; family != nil {
+			for _, metric := range family.GetMetric() {
+				connections += metric.GetGauge().GetValue()
+			}
+		}
+	}
+	return requests, connections, nil
+}
+
+// scaleServer sets the replicas of the server Deployment
+func (r *MCPServerReconciler) scaleServer(ctx context.Context, mcpServer *mcpv1.MCPServer, replicas int32) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment := &appsv1.Deployment{}
+		if err := r.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, deployment); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == replicas {
+			return nil
+		}
+		deployment.Spec.Replicas = &replicas
+		return r.Update(ctx, deployment)
+	})
+}
+
+// setServiceSelector points the server Service at the given pods
+func (r *MCPServerReconciler) setServiceSelector(ctx context.Context, mcpServer *mcpv1.MCPServer, selector map[string]string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		service := &corev1.Service{}
+		if err := r.Get(ctx, types.NamespacedName{Name: mcpServer.Name, Namespace: mcpServer.Namespace}, service); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if maps.Equal(service.Spec.Selector, selector) {
+			return nil
+		}
+		service.Spec.Selector = selector
+		return r.Update(ctx, service)
+	})
+}
+
+// reconcileActivator creates the activator of the MCPServer. When the server
+// pods are restricted by a NetworkPolicy, the activator pods get the same
+// ingress rules, since they receive the same clients.
+func (r *MCPServerReconciler) reconcileActivator(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	name := activatorName(mcpServer)
+	labels := activatorLabels(mcpServer)
+	objectMeta := metav1.ObjectMeta{Name: name, Namespace: mcpServer.Namespace}
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: objectMeta}
+	role := &rbacv1.Role{ObjectMeta: objectMeta}
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: objectMeta}
+	deployment := &appsv1.Deployment{ObjectMeta: objectMeta}
+
+	mutations := []struct {
+		object client.Object
+		mutate func()
+	}{
+		{serviceAccount, func() {}},
+		{role, func() {
+			role.Rules = []rbacv1.PolicyRule{
+				{
+					APIGroups:     []string{"apps"},
+					Resources:     []string{"deployments/scale"},
+					ResourceNames: []string{mcpServer.Name},
+					Verbs:         []string{"get", "patch"},
+				},
+				{
+					APIGroups: []string{""},
+					Resources: []string{"pods"},
+					Verbs:     []string{"list"},
+				},
+			}
+		}},
+		{roleBinding, func() {
+			// The role reference cannot change once created, and never does
+			roleBinding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name}
+			roleBinding.Subjects = []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: mcpServer.Namespace}}
+		}},
+		{deployment, func() {
+			deployment.Spec = buildActivatorDeploymentSpec(mcpServer)
+		}},
+	}
+
+	if mcpServer.Spec.NetworkPolicy != nil {
+		networkPolicy := &networkingv1.NetworkPolicy{ObjectMeta: objectMeta}
+		mutations = append(mutations, struct {
+			object client.Object
+			mutate func()
+		}{networkPolicy, func() {
+			networkPolicy.Spec = r.buildActivatorNetworkPolicySpec(mcpServer)
+		}})
+	} else if err := r.Delete(ctx, &networkingv1.NetworkPolicy{ObjectMeta: objectMeta}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	for _, m := range mutations {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			_, err := controllerutil.CreateOrUpdate(ctx, r.Client, m.object, func() error {
+				if err := controllerutil.SetControllerReference(mcpServer, m.object, r.Scheme); err != nil {
+					return err
+				}
+				m.object.SetLabels(labels)
+				m.mutate()
+				return nil
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile activator %T: %w", m.object, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteActivator removes the resources of an activator that is no longer enabled
+func (r *MCPServerReconciler) deleteActivator(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	objectMeta := metav1.ObjectMeta{Name: activatorName(mcpServer), Namespace: mcpServer.Namespace}
+
+	for _, object := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: objectMeta},
+		&networkingv1.NetworkPolicy{ObjectMeta: objectMeta},
+		&rbacv1.RoleBinding{ObjectMeta: objectMeta},
+		&rbacv1.Role{ObjectMeta: objectMeta},
+		&corev1.ServiceAccount{ObjectMeta: objectMeta},
+	} {
+		if err := r.Delete(ctx, object); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildActivatorNetworkPolicySpec admits the clients of the server to the
+// activator pods. Egress is left open: the activator calls the Kubernetes API.
+func (r *MCPServerReconciler) buildActivatorNetworkPolicySpec(mcpServer *mcpv1.MCPServer) networkingv1.NetworkPolicySpec {
+	spec := r.buildNetworkPolicy(mcpServer, nil).Spec
+	spec.PodSelector = metav1.LabelSelector{MatchLabels: transport.ActivatorSelectorLabels(mcpServer)}
+	spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	spec.Egress = nil
+	return spec
+}
+
+// buildActivatorDeploymentSpec builds the Deployment spec of the activator.
+// It listens on the ports of the server sidecar, so the server Service can
+// be pointed at it without changing its ports.
+func buildActivatorDeploymentSpec(mcpServer *mcpv1.MCPServer) appsv1.DeploymentSpec {
+	replicas := int32(1)
+
+	image := mcpv1.DefaultSidecarImage
+	if mcpServer.Spec.Sidecar != nil && mcpServer.Spec.Sidecar.Image != "" {
+		image = mcpServer.Spec.Sidecar.Image
+	}
+
+	listenPort := transport.GetSidecarPort(mcpServer)
+	metricsPort := mcpv1.DefaultMetricsPort
+	if mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.Port != 0 {
+		metricsPort = mcpServer.Spec.Metrics.Port
+	}
+
+	activationTimeout := defaultActivationTimeout
+	if timeout := mcpServer.Spec.ScaleToZero.ActivationTimeout; timeout != nil && timeout.Duration > 0 {
+		activationTimeout = timeout.Duration
+	}
+
+	runAsNonRoot, readOnlyRootFilesystem, allowPrivilegeEscalation := true, true, false
+
+	container := corev1.Container{
+		Name:  "mcp-activator",
+		Image: image,
+		Args: []string{
+			"--mode=activator",
+			fmt.Sprintf("--listen-addr=:%d", listenPort),
+			fmt.Sprintf("--metrics-addr=:%d", metricsPort),
+			fmt.Sprintf("--activator-deployment=%s", mcpServer.Name),
+			fmt.Sprintf("--activator-namespace=%s", mcpServer.Namespace),
+			fmt.Sprintf("--activator-pod-selector=app=%s", mcpServer.Name),
+			fmt.Sprintf("--activator-target-port=%d", listenPort),
+			fmt.Sprintf("--activator-replicas=%d", activationReplicas(mcpServer)),
+			fmt.Sprintf("--activator-timeout=%s", activationTimeout),
+			"--log-level=info",
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "mcp", ContainerPort: listenPort, Protocol: corev1.ProtocolTCP},
+			{Name: "metrics", ContainerPort: metricsPort, Protocol: corev1.ProtocolTCP},
+		},
+		LivenessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(metricsPort)},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+			FailureThreshold:    3,
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/readyz", Port: intstr.FromInt32(metricsPort)},
+			},
+			InitialDelaySeconds: 2,
+			PeriodSeconds:       5,
+			TimeoutSeconds:      3,
+			FailureThreshold:    3,
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPURequest),
+				corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryRequest),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPULimit),
+				corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryLimit),
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot:             &runAsNonRoot,
+			ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+
+	return appsv1.DeploymentSpec{
+		Replicas: &replicas,
+		Selector: &metav1.LabelSelector{MatchLabels: transport.ActivatorSelectorLabels(mcpServer)},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: activatorLabels(mcpServer)},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: activatorName(mcpServer),
+				Containers:         []corev1.Container{container},
+			},
+		},
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+)
+
+var _ = Describe("Scale To Zero", func() {
+	var (
+		ctx           context.Context
+		reconciler    *MCPServerReconciler
+		mcpServer     *mcpv1.MCPServer
+		metricsServer *httptest.Server
+		requests      atomic.Int64
+	)
+
+	serverKey := types.NamespacedName{Name: "weather", Namespace: "default"}
+	activatorKey := types.NamespacedName{Name: "weather-activator", Namespace: "default"}
+
+	// setReadyReplicas reports the given number of ready replicas for a Deployment
+	setReadyReplicas := func(key types.NamespacedName, replicas int32) {
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, key, deployment)).To(Succeed())
+		deployment.Status.Replicas = replicas
+		deployment.Status.ReadyReplicas = replicas
+		deployment.Status.AvailableReplicas = replicas
+		Expect(reconciler.Status().Update(ctx, deployment)).To(Succeed())
+	}
+
+	serverReplicas := func() int32 {
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, serverKey, deployment)).To(Succeed())
+		return *deployment.Spec.Replicas
+	}
+
+	serviceSelector := func() map[string]string {
+		service := &corev1.Service{}
+		Expect(reconciler.Get(ctx, serverKey, service)).To(Succeed())
+		return service.Spec.Selector
+	}
+
+	// goIdle makes the last activity older than the idle timeout and scales the server to zero
+	goIdle := func() {
+		setReadyReplicas(activatorKey, 1)
+		past := metav1.NewTime(time.Now().Add(-20 * time.Minute))
+		mcpServer.Status.ScaleToZero.LastActivityTime = &past
+
+		_, err := reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcpServer.Status.ScaleToZero.Idle).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		requests.Store(40)
+		metricsServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, `# TYPE mcp_requests_total counter
+mcp_requests_total{method="tools/call",status="200"} %d
+# TYPE mcp_sse_connections_active gauge
+mcp_sse_connections_active 0
+`, requests.Load())
+		}))
+
+		metricsURL, err := url.Parse(metricsServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+		metricsPort, err := strconv.Atoi(metricsURL.Port())
+		Expect(err).NotTo(HaveOccurred())
+
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "weather-uid"},
+			Spec: mcpv1.MCPServerSpec{
+				Image:    "weather-server:v1",
+				Replicas: ptr(int32(2)),
+				Transport: &mcpv1.MCPServerTransport{
+					Type:     mcpv1.MCPTransportHTTP,
+					Protocol: mcpv1.MCPProtocolStreamableHTTP,
+				},
+				Metrics: &mcpv1.MetricsConfig{Enabled: true, Port: int32(metricsPort)},
+				ScaleToZero: &mcpv1.ScaleToZeroConfig{
+					Enabled:     true,
+					IdleTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+				},
+			},
+			Status: mcpv1.MCPServerStatus{
+				Phase: mcpv1.MCPServerPhaseRunning,
+				Validation: &mcpv1.ValidationStatus{
+					State:     mcpv1.ValidationStateValidated,
+					Compliant: true,
+				},
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather-abc12", Namespace: "default", Labels: map[string]string{"app": "weather"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"},
+		}
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(runtimeScheme).
+			WithObjects(mcpServer, pod).
+			WithStatusSubresource(&mcpv1.MCPServer{}, &appsv1.Deployment{}).
+			Build()
+		reconciler = &MCPServerReconciler{
+			Client:           k8sClient,
+			Scheme:           runtimeScheme,
+			TransportFactory: transport.NewManagerFactory(k8sClient, runtimeScheme),
+			Recorder:         record.NewFakeRecorder(100),
+		}
+
+		Expect(reconciler.reconcileTransportResources(ctx, mcpServer)).To(Succeed())
+		setReadyReplicas(serverKey, 2)
+	})
+
+	AfterEach(func() {
+		metricsServer.Close()
+	})
+
+	It("should create the activator and track the activity of the server", func() {
+		requeueAfter, err := reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(Equal(scaleToZeroPollInterval))
+
+		activator := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, activatorKey, activator)).To(Succeed())
+		Expect(activator.Spec.Template.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", "mcp-activator"))
+		Expect(activator.Spec.Template.Labels).NotTo(HaveKey("app"))
+		Expect(activator.Spec.Template.Spec.ServiceAccountName).To(Equal("weather-activator"))
+		Expect(activator.Spec.Template.Spec.Containers[0].Args).To(ContainElements(
+			"--mode=activator", "--activator-deployment=weather", "--activator-replicas=2"))
+
+		role := &rbacv1.Role{}
+		Expect(reconciler.Get(ctx, activatorKey, role)).To(Succeed())
+		Expect(role.Rules[0].Resources).To(Equal([]string{"deployments/scale"}))
+		Expect(role.Rules[0].ResourceNames).To(Equal([]string{"weather"}))
+
+		status := mcpServer.Status.ScaleToZero
+		Expect(status.Idle).To(BeFalse())
+		Expect(status.LastActivityTime).NotTo(BeNil())
+		Expect(status.ObservedRequests).To(Equal(int64(40)))
+
+		By("Recording new requests as activity")
+		past := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+		status.LastActivityTime = &past
+		requests.Store(41)
+		_, err = reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcpServer.Status.ScaleToZero.ObservedRequests).To(Equal(int64(41)))
+		Expect(mcpServer.Status.ScaleToZero.LastActivityTime.Time).To(BeTemporally("~", time.Now(), 5*time.Second))
+	})
+
+	It("should scale an idle server to zero once the activator is ready", func() {
+		_, err := reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Waiting for the activator")
+		past := metav1.NewTime(time.Now().Add(-20 * time.Minute))
+		mcpServer.Status.ScaleToZero.LastActivityTime = &past
+		_, err = reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcpServer.Status.ScaleToZero.Idle).To(BeFalse())
+		Expect(serverReplicas()).To(Equal(int32(2)))
+
+		goIdle()
+		Expect(serverReplicas()).To(Equal(int32(0)))
+		Expect(serviceSelector()).To(Equal(transport.ActivatorSelectorLabels(mcpServer)))
+
+		By("Keeping the server at zero when the transport resources are reconciled")
+		Expect(reconciler.reconcileTransportResources(ctx, mcpServer)).To(Succeed())
+		Expect(serverReplicas()).To(Equal(int32(0)))
+		Expect(serviceSelector()).To(Equal(transport.ActivatorSelectorLabels(mcpServer)))
+
+		By("Reporting the Idle phase and keeping the validation result")
+		setReadyReplicas(serverKey, 0)
+		Expect(reconciler.updateMCPServerStatus(ctx, mcpServer)).To(Succeed())
+		Expect(mcpServer.Status.Phase).To(Equal(mcpv1.MCPServerPhaseIdle))
+		Expect(mcpServer.Status.Validation.State).To(Equal(mcpv1.ValidationStateValidated))
+		for _, condition := range mcpServer.Status.Conditions {
+			if condition.Type == mcpv1.MCPServerConditionReady {
+				Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("ScaledToZero"))
+			}
+		}
+	})
+
+	It("should send traffic to the server again once the activator scaled it up", func() {
+		_, err := reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		goIdle()
+		setReadyReplicas(serverKey, 0)
+
+		By("Waiting while the server is at zero")
+		requeueAfter, err := reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+		Expect(mcpServer.Status.ScaleToZero.Idle).To(BeTrue())
+
+		By("Activating once a scaled up pod is ready")
+		Expect(reconciler.scaleServer(ctx, mcpServer, 2)).To(Succeed())
+		setReadyReplicas(serverKey, 1)
+		_, err = reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mcpServer.Status.ScaleToZero.Idle).To(BeFalse())
+		Expect(serviceSelector()).To(Equal(map[string]string{"app": "weather"}))
+	})
+
+	It("should scale the server up and remove the activator when disabled", func() {
+		_, err := reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		goIdle()
+
+		mcpServer.Spec.ScaleToZero.Enabled = false
+		_, err = reconciler.reconcileScaleToZero(ctx, mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(mcpServer.Status.ScaleToZero).To(BeNil())
+		Expect(serverReplicas()).To(Equal(int32(2)))
+		Expect(serviceSelector()).To(Equal(map[string]string{"app": "weather"}))
+		Expect(errors.IsNotFound(reconciler.Get(ctx, activatorKey, &appsv1.Deployment{}))).To(BeTrue())
+		Expect(errors.IsNotFound(reconciler.Get(ctx, activatorKey, &rbacv1.Role{}))).To(BeTrue())
+	})
+})
@@ -0,0 +1,270 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+)
+
+// The router lists the EndpointSlices of the server Service, and the operator
+// can only grant permissions it holds itself.
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
+
+const (
+	// sessionRouterListenPort is the port the router serves MCP traffic on
+	sessionRouterListenPort = 8080
+
+	// sessionRouterMetricsPort is the port the router exposes metrics and health endpoints on
+	sessionRouterMetricsPort = 9090
+
+	// sessionRouterServicePortName is the name of the server Service port the router sends requests to
+	sessionRouterServicePortName = "http"
+)
+
+// sessionRoutingEnabled reports whether the MCPServer asks for a session router
+func sessionRoutingEnabled(mcpServer *mcpv1.MCPServer) bool {
+	if mcpServer.Spec.Transport == nil || mcpServer.Spec.Transport.Config == nil ||
+		mcpServer.Spec.Transport.Config.HTTP == nil {
+		return false
+	}
+	routing := mcpServer.Spec.Transport.Config.HTTP.SessionRouting
+	return routing != nil && routing.Enabled
+}
+
+// sessionRouterName is the name of the Deployment, Service and RBAC resources of the router
+func sessionRouterName(mcpServer *mcpv1.MCPServer) string {
+	return mcpServer.Name + "-router"
+}
+
+// sessionRouterSelectorLabels selects the router pods. Router pods carry no
+// "app" label, so the server Service never selects them.
+func sessionRouterSelectorLabels(mcpServer *mcpv1.MCPServer) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     "mcp-session-router",
+		"app.kubernetes.io/instance": mcpServer.Name,
+	}
+}
+
+// sessionRouterLabels are the labels set on every resource of a router
+func sessionRouterLabels(mcpServer *mcpv1.MCPServer) map[string]string {
+	labels := sessionRouterSelectorLabels(mcpServer)
+	labels["app.kubernetes.io/component"] = "session-router"
+	labels["app.kubernetes.io/managed-by"] = "mcp-operator"
+	return labels
+}
+
+// reconcileSessionRouter creates the session router of the MCPServer when
+// enabled, and removes it otherwise
+func (r *MCPServerReconciler) reconcileSessionRouter(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	if !sessionRoutingEnabled(mcpServer) {
+		return r.deleteSessionRouter(ctx, mcpServer)
+	}
+
+	name := sessionRouterName(mcpServer)
+	labels := sessionRouterLabels(mcpServer)
+	objectMeta := metav1.ObjectMeta{Name: name, Namespace: mcpServer.Namespace}
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: objectMeta}
+	role := &rbacv1.Role{ObjectMeta: objectMeta}
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: objectMeta}
+	deployment := &appsv1.Deployment{ObjectMeta: objectMeta}
+	service := &corev1.Service{ObjectMeta: objectMeta}
+
+	mutations := []struct {
+		object client.Object
+		mutate func()
+	}{
+		{serviceAccount, func() {}},
+		{role, func() {
+			role.Rules = []rbacv1.PolicyRule{{
+				APIGroups: []string{"discovery.k8s.io"},
+				Resources: []string{"endpointslices"},
+				Verbs:     []string{"get", "list", "watch"},
+			}}
+		}},
+		{roleBinding, func() {
+			// The role reference cannot change once created, and never does
+			roleBinding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name}
+			roleBinding.Subjects = []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: mcpServer.Namespace}}
+		}},
+		{deployment, func() {
+			deployment.Spec = buildSessionRouterDeploymentSpec(mcpServer)
+		}},
+		{service, func() {
+			// Only set the fields we own so the allocated ClusterIP is kept
+			service.Spec.Type = corev1.ServiceTypeClusterIP
+			service.Spec.Selector = sessionRouterSelectorLabels(mcpServer)
+			service.Spec.Ports = []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       transport.GetServicePort(mcpServer),
+					TargetPort: intstr.FromString("mcp"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+				{
+					Name:       "metrics",
+					Port:       sessionRouterMetricsPort,
+					TargetPort: intstr.FromString("metrics"),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			}
+		}},
+	}
+
+	for _, m := range mutations {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			_, err := controllerutil.CreateOrUpdate(ctx, r.Client, m.object, func() error {
+				if err := controllerutil.SetControllerReference(mcpServer, m.object, r.Scheme); err != nil {
+					return err
+				}
+				m.object.SetLabels(labels)
+				m.mutate()
+				return nil
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile session router %T: %w", m.object, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteSessionRouter removes the resources of a session router that is no longer enabled
+func (r *MCPServerReconciler) deleteSessionRouter(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	objectMeta := metav1.ObjectMeta{Name: sessionRouterName(mcpServer), Namespace: mcpServer.Namespace}
+
+	for _, object := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: objectMeta},
+		&corev1.Service{ObjectMeta: objectMeta},
+		&rbacv1.RoleBinding{ObjectMeta: objectMeta},
+		&rbacv1.Role{ObjectMeta: objectMeta},
+		&corev1.ServiceAccount{ObjectMeta: objectMeta},
+	} {
+		if err := r.Delete(ctx, object); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildSessionRouterDeploymentSpec builds the Deployment spec of the session router
+func buildSessionRouterDeploymentSpec(mcpServer *mcpv1.MCPServer) appsv1.DeploymentSpec {
+	routing := mcpServer.Spec.Transport.Config.HTTP.SessionRouting
+
+	replicas := int32(1)
+	if routing.Replicas != nil {
+		replicas = *routing.Replicas
+	}
+
+	image := mcpv1.DefaultSidecarImage
+	if mcpServer.Spec.Sidecar != nil && mcpServer.Spec.Sidecar.Image != "" {
+		image = mcpServer.Spec.Sidecar.Image
+	}
+
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPURequest),
+			corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPULimit),
+			corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryLimit),
+		},
+	}
+	if routing.Resources != nil {
+		resources = *routing.Resources
+	}
+
+	runAsNonRoot, readOnlyRootFilesystem, allowPrivilegeEscalation := true, true, false
+
+	container := corev1.Container{
+		Name:  "mcp-session-router",
+		Image: image,
+		Args: []string{
+			"--mode=session-router",
+			fmt.Sprintf("--listen-addr=:%d", sessionRouterListenPort),
+			fmt.Sprintf("--metrics-addr=:%d", sessionRouterMetricsPort),
+			fmt.Sprintf("--router-service=%s", mcpServer.Name),
+			fmt.Sprintf("--router-namespace=%s", mcpServer.Namespace),
+			fmt.Sprintf("--router-port-name=%s", sessionRouterServicePortName),
+			"--log-level=info",
+		},
+		Ports: []corev1.ContainerPort{
+			{Name: "mcp", ContainerPort: sessionRouterListenPort, Protocol: corev1.ProtocolTCP},
+			{Name: "metrics", ContainerPort: sessionRouterMetricsPort, Protocol: corev1.ProtocolTCP},
+		},
+		LivenessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(sessionRouterMetricsPort)},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+			FailureThreshold:    3,
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/readyz", Port: intstr.FromInt(sessionRouterMetricsPort)},
+			},
+			InitialDelaySeconds: 2,
+			PeriodSeconds:       5,
+			TimeoutSeconds:      3,
+			FailureThreshold:    3,
+		},
+		Resources: resources,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot:             &runAsNonRoot,
+			ReadOnlyRootFilesystem:   &readOnlyRootFilesystem,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+
+	return appsv1.DeploymentSpec{
+		Replicas: &replicas,
+		Selector: &metav1.LabelSelector{MatchLabels: sessionRouterSelectorLabels(mcpServer)},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: sessionRouterLabels(mcpServer)},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: sessionRouterName(mcpServer),
+				Containers:         []corev1.Container{container},
+			},
+		},
+	}
+}
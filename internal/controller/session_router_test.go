@@ -0,0 +1,143 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+var _ = Describe("Session Router", func() {
+	var (
+		ctx        context.Context
+		reconciler *MCPServerReconciler
+		mcpServer  *mcpv1.MCPServer
+	)
+
+	routerKey := types.NamespacedName{Name: "notes-router", Namespace: "default"}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "notes", Namespace: "default", UID: "notes-uid"},
+			Spec: mcpv1.MCPServerSpec{
+				Image:    "notes-server:latest",
+				Replicas: ptr(int32(3)),
+				Transport: &mcpv1.MCPServerTransport{
+					Type:     mcpv1.MCPTransportHTTP,
+					Protocol: mcpv1.MCPProtocolStreamableHTTP,
+					Config: &mcpv1.MCPTransportConfigDetails{
+						HTTP: &mcpv1.MCPHTTPTransportConfig{
+							Port: 3001,
+							Path: "/mcp",
+							SessionRouting: &mcpv1.SessionRoutingConfig{
+								Enabled:  true,
+								Replicas: ptr(int32(2)),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		reconciler = &MCPServerReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(runtimeScheme).
+				WithObjects(mcpServer).
+				Build(),
+			Scheme: runtimeScheme,
+		}
+	})
+
+	It("should deploy a router in front of the server pods", func() {
+		Expect(reconciler.reconcileSessionRouter(ctx, mcpServer)).To(Succeed())
+
+		By("Checking the router Deployment")
+		deployment := &appsv1.Deployment{}
+		Expect(reconciler.Get(ctx, routerKey, deployment)).To(Succeed())
+		Expect(*deployment.Spec.Replicas).To(Equal(int32(2)))
+		Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(routerKey.Name))
+		Expect(deployment.Spec.Template.Labels).NotTo(HaveKey("app"))
+		Expect(deployment.OwnerReferences).To(HaveLen(1))
+		Expect(deployment.OwnerReferences[0].Name).To(Equal("notes"))
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).To(Equal(mcpv1.DefaultSidecarImage))
+		Expect(container.Args).To(ContainElements(
+			"--mode=session-router",
+			"--router-service=notes",
+			"--router-namespace=default",
+			"--router-port-name=http",
+		))
+
+		By("Checking the router Service")
+		service := &corev1.Service{}
+		Expect(reconciler.Get(ctx, routerKey, service)).To(Succeed())
+		Expect(service.Spec.Selector).To(Equal(deployment.Spec.Selector.MatchLabels))
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32(3001)))
+		Expect(service.Spec.Ports[0].TargetPort.StrVal).To(Equal("mcp"))
+
+		By("Checking the router may list EndpointSlices")
+		role := &rbacv1.Role{}
+		Expect(reconciler.Get(ctx, routerKey, role)).To(Succeed())
+		Expect(role.Rules).To(HaveLen(1))
+		Expect(role.Rules[0].APIGroups).To(Equal([]string{"discovery.k8s.io"}))
+		Expect(role.Rules[0].Resources).To(Equal([]string{"endpointslices"}))
+
+		roleBinding := &rbacv1.RoleBinding{}
+		Expect(reconciler.Get(ctx, routerKey, roleBinding)).To(Succeed())
+		Expect(roleBinding.RoleRef.Name).To(Equal(routerKey.Name))
+		Expect(roleBinding.Subjects).To(ConsistOf(rbacv1.Subject{
+			Kind: rbacv1.ServiceAccountKind, Name: routerKey.Name, Namespace: "default",
+		}))
+		Expect(reconciler.Get(ctx, routerKey, &corev1.ServiceAccount{})).To(Succeed())
+	})
+
+	It("should remove the router when session routing is disabled", func() {
+		Expect(reconciler.reconcileSessionRouter(ctx, mcpServer)).To(Succeed())
+
+		mcpServer.Spec.Transport.Config.HTTP.SessionRouting.Enabled = false
+		Expect(reconciler.reconcileSessionRouter(ctx, mcpServer)).To(Succeed())
+
+		for _, object := range []client.Object{
+			&appsv1.Deployment{}, &corev1.Service{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}, &corev1.ServiceAccount{},
+		} {
+			Expect(errors.IsNotFound(reconciler.Get(ctx, routerKey, object))).To(BeTrue(), "%T was not removed", object)
+		}
+
+		By("Tolerating a router that is already gone")
+		Expect(reconciler.reconcileSessionRouter(ctx, mcpServer)).To(Succeed())
+	})
+})
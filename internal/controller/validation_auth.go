@@ -0,0 +1,128 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+// loadValidationCredentials builds validator credentials from the Secret referenced
+// by spec.validation.auth. Returns nil credentials when auth is not configured.
+func (r *MCPServerReconciler) loadValidationCredentials(ctx context.Context, mcpServer *mcpv1.MCPServer) (*validator.Credentials, error) {
+	if mcpServer.Spec.Validation == nil || mcpServer.Spec.Validation.Auth == nil {
+		return nil, nil
+	}
+	auth := mcpServer.Spec.Validation.Auth
+
+	if auth.BearerTokenKey == "" && len(auth.Headers) == 0 && auth.OAuth2 == nil {
+		return nil, fmt.Errorf("validation.auth must set at least one of bearerTokenKey, headers or oauth2")
+	}
+
+	// Read uncached, so that validating one server does not cache every Secret of the cluster
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, client.ObjectKey{
+		Name:      auth.SecretRef.Name,
+		Namespace: mcpServer.Namespace,
+	}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s: %w", auth.SecretRef.Name, err)
+	}
+
+	creds := &validator.Credentials{}
+
+	if auth.BearerTokenKey != "" {
+		token, err := getSecretValue(secret, auth.BearerTokenKey)
+		if err != nil {
+			return nil, err
+		}
+		creds.BearerToken = token
+	}
+
+	if len(auth.Headers) > 0 {
+		creds.Headers = make(map[string]string, len(auth.Headers))
+		for header, key := range auth.Headers {
+			value, err := getSecretValue(secret, key)
+			if err != nil {
+				return nil, err
+			}
+			creds.Headers[header] = value
+		}
+	}
+
+	if auth.OAuth2 != nil {
+		clientIDKey := auth.OAuth2.ClientIDKey
+		if clientIDKey == "" {
+			clientIDKey = "client_id"
+		}
+		clientSecretKey := auth.OAuth2.ClientSecretKey
+		if clientSecretKey == "" {
+			clientSecretKey = "client_secret"
+		}
+
+		clientID, err := getSecretValue(secret, clientIDKey)
+		if err != nil {
+			return nil, err
+		}
+		clientSecret, err := getSecretValue(secret, clientSecretKey)
+		if err != nil {
+			return nil, err
+		}
+
+		creds.OAuth2 = &validator.OAuth2ClientCredentials{
+			TokenURL:     auth.OAuth2.TokenURL,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			Scopes:       auth.OAuth2.Scopes,
+		}
+	}
+
+	return creds, nil
+}
+
+// getSecretValue returns the value of a Secret key, failing if the key is missing or empty
+func getSecretValue(secret *corev1.Secret, key string) (string, error) {
+	value, ok := secret.Data[key]
+	if !ok || len(value) == 0 {
+		return "", fmt.Errorf("key %q not found in Secret %s", key, secret.Name)
+	}
+	return string(value), nil
+}
+
+// newCredentialsFailureResult reports credentials that could not be loaded as a failed
+// validation, so the problem is visible in status and retried like other failures
+func newCredentialsFailureResult(err error) *validator.ValidationResult {
+	return &validator.ValidationResult{
+		Success: false,
+		Issues: []validator.ValidationIssue{
+			{
+				Level:   validator.LevelError,
+				Code:    validator.CodeAuthFailed,
+				Message: fmt.Sprintf("Failed to load validation credentials: %v", err),
+			},
+		},
+	}
+}
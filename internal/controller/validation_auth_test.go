@@ -0,0 +1,149 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+var _ = Describe("Validation credentials", func() {
+	var (
+		reconciler *MCPServerReconciler
+		mcpServer  *mcpv1.MCPServer
+		secret     *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mcp-credentials",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"token":         []byte("secret-token"),
+				"api-key":       []byte("key123"),
+				"client_id":     []byte("validator"),
+				"client_secret": []byte("s3cret"),
+			},
+		}
+
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "protected-server",
+				Namespace: "default",
+			},
+			Spec: mcpv1.MCPServerSpec{
+				Image: "test-server:latest",
+				Validation: &mcpv1.ValidationSpec{
+					Auth: &mcpv1.ValidationAuth{
+						SecretRef: corev1.LocalObjectReference{Name: "mcp-credentials"},
+					},
+				},
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		reconciler = &MCPServerReconciler{
+			Client: fake.NewClientBuilder().WithScheme(runtimeScheme).WithObjects(secret).Build(),
+			Scheme: runtimeScheme,
+		}
+	})
+
+	It("should return no credentials when auth is not configured", func() {
+		mcpServer.Spec.Validation.Auth = nil
+		creds, err := reconciler.loadValidationCredentials(context.Background(), mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds).To(BeNil())
+	})
+
+	It("should load a bearer token and headers from the Secret", func() {
+		mcpServer.Spec.Validation.Auth.BearerTokenKey = "token"
+		mcpServer.Spec.Validation.Auth.Headers = map[string]string{"X-API-Key": "api-key"}
+
+		creds, err := reconciler.loadValidationCredentials(context.Background(), mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.BearerToken).To(Equal("secret-token"))
+		Expect(creds.Headers).To(HaveKeyWithValue("X-API-Key", "key123"))
+		Expect(creds.OAuth2).To(BeNil())
+	})
+
+	It("should read the Secret through the API reader when one is set", func() {
+		mcpServer.Spec.Validation.Auth.BearerTokenKey = "token"
+		reconciler.APIReader = reconciler.Client
+		reconciler.Client = fake.NewClientBuilder().WithScheme(reconciler.Scheme).Build()
+
+		creds, err := reconciler.loadValidationCredentials(context.Background(), mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.BearerToken).To(Equal("secret-token"))
+	})
+
+	It("should load OAuth2 client credentials using the default keys", func() {
+		mcpServer.Spec.Validation.Auth.OAuth2 = &mcpv1.ValidationOAuth2{
+			TokenURL: "https://auth.example.com/token",
+			Scopes:   []string{"mcp:read"},
+		}
+
+		creds, err := reconciler.loadValidationCredentials(context.Background(), mcpServer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(creds.OAuth2).NotTo(BeNil())
+		Expect(creds.OAuth2.TokenURL).To(Equal("https://auth.example.com/token"))
+		Expect(creds.OAuth2.ClientID).To(Equal("validator"))
+		Expect(creds.OAuth2.ClientSecret).To(Equal("s3cret"))
+		Expect(creds.OAuth2.Scopes).To(Equal([]string{"mcp:read"}))
+	})
+
+	It("should fail when a referenced key is missing", func() {
+		mcpServer.Spec.Validation.Auth.BearerTokenKey = "missing"
+
+		_, err := reconciler.loadValidationCredentials(context.Background(), mcpServer)
+		Expect(err).To(MatchError(ContainSubstring(`key "missing" not found`)))
+	})
+
+	It("should fail when the Secret does not exist", func() {
+		mcpServer.Spec.Validation.Auth.SecretRef.Name = "does-not-exist"
+		mcpServer.Spec.Validation.Auth.BearerTokenKey = "token"
+
+		_, err := reconciler.loadValidationCredentials(context.Background(), mcpServer)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail when no credential type is configured", func() {
+		_, err := reconciler.loadValidationCredentials(context.Background(), mcpServer)
+		Expect(err).To(MatchError(ContainSubstring("at least one of")))
+	})
+
+	It("should report load failures as a failed validation", func() {
+		result := newCredentialsFailureResult(context.DeadlineExceeded)
+		Expect(result.IsCompliant()).To(BeFalse())
+		Expect(result.Issues).To(ConsistOf(HaveField("Code", validator.CodeAuthFailed)))
+		Expect(reconciler.isPermanentError(result, false)).To(BeTrue())
+	})
+})
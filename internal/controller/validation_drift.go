@@ -0,0 +1,257 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+// minRevalidationInterval is the lower bound for spec.validation.interval.
+// Shorter intervals would put needless load on servers and the API server.
+const minRevalidationInterval = time.Minute
+
+// getRevalidationInterval returns the periodic re-validation interval, or 0 when disabled
+func getRevalidationInterval(mcpServer *mcpv1.MCPServer) time.Duration {
+	if mcpServer.Spec.Validation == nil || mcpServer.Spec.Validation.Interval == nil {
+		return 0
+	}
+
+	interval := mcpServer.Spec.Validation.Interval.Duration
+	if interval <= 0 {
+		return 0
+	}
+	if interval < minRevalidationInterval {
+		return minRevalidationInterval
+	}
+	return interval
+}
+
+// isValidationSucceeded returns true if the last validation reached a successful terminal state
+func isValidationSucceeded(validation *mcpv1.ValidationStatus) bool {
+	return validation != nil &&
+		(validation.State == mcpv1.ValidationStateValidated ||
+			validation.State == mcpv1.ValidationStateAuthRequired)
+}
+
+// timeUntilRevalidation returns how long until the next periodic re-validation is due.
+// Returns 0 if periodic validation is disabled or the server has not been validated.
+// A due re-validation returns a small positive value so callers can requeue on it.
+func timeUntilRevalidation(mcpServer *mcpv1.MCPServer) time.Duration {
+	interval := getRevalidationInterval(mcpServer)
+	validation := mcpServer.Status.Validation
+	if interval == 0 || !isValidationSucceeded(validation) || validation.LastAttemptTime == nil {
+		return 0
+	}
+
+	remaining := time.Until(validation.LastAttemptTime.Add(interval))
+	if remaining < time.Second {
+		return time.Second
+	}
+	return remaining
+}
+
+// isRevalidationDue returns true when a validated server should be re-validated
+func isRevalidationDue(mcpServer *mcpv1.MCPServer) bool {
+	interval := getRevalidationInterval(mcpServer)
+	validation := mcpServer.Status.Validation
+	if interval == 0 || !isValidationSucceeded(validation) {
+		return false
+	}
+
+	// Status written by an older operator may lack the attempt time
+	if validation.LastAttemptTime == nil {
+		return true
+	}
+
+	return time.Since(validation.LastAttemptTime.Time) >= interval
+}
+
+// isPeriodicRevalidation returns true when the validation about to be recorded
+// re-checks an already validated generation, as opposed to validating a new spec
+func isPeriodicRevalidation(mcpServer *mcpv1.MCPServer) bool {
+	validation := mcpServer.Status.Validation
+	return isValidationSucceeded(validation) && validation.ValidatedGeneration == mcpServer.Generation
+}
+
+// detectValidationDrift compares a new validation result with the previous
+// validation status and returns a human-readable description of each change.
+// Results from failed initialization are not compared since they carry no
+// server data; those are handled by the regular failure path.
+func detectValidationDrift(previous *mcpv1.ValidationStatus, result *validator.ValidationResult) []string {
+	if previous == nil || result == nil || result.ProtocolVersion == "" {
+		return nil
+	}
+
+	var changes []string
+
+	if previous.ProtocolVersion != "" && previous.ProtocolVersion != result.ProtocolVersion {
+		changes = append(changes, fmt.Sprintf("protocol version changed from %s to %s",
+			previous.ProtocolVersion, result.ProtocolVersion))
+	}
+
+	added, removed := diffStringSets(previous.Capabilities, result.Capabilities)
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("capabilities added: %s", strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("capabilities removed: %s", strings.Join(removed, ", ")))
+	}
+
+	// Status written before server info was recorded has nothing to compare against
+	if previous.ServerInfo != nil && result.ServerInfo != nil {
+		if previous.ServerInfo.Name != result.ServerInfo.Name {
+			changes = append(changes, fmt.Sprintf("server name changed from %q to %q",
+				previous.ServerInfo.Name, result.ServerInfo.Name))
+		}
+		if previous.ServerInfo.Version != result.ServerInfo.Version {
+			changes = append(changes, fmt.Sprintf("server version changed from %q to %q",
+				previous.ServerInfo.Version, result.ServerInfo.Version))
+		}
+	}
+
+	return changes
+}
+
+// updateDriftCondition sets the CapabilityDrift condition.
+// Drift found during periodic re-validation sets the condition to True, where it stays
+// until a spec change produces a new baseline.
+func (r *MCPServerReconciler) updateDriftCondition(mcpServer *mcpv1.MCPServer, specChanged bool, drift []string) {
+	if len(drift) > 0 {
+		r.setCondition(mcpServer, mcpv1.MCPServerCondition{
+			Type:               mcpv1.MCPServerConditionCapabilityDrift,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "DriftDetected",
+			Message:            strings.Join(drift, "; "),
+		})
+		return
+	}
+
+	// A failed re-validation is reported until the next one passes
+	if !specChanged && isValidationSucceeded(mcpServer.Status.Validation) {
+		for _, condition := range mcpServer.Status.Conditions {
+			if condition.Type == mcpv1.MCPServerConditionCapabilityDrift && condition.Reason == "RevalidationFailed" {
+				r.setCondition(mcpServer, mcpv1.MCPServerCondition{
+					Type:               mcpv1.MCPServerConditionCapabilityDrift,
+					Status:             corev1.ConditionFalse,
+					LastTransitionTime: metav1.Now(),
+					Reason:             "RevalidationPassed",
+					Message:            "Periodic re-validation succeeded again",
+				})
+			}
+		}
+		return
+	}
+
+	if specChanged && getRevalidationInterval(mcpServer) > 0 {
+		r.setCondition(mcpServer, mcpv1.MCPServerCondition{
+			Type:               mcpv1.MCPServerConditionCapabilityDrift,
+			Status:             corev1.ConditionFalse,
+			LastTransitionTime: metav1.Now(),
+			Reason:             "BaselineUpdated",
+			Message:            "Validation result recorded as the baseline for drift detection",
+		})
+	}
+}
+
+// recordRevalidationFailure records a failed periodic re-validation. The server
+// already passed validation for its current spec, so the previous result is kept
+// and only the CapabilityDrift condition reports the failure: a running server
+// is neither failed nor, in strict mode, deleted over what may be a brief outage.
+// The next attempt follows at the re-validation interval.
+func (r *MCPServerReconciler) recordRevalidationFailure(ctx context.Context, mcpServer *mcpv1.MCPServer,
+	result *validator.ValidationResult, drift []string) error {
+	log := logf.FromContext(ctx)
+
+	reason := "Unknown validation failure"
+	for _, issue := range result.Issues {
+		if issue.Level == validator.LevelError {
+			reason = issue.Message
+			break
+		}
+	}
+	message := "Periodic re-validation failed: " + reason
+	if len(drift) > 0 {
+		message += "; " + strings.Join(drift, "; ")
+	}
+
+	now := metav1.Now()
+	mcpServer.Status.Validation.LastAttemptTime = &now
+	r.setCondition(mcpServer, mcpv1.MCPServerCondition{
+		Type:               mcpv1.MCPServerConditionCapabilityDrift,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: now,
+		Reason:             "RevalidationFailed",
+		Message:            message,
+	})
+
+	if err := r.updateStatus(ctx, mcpServer); err != nil {
+		log.Error(err, "Failed to update validation status")
+		return err
+	}
+
+	log.Info("Periodic re-validation failed", "reason", reason)
+	r.Recorder.Event(mcpServer, corev1.EventTypeWarning, "RevalidationFailed", message)
+	return nil
+}
+
+// diffStringSets returns the sorted elements only present in next (added)
+// and only present in prev (removed)
+func diffStringSets(prev, next []string) (added, removed []string) {
+	prevSet := make(map[string]bool, len(prev))
+	for _, s := range prev {
+		prevSet[s] = true
+	}
+	nextSet := make(map[string]bool, len(next))
+	for _, s := range next {
+		nextSet[s] = true
+		if !prevSet[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range prev {
+		if !nextSet[s] {
+			removed = append(removed, s)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// toValidationServerInfo converts validator server info to its status representation
+func toValidationServerInfo(info *validator.ServerInfo) *mcpv1.ValidationServerInfo {
+	if info == nil {
+		return nil
+	}
+	return &mcpv1.ValidationServerInfo{
+		Name:    info.Name,
+		Version: info.Version,
+	}
+}
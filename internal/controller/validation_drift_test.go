@@ -0,0 +1,261 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+var _ = Describe("Periodic validation", func() {
+	var mcpServer *mcpv1.MCPServer
+
+	BeforeEach(func() {
+		lastAttempt := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "drift-server",
+				Namespace:  "default",
+				Generation: 3,
+			},
+			Spec: mcpv1.MCPServerSpec{
+				Image: "test-server:latest",
+				Validation: &mcpv1.ValidationSpec{
+					Interval: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+			Status: mcpv1.MCPServerStatus{
+				Validation: &mcpv1.ValidationStatus{
+					State:               mcpv1.ValidationStateValidated,
+					LastAttemptTime:     &lastAttempt,
+					ValidatedGeneration: 3,
+					ProtocolVersion:     "2025-03-26",
+					Capabilities:        []string{"tools", "resources"},
+					ServerInfo:          &mcpv1.ValidationServerInfo{Name: "everything", Version: "1.0.0"},
+				},
+			},
+		}
+	})
+
+	Context("interval handling", func() {
+		It("should be disabled when no interval is set", func() {
+			mcpServer.Spec.Validation.Interval = nil
+			Expect(getRevalidationInterval(mcpServer)).To(BeZero())
+			Expect(isRevalidationDue(mcpServer)).To(BeFalse())
+			Expect(timeUntilRevalidation(mcpServer)).To(BeZero())
+		})
+
+		It("should raise short intervals to the minimum", func() {
+			mcpServer.Spec.Validation.Interval = &metav1.Duration{Duration: 10 * time.Second}
+			Expect(getRevalidationInterval(mcpServer)).To(Equal(minRevalidationInterval))
+		})
+
+		It("should not be due before the interval elapses", func() {
+			Expect(isRevalidationDue(mcpServer)).To(BeFalse())
+			Expect(timeUntilRevalidation(mcpServer)).To(BeNumerically("~", 3*time.Minute, 5*time.Second))
+		})
+
+		It("should be due once the interval elapses", func() {
+			lastAttempt := metav1.NewTime(time.Now().Add(-6 * time.Minute))
+			mcpServer.Status.Validation.LastAttemptTime = &lastAttempt
+			Expect(isRevalidationDue(mcpServer)).To(BeTrue())
+			Expect(timeUntilRevalidation(mcpServer)).To(Equal(time.Second))
+		})
+
+		It("should not re-validate servers that have not passed validation", func() {
+			lastAttempt := metav1.NewTime(time.Now().Add(-6 * time.Minute))
+			mcpServer.Status.Validation.LastAttemptTime = &lastAttempt
+			mcpServer.Status.Validation.State = mcpv1.ValidationStateFailed
+			Expect(isRevalidationDue(mcpServer)).To(BeFalse())
+			Expect(timeUntilRevalidation(mcpServer)).To(BeZero())
+		})
+
+		It("should only treat the validated generation as a periodic re-validation", func() {
+			Expect(isPeriodicRevalidation(mcpServer)).To(BeTrue())
+			mcpServer.Generation = 4
+			Expect(isPeriodicRevalidation(mcpServer)).To(BeFalse())
+		})
+	})
+
+	Context("drift detection", func() {
+		var result *validator.ValidationResult
+
+		BeforeEach(func() {
+			result = &validator.ValidationResult{
+				ProtocolVersion: "2025-03-26",
+				Capabilities:    []string{"resources", "tools"},
+				ServerInfo:      &validator.ServerInfo{Name: "everything", Version: "1.0.0"},
+			}
+		})
+
+		It("should report no drift for an unchanged server", func() {
+			Expect(detectValidationDrift(mcpServer.Status.Validation, result)).To(BeEmpty())
+		})
+
+		It("should report protocol version changes", func() {
+			result.ProtocolVersion = "2025-06-18"
+			Expect(detectValidationDrift(mcpServer.Status.Validation, result)).To(ConsistOf(
+				"protocol version changed from 2025-03-26 to 2025-06-18"))
+		})
+
+		It("should report added and removed capabilities", func() {
+			result.Capabilities = []string{"tools", "prompts", "logging"}
+			Expect(detectValidationDrift(mcpServer.Status.Validation, result)).To(ConsistOf(
+				"capabilities added: logging, prompts",
+				"capabilities removed: resources"))
+		})
+
+		It("should report server info changes", func() {
+			result.ServerInfo = &validator.ServerInfo{Name: "everything", Version: "1.1.0"}
+			Expect(detectValidationDrift(mcpServer.Status.Validation, result)).To(ConsistOf(
+				`server version changed from "1.0.0" to "1.1.0"`))
+		})
+
+		It("should skip server info when no baseline was recorded", func() {
+			mcpServer.Status.Validation.ServerInfo = nil
+			result.ServerInfo = &validator.ServerInfo{Name: "other", Version: "2.0.0"}
+			Expect(detectValidationDrift(mcpServer.Status.Validation, result)).To(BeEmpty())
+		})
+
+		It("should not compare results from failed initialization", func() {
+			Expect(detectValidationDrift(mcpServer.Status.Validation, &validator.ValidationResult{})).To(BeEmpty())
+		})
+	})
+
+	Context("CapabilityDrift condition", func() {
+		var reconciler *MCPServerReconciler
+
+		BeforeEach(func() {
+			reconciler = &MCPServerReconciler{}
+		})
+
+		findCondition := func() *mcpv1.MCPServerCondition {
+			for i := range mcpServer.Status.Conditions {
+				if mcpServer.Status.Conditions[i].Type == mcpv1.MCPServerConditionCapabilityDrift {
+					return &mcpServer.Status.Conditions[i]
+				}
+			}
+			return nil
+		}
+
+		It("should set the condition when drift is detected", func() {
+			reconciler.updateDriftCondition(mcpServer, false, []string{"capabilities added: prompts"})
+
+			condition := findCondition()
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("DriftDetected"))
+			Expect(condition.Message).To(Equal("capabilities added: prompts"))
+		})
+
+		It("should keep the condition until the spec changes", func() {
+			reconciler.updateDriftCondition(mcpServer, false, []string{"capabilities added: prompts"})
+			reconciler.updateDriftCondition(mcpServer, false, nil)
+			Expect(findCondition().Status).To(Equal(corev1.ConditionTrue))
+
+			reconciler.updateDriftCondition(mcpServer, true, nil)
+			Expect(findCondition().Status).To(Equal(corev1.ConditionFalse))
+			Expect(findCondition().Reason).To(Equal("BaselineUpdated"))
+		})
+
+		It("should not add the condition when periodic validation is disabled", func() {
+			mcpServer.Spec.Validation.Interval = nil
+			reconciler.updateDriftCondition(mcpServer, true, nil)
+			Expect(findCondition()).To(BeNil())
+		})
+	})
+
+	Context("failed re-validation", func() {
+		var (
+			ctx        context.Context
+			reconciler *MCPServerReconciler
+		)
+
+		failedResult := &validator.ValidationResult{
+			Issues: []validator.ValidationIssue{{
+				Level:   validator.LevelError,
+				Message: "connection refused",
+				Code:    validator.CodeInitializeFailed,
+			}},
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			mcpServer.Spec.Validation.StrictMode = ptr(true)
+			mcpServer.Status.Phase = mcpv1.MCPServerPhaseRunning
+			mcpServer.Status.Validation.Attempts = 1
+
+			runtimeScheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+			Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "drift-server", Namespace: "default"}}
+			k8sClient := fake.NewClientBuilder().
+				WithScheme(runtimeScheme).
+				WithObjects(mcpServer, deployment).
+				WithStatusSubresource(&mcpv1.MCPServer{}).
+				Build()
+			reconciler = &MCPServerReconciler{
+				Client:   k8sClient,
+				Scheme:   runtimeScheme,
+				Recorder: record.NewFakeRecorder(100),
+			}
+		})
+
+		It("should keep a running server in strict mode and report the failure as drift", func() {
+			for range maxValidationAttempts {
+				Expect(reconciler.updateValidationStatus(ctx, mcpServer, failedResult)).To(Succeed())
+			}
+
+			Expect(mcpServer.Status.Phase).To(Equal(mcpv1.MCPServerPhaseRunning))
+			Expect(mcpServer.Status.Validation.State).To(Equal(mcpv1.ValidationStateValidated))
+			Expect(mcpServer.Status.Validation.LastAttemptTime.Time).To(BeTemporally("~", time.Now(), 5*time.Second))
+			Expect(reconciler.Get(ctx, types.NamespacedName{Name: "drift-server", Namespace: "default"},
+				&appsv1.Deployment{})).To(Succeed())
+
+			var condition *mcpv1.MCPServerCondition
+			for i := range mcpServer.Status.Conditions {
+				if mcpServer.Status.Conditions[i].Type == mcpv1.MCPServerConditionCapabilityDrift {
+					condition = &mcpServer.Status.Conditions[i]
+				}
+			}
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("RevalidationFailed"))
+			Expect(condition.Message).To(ContainSubstring("connection refused"))
+
+			By("Clearing the condition once a re-validation passes again")
+			reconciler.updateDriftCondition(mcpServer, false, nil)
+			Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("RevalidationPassed"))
+		})
+	})
+})
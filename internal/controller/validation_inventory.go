@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+// maxInlineInventoryItems is the largest inventory stored directly in the status.
+// Larger inventories are written to a ConfigMap so the MCPServer object stays small.
+const maxInlineInventoryItems = 100
+
+// inventoryConfigMapName returns the name of the ConfigMap holding a large inventory
+func inventoryConfigMapName(mcpServer *mcpv1.MCPServer) string {
+	return mcpServer.Name + "-inventory"
+}
+
+// reconcileInventory builds the inventory status for a validation result and
+// stores large inventories in a ConfigMap.
+// Results that never reached the server carry no inventory, so the previous
+// inventory is kept to avoid blanking it on a transient failure.
+func (r *MCPServerReconciler) reconcileInventory(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	result *validator.ValidationResult,
+) *mcpv1.ValidationInventory {
+	log := logf.FromContext(ctx)
+
+	if result.ProtocolVersion == "" {
+		if mcpServer.Status.Validation != nil {
+			return mcpServer.Status.Validation.Inventory
+		}
+		return nil
+	}
+
+	tools := sortedCopy(result.Tools)
+	resources := sortedCopy(result.Resources)
+	prompts := sortedCopy(result.Prompts)
+
+	inventory := &mcpv1.ValidationInventory{
+		ToolCount:     int32(len(tools)),
+		ResourceCount: int32(len(resources)),
+		PromptCount:   int32(len(prompts)),
+	}
+
+	if len(tools)+len(resources)+len(prompts) <= maxInlineInventoryItems {
+		inventory.Tools = tools
+		inventory.Resources = resources
+		inventory.Prompts = prompts
+
+		if err := r.deleteInventoryConfigMap(ctx, mcpServer); err != nil {
+			log.Error(err, "Failed to delete inventory ConfigMap")
+		}
+		return inventory
+	}
+
+	if err := r.writeInventoryConfigMap(ctx, mcpServer, tools, resources, prompts); err != nil {
+		// The counts are still useful; the lists will be written on the next validation
+		log.Error(err, "Failed to write inventory ConfigMap")
+		return inventory
+	}
+
+	inventory.ConfigMapName = inventoryConfigMapName(mcpServer)
+	return inventory
+}
+
+// writeInventoryConfigMap creates or updates the ConfigMap holding the inventory
+func (r *MCPServerReconciler) writeInventoryConfigMap(
+	ctx context.Context,
+	mcpServer *mcpv1.MCPServer,
+	tools, resources, prompts []string,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      inventoryConfigMapName(mcpServer),
+				Namespace: mcpServer.Namespace,
+			},
+		}
+
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+			if err := controllerutil.SetControllerReference(mcpServer, configMap, r.Scheme); err != nil {
+				return err
+			}
+
+			configMap.Labels = map[string]string{
+				"app":                          mcpServer.Name,
+				"app.kubernetes.io/name":       "mcpserver",
+				"app.kubernetes.io/instance":   mcpServer.Name,
+				"app.kubernetes.io/component":  "inventory",
+				"app.kubernetes.io/managed-by": "mcp-operator",
+			}
+			configMap.Data = map[string]string{
+				"tools":     strings.Join(tools, "\n"),
+				"resources": strings.Join(resources, "\n"),
+				"prompts":   strings.Join(prompts, "\n"),
+			}
+			return nil
+		})
+		return err
+	})
+}
+
+// deleteInventoryConfigMap removes the inventory ConfigMap if it exists
+func (r *MCPServerReconciler) deleteInventoryConfigMap(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	// Nothing was written unless the status points at the ConfigMap
+	if mcpServer.Status.Validation == nil ||
+		mcpServer.Status.Validation.Inventory == nil ||
+		mcpServer.Status.Validation.Inventory.ConfigMapName == "" {
+		return nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: inventoryConfigMapName(mcpServer), Namespace: mcpServer.Namespace}, configMap)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := r.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// sortedCopy returns a sorted copy of a string slice, or nil for an empty one
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := make([]string, len(values))
+	copy(sorted, values)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/pkg/validator"
+)
+
+var _ = Describe("Validation inventory", func() {
+	var (
+		reconciler *MCPServerReconciler
+		mcpServer  *mcpv1.MCPServer
+	)
+
+	BeforeEach(func() {
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "inventory-server",
+				Namespace: "default",
+				UID:       "inventory-server-uid",
+			},
+			Spec: mcpv1.MCPServerSpec{
+				Image: "test-server:latest",
+			},
+		}
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		reconciler = &MCPServerReconciler{
+			Client: fake.NewClientBuilder().WithScheme(runtimeScheme).Build(),
+			Scheme: runtimeScheme,
+		}
+	})
+
+	getConfigMap := func() (*corev1.ConfigMap, error) {
+		configMap := &corev1.ConfigMap{}
+		err := reconciler.Get(context.Background(), types.NamespacedName{
+			Name:      "inventory-server-inventory",
+			Namespace: "default",
+		}, configMap)
+		return configMap, err
+	}
+
+	largeResult := func() *validator.ValidationResult {
+		result := &validator.ValidationResult{ProtocolVersion: validator.ProtocolVersion20250326}
+		for i := 0; i <= maxInlineInventoryItems; i++ {
+			result.Tools = append(result.Tools, fmt.Sprintf("tool-%03d", i))
+		}
+		result.Prompts = []string{"summarize"}
+		return result
+	}
+
+	It("should store small inventories inline, sorted", func() {
+		result := &validator.ValidationResult{
+			ProtocolVersion: validator.ProtocolVersion20250326,
+			Tools:           []string{"search", "fetch"},
+			Resources:       []string{"file:///readme.md"},
+		}
+
+		inventory := reconciler.reconcileInventory(context.Background(), mcpServer, result)
+		Expect(inventory.Tools).To(Equal([]string{"fetch", "search"}))
+		Expect(inventory.Resources).To(Equal([]string{"file:///readme.md"}))
+		Expect(inventory.Prompts).To(BeNil())
+		Expect(inventory.ToolCount).To(Equal(int32(2)))
+		Expect(inventory.ResourceCount).To(Equal(int32(1)))
+		Expect(inventory.PromptCount).To(BeZero())
+		Expect(inventory.ConfigMapName).To(BeEmpty())
+
+		_, err := getConfigMap()
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should write large inventories to an owned ConfigMap", func() {
+		inventory := reconciler.reconcileInventory(context.Background(), mcpServer, largeResult())
+		Expect(inventory.ConfigMapName).To(Equal("inventory-server-inventory"))
+		Expect(inventory.Tools).To(BeNil())
+		Expect(inventory.ToolCount).To(Equal(int32(maxInlineInventoryItems + 1)))
+		Expect(inventory.PromptCount).To(Equal(int32(1)))
+
+		configMap, err := getConfigMap()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMap.Data["tools"]).To(HavePrefix("tool-000\ntool-001\n"))
+		Expect(configMap.Data["prompts"]).To(Equal("summarize"))
+		Expect(configMap.Data).To(HaveKeyWithValue("resources", ""))
+		Expect(configMap.OwnerReferences).To(ConsistOf(HaveField("Name", "inventory-server")))
+	})
+
+	It("should delete the ConfigMap when the inventory shrinks", func() {
+		inventory := reconciler.reconcileInventory(context.Background(), mcpServer, largeResult())
+		mcpServer.Status.Validation = &mcpv1.ValidationStatus{Inventory: inventory}
+
+		small := &validator.ValidationResult{
+			ProtocolVersion: validator.ProtocolVersion20250326,
+			Tools:           []string{"search"},
+		}
+		inventory = reconciler.reconcileInventory(context.Background(), mcpServer, small)
+		Expect(inventory.ConfigMapName).To(BeEmpty())
+		Expect(inventory.Tools).To(Equal([]string{"search"}))
+
+		_, err := getConfigMap()
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should keep the previous inventory when the server was not reached", func() {
+		previous := &mcpv1.ValidationInventory{Tools: []string{"search"}, ToolCount: 1}
+		mcpServer.Status.Validation = &mcpv1.ValidationStatus{Inventory: previous}
+
+		inventory := reconciler.reconcileInventory(context.Background(), mcpServer, &validator.ValidationResult{})
+		Expect(inventory).To(Equal(previous))
+	})
+
+	It("should treat missing required inventory in strict mode as a permanent error", func() {
+		result := &validator.ValidationResult{
+			Success:         false,
+			ProtocolVersion: validator.ProtocolVersion20250326,
+			Capabilities:    []string{"tools"},
+			Issues: []validator.ValidationIssue{
+				{Level: validator.LevelError, Code: validator.CodeMissingTool, Message: "Required tool 'delete' is not exposed by the server"},
+			},
+		}
+		Expect(reconciler.isPermanentError(result, false)).To(BeTrue())
+
+		result.Issues[0].Level = validator.LevelWarning
+		Expect(reconciler.isPermanentError(result, false)).To(BeFalse())
+	})
+
+	It("should treat invalid tool definitions as a permanent error", func() {
+		for _, code := range []string{
+			validator.CodeToolInvalidSchema,
+			validator.CodeToolInvalidName,
+			validator.CodeToolDuplicateName,
+		} {
+			result := &validator.ValidationResult{
+				Success:         false,
+				ProtocolVersion: validator.ProtocolVersion20250326,
+				Issues:          []validator.ValidationIssue{{Level: validator.LevelError, Code: code}},
+			}
+			Expect(reconciler.isPermanentError(result, false)).To(BeTrue(), code)
+		}
+	})
+})
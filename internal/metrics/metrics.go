@@ -246,7 +246,7 @@ func UpdateMCPServerMetrics(mcpServer *mcpv1.MCPServer) {
 	}
 
 	// Track MCPServer phase
-	phases := []string{"Pending", "Creating", "Running", "Updating", "Scaling", "Failed", "ValidationFailed", "Terminating"}
+	phases := []string{"Pending", "Creating", "Running", "Updating", "Scaling", "Idle", "Failed", "ValidationFailed", "Terminating"}
 	for _, phase := range phases {
 		value := 0.0
 		if string(mcpServer.Status.Phase) == phase {
@@ -362,7 +362,7 @@ func DeleteMCPServerMetrics(mcpServer *mcpv1.MCPServer) {
 	mcpServerAvailableReplicas.DeleteLabelValues(replicaLabels...)
 
 	// Remove phase metrics
-	phases := []string{"Creating", "Running", "Updating", "Scaling", "Failed", "Terminating"}
+	phases := []string{"Creating", "Running", "Updating", "Scaling", "Idle", "Failed", "Terminating"}
 	for _, phase := range phases {
 		mcpServerPhase.DeleteLabelValues(mcpServer.Namespace, mcpServer.Name, phase)
 	}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+func TestMetricsAdapter(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Metrics Adapter Suite")
+}
+
+// sidecarMetrics renders the metrics of a sidecar in the Prometheus text format
+func sidecarMetrics(sse, requests int, fastCalls, slowCalls int) string {
+	return fmt.Sprintf(`# TYPE mcp_sse_connections_active gauge
+mcp_sse_connections_active %d
+# TYPE mcp_requests_total counter
+mcp_requests_total{method="tools/call",status="200"} %d
+# TYPE mcp_tool_call_duration_seconds histogram
+mcp_tool_call_duration_seconds_bucket{tool_name="forecast",le="0.1"} %d
+mcp_tool_call_duration_seconds_bucket{tool_name="forecast",le="0.5"} %d
+mcp_tool_call_duration_seconds_bucket{tool_name="forecast",le="1"} %d
+mcp_tool_call_duration_seconds_bucket{tool_name="forecast",le="+Inf"} %d
+mcp_tool_call_duration_seconds_sum{tool_name="forecast"} 1
+mcp_tool_call_duration_seconds_count{tool_name="forecast"} %d
+`, sse, requests, fastCalls, fastCalls+slowCalls, fastCalls+slowCalls, fastCalls+slowCalls, fastCalls+slowCalls)
+}
+
+var _ = Describe("Metrics Adapter", func() {
+	var (
+		ctx       context.Context
+		collector *Collector
+		handler   http.Handler
+		sidecar   *httptest.Server
+
+		mu      sync.Mutex
+		metrics string
+	)
+
+	setMetrics := func(text string) {
+		mu.Lock()
+		defer mu.Unlock()
+		metrics = text
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		setMetrics(sidecarMetrics(3, 100, 0, 0))
+		sidecar = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			_, _ = fmt.Fprint(w, metrics)
+		}))
+		DeferCleanup(sidecar.Close)
+
+		_, portText, err := net.SplitHostPort(sidecar.Listener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+		port, err := strconv.Atoi(portText)
+		Expect(err).NotTo(HaveOccurred())
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(scheme)).To(Succeed())
+
+		mcpServer := &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default"},
+			Spec: mcpv1.MCPServerSpec{
+				Image:   "weather:1.0",
+				Metrics: &mcpv1.MetricsConfig{Enabled: true, Port: int32(port)},
+				HPA: &mcpv1.MCPServerHPA{
+					Enabled: ptr(true),
+					Metrics: []mcpv1.MCPServerHPAMetric{
+						{Type: mcpv1.HPAMetricRequestsPerSecond, Target: resource.MustParse("20")},
+					},
+				},
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "weather-abc",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "weather"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"},
+		}
+		// Servers without traffic metrics in their HPA are not scraped
+		other := &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "default"},
+			Spec: mcpv1.MCPServerSpec{
+				Image:   "search:1.0",
+				Metrics: &mcpv1.MetricsConfig{Enabled: true, Port: int32(port)},
+			},
+		}
+		otherPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "search-abc",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "search"},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "127.0.0.1"},
+		}
+
+		collector = &Collector{
+			Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(mcpServer, pod, other, otherPod).Build(),
+		}
+		handler = (&Server{Collector: collector}).Handler()
+	})
+
+	get := func(path string, body any) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if body != nil && recorder.Code == http.StatusOK {
+			Expect(json.Unmarshal(recorder.Body.Bytes(), body)).To(Succeed())
+		}
+		return recorder.Code
+	}
+
+	It("should serve gauges after one scrape and rates after two", func() {
+		Expect(collector.Collect(ctx)).To(Succeed())
+
+		sse := collector.PodValues("default", "*", mcpv1.HPAMetricNameActiveSSEConnections, labels.Everything())
+		Expect(sse).To(HaveLen(1))
+		Expect(sse[0].Name).To(Equal("weather-abc"))
+		Expect(sse[0].Value).To(Equal(3.0))
+
+		Expect(collector.PodValues("default", "*", mcpv1.HPAMetricNameRequestsPerSecond, labels.Everything())).To(BeEmpty())
+		Expect(collector.ServerValues("default", mcpv1.HPAMetricNameToolCallLatencyP95, labels.Everything())).To(BeEmpty())
+
+		setMetrics(sidecarMetrics(5, 150, 0, 10))
+		Expect(collector.Collect(ctx)).To(Succeed())
+
+		requests := collector.PodValues("default", "weather-abc", mcpv1.HPAMetricNameRequestsPerSecond, labels.Everything())
+		Expect(requests).To(HaveLen(1))
+		Expect(requests[0].Value).To(BeNumerically(">", 0))
+		Expect(requests[0].Window).To(BeNumerically(">", 0))
+
+		latency := collector.ServerValues("default", mcpv1.HPAMetricNameToolCallLatencyP95,
+			labels.SelectorFromSet(labels.Set{mcpv1.HPAMetricServerLabel: "weather"}))
+		Expect(latency).To(HaveLen(1))
+		Expect(latency[0].Server).To(Equal("weather"))
+		// The 10 calls fall between 100ms and 500ms
+		Expect(latency[0].Value).To(BeNumerically("~", 0.48, 0.001))
+	})
+
+	It("should serve the values through the custom and external metrics APIs", func() {
+		Expect(collector.Collect(ctx)).To(Succeed())
+		setMetrics(sidecarMetrics(5, 150, 10, 0))
+		Expect(collector.Collect(ctx)).To(Succeed())
+
+		var resources metav1.APIResourceList
+		Expect(get("/apis/custom.metrics.k8s.io/v1beta2", &resources)).To(Equal(http.StatusOK))
+		Expect(resources.APIResources).To(HaveLen(2))
+		Expect(resources.APIResources[0].Name).To(Equal("pods/mcp_sse_connections_active"))
+
+		var podList MetricValueList
+		Expect(get("/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/*/mcp_sse_connections_active"+
+			"?labelSelector=app%3Dweather", &podList)).To(Equal(http.StatusOK))
+		Expect(podList.Kind).To(Equal("MetricValueList"))
+		Expect(podList.Items).To(HaveLen(1))
+		Expect(podList.Items[0].DescribedObject.Name).To(Equal("weather-abc"))
+		Expect(podList.Items[0].Value.String()).To(Equal("5"))
+
+		Expect(get("/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/*/mcp_sse_connections_active"+
+			"?labelSelector=app%3Dsearch", &podList)).To(Equal(http.StatusOK))
+		Expect(podList.Items).To(BeEmpty())
+
+		Expect(get("/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/weather-abc/cpu_usage", nil)).
+			To(Equal(http.StatusNotFound))
+		Expect(get("/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/search-abc/mcp_requests_per_second", nil)).
+			To(Equal(http.StatusNotFound))
+
+		var externalList ExternalMetricValueList
+		Expect(get("/apis/external.metrics.k8s.io/v1beta1/namespaces/default/mcp_tool_call_duration_seconds_p95"+
+			"?labelSelector=mcpserver%3Dweather", &externalList)).To(Equal(http.StatusOK))
+		Expect(externalList.Items).To(HaveLen(1))
+		Expect(externalList.Items[0].MetricLabels).To(Equal(map[string]string{"mcpserver": "weather"}))
+		// The 10 calls fall below 100ms
+		Expect(externalList.Items[0].Value.String()).To(Equal("95m"))
+	})
+
+	It("should estimate quantiles like histogram_quantile", func() {
+		buckets := map[float64]float64{0.1: 50, 0.5: 90, 1: 100, math.Inf(1): 100}
+		Expect(bucketQuantile(0.5, buckets, 100)).To(Equal(0.1))
+		Expect(bucketQuantile(0.95, buckets, 100)).To(BeNumerically("~", 0.75, 0.001))
+		Expect(bucketQuantile(0.95, map[float64]float64{}, 0)).To(Equal(0.0))
+
+		// Observations above the largest bound report the largest bound
+		Expect(bucketQuantile(0.95, map[float64]float64{0.1: 1, 1: 2, math.Inf(1): 10}, 10)).To(Equal(1.0))
+	})
+})
+
+func ptr[T any](v T) *T {
+	return &v
+}
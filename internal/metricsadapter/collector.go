@@ -0,0 +1,427 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricsadapter serves the MCP traffic metrics of the sidecars
+// through the custom and external metrics APIs, so MCPServer HPAs can scale on
+// them without prometheus-adapter.
+package metricsadapter
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+const (
+	// DefaultInterval is how often the sidecars are scraped by default
+	DefaultInterval = 15 * time.Second
+
+	// scrapeTimeout bounds the scrape of a single sidecar
+	scrapeTimeout = 5 * time.Second
+
+	// latencyQuantile is the quantile of the tool call latency served
+	latencyQuantile = 0.95
+)
+
+// sample holds the values read from a sidecar in one scrape
+type sample struct {
+	time time.Time
+
+	// sseConnections is the mcp_sse_connections_active gauge
+	sseConnections float64
+	// requests is the mcp_requests_total counter, summed over its series
+	requests float64
+	// toolCalls is the mcp_tool_call_duration_seconds count, summed over tools
+	toolCalls float64
+	// buckets are the cumulative counts of the tool call duration histogram
+	// by upper bound, summed over tools
+	buckets map[float64]float64
+}
+
+// podMetrics holds the last two scrapes of a pod, to compute rates
+type podMetrics struct {
+	server   string
+	labels   labels.Set
+	previous *sample
+	latest   *sample
+}
+
+// PodValue is the value of a Pods metric for one pod
+type PodValue struct {
+	Name      string
+	Value     float64
+	Timestamp time.Time
+	Window    time.Duration
+}
+
+// ServerValue is the value of an External metric for one MCPServer
+type ServerValue struct {
+	Server    string
+	Value     float64
+	Timestamp time.Time
+	Window    time.Duration
+}
+
+// Collector scrapes the sidecars of the MCPServers scaling on MCP traffic
+// metrics and keeps the values the adapter serves.
+// It implements manager.Runnable and runs on every replica, not just the leader.
+type Collector struct {
+	// Reader is used to list MCPServers and their pods, normally the manager's cached client
+	Reader client.Reader
+
+	// Interval is how often the sidecars are scraped, DefaultInterval when zero
+	Interval time.Duration
+
+	// HTTPClient scrapes the sidecars, a client with a short timeout that
+	// does not verify sidecar certificates when nil
+	HTTPClient *http.Client
+
+	mu      sync.RWMutex
+	pods    map[types.NamespacedName]*podMetrics
+	latency map[types.NamespacedName]ServerValue
+}
+
+// Start scrapes the sidecars every interval until the context is cancelled
+func (c *Collector) Start(ctx context.Context) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Collect(ctx); err != nil {
+			logf.FromContext(ctx).WithName("metrics-adapter").Error(err, "Failed to collect MCP traffic metrics")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false so every replica can answer the metrics APIs
+func (c *Collector) NeedLeaderElection() bool {
+	return false
+}
+
+// Collect scrapes the sidecars of every MCPServer scaling on MCP traffic
+// metrics once. Pods whose sidecar cannot be read keep no values.
+func (c *Collector) Collect(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("metrics-adapter")
+
+	serverList := &mcpv1.MCPServerList{}
+	if err := c.Reader.List(ctx, serverList); err != nil {
+		return fmt.Errorf("failed to list MCPServers: %w", err)
+	}
+
+	type target struct {
+		key    types.NamespacedName
+		server string
+		labels labels.Set
+		url    string
+	}
+	var targets []target
+	servers := map[types.NamespacedName]bool{}
+	for _, mcpServer := range serverList.Items {
+		if !scalesOnTraffic(&mcpServer) {
+			continue
+		}
+		servers[types.NamespacedName{Namespace: mcpServer.Namespace, Name: mcpServer.Name}] = true
+
+		metricsPort := mcpv1.DefaultMetricsPort
+		if mcpServer.Spec.Metrics.Port != 0 {
+			metricsPort = mcpServer.Spec.Metrics.Port
+		}
+		// The sidecar serves its metrics over TLS too when TLS is enabled
+		scheme := "http"
+		if mcpServer.Spec.Sidecar != nil && mcpServer.Spec.Sidecar.TLS != nil && mcpServer.Spec.Sidecar.TLS.Enabled {
+			scheme = "https"
+		}
+
+		podList := &corev1.PodList{}
+		if err := c.Reader.List(ctx, podList, client.InNamespace(mcpServer.Namespace),
+			client.MatchingLabels{"app": mcpServer.Name}); err != nil {
+			return fmt.Errorf("failed to list pods of %s/%s: %w", mcpServer.Namespace, mcpServer.Name, err)
+		}
+		for _, pod := range podList.Items {
+			if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" {
+				continue
+			}
+			targets = append(targets, target{
+				key:    types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name},
+				server: mcpServer.Name,
+				labels: labels.Set(pod.Labels),
+				url: fmt.Sprintf("%s://%s/metrics", scheme,
+					net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(metricsPort)))),
+			})
+		}
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		// Sidecar certificates are not verified, as with the ServiceMonitor
+		httpClient = &http.Client{
+			Timeout: scrapeTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+				DisableKeepAlives: true,
+			},
+		}
+	}
+
+	samples := make([]*sample, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := scrape(ctx, httpClient, t.url)
+			if err != nil {
+				log.V(1).Info("Failed to read sidecar metrics", "pod", t.key, "error", err)
+				return
+			}
+			samples[i] = s
+		}()
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pods := make(map[types.NamespacedName]*podMetrics, len(targets))
+	for i, t := range targets {
+		if samples[i] == nil {
+			continue
+		}
+		metrics := &podMetrics{server: t.server, labels: t.labels, latest: samples[i]}
+		if previous, ok := c.pods[t.key]; ok {
+			metrics.previous = previous.latest
+		}
+		pods[t.key] = metrics
+	}
+	c.pods = pods
+
+	c.latency = make(map[types.NamespacedName]ServerValue, len(servers))
+	for key := range servers {
+		if value, ok := serverLatency(key, pods); ok {
+			c.latency[key] = value
+		}
+	}
+	return nil
+}
+
+// PodValues returns the values of a Pods metric for the pods of a namespace
+// matching the selector, and the pod named name unless it is empty or "*".
+// Pods without a value yet, such as a rate scraped only once, are omitted.
+func (c *Collector) PodValues(namespace, name, metric string, selector labels.Selector) []PodValue {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var values []PodValue
+	for key, pod := range c.pods {
+		if key.Namespace != namespace || (name != "" && name != "*" && key.Name != name) ||
+			!selector.Matches(pod.labels) {
+			continue
+		}
+
+		value := PodValue{Name: key.Name, Timestamp: pod.latest.time}
+		switch metric {
+		case mcpv1.HPAMetricNameActiveSSEConnections:
+			value.Value = pod.latest.sseConnections
+		case mcpv1.HPAMetricNameRequestsPerSecond:
+			if pod.previous == nil {
+				continue
+			}
+			value.Window = pod.latest.time.Sub(pod.previous.time)
+			value.Value = increase(pod.previous.requests, pod.latest.requests) / value.Window.Seconds()
+		default:
+			continue
+		}
+		values = append(values, value)
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+	return values
+}
+
+// ServerValues returns the values of an External metric for the MCPServers
+// of a namespace whose HPAMetricServerLabel matches the selector
+func (c *Collector) ServerValues(namespace, metric string, selector labels.Selector) []ServerValue {
+	if metric != mcpv1.HPAMetricNameToolCallLatencyP95 {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var values []ServerValue
+	for key, value := range c.latency {
+		if key.Namespace == namespace && selector.Matches(labels.Set{mcpv1.HPAMetricServerLabel: key.Name}) {
+			values = append(values, value)
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Server < values[j].Server })
+	return values
+}
+
+// scalesOnTraffic returns true when the HPA of a server targets MCP traffic
+// metrics recorded by its sidecar
+func scalesOnTraffic(mcpServer *mcpv1.MCPServer) bool {
+	hpa := mcpServer.Spec.HPA
+	return hpa != nil && hpa.Enabled != nil && *hpa.Enabled && len(hpa.Metrics) > 0 &&
+		mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.Enabled
+}
+
+// serverLatency computes the tool call latency quantile of a server from the
+// tool calls its pods answered since their previous scrape
+func serverLatency(key types.NamespacedName, pods map[types.NamespacedName]*podMetrics) (ServerValue, bool) {
+	var found bool
+	var toolCalls float64
+	var timestamp time.Time
+	var window time.Duration
+	buckets := map[float64]float64{}
+
+	for podKey, pod := range pods {
+		if podKey.Namespace != key.Namespace || pod.server != key.Name || pod.previous == nil {
+			continue
+		}
+		found = true
+		toolCalls += increase(pod.previous.toolCalls, pod.latest.toolCalls)
+		for upperBound, count := range pod.latest.buckets {
+			buckets[upperBound] += increase(pod.previous.buckets[upperBound], count)
+		}
+		if pod.latest.time.After(timestamp) {
+			timestamp = pod.latest.time
+		}
+		window = max(window, pod.latest.time.Sub(pod.previous.time))
+	}
+	if !found {
+		return ServerValue{}, false
+	}
+
+	return ServerValue{
+		Server:    key.Name,
+		Value:     bucketQuantile(latencyQuantile, buckets, toolCalls),
+		Timestamp: timestamp,
+		Window:    window,
+	}, true
+}
+
+// increase returns how much a counter grew between two scrapes, assuming it
+// restarted from zero when it went down
+func increase(previous, latest float64) float64 {
+	if latest < previous {
+		return latest
+	}
+	return latest - previous
+}
+
+// bucketQuantile estimates a quantile from cumulative histogram buckets by
+// linear interpolation within the bucket holding it, as histogram_quantile
+// does in Prometheus. It returns 0 when no observation was made, and the
+// largest finite upper bound when the quantile falls in the +Inf bucket.
+func bucketQuantile(q float64, buckets map[float64]float64, count float64) float64 {
+	if count <= 0 {
+		return 0
+	}
+
+	upperBounds := make([]float64, 0, len(buckets))
+	for upperBound := range buckets {
+		if !math.IsInf(upperBound, 1) {
+			upperBounds = append(upperBounds, upperBound)
+		}
+	}
+	sort.Float64s(upperBounds)
+
+	rank := q * count
+	lowerBound, lowerCount := 0.0, 0.0
+	for _, upperBound := range upperBounds {
+		upperCount := buckets[upperBound]
+		if upperCount >= rank {
+			if upperCount == lowerCount {
+				return upperBound
+			}
+			return lowerBound + (upperBound-lowerBound)*(rank-lowerCount)/(upperCount-lowerCount)
+		}
+		lowerBound, lowerCount = upperBound, upperCount
+	}
+	return lowerBound
+}
+
+// scrape reads the MCP traffic metrics of a sidecar
+func scrape(ctx context.Context, httpClient *http.Client, url string) (*sample, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &sample{time: time.Now(), buckets: map[float64]float64{}}
+	if family := families["mcp_sse_connections_active"]; family != nil {
+		for _, metric := range family.GetMetric() {
+			s.sseConnections += metric.GetGauge().GetValue()
+		}
+	}
+	if family := families["mcp_requests_total"]; family != nil {
+		for _, metric := range family.GetMetric() {
+			s.requests += metric.GetCounter().GetValue()
+		}
+	}
+	if family := families["mcp_tool_call_duration_seconds"]; family != nil {
+		for _, metric := range family.GetMetric() {
+			histogram := metric.GetHistogram()
+			s.toolCalls += float64(histogram.GetSampleCount())
+			for _, bucket := range histogram.GetBucket() {
+				s.buckets[bucket.GetUpperBound()] += float64(bucket.GetCumulativeCount())
+			}
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadapter
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+const (
+	shutdownTimeout = 5 * time.Second
+
+	// CustomMetricsGroupVersion is the custom metrics API version served
+	CustomMetricsGroupVersion = "custom.metrics.k8s.io/v1beta2"
+
+	// ExternalMetricsGroupVersion is the external metrics API version served
+	ExternalMetricsGroupVersion = "external.metrics.k8s.io/v1beta1"
+)
+
+// Where the Kubernetes API server publishes the CA of the client certificate
+// it presents to aggregated APIs
+const (
+	authenticationConfigMapNamespace = "kube-system"
+	authenticationConfigMapName      = "extension-apiserver-authentication"
+	requestHeaderClientCAKey         = "requestheader-client-ca-file"
+)
+
+// MetricIdentifier identifies a custom metric
+type MetricIdentifier struct {
+	Name     string                `json:"name"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// MetricValue is the value of a custom metric for an object
+type MetricValue struct {
+	DescribedObject corev1.ObjectReference `json:"describedObject"`
+	Metric          MetricIdentifier       `json:"metric"`
+	Timestamp       metav1.Time            `json:"timestamp"`
+	WindowSeconds   *int64                 `json:"windowSeconds,omitempty"`
+	Value           resource.Quantity      `json:"value"`
+}
+
+// MetricValueList is the custom metrics API response
+type MetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []MetricValue `json:"items"`
+}
+
+// ExternalMetricValue is the value of an external metric
+type ExternalMetricValue struct {
+	MetricName    string            `json:"metricName"`
+	MetricLabels  map[string]string `json:"metricLabels"`
+	Timestamp     metav1.Time       `json:"timestamp"`
+	WindowSeconds *int64            `json:"window,omitempty"`
+	Value         resource.Quantity `json:"value"`
+}
+
+// ExternalMetricValueList is the external metrics API response
+type ExternalMetricValueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ExternalMetricValue `json:"items"`
+}
+
+// Server serves the values of a Collector as the custom and external metrics
+// APIs, registered with the Kubernetes API server through APIServices.
+// It implements manager.Runnable and runs on every replica, not just the leader.
+type Server struct {
+	// Collector holds the values served
+	Collector *Collector
+
+	// BindAddress is the address the server listens on, e.g. ":6443"
+	BindAddress string
+
+	// TLSOpts configure the listener and must provide the serving certificate
+	TLSOpts []func(*tls.Config)
+
+	// ClientCAs verify the client certificate the Kubernetes API server
+	// presents when proxying requests. When nil, any client is served.
+	ClientCAs *x509.CertPool
+}
+
+// Handler returns the HTTP handler serving the metrics APIs:
+//
+//	GET /apis/custom.metrics.k8s.io/v1beta2                                           discovery
+//	GET /apis/custom.metrics.k8s.io/v1beta2/namespaces/{namespace}/pods/{name}/{metric}  Pods metrics
+//	GET /apis/external.metrics.k8s.io/v1beta1                                         discovery
+//	GET /apis/external.metrics.k8s.io/v1beta1/namespaces/{namespace}/{metric}            External metrics
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /apis/"+CustomMetricsGroupVersion, s.customResources)
+	mux.HandleFunc("GET /apis/"+CustomMetricsGroupVersion+"/namespaces/{namespace}/pods/{name}/{metric}", s.podMetrics)
+	mux.HandleFunc("GET /apis/"+ExternalMetricsGroupVersion, s.externalResources)
+	mux.HandleFunc("GET /apis/"+ExternalMetricsGroupVersion+"/namespaces/{namespace}/{metric}", s.externalMetrics)
+	return mux
+}
+
+// Start serves the metrics APIs until the context is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("metrics-adapter")
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	for _, opt := range s.TLSOpts {
+		opt(tlsConfig)
+	}
+	if s.ClientCAs != nil {
+		tlsConfig.ClientCAs = s.ClientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	listener, err := tls.Listen("tcp", s.BindAddress, tlsConfig)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Info("Serving metrics APIs", "address", listener.Addr().String())
+		errChan <- server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errChan:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// NeedLeaderElection returns false so every replica serves the metrics APIs
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// RequestHeaderClientCAs reads the CA the Kubernetes API server signs its
+// client certificate for aggregated APIs with
+func RequestHeaderClientCAs(ctx context.Context, reader client.Reader) (*x509.CertPool, error) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: authenticationConfigMapNamespace, Name: authenticationConfigMapName}
+	if err := reader.Get(ctx, key, configMap); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(configMap.Data[requestHeaderClientCAKey])) {
+		return nil, fmt.Errorf("%s has no certificates in %s", key, requestHeaderClientCAKey)
+	}
+	return pool, nil
+}
+
+func (s *Server) customResources(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: CustomMetricsGroupVersion,
+		APIResources: []metav1.APIResource{
+			metricResource("pods/"+mcpv1.HPAMetricNameActiveSSEConnections, "MetricValueList"),
+			metricResource("pods/"+mcpv1.HPAMetricNameRequestsPerSecond, "MetricValueList"),
+		},
+	})
+}
+
+func (s *Server) externalResources(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: ExternalMetricsGroupVersion,
+		APIResources: []metav1.APIResource{
+			metricResource(mcpv1.HPAMetricNameToolCallLatencyP95, "ExternalMetricValueList"),
+		},
+	})
+}
+
+func (s *Server) podMetrics(w http.ResponseWriter, r *http.Request) {
+	metric := r.PathValue("metric")
+	if metric != mcpv1.HPAMetricNameActiveSSEConnections && metric != mcpv1.HPAMetricNameRequestsPerSecond {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("metric %q not found", metric))
+		return
+	}
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	values := s.Collector.PodValues(namespace, name, metric, selector)
+	if name != "*" && len(values) == 0 {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound,
+			fmt.Sprintf("metric %q not available for pod %s/%s", metric, namespace, name))
+		return
+	}
+
+	list := &MetricValueList{
+		TypeMeta: metav1.TypeMeta{Kind: "MetricValueList", APIVersion: CustomMetricsGroupVersion},
+		Items:    make([]MetricValue, 0, len(values)),
+	}
+	for _, value := range values {
+		list.Items = append(list.Items, MetricValue{
+			DescribedObject: corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Namespace: namespace, Name: value.Name},
+			Metric:          MetricIdentifier{Name: metric},
+			Timestamp:       metav1.NewTime(value.Timestamp),
+			WindowSeconds:   windowSeconds(value.Window),
+			Value:           quantity(value.Value),
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) externalMetrics(w http.ResponseWriter, r *http.Request) {
+	metric := r.PathValue("metric")
+	if metric != mcpv1.HPAMetricNameToolCallLatencyP95 {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, fmt.Sprintf("metric %q not found", metric))
+		return
+	}
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+
+	values := s.Collector.ServerValues(r.PathValue("namespace"), metric, selector)
+	list := &ExternalMetricValueList{
+		TypeMeta: metav1.TypeMeta{Kind: "ExternalMetricValueList", APIVersion: ExternalMetricsGroupVersion},
+		Items:    make([]ExternalMetricValue, 0, len(values)),
+	}
+	for _, value := range values {
+		list.Items = append(list.Items, ExternalMetricValue{
+			MetricName:    metric,
+			MetricLabels:  map[string]string{mcpv1.HPAMetricServerLabel: value.Server},
+			Timestamp:     metav1.NewTime(value.Timestamp),
+			WindowSeconds: windowSeconds(value.Window),
+			Value:         quantity(value.Value),
+		})
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func metricResource(name, kind string) metav1.APIResource {
+	return metav1.APIResource{Name: name, Namespaced: true, Kind: kind, Verbs: metav1.Verbs{"get"}}
+}
+
+// quantity converts a metric value to a quantity with millesimal precision
+func quantity(value float64) resource.Quantity {
+	return *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI)
+}
+
+func windowSeconds(window time.Duration) *int64 {
+	if window <= 0 {
+		return nil
+	}
+	seconds := int64(window.Round(time.Second).Seconds())
+	return &seconds
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+	writeJSON(w, code, &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Code:     int32(code),
+		Reason:   reason,
+		Message:  message,
+	})
+}
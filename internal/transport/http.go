@@ -18,13 +18,17 @@ package transport
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -36,6 +40,42 @@ import (
 	"github.com/vitorbari/mcp-operator/internal/utils"
 )
 
+const (
+	// sidecarAPIKeysVolume holds the API keys the sidecar accepts
+	sidecarAPIKeysVolume = "sidecar-api-keys"
+
+	// sidecarAPIKeysDir is where the API key Secret is mounted in the sidecar
+	sidecarAPIKeysDir = "/etc/mcp-auth/api-keys"
+
+	// sidecarJWKSVolume holds the JSON Web Key Set the sidecar verifies tokens against
+	sidecarJWKSVolume = "sidecar-jwks"
+
+	// sidecarJWKSDir is where the JWKS ConfigMap is mounted in the sidecar
+	sidecarJWKSDir = "/etc/mcp-auth/jwks"
+
+	// sidecarJWKSFile is the file name the JWKS ConfigMap key is mounted as
+	sidecarJWKSFile = "jwks.json"
+
+	// sidecarAuditVolume is the emptyDir audit records are written to by default
+	sidecarAuditVolume = "sidecar-audit"
+
+	// sidecarAuditDir is where the audit volume is mounted in the sidecar
+	sidecarAuditDir = "/var/log/mcp-audit"
+
+	// defaultAuditFileName is the file audit records are appended to
+	defaultAuditFileName = "audit.jsonl"
+
+	// sidecarBinaryPath is the mcp-proxy binary in the sidecar image
+	sidecarBinaryPath = "/mcp-proxy"
+
+	// defaultTerminationGracePeriod is the Kubernetes default grace period of pods
+	defaultTerminationGracePeriod = int64(30)
+
+	// sidecarDrainMargin is the part of the grace period left to the sidecar
+	// to shut down after its drain timed out
+	sidecarDrainMargin = int64(5)
+)
+
 // HTTPResourceManager manages resources for HTTP transport (MCP streamable HTTP)
 type HTTPResourceManager struct {
 	client client.Client
@@ -96,6 +136,11 @@ func (h *HTTPResourceManager) RequiresService() bool {
 	return true
 }
 
+// DesiredDeployment returns the Deployment the manager maintains for the MCPServer
+func (h *HTTPResourceManager) DesiredDeployment(mcpServer *mcpv1.MCPServer) *appsv1.Deployment {
+	return h.buildDeployment(mcpServer)
+}
+
 // getHTTPPort returns the port for HTTP transport
 func (h *HTTPResourceManager) getHTTPPort(mcpServer *mcpv1.MCPServer) int32 {
 	if mcpServer.Spec.Transport != nil &&
@@ -109,13 +154,7 @@ func (h *HTTPResourceManager) getHTTPPort(mcpServer *mcpv1.MCPServer) int32 {
 
 // getHTTPPath returns the path for HTTP transport
 func (h *HTTPResourceManager) getHTTPPath(mcpServer *mcpv1.MCPServer) string {
-	if mcpServer.Spec.Transport != nil &&
-		mcpServer.Spec.Transport.Config != nil &&
-		mcpServer.Spec.Transport.Config.HTTP != nil &&
-		mcpServer.Spec.Transport.Config.HTTP.Path != "" {
-		return mcpServer.Spec.Transport.Config.HTTP.Path
-	}
-	return "/mcp" // default per ADR
+	return GetHTTPPath(mcpServer)
 }
 
 // hasSessionManagement returns whether session management is enabled
@@ -129,34 +168,9 @@ func (h *HTTPResourceManager) hasSessionManagement(mcpServer *mcpv1.MCPServer) b
 	return false // default
 }
 
-// isSSEActive returns true if SSE transport is active (explicit or auto-detected).
-// SSE is considered active when:
-// 1. transport.protocol is explicitly set to "sse", OR
-// 2. transport.protocol is "auto" AND SSE was detected (stored in status.resolvedTransport)
+// isSSEActive returns true if SSE transport is active (explicit or auto-detected)
 func (h *HTTPResourceManager) isSSEActive(mcpServer *mcpv1.MCPServer) bool {
-	// Check for explicit SSE configuration
-	if mcpServer.Spec.Transport != nil && mcpServer.Spec.Transport.Protocol == mcpv1.MCPProtocolSSE {
-		return true
-	}
-
-	// Check for auto-detected SSE (resolved in status)
-	if mcpServer.Status.ResolvedTransport != nil &&
-		mcpServer.Status.ResolvedTransport.Protocol == mcpv1.MCPProtocolSSE {
-		return true
-	}
-
-	// Also check validation status for detected protocol (legacy/fallback)
-	if mcpServer.Status.Validation != nil &&
-		mcpServer.Status.Validation.Protocol == string(mcpv1.MCPProtocolSSE) {
-		// Only use this if auto-detect mode is enabled
-		if mcpServer.Spec.Transport == nil ||
-			mcpServer.Spec.Transport.Protocol == "" ||
-			mcpServer.Spec.Transport.Protocol == mcpv1.MCPProtocolAuto {
-			return true
-		}
-	}
-
-	return false
+	return IsSSEActive(mcpServer)
 }
 
 // isExplicitSSE returns true only when SSE is explicitly configured (not auto-detected)
@@ -208,6 +222,17 @@ func (h *HTTPResourceManager) getSSETerminationGracePeriod(mcpServer *mcpv1.MCPS
 	return &defaultGracePeriod
 }
 
+// getSidecarDrainTimeout returns how long the sidecar drains when the pod
+// terminates, in seconds: the grace period of the pod, less a margin for the
+// sidecar to shut down before it is killed.
+func (h *HTTPResourceManager) getSidecarDrainTimeout(mcpServer *mcpv1.MCPServer) int64 {
+	gracePeriod := defaultTerminationGracePeriod
+	if configured := h.getSSETerminationGracePeriod(mcpServer); configured != nil {
+		gracePeriod = *configured
+	}
+	return max(gracePeriod-sidecarDrainMargin, 1)
+}
+
 // getSSEMaxSurge returns the maxSurge value for SSE rolling updates.
 // Returns the configured value, or a default of "25%" if not specified.
 func (h *HTTPResourceManager) getSSEMaxSurge(mcpServer *mcpv1.MCPServer) *intstr.IntOrString {
@@ -227,16 +252,31 @@ func (h *HTTPResourceManager) getSSEMaxSurge(mcpServer *mcpv1.MCPServer) *intstr
 
 // createDeployment creates a deployment for HTTP transport
 func (h *HTTPResourceManager) createDeployment(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
-	deployment := h.buildDeployment(mcpServer)
+	return createDeployment(ctx, h.client, h.scheme, mcpServer, h.buildDeployment(mcpServer))
+}
+
+// updateDeployment updates the HTTP deployment
+func (h *HTTPResourceManager) updateDeployment(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	return updateDeployment(ctx, h.client, h.scheme, mcpServer, h.buildDeployment(mcpServer))
+}
 
-	if err := controllerutil.SetControllerReference(mcpServer, deployment, h.scheme); err != nil {
+// createDeployment creates the given deployment if it does not exist yet
+func createDeployment(
+	ctx context.Context,
+	k8sClient client.Client,
+	scheme *runtime.Scheme,
+	mcpServer *mcpv1.MCPServer,
+	deployment *appsv1.Deployment,
+) error {
+	if err := controllerutil.SetControllerReference(mcpServer, deployment, scheme); err != nil {
 		return err
 	}
 
 	found := &appsv1.Deployment{}
-	err := h.client.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
-		return h.client.Create(ctx, deployment)
+		metav1.SetMetaDataAnnotation(&deployment.ObjectMeta, TemplateHashAnnotation, TemplateHash(&deployment.Spec.Template))
+		return k8sClient.Create(ctx, deployment)
 	} else if err != nil {
 		return err
 	}
@@ -244,22 +284,26 @@ func (h *HTTPResourceManager) createDeployment(ctx context.Context, mcpServer *m
 	return nil
 }
 
-// updateDeployment updates the HTTP deployment using selective field comparison
+// updateDeployment updates a deployment using selective field comparison
 // to avoid reconciliation loops when HPA is managing replicas.
 //
 // This implements the recommended kubebuilder pattern: only update the specific
 // fields the operator manages, leaving HPA-managed fields untouched.
-func (h *HTTPResourceManager) updateDeployment(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
-	deployment := h.buildDeployment(mcpServer)
-
-	if err := controllerutil.SetControllerReference(mcpServer, deployment, h.scheme); err != nil {
+func updateDeployment(
+	ctx context.Context,
+	k8sClient client.Client,
+	scheme *runtime.Scheme,
+	mcpServer *mcpv1.MCPServer,
+	deployment *appsv1.Deployment,
+) error {
+	if err := controllerutil.SetControllerReference(mcpServer, deployment, scheme); err != nil {
 		return err
 	}
 
 	// Use retry logic for optimistic concurrency conflicts
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		found := &appsv1.Deployment{}
-		err := h.client.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, found)
 		if err != nil {
 			return err
 		}
@@ -272,8 +316,9 @@ func (h *HTTPResourceManager) updateDeployment(ctx context.Context, mcpServer *m
 		needsUpdate := false
 
 		// 1. Update replicas only if HPA is not enabled
-		// When HPA is enabled, it owns the replicas field via the scale subresource
-		if !isHPAEnabled(mcpServer) {
+		// When HPA is enabled, it owns the replicas field via the scale subresource.
+		// A server scaled to zero keeps its replicas until the activator scales it up.
+		if !isHPAEnabled(mcpServer) && !IsScaledToZero(mcpServer) {
 			if deployment.Spec.Replicas != nil &&
 				(found.Spec.Replicas == nil || *found.Spec.Replicas != *deployment.Spec.Replicas) {
 				found.Spec.Replicas = deployment.Spec.Replicas
@@ -281,18 +326,31 @@ func (h *HTTPResourceManager) updateDeployment(ctx context.Context, mcpServer *m
 			}
 		}
 
-		// 2. Update selector (immutable after creation, but verify for safety)
+		// 2. The selector is immutable. A Deployment created with another selector,
+		// e.g. before canary pods were excluded from it, is deleted without its
+		// pods. The next reconcile recreates it, and it adopts the orphaned
+		// ReplicaSet without rolling the pods.
 		if !reflect.DeepEqual(found.Spec.Selector, deployment.Spec.Selector) {
-			found.Spec.Selector = deployment.Spec.Selector
-			needsUpdate = true
+			if !found.DeletionTimestamp.IsZero() {
+				return nil
+			}
+			return k8sClient.Delete(ctx, found, client.PropagationPolicy(metav1.DeletePropagationOrphan))
 		}
 
 		// 3. Update pod template spec
 		// DeepEqual works here because 'found' was read from API server with all defaults applied,
 		// and 'deployment' contains our desired state. If they match, nothing changed.
-		if !reflect.DeepEqual(found.Spec.Template, deployment.Spec.Template) {
-			found.Spec.Template = deployment.Spec.Template
-			needsUpdate = true
+		// A canary rollout keeps the current template until the canary is promoted.
+		desiredHash := TemplateHash(&deployment.Spec.Template)
+		if !holdsStableTemplate(mcpServer, found, desiredHash) {
+			if !reflect.DeepEqual(found.Spec.Template, deployment.Spec.Template) {
+				found.Spec.Template = deployment.Spec.Template
+				needsUpdate = true
+			}
+			if found.Annotations[TemplateHashAnnotation] != desiredHash {
+				metav1.SetMetaDataAnnotation(&found.ObjectMeta, TemplateHashAnnotation, desiredHash)
+				needsUpdate = true
+			}
 		}
 
 		// 4. Update deployment strategy (for SSE-specific rolling update settings)
@@ -302,7 +360,7 @@ func (h *HTTPResourceManager) updateDeployment(ctx context.Context, mcpServer *m
 		}
 
 		if needsUpdate {
-			return h.client.Update(ctx, found)
+			return k8sClient.Update(ctx, found)
 		}
 
 		// No update needed - idempotent success
@@ -341,6 +399,16 @@ func (h *HTTPResourceManager) buildDeployment(mcpServer *mcpv1.MCPServer) *appsv
 	// Add health probes for HTTP endpoints
 	utils.AddHealthProbes(&container, mcpServer, port)
 
+	// Keep the server running while the sidecar drains, so the requests in
+	// flight still reach it
+	if h.shouldInjectSidecar(mcpServer) {
+		container.Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Sleep: &corev1.SleepAction{Seconds: h.getSidecarDrainTimeout(mcpServer)},
+			},
+		}
+	}
+
 	containers := []corev1.Container{container}
 
 	// Inject sidecar if metrics is enabled
@@ -364,6 +432,12 @@ func (h *HTTPResourceManager) buildDeployment(mcpServer *mcpv1.MCPServer) *appsv
 		})
 	}
 
+	// Add auth and audit volumes if configured
+	if h.shouldInjectSidecar(mcpServer) {
+		podSpec.Volumes = append(podSpec.Volumes, sidecarAuthVolumes(mcpServer)...)
+		podSpec.Volumes = append(podSpec.Volumes, sidecarAuditVolumes(mcpServer)...)
+	}
+
 	// Apply SSE-specific termination grace period
 	if gracePeriod := h.getSSETerminationGracePeriod(mcpServer); gracePeriod != nil {
 		podSpec.TerminationGracePeriodSeconds = gracePeriod
@@ -412,16 +486,25 @@ func (h *HTTPResourceManager) applySSEDeploymentSettings(mcpServer *mcpv1.MCPSer
 
 // createService creates a service for HTTP transport
 func (h *HTTPResourceManager) createService(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
-	service := h.buildService(mcpServer)
+	return createService(ctx, h.client, h.scheme, mcpServer, h.buildService(mcpServer))
+}
 
-	if err := controllerutil.SetControllerReference(mcpServer, service, h.scheme); err != nil {
+// createService creates the given service if it does not exist yet
+func createService(
+	ctx context.Context,
+	k8sClient client.Client,
+	scheme *runtime.Scheme,
+	mcpServer *mcpv1.MCPServer,
+	service *corev1.Service,
+) error {
+	if err := controllerutil.SetControllerReference(mcpServer, service, scheme); err != nil {
 		return err
 	}
 
 	found := &corev1.Service{}
-	err := h.client.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, found)
 	if err != nil && errors.IsNotFound(err) {
-		return h.client.Create(ctx, service)
+		return k8sClient.Create(ctx, service)
 	} else if err != nil {
 		return err
 	}
@@ -472,6 +555,12 @@ func (h *HTTPResourceManager) buildService(mcpServer *mcpv1.MCPServer) *corev1.S
 
 	service := utils.BuildService(mcpServer, servicePort, corev1.ProtocolTCP, annotations)
 
+	// While the server is scaled to zero, its traffic goes to the activator,
+	// which listens on the same ports
+	if IsScaledToZero(mcpServer) {
+		service.Spec.Selector = ActivatorSelectorLabels(mcpServer)
+	}
+
 	// Add metrics port if sidecar is enabled
 	if h.shouldInjectSidecar(mcpServer) {
 		metricsPort := h.getMetricsPort(mcpServer)
@@ -566,6 +655,11 @@ func (h *HTTPResourceManager) buildSidecarContainer(mcpServer *mcpv1.MCPServer,
 		"--log-level=info",
 	}
 
+	// Bound the tools given their own metric label if configured
+	if mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.MaxToolLabels != nil {
+		args = append(args, fmt.Sprintf("--max-tool-labels=%d", *mcpServer.Spec.Metrics.MaxToolLabels))
+	}
+
 	// Add TLS args if configured
 	if mcpServer.Spec.Sidecar != nil && mcpServer.Spec.Sidecar.TLS != nil && mcpServer.Spec.Sidecar.TLS.Enabled {
 		args = append(args,
@@ -578,6 +672,36 @@ func (h *HTTPResourceManager) buildSidecarContainer(mcpServer *mcpv1.MCPServer,
 		}
 	}
 
+	// Add authentication args if configured
+	args = append(args, sidecarAuthArgs(mcpServer)...)
+
+	// Add the policy the sidecar enforces, in the JSON form it parses
+	if mcpServer.Spec.Policy != nil {
+		if policy, err := json.Marshal(mcpServer.Spec.Policy); err == nil {
+			args = append(args, fmt.Sprintf("--policy=%s", policy))
+		}
+	}
+
+	// Add the rate limits the sidecar enforces, in the JSON form it parses
+	if mcpServer.Spec.Sidecar != nil && mcpServer.Spec.Sidecar.RateLimit != nil {
+		if limits, err := json.Marshal(mcpServer.Spec.Sidecar.RateLimit); err == nil {
+			args = append(args, fmt.Sprintf("--rate-limit=%s", limits))
+		}
+	}
+
+	// Add tracing args if configured
+	args = append(args, sidecarTracingArgs(mcpServer)...)
+
+	// Add audit args if configured
+	args = append(args, sidecarAuditArgs(mcpServer)...)
+
+	// Add MCP health check args if configured
+	args = append(args, sidecarHealthCheckArgs(mcpServer)...)
+
+	// Bound the drain by the grace period of the pod
+	drainTimeout := fmt.Sprintf("--drain-timeout=%ds", h.getSidecarDrainTimeout(mcpServer))
+	args = append(args, drainTimeout)
+
 	container := corev1.Container{
 		Name:  "mcp-proxy",
 		Image: sidecarImage,
@@ -618,6 +742,21 @@ func (h *HTTPResourceManager) buildSidecarContainer(mcpServer *mcpv1.MCPServer,
 			TimeoutSeconds:      3,
 			FailureThreshold:    3,
 		},
+		// Drain the sidecar before it receives SIGTERM: readiness fails,
+		// clients of event streams are asked to reconnect and requests in
+		// flight complete
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{
+					Command: []string{
+						sidecarBinaryPath,
+						"--mode=drain",
+						fmt.Sprintf("--metrics-addr=:%d", metricsPort),
+						drainTimeout,
+					},
+				},
+			},
+		},
 		Resources: h.getSidecarResources(mcpServer),
 		SecurityContext: &corev1.SecurityContext{
 			RunAsNonRoot:             boolPtr(true),
@@ -641,9 +780,234 @@ func (h *HTTPResourceManager) buildSidecarContainer(mcpServer *mcpv1.MCPServer,
 		})
 	}
 
+	// Identify the pod in the resource of exported spans
+	if mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.Tracing != nil {
+		container.Env = append(container.Env,
+			corev1.EnvVar{
+				Name: "K8S_POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+			corev1.EnvVar{
+				Name:  "OTEL_RESOURCE_ATTRIBUTES",
+				Value: fmt.Sprintf("k8s.namespace.name=%s,k8s.pod.name=$(K8S_POD_NAME)", mcpServer.Namespace),
+			},
+		)
+	}
+
+	// Add auth volume mounts if configured
+	if auth := getSidecarAuth(mcpServer); auth != nil {
+		if auth.APIKeys != nil {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      sidecarAPIKeysVolume,
+				MountPath: sidecarAPIKeysDir,
+				ReadOnly:  true,
+			})
+		}
+		if auth.JWT != nil && auth.JWT.JWKSConfigMapRef != nil {
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      sidecarJWKSVolume,
+				MountPath: sidecarJWKSDir,
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	// Add the audit volume mount if records are written to a file
+	if audit := getSidecarAudit(mcpServer); audit != nil && audit.Sink == mcpv1.AuditSinkFile {
+		volumeName := sidecarAuditVolume
+		if audit.File != nil && audit.File.VolumeName != "" {
+			volumeName = audit.File.VolumeName
+		}
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			MountPath: sidecarAuditDir,
+		})
+	}
+
 	return container
 }
 
+// getSidecarAudit returns the sidecar audit config, if any
+func getSidecarAudit(mcpServer *mcpv1.MCPServer) *mcpv1.SidecarAuditConfig {
+	if mcpServer.Spec.Sidecar == nil {
+		return nil
+	}
+	return mcpServer.Spec.Sidecar.Audit
+}
+
+// sidecarAuditArgs returns the sidecar args enabling audit records
+func sidecarAuditArgs(mcpServer *mcpv1.MCPServer) []string {
+	audit := getSidecarAudit(mcpServer)
+	if audit == nil {
+		return nil
+	}
+
+	args := []string{"--audit-file=-"}
+	if audit.Sink == mcpv1.AuditSinkFile {
+		fileName := defaultAuditFileName
+		if audit.File != nil && audit.File.FileName != "" {
+			fileName = audit.File.FileName
+		}
+		args = []string{fmt.Sprintf("--audit-file=%s/%s", sidecarAuditDir, fileName)}
+	}
+	if len(audit.Redact) > 0 {
+		args = append(args, fmt.Sprintf("--audit-redact=%s", strings.Join(audit.Redact, ",")))
+	}
+	if audit.MaxArgumentBytes > 0 {
+		args = append(args, fmt.Sprintf("--audit-max-argument-bytes=%d", audit.MaxArgumentBytes))
+	}
+
+	return args
+}
+
+// sidecarAuditVolumes returns the emptyDir audit records are written to when
+// the file sink names no volume of its own
+func sidecarAuditVolumes(mcpServer *mcpv1.MCPServer) []corev1.Volume {
+	audit := getSidecarAudit(mcpServer)
+	if audit == nil || audit.Sink != mcpv1.AuditSinkFile || (audit.File != nil && audit.File.VolumeName != "") {
+		return nil
+	}
+
+	return []corev1.Volume{{
+		Name:         sidecarAuditVolume,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}}
+}
+
+// sidecarTracingArgs returns the sidecar flags exporting spans, named after the MCPServer
+func sidecarTracingArgs(mcpServer *mcpv1.MCPServer) []string {
+	if mcpServer.Spec.Metrics == nil || mcpServer.Spec.Metrics.Tracing == nil {
+		return nil
+	}
+	tracing := mcpServer.Spec.Metrics.Tracing
+
+	args := []string{fmt.Sprintf("--tracing-service-name=%s", mcpServer.Name)}
+	if tracing.Exporter == mcpv1.TracingExporterStdout {
+		args = append(args, "--tracing-file=-")
+	} else {
+		args = append(args, fmt.Sprintf("--tracing-endpoint=%s", tracing.Endpoint))
+	}
+	if tracing.SamplingPercentage != nil {
+		ratio := strconv.FormatFloat(float64(*tracing.SamplingPercentage)/100, 'f', -1, 64)
+		args = append(args, fmt.Sprintf("--tracing-sample-ratio=%s", ratio))
+	}
+
+	return args
+}
+
+// sidecarHealthCheckArgs returns the sidecar args checking the server with
+// MCP requests on its transport path
+func sidecarHealthCheckArgs(mcpServer *mcpv1.MCPServer) []string {
+	if !utils.MCPHealthCheckEnabled(mcpServer) {
+		return nil
+	}
+
+	method := "ping"
+	timeoutSeconds := int32(5)
+	failureThreshold := int32(3)
+	if config := mcpServer.Spec.HealthCheck.MCP; config != nil {
+		if config.Method != "" {
+			method = config.Method
+		}
+		if config.TimeoutSeconds != nil {
+			timeoutSeconds = *config.TimeoutSeconds
+		}
+		if config.FailureThreshold != nil {
+			failureThreshold = *config.FailureThreshold
+		}
+	}
+
+	return []string{
+		"--health-check-mode=mcp",
+		fmt.Sprintf("--health-check-method=%s", method),
+		fmt.Sprintf("--health-check-path=%s", GetHTTPPath(mcpServer)),
+		fmt.Sprintf("--health-check-timeout=%ds", timeoutSeconds),
+		fmt.Sprintf("--health-check-failure-threshold=%d", failureThreshold),
+	}
+}
+
+// getSidecarAuth returns the sidecar authentication config, if any
+func getSidecarAuth(mcpServer *mcpv1.MCPServer) *mcpv1.SidecarAuthConfig {
+	if mcpServer.Spec.Sidecar == nil {
+		return nil
+	}
+	return mcpServer.Spec.Sidecar.Auth
+}
+
+// sidecarAuthArgs returns the sidecar args enabling authentication.
+// API keys and the JWKS ConfigMap are mounted as files the sidecar reads.
+func sidecarAuthArgs(mcpServer *mcpv1.MCPServer) []string {
+	auth := getSidecarAuth(mcpServer)
+	if auth == nil {
+		return nil
+	}
+
+	var args []string
+	if auth.APIKeys != nil {
+		args = append(args, fmt.Sprintf("--auth-api-keys-dir=%s", sidecarAPIKeysDir))
+	}
+
+	if jwt := auth.JWT; jwt != nil {
+		if jwt.JWKSConfigMapRef != nil {
+			args = append(args, fmt.Sprintf("--auth-jwks-file=%s/%s", sidecarJWKSDir, sidecarJWKSFile))
+		} else {
+			args = append(args, fmt.Sprintf("--auth-jwks-url=%s", jwt.JWKSURL))
+		}
+		if jwt.Issuer != "" {
+			args = append(args, fmt.Sprintf("--auth-issuer=%s", jwt.Issuer))
+		}
+		if len(jwt.Audiences) > 0 {
+			args = append(args, fmt.Sprintf("--auth-audiences=%s", strings.Join(jwt.Audiences, ",")))
+		}
+		if jwt.IdentityClaim != "" {
+			args = append(args, fmt.Sprintf("--auth-identity-claim=%s", jwt.IdentityClaim))
+		}
+		if len(jwt.AuthorizationServers) > 0 {
+			args = append(args, fmt.Sprintf("--auth-authorization-servers=%s", strings.Join(jwt.AuthorizationServers, ",")))
+		}
+		if jwt.Resource != "" {
+			args = append(args, fmt.Sprintf("--auth-resource=%s", jwt.Resource))
+		}
+	}
+
+	return args
+}
+
+// sidecarAuthVolumes returns the volumes holding the sidecar API keys and JWKS
+func sidecarAuthVolumes(mcpServer *mcpv1.MCPServer) []corev1.Volume {
+	auth := getSidecarAuth(mcpServer)
+	if auth == nil {
+		return nil
+	}
+
+	var volumes []corev1.Volume
+	if auth.APIKeys != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: sidecarAPIKeysVolume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: auth.APIKeys.SecretRef.Name,
+				},
+			},
+		})
+	}
+	if auth.JWT != nil && auth.JWT.JWKSConfigMapRef != nil {
+		ref := auth.JWT.JWKSConfigMapRef
+		volumes = append(volumes, corev1.Volume{
+			Name: sidecarJWKSVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: ref.LocalObjectReference,
+					Items:                []corev1.KeyToPath{{Key: ref.Key, Path: sidecarJWKSFile}},
+				},
+			},
+		})
+	}
+	return volumes
+}
+
 // getSidecarResources returns the resource requirements for the sidecar
 func (h *HTTPResourceManager) getSidecarResources(mcpServer *mcpv1.MCPServer) corev1.ResourceRequirements {
 	// Use custom resources if specified
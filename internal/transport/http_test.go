@@ -24,14 +24,17 @@ import (
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/utils"
 )
 
 func TestHTTPTransport(t *testing.T) {
@@ -187,6 +190,27 @@ var _ = Describe("HTTPResourceManager", func() {
 
 			Expect(*deployment.Spec.Replicas).To(Equal(int32(5)))
 		})
+
+		It("should recreate a deployment whose selector includes canary pods", func() {
+			key := client.ObjectKey{Name: mcpServer.Name, Namespace: mcpServer.Namespace}
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": mcpServer.Name}}
+			Expect(k8sClient.Update(ctx, deployment)).To(Succeed())
+
+			By("Deleting the deployment, since its selector is immutable")
+			Expect(httpManager.UpdateResources(ctx, mcpServer)).To(Succeed())
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, key, deployment))).To(BeTrue())
+
+			By("Creating it again with the selector excluding canary pods")
+			Expect(httpManager.CreateResources(ctx, mcpServer)).To(Succeed())
+			Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			Expect(deployment.Spec.Selector.MatchExpressions).To(ConsistOf(metav1.LabelSelectorRequirement{
+				Key:      utils.CanaryTrackLabel,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"canary"},
+			}))
+		})
 	})
 
 	Describe("GetTransportType", func() {
@@ -353,6 +377,185 @@ var _ = Describe("HTTPResourceManager", func() {
 			Expect(httpServicePort).NotTo(BeNil())
 			Expect(httpServicePort.Port).To(Equal(mcpv1.FallbackSidecarPort))
 		})
+
+		It("should pass the policy to the sidecar", func() {
+			container := httpManager.buildSidecarContainer(mcpServer, 3000)
+			for _, arg := range container.Args {
+				Expect(arg).NotTo(HavePrefix("--policy="))
+			}
+
+			mcpServer.Spec.Policy = &mcpv1.PolicySpec{
+				Tools: &mcpv1.PolicyRules{Deny: []string{"delete_*"}},
+				Identities: []mcpv1.IdentityPolicy{
+					{Names: []string{"admin"}, Tools: &mcpv1.PolicyRules{}},
+				},
+			}
+
+			container = httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElement(
+				`--policy={"tools":{"deny":["delete_*"]},"identities":[{"names":["admin"],"tools":{}}]}`,
+			))
+		})
+
+		It("should configure sidecar tracing", func() {
+			mcpServer.Spec.Metrics = &mcpv1.MetricsConfig{
+				Enabled: true,
+				Tracing: &mcpv1.TracingConfig{
+					Exporter:           mcpv1.TracingExporterOTLP,
+					Endpoint:           "http://otel-collector.observability:4318",
+					SamplingPercentage: ptr(int32(25)),
+				},
+			}
+
+			container := httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElements(
+				"--tracing-service-name=test-mcpserver",
+				"--tracing-endpoint=http://otel-collector.observability:4318",
+				"--tracing-sample-ratio=0.25",
+			))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{
+				Name:  "OTEL_RESOURCE_ATTRIBUTES",
+				Value: "k8s.namespace.name=default,k8s.pod.name=$(K8S_POD_NAME)",
+			}))
+
+			mcpServer.Spec.Metrics.Tracing = &mcpv1.TracingConfig{Exporter: mcpv1.TracingExporterStdout}
+			container = httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElement("--tracing-file=-"))
+			Expect(container.Args).NotTo(ContainElement(HavePrefix("--tracing-endpoint")))
+			Expect(container.Args).NotTo(ContainElement(HavePrefix("--tracing-sample-ratio")))
+		})
+
+		It("should pass the tool label limit to the sidecar", func() {
+			container := httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).NotTo(ContainElement(HavePrefix("--max-tool-labels")))
+
+			mcpServer.Spec.Metrics.MaxToolLabels = ptr(int32(25))
+
+			container = httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElement("--max-tool-labels=25"))
+		})
+
+		It("should pass the rate limits to the sidecar", func() {
+			mcpServer.Spec.Sidecar = &mcpv1.SidecarConfig{
+				RateLimit: &mcpv1.SidecarRateLimitConfig{
+					Rules: []mcpv1.RateLimitRule{{
+						By:                []mcpv1.RateLimitKey{mcpv1.RateLimitKeyIdentity, mcpv1.RateLimitKeyTool},
+						RequestsPerMinute: 10,
+						Tools:             []string{"expensive_*"},
+					}},
+				},
+			}
+
+			container := httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElement(
+				`--rate-limit={"rules":[{"by":["identity","tool"],"requestsPerMinute":10,"tools":["expensive_*"]}]}`,
+			))
+		})
+
+		It("should write audit records to stdout", func() {
+			mcpServer.Spec.Sidecar = &mcpv1.SidecarConfig{
+				Audit: &mcpv1.SidecarAuditConfig{
+					Sink:             mcpv1.AuditSinkStdout,
+					Redact:           []string{"$.password", "$..token"},
+					MaxArgumentBytes: 1024,
+				},
+			}
+
+			container := httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElements(
+				"--audit-file=-",
+				"--audit-redact=$.password,$..token",
+				"--audit-max-argument-bytes=1024",
+			))
+			Expect(container.VolumeMounts).To(BeEmpty())
+		})
+
+		It("should write audit records to a file on an emptyDir or a pod template volume", func() {
+			mcpServer.Spec.Sidecar = &mcpv1.SidecarConfig{
+				Audit: &mcpv1.SidecarAuditConfig{Sink: mcpv1.AuditSinkFile},
+			}
+
+			podSpec := httpManager.buildDeployment(mcpServer).Spec.Template.Spec
+			var sidecar *corev1.Container
+			for i := range podSpec.Containers {
+				if podSpec.Containers[i].Name == "mcp-proxy" {
+					sidecar = &podSpec.Containers[i]
+				}
+			}
+			Expect(sidecar).NotTo(BeNil())
+			Expect(sidecar.Args).To(ContainElement("--audit-file=/var/log/mcp-audit/audit.jsonl"))
+			Expect(sidecar.VolumeMounts).To(ContainElement(
+				corev1.VolumeMount{Name: "sidecar-audit", MountPath: "/var/log/mcp-audit"},
+			))
+			Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+				Name:         "sidecar-audit",
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}))
+
+			mcpServer.Spec.Sidecar.Audit.File = &mcpv1.AuditFileConfig{VolumeName: "audit-pvc", FileName: "tools.jsonl"}
+			podSpec = httpManager.buildDeployment(mcpServer).Spec.Template.Spec
+			for _, volume := range podSpec.Volumes {
+				Expect(volume.Name).NotTo(Equal("sidecar-audit"))
+			}
+			container := httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElement("--audit-file=/var/log/mcp-audit/tools.jsonl"))
+			Expect(container.VolumeMounts).To(ContainElement(
+				corev1.VolumeMount{Name: "audit-pvc", MountPath: "/var/log/mcp-audit"},
+			))
+		})
+
+		It("should configure sidecar authentication and mount its credentials", func() {
+			mcpServer.Spec.Sidecar = &mcpv1.SidecarConfig{
+				Auth: &mcpv1.SidecarAuthConfig{
+					APIKeys: &mcpv1.SidecarAPIKeyAuth{
+						SecretRef: corev1.LocalObjectReference{Name: "mcp-api-keys"},
+					},
+					JWT: &mcpv1.SidecarJWTAuth{
+						JWKSConfigMapRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "idp-jwks"},
+							Key:                  "keys.json",
+						},
+						Issuer:               "https://auth.example.com",
+						Audiences:            []string{"mcp", "tools"},
+						AuthorizationServers: []string{"https://auth.example.com"},
+					},
+				},
+			}
+
+			deployment := httpManager.buildDeployment(mcpServer)
+			podSpec := deployment.Spec.Template.Spec
+
+			var sidecar *corev1.Container
+			for i := range podSpec.Containers {
+				if podSpec.Containers[i].Name == "mcp-proxy" {
+					sidecar = &podSpec.Containers[i]
+				}
+			}
+			Expect(sidecar).NotTo(BeNil())
+			Expect(sidecar.Args).To(ContainElements(
+				"--auth-api-keys-dir=/etc/mcp-auth/api-keys",
+				"--auth-jwks-file=/etc/mcp-auth/jwks/jwks.json",
+				"--auth-issuer=https://auth.example.com",
+				"--auth-audiences=mcp,tools",
+				"--auth-authorization-servers=https://auth.example.com",
+			))
+			Expect(sidecar.VolumeMounts).To(ContainElements(
+				corev1.VolumeMount{Name: "sidecar-api-keys", MountPath: "/etc/mcp-auth/api-keys", ReadOnly: true},
+				corev1.VolumeMount{Name: "sidecar-jwks", MountPath: "/etc/mcp-auth/jwks", ReadOnly: true},
+			))
+
+			volumes := map[string]corev1.VolumeSource{}
+			for _, volume := range podSpec.Volumes {
+				volumes[volume.Name] = volume.VolumeSource
+			}
+			Expect(volumes).To(HaveKey("sidecar-api-keys"))
+			Expect(volumes["sidecar-api-keys"].Secret.SecretName).To(Equal("mcp-api-keys"))
+			Expect(volumes).To(HaveKey("sidecar-jwks"))
+			Expect(volumes["sidecar-jwks"].ConfigMap.Name).To(Equal("idp-jwks"))
+			Expect(volumes["sidecar-jwks"].ConfigMap.Items).To(ConsistOf(
+				corev1.KeyToPath{Key: "keys.json", Path: "jwks.json"},
+			))
+		})
 	})
 })
 
@@ -689,6 +892,69 @@ var _ = Describe("SSE-Aware Reconciliation", func() {
 			Expect(*gracePeriod).To(Equal(int64(90)))
 		})
 
+		It("should point the server probes at the MCP health check of the sidecar", func() {
+			mcpServer.Spec.Transport.Protocol = mcpv1.MCPProtocolStreamableHTTP
+			mcpServer.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			mcpServer.Spec.HealthCheck = &mcpv1.MCPServerHealthCheck{
+				Type: mcpv1.HealthCheckTypeMCP,
+				MCP:  &mcpv1.MCPHealthCheckConfig{Method: "initialize", FailureThreshold: ptr(int32(2))},
+			}
+
+			podSpec := httpManager.buildDeployment(mcpServer).Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(2))
+			server, sidecar := podSpec.Containers[0], podSpec.Containers[1]
+			Expect(server.ReadinessProbe.HTTPGet.Path).To(Equal("/targetz"))
+			Expect(server.ReadinessProbe.HTTPGet.Port).To(Equal(intstr.FromInt(9090)))
+			Expect(server.ReadinessProbe.FailureThreshold).To(Equal(int32(1)))
+			Expect(server.LivenessProbe.HTTPGet).To(Equal(server.ReadinessProbe.HTTPGet))
+			Expect(server.LivenessProbe.FailureThreshold).To(Equal(int32(3)))
+			Expect(sidecar.Args).To(ContainElements(
+				"--health-check-mode=mcp",
+				"--health-check-method=initialize",
+				"--health-check-path="+GetHTTPPath(mcpServer),
+				"--health-check-timeout=5s",
+				"--health-check-failure-threshold=2",
+			))
+
+			// Without the sidecar nothing checks the server
+			mcpServer.Spec.Metrics.Enabled = false
+			podSpec = httpManager.buildDeployment(mcpServer).Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(1))
+			Expect(podSpec.Containers[0].ReadinessProbe).To(BeNil())
+		})
+
+		It("should drain the sidecar within the termination grace period", func() {
+			mcpServer.Spec.Transport.Protocol = mcpv1.MCPProtocolStreamableHTTP
+			container := httpManager.buildSidecarContainer(mcpServer, 3000)
+			Expect(container.Args).To(ContainElement("--drain-timeout=25s"))
+			Expect(container.Lifecycle).NotTo(BeNil())
+			Expect(container.Lifecycle.PreStop.Exec.Command).To(Equal([]string{
+				"/mcp-proxy", "--mode=drain", "--metrics-addr=:9090", "--drain-timeout=25s",
+			}))
+
+			// SSE streams are drained for the SSE grace period
+			mcpServer.Spec.Transport.Protocol = mcpv1.MCPProtocolSSE
+			Expect(httpManager.getSidecarDrainTimeout(mcpServer)).To(Equal(int64(55)))
+
+			shortPeriod := int64(3)
+			mcpServer.Spec.Transport.Config.HTTP.SSE = &mcpv1.SSEConfig{
+				TerminationGracePeriodSeconds: &shortPeriod,
+			}
+			Expect(httpManager.getSidecarDrainTimeout(mcpServer)).To(Equal(int64(1)))
+		})
+
+		It("should keep the server running while the sidecar drains", func() {
+			mcpServer.Spec.Transport.Protocol = mcpv1.MCPProtocolStreamableHTTP
+			mcpServer.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			podSpec := httpManager.buildDeployment(mcpServer).Spec.Template.Spec
+			Expect(podSpec.Containers).To(HaveLen(2))
+			Expect(podSpec.Containers[0].Lifecycle.PreStop.Sleep).To(Equal(&corev1.SleepAction{Seconds: 25}))
+
+			mcpServer.Spec.Metrics.Enabled = false
+			podSpec = httpManager.buildDeployment(mcpServer).Spec.Template.Spec
+			Expect(podSpec.Containers[0].Lifecycle).To(BeNil())
+		})
+
 		It("should correctly determine session affinity requirement", func() {
 			// Non-SSE should not require affinity
 			mcpServer.Spec.Transport.Protocol = mcpv1.MCPProtocolStreamableHTTP
@@ -59,6 +59,15 @@ var _ = Describe("ManagerFactory", func() {
 			Expect(httpManager.GetTransportType()).To(Equal(mcpv1.MCPTransportHTTP))
 		})
 
+		It("should return StdioResourceManager for stdio transport", func() {
+			manager, err := factory.GetManager(mcpv1.MCPTransportStdio)
+			Expect(err).NotTo(HaveOccurred())
+
+			stdioManager, ok := manager.(*StdioResourceManager)
+			Expect(ok).To(BeTrue())
+			Expect(stdioManager.GetTransportType()).To(Equal(mcpv1.MCPTransportStdio))
+		})
+
 		It("should return error for unsupported transport type", func() {
 			_, err := factory.GetManager("unsupported")
 			Expect(err).To(HaveOccurred())
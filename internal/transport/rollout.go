@@ -0,0 +1,69 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+// TemplateHashAnnotation records on a Deployment the hash of the pod template
+// the operator built for it. The API server defaults fields of the stored
+// template, so the hash is the reliable way to tell whether the template changed.
+const TemplateHashAnnotation = "mcp.mcp-operator.io/template-hash"
+
+// TemplateHash returns a short hash identifying a pod template
+func TemplateHash(template *corev1.PodTemplateSpec) string {
+	// Marshalling a pod template cannot fail: it only holds JSON-safe types
+	data, _ := json.Marshal(template)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// IsCanaryRollout reports whether pod template changes of the MCPServer are
+// rolled out through a canary Deployment
+func IsCanaryRollout(mcpServer *mcpv1.MCPServer) bool {
+	return mcpServer.Spec.Rollout != nil && mcpServer.Spec.Rollout.Strategy == mcpv1.RolloutStrategyCanary
+}
+
+// holdsStableTemplate reports whether the stable Deployment must keep its
+// current pod template. With the canary strategy, the stable Deployment only
+// moves to a revision once the controller promoted its canary.
+// Deployments created before the template hash was recorded are updated in
+// place once, which records it.
+func holdsStableTemplate(mcpServer *mcpv1.MCPServer, found *appsv1.Deployment, desiredHash string) bool {
+	if !IsCanaryRollout(mcpServer) {
+		return false
+	}
+
+	current := found.Annotations[TemplateHashAnnotation]
+	if current == "" || current == desiredHash {
+		return false
+	}
+
+	rollout := mcpServer.Status.Rollout
+	promoted := rollout != nil &&
+		rollout.Phase == mcpv1.RolloutPhasePromoting &&
+		rollout.CanaryRevision == desiredHash
+	return !promoted
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/utils"
+)
+
+const (
+	// StdioBridgeVolumeName is the shared volume the bridge binary is installed into
+	StdioBridgeVolumeName = "mcp-bridge"
+
+	// StdioBridgeMountPath is where the bridge volume is mounted in both containers
+	StdioBridgeMountPath = "/mcp-bridge"
+
+	// StdioBridgeBinaryPath is the path of the installed bridge binary
+	StdioBridgeBinaryPath = StdioBridgeMountPath + "/mcp-proxy"
+
+	// stdioBridgeInitContainerName is the init container that installs the bridge
+	stdioBridgeInitContainerName = "install-mcp-bridge"
+)
+
+// StdioResourceManager manages resources for stdio transport.
+//
+// Stdio servers cannot be reached over the network, so the MCP server container
+// runs the mcp-proxy binary in stdio-bridge mode as its entrypoint. The bridge
+// spawns the configured command and exposes it as Streamable HTTP on the
+// transport port. The binary is copied from the sidecar image by an init
+// container, which keeps the server image unchanged.
+//
+// Everything past the bridge (Service, sidecar, validation) behaves exactly as
+// for HTTP transport, so the HTTP manager builds the base resources.
+type StdioResourceManager struct {
+	client client.Client
+	scheme *runtime.Scheme
+	http   *HTTPResourceManager
+}
+
+// NewStdioResourceManager creates a new StdioResourceManager
+func NewStdioResourceManager(k8sClient client.Client, scheme *runtime.Scheme) *StdioResourceManager {
+	return &StdioResourceManager{
+		client: k8sClient,
+		scheme: scheme,
+		http:   NewHTTPResourceManager(k8sClient, scheme),
+	}
+}
+
+// CreateResources creates stdio transport resources
+func (s *StdioResourceManager) CreateResources(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	if err := s.validate(mcpServer); err != nil {
+		return err
+	}
+
+	// Create deployment
+	if err := createDeployment(ctx, s.client, s.scheme, mcpServer, s.buildDeployment(mcpServer)); err != nil {
+		return err
+	}
+
+	// Create service
+	if err := createService(ctx, s.client, s.scheme, mcpServer, s.buildService(mcpServer)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateResources updates stdio transport resources
+func (s *StdioResourceManager) UpdateResources(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	if err := s.validate(mcpServer); err != nil {
+		return err
+	}
+
+	// Update deployment
+	if err := updateDeployment(ctx, s.client, s.scheme, mcpServer, s.buildDeployment(mcpServer)); err != nil {
+		return err
+	}
+
+	// Update service
+	return utils.UpdateService(ctx, s.client, s.scheme, mcpServer, s.buildService(mcpServer))
+}
+
+// DeleteResources cleans up stdio transport resources
+func (s *StdioResourceManager) DeleteResources(ctx context.Context, mcpServer *mcpv1.MCPServer) error {
+	// Resources will be cleaned up automatically via owner references
+	return nil
+}
+
+// GetTransportType returns the transport type
+func (s *StdioResourceManager) GetTransportType() mcpv1.MCPTransportType {
+	return mcpv1.MCPTransportStdio
+}
+
+// RequiresService returns true since the bridge is exposed over HTTP
+func (s *StdioResourceManager) RequiresService() bool {
+	return true
+}
+
+// DesiredDeployment returns the Deployment the manager maintains for the MCPServer
+func (s *StdioResourceManager) DesiredDeployment(mcpServer *mcpv1.MCPServer) *appsv1.Deployment {
+	return s.buildDeployment(mcpServer)
+}
+
+// validate checks that the MCPServer can be run behind the stdio bridge
+func (s *StdioResourceManager) validate(mcpServer *mcpv1.MCPServer) error {
+	// The bridge replaces the container entrypoint, so the server command
+	// cannot be inherited from the image.
+	if len(mcpServer.Spec.Command) == 0 {
+		return fmt.Errorf("stdio transport requires spec.command to be set")
+	}
+	return nil
+}
+
+// buildDeployment builds a deployment that runs the server behind the stdio bridge
+func (s *StdioResourceManager) buildDeployment(mcpServer *mcpv1.MCPServer) *appsv1.Deployment {
+	deployment := s.http.buildDeployment(mcpServer)
+	podSpec := &deployment.Spec.Template.Spec
+
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name != "mcp-server" {
+			continue
+		}
+
+		container.Command = []string{StdioBridgeBinaryPath}
+		container.Args = s.buildBridgeArgs(mcpServer)
+		container.Env = s.buildBridgeEnv(container.Env)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      StdioBridgeVolumeName,
+			MountPath: StdioBridgeMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	podSpec.InitContainers = append(podSpec.InitContainers, s.buildBridgeInstallContainer(mcpServer))
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: StdioBridgeVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	return deployment
+}
+
+// buildBridgeArgs returns the bridge arguments followed by the server command
+func (s *StdioResourceManager) buildBridgeArgs(mcpServer *mcpv1.MCPServer) []string {
+	args := []string{
+		"--mode=stdio-bridge",
+		fmt.Sprintf("--listen-addr=:%d", s.http.getHTTPPort(mcpServer)),
+		fmt.Sprintf("--bridge-path=%s", s.http.getHTTPPath(mcpServer)),
+		"--log-level=info",
+		"--",
+	}
+	args = append(args, mcpServer.Spec.Command...)
+	return append(args, mcpServer.Spec.Args...)
+}
+
+// buildBridgeEnv returns a copy of env with MCP_TRANSPORT set to stdio.
+// The slice is copied since it may share its backing array with the spec.
+func (s *StdioResourceManager) buildBridgeEnv(env []corev1.EnvVar) []corev1.EnvVar {
+	result := make([]corev1.EnvVar, 0, len(env))
+	for _, e := range env {
+		if e.Name == "MCP_TRANSPORT" {
+			e.Value = string(mcpv1.MCPTransportStdio)
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// buildBridgeInstallContainer builds the init container that copies the
+// bridge binary from the sidecar image into the shared volume
+func (s *StdioResourceManager) buildBridgeInstallContainer(mcpServer *mcpv1.MCPServer) corev1.Container {
+	return corev1.Container{
+		Name:  stdioBridgeInitContainerName,
+		Image: s.http.getSidecarImage(mcpServer),
+		Args: []string{
+			"--mode=install",
+			fmt.Sprintf("--install-path=%s", StdioBridgeBinaryPath),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      StdioBridgeVolumeName,
+				MountPath: StdioBridgeMountPath,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPURequest),
+				corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryRequest),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(mcpv1.DefaultSidecarCPULimit),
+				corev1.ResourceMemory: resource.MustParse(mcpv1.DefaultSidecarMemoryLimit),
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsNonRoot:             boolPtr(true),
+			ReadOnlyRootFilesystem:   boolPtr(true),
+			AllowPrivilegeEscalation: boolPtr(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+}
+
+// buildService builds the service exposing the bridge
+func (s *StdioResourceManager) buildService(mcpServer *mcpv1.MCPServer) *corev1.Service {
+	service := s.http.buildService(mcpServer)
+	service.Annotations["mcp.transport.type"] = string(mcpv1.MCPTransportStdio)
+	return service
+}
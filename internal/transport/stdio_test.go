@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transport
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+var _ = Describe("StdioResourceManager", func() {
+	var (
+		ctx          context.Context
+		k8sClient    client.Client
+		stdioManager *StdioResourceManager
+		mcpServer    *mcpv1.MCPServer
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		runtimeScheme := runtime.NewScheme()
+		Expect(scheme.AddToScheme(runtimeScheme)).To(Succeed())
+		Expect(mcpv1.AddToScheme(runtimeScheme)).To(Succeed())
+
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(runtimeScheme).
+			Build()
+
+		stdioManager = NewStdioResourceManager(k8sClient, runtimeScheme)
+
+		mcpServer = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "stdio-server",
+				Namespace: "default",
+			},
+			Spec: mcpv1.MCPServerSpec{
+				Image:   "node:22-alpine",
+				Command: []string{"npx", "-y"},
+				Args:    []string{"@modelcontextprotocol/server-everything"},
+				Environment: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+				},
+				Transport: &mcpv1.MCPServerTransport{
+					Type: mcpv1.MCPTransportStdio,
+				},
+			},
+		}
+	})
+
+	It("should report stdio transport type", func() {
+		Expect(stdioManager.GetTransportType()).To(Equal(mcpv1.MCPTransportStdio))
+		Expect(stdioManager.RequiresService()).To(BeTrue())
+	})
+
+	It("should reject servers without a command", func() {
+		mcpServer.Spec.Command = nil
+		err := stdioManager.CreateResources(ctx, mcpServer)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.command"))
+	})
+
+	Describe("buildDeployment", func() {
+		It("should run the server command behind the bridge", func() {
+			deployment := stdioManager.buildDeployment(mcpServer)
+			podSpec := deployment.Spec.Template.Spec
+
+			Expect(podSpec.Containers).To(HaveLen(1))
+			container := podSpec.Containers[0]
+			Expect(container.Image).To(Equal("node:22-alpine"))
+			Expect(container.Command).To(Equal([]string{StdioBridgeBinaryPath}))
+			Expect(container.Args).To(Equal([]string{
+				"--mode=stdio-bridge",
+				"--listen-addr=:8080",
+				"--bridge-path=/mcp",
+				"--log-level=info",
+				"--",
+				"npx", "-y", "@modelcontextprotocol/server-everything",
+			}))
+			Expect(container.Ports[0].ContainerPort).To(Equal(int32(8080)))
+			Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+				Name:      StdioBridgeVolumeName,
+				MountPath: StdioBridgeMountPath,
+				ReadOnly:  true,
+			}))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "MCP_TRANSPORT", Value: "stdio"}))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"}))
+		})
+
+		It("should install the bridge binary with an init container", func() {
+			deployment := stdioManager.buildDeployment(mcpServer)
+			podSpec := deployment.Spec.Template.Spec
+
+			Expect(podSpec.InitContainers).To(HaveLen(1))
+			initContainer := podSpec.InitContainers[0]
+			Expect(initContainer.Image).To(Equal(mcpv1.DefaultSidecarImage))
+			Expect(initContainer.Args).To(ContainElement("--mode=install"))
+			Expect(initContainer.Args).To(ContainElement("--install-path=" + StdioBridgeBinaryPath))
+
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", StdioBridgeVolumeName)))
+		})
+
+		It("should use the configured HTTP port and path for the bridge", func() {
+			mcpServer.Spec.Transport.Config = &mcpv1.MCPTransportConfigDetails{
+				HTTP: &mcpv1.MCPHTTPTransportConfig{
+					Port: 3000,
+					Path: "/rpc",
+				},
+			}
+
+			deployment := stdioManager.buildDeployment(mcpServer)
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Args).To(ContainElement("--listen-addr=:3000"))
+			Expect(container.Args).To(ContainElement("--bridge-path=/rpc"))
+		})
+
+		It("should keep the metrics sidecar pointed at the bridge", func() {
+			mcpServer.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+
+			deployment := stdioManager.buildDeployment(mcpServer)
+			podSpec := deployment.Spec.Template.Spec
+
+			Expect(podSpec.Containers).To(HaveLen(2))
+			Expect(podSpec.Containers[1].Name).To(Equal("mcp-proxy"))
+			Expect(podSpec.Containers[1].Args).To(ContainElement("--target-addr=localhost:8080"))
+		})
+
+		It("should not modify the MCPServer spec environment", func() {
+			mcpServer.Spec.Environment = make([]corev1.EnvVar, 1, 10)
+			mcpServer.Spec.Environment[0] = corev1.EnvVar{Name: "A", Value: "1"}
+
+			stdioManager.buildDeployment(mcpServer)
+			Expect(mcpServer.Spec.Environment[:cap(mcpServer.Spec.Environment)][1].Value).NotTo(Equal("stdio"))
+		})
+	})
+
+	It("should create deployment and service", func() {
+		Expect(stdioManager.CreateResources(ctx, mcpServer)).To(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "stdio-server", Namespace: "default"}, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+
+		service := &corev1.Service{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "stdio-server", Namespace: "default"}, service)).To(Succeed())
+		Expect(service.Annotations).To(HaveKeyWithValue("mcp.transport.type", "stdio"))
+		Expect(service.Spec.Ports[0].Port).To(Equal(int32(8080)))
+	})
+})
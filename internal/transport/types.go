@@ -43,6 +43,9 @@ type ResourceManager interface {
 
 	// RequiresService returns whether this transport needs a Service resource
 	RequiresService() bool
+
+	// DesiredDeployment returns the Deployment the manager maintains for the MCPServer
+	DesiredDeployment(mcpServer *mcpv1.MCPServer) *appsv1.Deployment
 }
 
 // ResourceManagerConfig contains common configuration for resource managers
@@ -79,6 +82,47 @@ func GetTransportPort(mcpServer *mcpv1.MCPServer) int32 {
 	return 8080 // default
 }
 
+// GetHTTPPath returns the path the MCP server serves its endpoint on
+func GetHTTPPath(mcpServer *mcpv1.MCPServer) string {
+	if mcpServer.Spec.Transport != nil &&
+		mcpServer.Spec.Transport.Config != nil &&
+		mcpServer.Spec.Transport.Config.HTTP != nil &&
+		mcpServer.Spec.Transport.Config.HTTP.Path != "" {
+		return mcpServer.Spec.Transport.Config.HTTP.Path
+	}
+	return "/mcp" // default per ADR
+}
+
+// IsSSEActive returns true if SSE transport is active (explicit or auto-detected).
+// SSE is considered active when:
+// 1. transport.protocol is explicitly set to "sse", OR
+// 2. transport.protocol is "auto" AND SSE was detected (stored in status.resolvedTransport)
+func IsSSEActive(mcpServer *mcpv1.MCPServer) bool {
+	// Check for explicit SSE configuration
+	if mcpServer.Spec.Transport != nil && mcpServer.Spec.Transport.Protocol == mcpv1.MCPProtocolSSE {
+		return true
+	}
+
+	// Check for auto-detected SSE (resolved in status)
+	if mcpServer.Status.ResolvedTransport != nil &&
+		mcpServer.Status.ResolvedTransport.Protocol == mcpv1.MCPProtocolSSE {
+		return true
+	}
+
+	// Also check validation status for detected protocol (legacy/fallback)
+	if mcpServer.Status.Validation != nil &&
+		mcpServer.Status.Validation.Protocol == string(mcpv1.MCPProtocolSSE) {
+		// Only use this if auto-detect mode is enabled
+		if mcpServer.Spec.Transport == nil ||
+			mcpServer.Spec.Transport.Protocol == "" ||
+			mcpServer.Spec.Transport.Protocol == mcpv1.MCPProtocolAuto {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetServicePort returns the port exposed by the Service
 // When sidecar is enabled, this returns the sidecar port
 // Otherwise, it returns the transport port
@@ -108,3 +152,18 @@ func GetSidecarPort(mcpServer *mcpv1.MCPServer) int32 {
 
 	return mcpv1.DefaultSidecarPort
 }
+
+// IsScaledToZero returns true while the server Deployment is scaled to zero
+// and the activator receives the traffic of the server in its place
+func IsScaledToZero(mcpServer *mcpv1.MCPServer) bool {
+	return mcpServer.Status.ScaleToZero != nil && mcpServer.Status.ScaleToZero.Idle
+}
+
+// ActivatorSelectorLabels selects the activator pods of an MCPServer. Like
+// session router pods, they carry no "app" label.
+func ActivatorSelectorLabels(mcpServer *mcpv1.MCPServer) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":     "mcp-activator",
+		"app.kubernetes.io/instance": mcpServer.Name,
+	}
+}
@@ -32,6 +32,11 @@ import (
 	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
 )
 
+// CanaryTrackLabel tells canary pods apart from stable ones. Canary pods carry
+// it with the value "canary", and the stable Deployment selects only pods
+// without that value, so the two Deployments never select the same pods.
+const CanaryTrackLabel = "mcp.mcp-operator.io/track"
+
 // BuildStandardLabels constructs the standard labels for MCPServer resources
 func BuildStandardLabels(mcpServer *mcpv1.MCPServer) map[string]string {
 	labels := map[string]string{
@@ -166,6 +171,12 @@ func AddHealthProbes(container *corev1.Container, mcpServer *mcpv1.MCPServer, po
 		return
 	}
 
+	// The sidecar checks the server with MCP requests, and reports the result
+	if mcpServer.Spec.HealthCheck.Type == mcpv1.HealthCheckTypeMCP {
+		addMCPHealthProbes(container, mcpServer)
+		return
+	}
+
 	// Get health check path
 	healthPath := "/health"
 	if mcpServer.Spec.HealthCheck.Path != "" {
@@ -197,6 +208,54 @@ func AddHealthProbes(container *corev1.Container, mcpServer *mcpv1.MCPServer, po
 	container.ReadinessProbe = probe
 }
 
+// MCPHealthCheckEnabled reports whether the sidecar checks the server with MCP requests
+func MCPHealthCheckEnabled(mcpServer *mcpv1.MCPServer) bool {
+	healthCheck := mcpServer.Spec.HealthCheck
+	if healthCheck == nil || healthCheck.Type != mcpv1.HealthCheckTypeMCP {
+		return false
+	}
+	if healthCheck.Enabled != nil && !*healthCheck.Enabled {
+		return false
+	}
+	return mcpServer.Spec.Metrics != nil && mcpServer.Spec.Metrics.Enabled
+}
+
+// addMCPHealthProbes points the probes of the server container at the result
+// of the MCP health check of the sidecar. The sidecar already waits for the
+// configured number of failed checks, so the first failed readiness probe takes
+// the pod out of the Service. Liveness tolerates a few failed probes, so that a
+// sidecar restart or a slow check does not restart the server.
+// Without the sidecar, nothing checks the server and no probe is added.
+func addMCPHealthProbes(container *corev1.Container, mcpServer *mcpv1.MCPServer) {
+	if !MCPHealthCheckEnabled(mcpServer) {
+		return
+	}
+
+	metricsPort := mcpServer.Spec.Metrics.Port
+	if metricsPort == 0 {
+		metricsPort = mcpv1.DefaultMetricsPort
+	}
+
+	probe := func(failureThreshold int32) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/targetz",
+					Port: intstr.FromInt(int(metricsPort)),
+				},
+			},
+			InitialDelaySeconds: 30,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+			FailureThreshold:    failureThreshold,
+			SuccessThreshold:    1,
+		}
+	}
+
+	container.LivenessProbe = probe(3)
+	container.ReadinessProbe = probe(1)
+}
+
 // BuildBasePodSpec creates a base PodSpec with common configuration
 func BuildBasePodSpec(mcpServer *mcpv1.MCPServer, containers []corev1.Container) corev1.PodSpec {
 	// Apply pod-level security context defaults
@@ -306,10 +365,18 @@ func BuildDeployment(mcpServer *mcpv1.MCPServer, podSpec corev1.PodSpec) *appsv1
 	annotations := BuildAnnotations(mcpServer)
 
 	deploymentSpec := appsv1.DeploymentSpec{
+		// Canary pods also carry the app label, which the Service selects,
+		// but belong to the canary Deployment and must not count as stable
+		// pods, e.g. in the HPA that scales on this Deployment's selector
 		Selector: &metav1.LabelSelector{
 			MatchLabels: map[string]string{
 				"app": mcpServer.Name,
 			},
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      CanaryTrackLabel,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"canary"},
+			}},
 		},
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -176,8 +177,12 @@ var _ = Describe("Resource Utils", func() {
 			// Check deployment labels
 			Expect(deployment.Labels).To(HaveKeyWithValue("app", "test-server"))
 
-			// Check selector
+			// Check selector, which leaves canary pods to the canary Deployment
 			Expect(deployment.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", "test-server"))
+			selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"app": "test-server"})).To(BeTrue())
+			Expect(selector.Matches(labels.Set{"app": "test-server", CanaryTrackLabel: "canary"})).To(BeFalse())
 
 			// Check pod template labels
 			Expect(deployment.Spec.Template.Labels).To(HaveKeyWithValue("app", "test-server"))
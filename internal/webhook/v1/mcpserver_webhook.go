@@ -0,0 +1,764 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+	"github.com/vitorbari/mcp-operator/internal/transport"
+)
+
+// log is for logging in this package.
+var mcpserverlog = logf.Log.WithName("mcpserver-resource")
+
+// validCapabilities are the values accepted in spec.validation.requiredCapabilities
+var validCapabilities = []string{"tools", "resources", "prompts"}
+
+// SetupMCPServerWebhookWithManager registers the webhook for MCPServer in the manager.
+func SetupMCPServerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&mcpv1.MCPServer{}).
+		WithValidator(&MCPServerCustomValidator{}).
+		WithDefaulter(&MCPServerCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-mcp-mcp-operator-io-v1-mcpserver,mutating=true,failurePolicy=fail,sideEffects=None,groups=mcp.mcp-operator.io,resources=mcpservers,verbs=create;update,versions=v1,name=mmcpserver-v1.kb.io,admissionReviewVersions=v1
+
+// MCPServerCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind MCPServer when those are created or updated.
+type MCPServerCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &MCPServerCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind MCPServer.
+func (d *MCPServerCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	mcpserver, ok := obj.(*mcpv1.MCPServer)
+	if !ok {
+		return fmt.Errorf("expected an MCPServer object but got %T", obj)
+	}
+	mcpserverlog.Info("Defaulting for MCPServer", "name", mcpserver.GetName())
+
+	// Normalize required capabilities so "Tools" and " tools" are not rejected
+	// as unknown values and duplicates don't show up in status
+	if mcpserver.Spec.Validation != nil && len(mcpserver.Spec.Validation.RequiredCapabilities) > 0 {
+		mcpserver.Spec.Validation.RequiredCapabilities = normalizeCapabilities(mcpserver.Spec.Validation.RequiredCapabilities)
+	}
+
+	return nil
+}
+
+// normalizeCapabilities lowercases, trims and de-duplicates capability names, preserving order
+func normalizeCapabilities(capabilities []string) []string {
+	seen := make(map[string]bool, len(capabilities))
+	normalized := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		capability = strings.ToLower(strings.TrimSpace(capability))
+		if seen[capability] {
+			continue
+		}
+		seen[capability] = true
+		normalized = append(normalized, capability)
+	}
+	return normalized
+}
+
+// +kubebuilder:webhook:path=/validate-mcp-mcp-operator-io-v1-mcpserver,mutating=false,failurePolicy=fail,sideEffects=None,groups=mcp.mcp-operator.io,resources=mcpservers,verbs=create;update,versions=v1,name=vmcpserver-v1.kb.io,admissionReviewVersions=v1
+
+// MCPServerCustomValidator struct is responsible for validating the MCPServer resource
+// when it is created, updated, or deleted.
+type MCPServerCustomValidator struct{}
+
+var _ webhook.CustomValidator = &MCPServerCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type MCPServer.
+func (v *MCPServerCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	mcpserver, ok := obj.(*mcpv1.MCPServer)
+	if !ok {
+		return nil, fmt.Errorf("expected a MCPServer object but got %T", obj)
+	}
+	mcpserverlog.Info("Validation for MCPServer upon creation", "name", mcpserver.GetName())
+
+	return validateMCPServer(mcpserver)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type MCPServer.
+func (v *MCPServerCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	mcpserver, ok := newObj.(*mcpv1.MCPServer)
+	if !ok {
+		return nil, fmt.Errorf("expected a MCPServer object for the newObj but got %T", newObj)
+	}
+	oldMCPServer, ok := oldObj.(*mcpv1.MCPServer)
+	if !ok {
+		return nil, fmt.Errorf("expected a MCPServer object for the oldObj but got %T", oldObj)
+	}
+	mcpserverlog.Info("Validation for MCPServer upon update", "name", mcpserver.GetName())
+
+	// Servers created before a rule existed must still accept the metadata and
+	// finalizer updates of the controller, so only a changed spec is re-checked
+	if !mcpserver.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldMCPServer.Spec, mcpserver.Spec) {
+		return nil, nil
+	}
+
+	return validateMCPServer(mcpserver)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type MCPServer.
+func (v *MCPServerCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateMCPServer runs the semantic checks that the CRD schema cannot express
+// and aggregates them into a single Invalid error
+func validateMCPServer(mcpserver *mcpv1.MCPServer) (admission.Warnings, error) {
+	var allErrs field.ErrorList
+	var warnings admission.Warnings
+
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validatePorts(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateSidecarTLS(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateHPA(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateRequiredCapabilities(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateSSEConfig(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateSessionRouting(mcpserver, specPath)...)
+	allErrs = append(allErrs, validatePolicy(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateSidecarAuth(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateRateLimit(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateAudit(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateTracing(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateRollout(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateExposure(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateNetworkPolicy(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateDisruption(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateHealthCheck(mcpserver, specPath)...)
+	allErrs = append(allErrs, validateScaleToZero(mcpserver, specPath)...)
+
+	if mcpserver.Spec.Sidecar != nil && !isMetricsEnabled(mcpserver) {
+		warnings = append(warnings, "spec.sidecar is ignored because spec.metrics.enabled is false")
+	}
+	if mcpserver.Spec.NetworkPolicy != nil && len(mcpserver.Spec.NetworkPolicy.MetricsFrom) > 0 &&
+		!isMetricsEnabled(mcpserver) {
+		warnings = append(warnings, "spec.networkPolicy.metricsFrom is ignored because spec.metrics.enabled is false")
+	}
+	if warning := disruptionWarning(mcpserver); warning != "" {
+		warnings = append(warnings, warning)
+	}
+	if warning := scaleToZeroWarning(mcpserver); warning != "" {
+		warnings = append(warnings, warning)
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+
+	return warnings, apierrors.NewInvalid(
+		schema.GroupKind{Group: mcpv1.GroupVersion.Group, Kind: "MCPServer"},
+		mcpserver.Name, allErrs)
+}
+
+// validatePorts rejects ports that would collide inside the pod or on the Service.
+// The sidecar and metrics ports only exist when the metrics sidecar is injected,
+// and the sidecar port is resolved the same way the controller resolves it.
+func validatePorts(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !isMetricsEnabled(mcpserver) {
+		return allErrs
+	}
+
+	serverPort := transport.GetTransportPort(mcpserver)
+	sidecarPort := transport.GetSidecarPort(mcpserver)
+	metricsPort := mcpv1.DefaultMetricsPort
+	if mcpserver.Spec.Metrics.Port != 0 {
+		metricsPort = mcpserver.Spec.Metrics.Port
+	}
+
+	httpPortPath := specPath.Child("transport", "config", "http", "port")
+	sidecarPortPath := specPath.Child("sidecar", "port")
+	metricsPortPath := specPath.Child("metrics", "port")
+
+	if sidecarPort == serverPort {
+		allErrs = append(allErrs, field.Invalid(sidecarPortPath, sidecarPort,
+			fmt.Sprintf("conflicts with %s; the sidecar and the MCP server share the pod network", httpPortPath)))
+	}
+	if metricsPort == serverPort {
+		allErrs = append(allErrs, field.Invalid(metricsPortPath, metricsPort,
+			fmt.Sprintf("conflicts with %s", httpPortPath)))
+	}
+	if metricsPort == sidecarPort {
+		allErrs = append(allErrs, field.Invalid(metricsPortPath, metricsPort,
+			fmt.Sprintf("conflicts with %s", sidecarPortPath)))
+	}
+
+	// The Service exposes the metrics port next to the MCP port
+	if mcpserver.Spec.Service != nil && mcpserver.Spec.Service.Port != 0 && mcpserver.Spec.Service.Port == metricsPort {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("service", "port"), mcpserver.Spec.Service.Port,
+			fmt.Sprintf("conflicts with %s", metricsPortPath)))
+	}
+
+	return allErrs
+}
+
+// validateSidecarTLS requires a Secret when sidecar TLS is enabled
+func validateSidecarTLS(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.Sidecar == nil || mcpserver.Spec.Sidecar.TLS == nil {
+		return allErrs
+	}
+
+	tls := mcpserver.Spec.Sidecar.TLS
+	if tls.Enabled && tls.SecretName == "" {
+		allErrs = append(allErrs, field.Required(specPath.Child("sidecar", "tls", "secretName"),
+			"must be set when TLS is enabled"))
+	}
+
+	return allErrs
+}
+
+// validatePolicy rejects policies that would not be enforced. Policies are
+// enforced by the sidecar, which is only injected with metrics enabled.
+func validatePolicy(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.Policy == nil {
+		return allErrs
+	}
+
+	if !isMetricsEnabled(mcpserver) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("policy"),
+			"requires spec.metrics.enabled; policies are enforced by the sidecar"))
+	}
+
+	if len(mcpserver.Spec.Policy.Identities) > 0 && (mcpserver.Spec.Sidecar == nil || mcpserver.Spec.Sidecar.Auth == nil) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("policy", "identities"),
+			"requires spec.sidecar.auth; identities only match clients the sidecar authenticated"))
+	}
+
+	return allErrs
+}
+
+// validateSidecarAuth requires a credential source for sidecar authentication,
+// and an audience for JWTs, and rejects it where no sidecar runs.
+func validateSidecarAuth(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.Sidecar == nil || mcpserver.Spec.Sidecar.Auth == nil {
+		return allErrs
+	}
+
+	auth := mcpserver.Spec.Sidecar.Auth
+	authPath := specPath.Child("sidecar", "auth")
+	if !isMetricsEnabled(mcpserver) {
+		allErrs = append(allErrs, field.Forbidden(authPath,
+			"requires spec.metrics.enabled; authentication is performed by the sidecar"))
+	}
+
+	if auth.APIKeys == nil && auth.JWT == nil {
+		allErrs = append(allErrs, field.Required(authPath, "one of apiKeys or jwt must be set"))
+	}
+	if auth.APIKeys != nil && auth.APIKeys.SecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(authPath.Child("apiKeys", "secretRef", "name"), ""))
+	}
+	if jwt := auth.JWT; jwt != nil {
+		jwtPath := authPath.Child("jwt")
+		switch {
+		case jwt.JWKSURL == "" && jwt.JWKSConfigMapRef == nil:
+			allErrs = append(allErrs, field.Required(jwtPath, "one of jwksURL or jwksConfigMapRef must be set"))
+		case jwt.JWKSURL != "" && jwt.JWKSConfigMapRef != nil:
+			allErrs = append(allErrs, field.Forbidden(jwtPath.Child("jwksConfigMapRef"),
+				"cannot be set together with jwksURL"))
+		}
+		if len(jwt.Audiences) == 0 {
+			allErrs = append(allErrs, field.Required(jwtPath.Child("audiences"),
+				"at least one audience is required, so tokens issued for other services are rejected"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRateLimit rejects rate limits that would not be enforced, and
+// identity limits without authentication, which clients could evade.
+func validateRateLimit(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.Sidecar == nil || mcpserver.Spec.Sidecar.RateLimit == nil {
+		return allErrs
+	}
+
+	rateLimitPath := specPath.Child("sidecar", "rateLimit")
+	if !isMetricsEnabled(mcpserver) {
+		allErrs = append(allErrs, field.Forbidden(rateLimitPath,
+			"requires spec.metrics.enabled; rate limits are enforced by the sidecar"))
+	}
+
+	for i, rule := range mcpserver.Spec.Sidecar.RateLimit.Rules {
+		if mcpserver.Spec.Sidecar.Auth != nil {
+			break
+		}
+		for _, key := range rule.By {
+			if key == mcpv1.RateLimitKeyIdentity {
+				allErrs = append(allErrs, field.Forbidden(rateLimitPath.Child("rules").Index(i).Child("by"),
+					"counting by identity requires spec.sidecar.auth"))
+				break
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateAudit rejects audit records the sidecar would not write, file
+// settings without the file sink, and redaction rules it cannot parse.
+func validateAudit(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.Sidecar == nil || mcpserver.Spec.Sidecar.Audit == nil {
+		return allErrs
+	}
+
+	audit := mcpserver.Spec.Sidecar.Audit
+	auditPath := specPath.Child("sidecar", "audit")
+	if !isMetricsEnabled(mcpserver) {
+		allErrs = append(allErrs, field.Forbidden(auditPath,
+			"requires spec.metrics.enabled; audit records are written by the sidecar"))
+	}
+
+	if audit.File != nil {
+		filePath := auditPath.Child("file")
+		if audit.Sink != mcpv1.AuditSinkFile {
+			allErrs = append(allErrs, field.Forbidden(filePath,
+				fmt.Sprintf("requires sink %q", mcpv1.AuditSinkFile)))
+		} else if name := audit.File.VolumeName; name != "" && !hasPodTemplateVolume(mcpserver, name) {
+			allErrs = append(allErrs, field.NotFound(filePath.Child("volumeName"), name))
+		}
+	}
+
+	for i, path := range audit.Redact {
+		if !strings.HasPrefix(path, "$") || strings.Contains(path, ",") {
+			allErrs = append(allErrs, field.Invalid(auditPath.Child("redact").Index(i), path,
+				"must be a JSONPath expression starting with $ and without commas"))
+		}
+	}
+
+	return allErrs
+}
+
+// hasPodTemplateVolume reports whether spec.podTemplate.volumes holds a volume
+func hasPodTemplateVolume(mcpserver *mcpv1.MCPServer, name string) bool {
+	if mcpserver.Spec.PodTemplate == nil {
+		return false
+	}
+	for _, volume := range mcpserver.Spec.PodTemplate.Volumes {
+		if volume.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTracing rejects tracing the sidecar would not run, and OTLP
+// exporting without a collector to send spans to.
+func validateTracing(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.Metrics == nil || mcpserver.Spec.Metrics.Tracing == nil {
+		return allErrs
+	}
+
+	tracingPath := specPath.Child("metrics", "tracing")
+	tracing := mcpserver.Spec.Metrics.Tracing
+	if !mcpserver.Spec.Metrics.Enabled {
+		allErrs = append(allErrs, field.Forbidden(tracingPath,
+			"requires spec.metrics.enabled; spans are exported by the sidecar"))
+	}
+
+	if tracing.Exporter != mcpv1.TracingExporterStdout && tracing.Endpoint == "" {
+		allErrs = append(allErrs, field.Required(tracingPath.Child("endpoint"),
+			"an OTLP endpoint is required unless exporter is \"stdout\""))
+	}
+
+	return allErrs
+}
+
+// validateRollout checks the canary settings of a rollout
+func validateRollout(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	rollout := mcpserver.Spec.Rollout
+	if rollout == nil || rollout.Canary == nil {
+		return allErrs
+	}
+
+	canaryPath := specPath.Child("rollout", "canary")
+	if rollout.Strategy != mcpv1.RolloutStrategyCanary {
+		allErrs = append(allErrs, field.Forbidden(canaryPath,
+			fmt.Sprintf("requires rollout.strategy %q", mcpv1.RolloutStrategyCanary)))
+	}
+
+	for i := 1; i < len(rollout.Canary.Steps); i++ {
+		if rollout.Canary.Steps[i].Weight <= rollout.Canary.Steps[i-1].Weight {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("steps").Index(i).Child("weight"),
+				rollout.Canary.Steps[i].Weight, "must be greater than the weight of the previous step"))
+		}
+	}
+
+	if rollout.Canary.MaxErrorRatePercent != nil &&
+		(mcpserver.Spec.Metrics == nil || !mcpserver.Spec.Metrics.Enabled) {
+		allErrs = append(allErrs, field.Forbidden(canaryPath.Child("maxErrorRatePercent"),
+			"requires spec.metrics.enabled; the error rate is read from the sidecar"))
+	}
+
+	return allErrs
+}
+
+// validateExposure rejects settings the selected kind of route cannot apply
+func validateExposure(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	exposure := mcpserver.Spec.Exposure
+	if exposure == nil {
+		return allErrs
+	}
+
+	exposurePath := specPath.Child("exposure")
+	if exposure.Type == mcpv1.ExposureTypeIngress {
+		if exposure.Gateway != nil {
+			allErrs = append(allErrs, field.Forbidden(exposurePath.Child("gateway"),
+				fmt.Sprintf("requires type %q", mcpv1.ExposureTypeHTTPRoute)))
+		}
+		if exposure.TLS != nil && exposure.TLS.SecretName == "" {
+			allErrs = append(allErrs, field.Required(exposurePath.Child("tls", "secretName"),
+				"the Ingress needs the Secret holding the certificate of the host"))
+		}
+	} else {
+		if exposure.Gateway == nil {
+			allErrs = append(allErrs, field.Required(exposurePath.Child("gateway"),
+				"the HTTPRoute needs a Gateway to attach to"))
+		}
+		if exposure.IngressClassName != nil {
+			allErrs = append(allErrs, field.Forbidden(exposurePath.Child("ingressClassName"),
+				fmt.Sprintf("requires type %q", mcpv1.ExposureTypeIngress)))
+		}
+		if exposure.TLS != nil && exposure.TLS.SecretName != "" {
+			allErrs = append(allErrs, field.Forbidden(exposurePath.Child("tls", "secretName"),
+				"HTTPRoutes cannot reference certificates; configure it on the Gateway listener"))
+		}
+	}
+
+	if exposure.Timeout != nil && exposure.Timeout.Duration < time.Second {
+		allErrs = append(allErrs, field.Invalid(exposurePath.Child("timeout"),
+			exposure.Timeout.Duration.String(), "must be at least 1s"))
+	}
+
+	return allErrs
+}
+
+// validateNetworkPolicy rejects egress destinations the NetworkPolicy cannot match
+func validateNetworkPolicy(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	networkPolicy := mcpserver.Spec.NetworkPolicy
+	if networkPolicy == nil || networkPolicy.Egress == nil {
+		return allErrs
+	}
+
+	egressPath := specPath.Child("networkPolicy", "egress")
+	for i, cidr := range networkPolicy.Egress.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(egressPath.Child("cidrs").Index(i), cidr,
+				"must be a CIDR, such as 10.0.0.0/16"))
+		}
+	}
+	for i, name := range networkPolicy.Egress.DNSNames {
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			allErrs = append(allErrs, field.Invalid(egressPath.Child("dnsNames").Index(i), name, msg))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDisruption rejects budgets setting both minAvailable and maxUnavailable,
+// which a PodDisruptionBudget cannot hold
+func validateDisruption(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	disruption := mcpserver.Spec.Disruption
+	if disruption == nil {
+		return allErrs
+	}
+
+	disruptionPath := specPath.Child("disruption")
+	if disruption.MinAvailable != nil && disruption.MaxUnavailable != nil {
+		allErrs = append(allErrs, field.Forbidden(disruptionPath.Child("maxUnavailable"),
+			"cannot be set together with minAvailable"))
+	}
+
+	return allErrs
+}
+
+// disruptionWarning warns about a minAvailable that keeps every replica of a
+// server without HPA, so the budget never allows a voluntary eviction
+func disruptionWarning(mcpserver *mcpv1.MCPServer) string {
+	disruption := mcpserver.Spec.Disruption
+	if disruption == nil || disruption.MinAvailable == nil || disruption.MinAvailable.Type != intstr.Int {
+		return ""
+	}
+	if disruption.Enabled != nil && !*disruption.Enabled {
+		return ""
+	}
+	if mcpserver.Spec.HPA != nil && mcpserver.Spec.HPA.Enabled != nil && *mcpserver.Spec.HPA.Enabled {
+		return ""
+	}
+
+	replicas := int32(1)
+	if mcpserver.Spec.Replicas != nil {
+		replicas = *mcpserver.Spec.Replicas
+	}
+	if disruption.MinAvailable.IntVal == 0 || disruption.MinAvailable.IntVal < replicas {
+		return ""
+	}
+	return fmt.Sprintf("spec.disruption.minAvailable %d is not below spec.replicas %d: "+
+		"node drains will wait until replicas is raised", disruption.MinAvailable.IntVal, replicas)
+}
+
+// validateScaleToZero rejects scale-to-zero where the activator cannot stand
+// in for the server: idleness is read from the sidecar, and the activator
+// serves plain HTTP to a single Deployment
+func validateScaleToZero(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.ScaleToZero == nil || !mcpserver.Spec.ScaleToZero.Enabled {
+		return allErrs
+	}
+
+	scaleToZeroPath := specPath.Child("scaleToZero")
+	if !isMetricsEnabled(mcpserver) {
+		allErrs = append(allErrs, field.Forbidden(scaleToZeroPath,
+			"requires spec.metrics.enabled; idleness is read from the sidecar"))
+	}
+	t := mcpserver.Spec.Transport
+	if t != nil && t.Config != nil && t.Config.HTTP != nil && t.Config.HTTP.SessionRouting != nil &&
+		t.Config.HTTP.SessionRouting.Enabled {
+		allErrs = append(allErrs, field.Forbidden(scaleToZeroPath,
+			"cannot be enabled together with transport.config.http.sessionRouting"))
+	}
+	if mcpserver.Spec.Rollout != nil && mcpserver.Spec.Rollout.Strategy == mcpv1.RolloutStrategyCanary {
+		allErrs = append(allErrs, field.Forbidden(scaleToZeroPath,
+			fmt.Sprintf("cannot be enabled when rollout.strategy is %q", mcpv1.RolloutStrategyCanary)))
+	}
+	if mcpserver.Spec.Sidecar != nil && mcpserver.Spec.Sidecar.TLS != nil && mcpserver.Spec.Sidecar.TLS.Enabled {
+		allErrs = append(allErrs, field.Forbidden(scaleToZeroPath,
+			"cannot be enabled together with sidecar.tls; the activator serves plain HTTP"))
+	}
+
+	return allErrs
+}
+
+// scaleToZeroWarning warns about periodic validation running more often than
+// the idle timeout, since its requests keep the server from ever being idle
+func scaleToZeroWarning(mcpserver *mcpv1.MCPServer) string {
+	scaleToZero := mcpserver.Spec.ScaleToZero
+	if scaleToZero == nil || !scaleToZero.Enabled {
+		return ""
+	}
+	validation := mcpserver.Spec.Validation
+	if validation == nil || validation.Interval == nil || validation.Interval.Duration <= 0 {
+		return ""
+	}
+	if validation.Enabled != nil && !*validation.Enabled {
+		return ""
+	}
+
+	idleTimeout := 15 * time.Minute
+	if scaleToZero.IdleTimeout != nil {
+		idleTimeout = scaleToZero.IdleTimeout.Duration
+	}
+	if validation.Interval.Duration >= idleTimeout {
+		return ""
+	}
+	return fmt.Sprintf("spec.validation.interval %s is below spec.scaleToZero.idleTimeout %s: "+
+		"periodic validation keeps the server from scaling to zero", validation.Interval.Duration, idleTimeout)
+}
+
+// validateHealthCheck rejects MCP health checks that could not run. The
+// sidecar performs them over Streamable HTTP.
+func validateHealthCheck(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	healthCheck := mcpserver.Spec.HealthCheck
+	if healthCheck == nil {
+		return allErrs
+	}
+
+	healthCheckPath := specPath.Child("healthCheck")
+	if healthCheck.Type != mcpv1.HealthCheckTypeMCP {
+		if healthCheck.MCP != nil {
+			allErrs = append(allErrs, field.Forbidden(healthCheckPath.Child("mcp"),
+				fmt.Sprintf("requires type %q", mcpv1.HealthCheckTypeMCP)))
+		}
+		return allErrs
+	}
+
+	typePath := healthCheckPath.Child("type")
+	t := mcpserver.Spec.Transport
+	switch {
+	case t != nil && t.Protocol == mcpv1.MCPProtocolSSE:
+		allErrs = append(allErrs, field.Forbidden(typePath,
+			fmt.Sprintf("cannot be %q when transport.protocol is %q; the check uses Streamable HTTP",
+				mcpv1.HealthCheckTypeMCP, mcpv1.MCPProtocolSSE)))
+	case !isMetricsEnabled(mcpserver):
+		allErrs = append(allErrs, field.Forbidden(typePath,
+			fmt.Sprintf("%q requires spec.metrics.enabled; the check is performed by the sidecar", mcpv1.HealthCheckTypeMCP)))
+	}
+
+	return allErrs
+}
+
+// validateHPA rejects HPA bounds that would never allow a valid replica count,
+// and MCP traffic metrics without the sidecar recording them
+func validateHPA(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	hpa := mcpserver.Spec.HPA
+	if hpa == nil {
+		return allErrs
+	}
+
+	// Unset bounds fall back to the CRD defaults
+	minReplicas := int32(1)
+	if hpa.MinReplicas != nil {
+		minReplicas = *hpa.MinReplicas
+	}
+	maxReplicas := int32(10)
+	if hpa.MaxReplicas != nil {
+		maxReplicas = *hpa.MaxReplicas
+	}
+
+	if minReplicas > maxReplicas {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("hpa", "minReplicas"), minReplicas,
+			fmt.Sprintf("must be less than or equal to maxReplicas (%d)", maxReplicas)))
+	}
+
+	metricsPath := specPath.Child("hpa", "metrics")
+	if len(hpa.Metrics) > 0 && !isMetricsEnabled(mcpserver) {
+		allErrs = append(allErrs, field.Forbidden(metricsPath,
+			"requires spec.metrics.enabled; the metrics are recorded by the sidecar"))
+	}
+	for i, metric := range hpa.Metrics {
+		if metric.Target.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(metricsPath.Index(i).Child("target"), metric.Target.String(),
+				"must be greater than zero"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRequiredCapabilities rejects capabilities the validator cannot check
+func validateRequiredCapabilities(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if mcpserver.Spec.Validation == nil {
+		return allErrs
+	}
+
+	capabilitiesPath := specPath.Child("validation", "requiredCapabilities")
+	for i, capability := range mcpserver.Spec.Validation.RequiredCapabilities {
+		if !isValidCapability(capability) {
+			allErrs = append(allErrs, field.NotSupported(capabilitiesPath.Index(i), capability, validCapabilities))
+		}
+	}
+
+	return allErrs
+}
+
+// isValidCapability returns true if the capability is one of validCapabilities
+func isValidCapability(capability string) bool {
+	for _, valid := range validCapabilities {
+		if capability == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSSEConfig rejects SSE settings when the protocol is pinned to Streamable HTTP,
+// where they would be silently ignored
+func validateSSEConfig(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	t := mcpserver.Spec.Transport
+	if t == nil || t.Protocol != mcpv1.MCPProtocolStreamableHTTP {
+		return allErrs
+	}
+
+	if t.Config != nil && t.Config.HTTP != nil && t.Config.HTTP.SSE != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("transport", "config", "http", "sse"),
+			fmt.Sprintf("cannot be set when transport.protocol is %q", mcpv1.MCPProtocolStreamableHTTP)))
+	}
+
+	return allErrs
+}
+
+// validateSessionRouting rejects session routing for transports without
+// Mcp-Session-Id sessions
+func validateSessionRouting(mcpserver *mcpv1.MCPServer, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	t := mcpserver.Spec.Transport
+	if t == nil || t.Config == nil || t.Config.HTTP == nil || t.Config.HTTP.SessionRouting == nil ||
+		!t.Config.HTTP.SessionRouting.Enabled {
+		return allErrs
+	}
+
+	routingPath := specPath.Child("transport", "config", "http", "sessionRouting")
+	switch {
+	case t.Type == mcpv1.MCPTransportStdio:
+		allErrs = append(allErrs, field.Forbidden(routingPath,
+			fmt.Sprintf("cannot be enabled when transport.type is %q", mcpv1.MCPTransportStdio)))
+	case t.Protocol == mcpv1.MCPProtocolSSE:
+		allErrs = append(allErrs, field.Forbidden(routingPath,
+			fmt.Sprintf("cannot be enabled when transport.protocol is %q; use sse.enableSessionAffinity", mcpv1.MCPProtocolSSE)))
+	}
+
+	return allErrs
+}
+
+// isMetricsEnabled returns true when the metrics sidecar is injected
+func isMetricsEnabled(mcpserver *mcpv1.MCPServer) bool {
+	return mcpserver.Spec.Metrics != nil && mcpserver.Spec.Metrics.Enabled
+}
@@ -0,0 +1,589 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	mcpv1 "github.com/vitorbari/mcp-operator/api/v1"
+)
+
+var _ = Describe("MCPServer Webhook", func() {
+	var (
+		obj       *mcpv1.MCPServer
+		oldObj    *mcpv1.MCPServer
+		validator MCPServerCustomValidator
+		defaulter MCPServerCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		obj = &mcpv1.MCPServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-server",
+				Namespace: "default",
+			},
+			Spec: mcpv1.MCPServerSpec{
+				Image: "test-server:latest",
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = MCPServerCustomValidator{}
+		defaulter = MCPServerCustomDefaulter{}
+	})
+
+	Context("When creating MCPServer under Defaulting Webhook", func() {
+		It("Should normalize required capabilities", func() {
+			obj.Spec.Validation = &mcpv1.ValidationSpec{
+				RequiredCapabilities: []string{"Tools", " resources ", "tools"},
+			}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Validation.RequiredCapabilities).To(Equal([]string{"tools", "resources"}))
+		})
+	})
+
+	Context("When creating or updating MCPServer under Validating Webhook", func() {
+		It("Should admit a minimal MCPServer", func() {
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should admit the default sidecar port fallback", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a sidecar port that conflicts with the server port", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{Port: 8080}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.port")))
+		})
+
+		It("Should deny a metrics port that conflicts with the server port", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true, Port: 3000}
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{
+				Config: &mcpv1.MCPTransportConfigDetails{
+					HTTP: &mcpv1.MCPHTTPTransportConfig{Port: 3000},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.metrics.port")))
+		})
+
+		It("Should deny a service port that conflicts with the metrics port", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Service = &mcpv1.MCPServerService{Port: mcpv1.DefaultMetricsPort}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.service.port")))
+		})
+
+		It("Should ignore sidecar ports when metrics are disabled", func() {
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{Port: 8080}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("spec.sidecar is ignored")))
+		})
+
+		It("Should deny sidecar TLS without a secret name", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				TLS: &mcpv1.SidecarTLSConfig{Enabled: true},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.tls.secretName")))
+		})
+
+		It("Should deny minReplicas greater than maxReplicas", func() {
+			obj.Spec.HPA = &mcpv1.MCPServerHPA{
+				Enabled:     ptr(true),
+				MinReplicas: ptr(int32(5)),
+				MaxReplicas: ptr(int32(2)),
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.hpa.minReplicas")))
+		})
+
+		It("Should deny HPA traffic metrics without the sidecar", func() {
+			obj.Spec.HPA = &mcpv1.MCPServerHPA{
+				Enabled: ptr(true),
+				Metrics: []mcpv1.MCPServerHPAMetric{
+					{Type: mcpv1.HPAMetricRequestsPerSecond, Target: resource.MustParse("20")},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.hpa.metrics: Forbidden")))
+
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.HPA.Metrics[0].Target = resource.MustParse("0")
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.hpa.metrics[0].target")))
+		})
+
+		It("Should deny unknown required capabilities", func() {
+			obj.Spec.Validation = &mcpv1.ValidationSpec{
+				RequiredCapabilities: []string{"tools", "sampling"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(`spec.validation.requiredCapabilities[1]: Unsupported value: "sampling"`)))
+		})
+
+		It("Should deny SSE settings with the streamable-http protocol", func() {
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{
+				Protocol: mcpv1.MCPProtocolStreamableHTTP,
+				Config: &mcpv1.MCPTransportConfigDetails{
+					HTTP: &mcpv1.MCPHTTPTransportConfig{
+						SSE: &mcpv1.SSEConfig{EnableSessionAffinity: ptr(true)},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.transport.config.http.sse")))
+		})
+
+		It("Should admit a policy when the sidecar is injected", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Policy = &mcpv1.PolicySpec{
+				Tools: &mcpv1.PolicyRules{Deny: []string{"delete_*"}},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a policy without the sidecar", func() {
+			obj.Spec.Policy = &mcpv1.PolicySpec{
+				Tools: &mcpv1.PolicyRules{Deny: []string{"delete_*"}},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy: Forbidden: requires spec.metrics.enabled")))
+
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{Type: mcpv1.MCPTransportStdio}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny policy identities without sidecar authentication", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Policy = &mcpv1.PolicySpec{
+				Identities: []mcpv1.IdentityPolicy{{Names: []string{"admin"}}},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.policy.identities: Forbidden: requires spec.sidecar.auth")))
+
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				Auth: &mcpv1.SidecarAuthConfig{APIKeys: &mcpv1.SidecarAPIKeyAuth{
+					SecretRef: corev1.LocalObjectReference{Name: "api-keys"},
+				}},
+			}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should admit sidecar authentication with API keys and a JWKS", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				Auth: &mcpv1.SidecarAuthConfig{
+					APIKeys: &mcpv1.SidecarAPIKeyAuth{
+						SecretRef: corev1.LocalObjectReference{Name: "mcp-api-keys"},
+					},
+					JWT: &mcpv1.SidecarJWTAuth{
+						JWKSURL:   "https://auth.example.com/jwks.json",
+						Audiences: []string{"https://weather.example.com/mcp"},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{Type: mcpv1.MCPTransportStdio}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny sidecar authentication without credentials or a sidecar", func() {
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				Auth: &mcpv1.SidecarAuthConfig{JWT: &mcpv1.SidecarJWTAuth{}},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.auth: Forbidden: requires spec.metrics.enabled")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.sidecar.auth.jwt: Required value: one of jwksURL or jwksConfigMapRef must be set")))
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.auth.jwt.audiences: Required value")))
+
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Sidecar.Auth = &mcpv1.SidecarAuthConfig{}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.auth: Required value: one of apiKeys or jwt must be set")))
+		})
+
+		It("Should deny identity rate limits without sidecar authentication", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				RateLimit: &mcpv1.SidecarRateLimitConfig{
+					Rules: []mcpv1.RateLimitRule{
+						{By: []mcpv1.RateLimitKey{mcpv1.RateLimitKeyIP}, RequestsPerMinute: 120},
+						{By: []mcpv1.RateLimitKey{mcpv1.RateLimitKeyIdentity, mcpv1.RateLimitKeyTool}, RequestsPerMinute: 10},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.sidecar.rateLimit.rules[1].by: Forbidden: counting by identity requires spec.sidecar.auth")))
+
+			obj.Spec.Sidecar.Auth = &mcpv1.SidecarAuthConfig{
+				APIKeys: &mcpv1.SidecarAPIKeyAuth{
+					SecretRef: corev1.LocalObjectReference{Name: "mcp-api-keys"},
+				},
+			}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{Type: mcpv1.MCPTransportStdio}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Metrics.Enabled = false
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.rateLimit: Forbidden: requires spec.metrics.enabled")))
+		})
+
+		It("Should deny audit file settings without the file sink and unknown volumes", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				Audit: &mcpv1.SidecarAuditConfig{
+					Sink:   mcpv1.AuditSinkStdout,
+					File:   &mcpv1.AuditFileConfig{VolumeName: "audit"},
+					Redact: []string{"$.password", "token"},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.audit.file: Forbidden: requires sink \"file\"")))
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.audit.redact[1]: Invalid value: \"token\"")))
+
+			obj.Spec.Sidecar.Audit.Sink = mcpv1.AuditSinkFile
+			obj.Spec.Sidecar.Audit.Redact = []string{"$.password", "$..token"}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.audit.file.volumeName: Not found: \"audit\"")))
+
+			obj.Spec.PodTemplate = &mcpv1.MCPServerPodTemplate{
+				Volumes: []corev1.Volume{{Name: "audit"}},
+			}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{Type: mcpv1.MCPTransportStdio}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Metrics.Enabled = false
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.audit: Forbidden: requires spec.metrics.enabled")))
+		})
+
+		It("Should require an endpoint for OTLP tracing", func() {
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{
+				Enabled: true,
+				Tracing: &mcpv1.TracingConfig{Exporter: mcpv1.TracingExporterOTLP},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.metrics.tracing.endpoint: Required value")))
+
+			obj.Spec.Metrics.Tracing.Exporter = mcpv1.TracingExporterStdout
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{Type: mcpv1.MCPTransportStdio}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Metrics.Enabled = false
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.metrics.tracing: Forbidden: requires spec.metrics.enabled")))
+		})
+
+		It("Should deny session routing for SSE servers", func() {
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{
+				Type:     mcpv1.MCPTransportHTTP,
+				Protocol: mcpv1.MCPProtocolSSE,
+				Config: &mcpv1.MCPTransportConfigDetails{
+					HTTP: &mcpv1.MCPHTTPTransportConfig{
+						SessionRouting: &mcpv1.SessionRoutingConfig{Enabled: true},
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.transport.config.http.sessionRouting: Forbidden: cannot be enabled when transport.protocol is \"sse\"")))
+
+			obj.Spec.Transport.Protocol = mcpv1.MCPProtocolStreamableHTTP
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny canary steps that do not increase", func() {
+			obj.Spec.Rollout = &mcpv1.RolloutConfig{
+				Strategy: mcpv1.RolloutStrategyCanary,
+				Canary: &mcpv1.CanaryConfig{
+					Steps:               []mcpv1.CanaryStep{{Weight: 50}, {Weight: 25}},
+					MaxErrorRatePercent: ptr(int32(5)),
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.rollout.canary.steps[1].weight: Invalid value: 25: must be greater than the weight of the previous step")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.rollout.canary.maxErrorRatePercent: Forbidden: requires spec.metrics.enabled")))
+
+			obj.Spec.Rollout.Canary.Steps = []mcpv1.CanaryStep{{Weight: 25}, {Weight: 50}}
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Rollout.Strategy = mcpv1.RolloutStrategyRollingUpdate
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.rollout.canary: Forbidden: requires rollout.strategy \"canary\"")))
+		})
+
+		It("Should deny exposure settings the route type cannot apply", func() {
+			obj.Spec.Exposure = &mcpv1.ExposureConfig{
+				Type:             mcpv1.ExposureTypeHTTPRoute,
+				Host:             "weather.example.com",
+				IngressClassName: ptr("nginx"),
+				TLS:              &mcpv1.ExposureTLSConfig{SecretName: "weather-tls"},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.exposure.gateway: Required value: the HTTPRoute needs a Gateway to attach to")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.exposure.ingressClassName: Forbidden: requires type \"ingress\"")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.exposure.tls.secretName: Forbidden: HTTPRoutes cannot reference certificates")))
+
+			obj.Spec.Exposure = &mcpv1.ExposureConfig{
+				Type:    mcpv1.ExposureTypeIngress,
+				Host:    "weather.example.com",
+				Gateway: &mcpv1.ExposureGatewayRef{Name: "public"},
+				TLS:     &mcpv1.ExposureTLSConfig{},
+				Timeout: &metav1.Duration{Duration: time.Millisecond},
+			}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.exposure.gateway: Forbidden: requires type \"httpRoute\"")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.exposure.tls.secretName: Required value")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.exposure.timeout: Invalid value: \"1ms\": must be at least 1s")))
+
+			obj.Spec.Exposure.Gateway = nil
+			obj.Spec.Exposure.TLS.SecretName = "weather-tls"
+			obj.Spec.Exposure.Timeout = &metav1.Duration{Duration: time.Minute}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny egress destinations the NetworkPolicy cannot match", func() {
+			obj.Spec.NetworkPolicy = &mcpv1.NetworkPolicyConfig{
+				MetricsFrom: []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}},
+				Egress: &mcpv1.NetworkPolicyEgress{
+					CIDRs:    []string{"10.0.0.0/16", "10.0.0.1"},
+					DNSNames: []string{"api.example.com", "https://api.example.com"},
+				},
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.networkPolicy.egress.cidrs[1]: Invalid value: \"10.0.0.1\": must be a CIDR")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.networkPolicy.egress.dnsNames[1]: Invalid value: \"https://api.example.com\"")))
+			Expect(warnings).To(ContainElement(ContainSubstring("spec.networkPolicy.metricsFrom is ignored")))
+
+			obj.Spec.NetworkPolicy.Egress.CIDRs = []string{"10.0.0.0/16", "2001:db8::/32"}
+			obj.Spec.NetworkPolicy.Egress.DNSNames = []string{"api.example.com"}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a disruption budget with both bounds and warn when it blocks evictions", func() {
+			minAvailable := intstr.FromInt32(2)
+			maxUnavailable := intstr.FromString("25%")
+			obj.Spec.Replicas = ptr(int32(2))
+			obj.Spec.Disruption = &mcpv1.DisruptionConfig{
+				MinAvailable:   &minAvailable,
+				MaxUnavailable: &maxUnavailable,
+			}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.disruption.maxUnavailable: Forbidden: cannot be set together with minAvailable")))
+			Expect(warnings).To(ContainElement(ContainSubstring("node drains will wait")))
+
+			obj.Spec.Disruption.MaxUnavailable = nil
+			obj.Spec.Replicas = ptr(int32(3))
+
+			warnings, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny MCP health checks without the sidecar", func() {
+			obj.Spec.HealthCheck = &mcpv1.MCPServerHealthCheck{Type: mcpv1.HealthCheckTypeMCP}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.healthCheck.type: Forbidden: \"mcp\" requires spec.metrics.enabled")))
+
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{Type: mcpv1.MCPTransportStdio}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.HealthCheck = &mcpv1.MCPServerHealthCheck{
+				Type: mcpv1.HealthCheckTypeHTTP,
+				MCP:  &mcpv1.MCPHealthCheckConfig{Method: "initialize"},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.healthCheck.mcp: Forbidden: requires type \"mcp\"")))
+		})
+
+		It("Should deny scale-to-zero the activator cannot serve and warn about frequent validation", func() {
+			obj.Spec.ScaleToZero = &mcpv1.ScaleToZeroConfig{Enabled: true}
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				TLS: &mcpv1.SidecarTLSConfig{Enabled: true, SecretName: "weather-tls"},
+			}
+			obj.Spec.Rollout = &mcpv1.RolloutConfig{Strategy: mcpv1.RolloutStrategyCanary}
+			obj.Spec.Validation = &mcpv1.ValidationSpec{Interval: &metav1.Duration{Duration: 5 * time.Minute}}
+
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.scaleToZero: Forbidden: requires spec.metrics.enabled")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.scaleToZero: Forbidden: cannot be enabled when rollout.strategy is \"canary\"")))
+			Expect(err).To(MatchError(ContainSubstring(
+				"spec.scaleToZero: Forbidden: cannot be enabled together with sidecar.tls")))
+			Expect(warnings).To(ContainElement(ContainSubstring("keeps the server from scaling to zero")))
+
+			obj.Spec.Metrics = &mcpv1.MetricsConfig{Enabled: true}
+			obj.Spec.Sidecar = nil
+			obj.Spec.Rollout = nil
+			obj.Spec.Validation.Interval = &metav1.Duration{Duration: time.Hour}
+
+			warnings, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+
+			obj.Spec.Transport = &mcpv1.MCPServerTransport{Type: mcpv1.MCPTransportStdio}
+
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should report all violations on update", func() {
+			obj.Spec.Sidecar = &mcpv1.SidecarConfig{
+				TLS: &mcpv1.SidecarTLSConfig{Enabled: true},
+			}
+			obj.Spec.Validation = &mcpv1.ValidationSpec{
+				RequiredCapabilities: []string{"unknown"},
+			}
+
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("spec.sidecar.tls.secretName")))
+			Expect(err).To(MatchError(ContainSubstring("spec.validation.requiredCapabilities[0]")))
+		})
+
+		It("Should admit finalizer updates on a legacy MCPServer that fails validation", func() {
+			obj.Spec.Validation = &mcpv1.ValidationSpec{
+				RequiredCapabilities: []string{"unknown"},
+			}
+			obj.Finalizers = []string{"mcp.mcp-operator.io/finalizer"}
+			oldObj = obj.DeepCopy()
+
+			obj.Finalizers = nil
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			now := metav1.Now()
+			oldObj.DeletionTimestamp = &now
+			obj.DeletionTimestamp = &now
+			obj.Spec.Validation.RequiredCapabilities = []string{"other"}
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
+
+// ptr is a helper function to get a pointer to a value
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -274,34 +274,106 @@ func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
 	return &result, nil
 }
 
-// ListTools lists available tools from the MCP server
+// ListTools lists available tools from the MCP server, following every page
 func (c *Client) ListTools(ctx context.Context) (*ListToolsResult, error) {
-	var result ListToolsResult
-	if err := c.call(ctx, MethodToolsList, nil, &result); err != nil {
+	result, err := ListAllTools(ctx, c.call)
+	if err != nil {
 		return nil, fmt.Errorf("list tools failed: %w", err)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // ListResources lists available resources from the MCP server
 func (c *Client) ListResources(ctx context.Context) (*ListResourcesResult, error) {
-	var result ListResourcesResult
-	if err := c.call(ctx, MethodResourcesList, nil, &result); err != nil {
+	result, err := ListAllResources(ctx, c.call)
+	if err != nil {
 		return nil, fmt.Errorf("list resources failed: %w", err)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // ListPrompts lists available prompts from the MCP server
 func (c *Client) ListPrompts(ctx context.Context) (*ListPromptsResult, error) {
-	var result ListPromptsResult
-	if err := c.call(ctx, MethodPromptsList, nil, &result); err != nil {
+	result, err := ListAllPrompts(ctx, c.call)
+	if err != nil {
 		return nil, fmt.Errorf("list prompts failed: %w", err)
 	}
 
-	return &result, nil
+	return result, nil
+}
+
+// MaxListPages bounds how many pages of a paginated list method are fetched
+const MaxListPages = 20
+
+// CallFunc sends a JSON-RPC 2.0 request and decodes its result into result
+type CallFunc func(ctx context.Context, method string, params any, result any) error
+
+// ListAllTools fetches every page of tools/list through call, following
+// nextCursor for at most MaxListPages pages. The NextCursor of the result is
+// only set when pages were left unread.
+func ListAllTools(ctx context.Context, call CallFunc) (*ListToolsResult, error) {
+	result := &ListToolsResult{}
+	cursor, err := listPages(ctx, call, MethodToolsList, func(page *ListToolsResult) string {
+		result.Tools = append(result.Tools, page.Tools...)
+		return page.NextCursor
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor = cursor
+	return result, nil
+}
+
+// ListAllResources fetches every page of resources/list the way ListAllTools does
+func ListAllResources(ctx context.Context, call CallFunc) (*ListResourcesResult, error) {
+	result := &ListResourcesResult{}
+	cursor, err := listPages(ctx, call, MethodResourcesList, func(page *ListResourcesResult) string {
+		result.Resources = append(result.Resources, page.Resources...)
+		return page.NextCursor
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor = cursor
+	return result, nil
+}
+
+// ListAllPrompts fetches every page of prompts/list the way ListAllTools does
+func ListAllPrompts(ctx context.Context, call CallFunc) (*ListPromptsResult, error) {
+	result := &ListPromptsResult{}
+	cursor, err := listPages(ctx, call, MethodPromptsList, func(page *ListPromptsResult) string {
+		result.Prompts = append(result.Prompts, page.Prompts...)
+		return page.NextCursor
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor = cursor
+	return result, nil
+}
+
+// listPages calls a paginated list method until a page has no nextCursor,
+// handing every page to add, which returns the page's nextCursor. It returns
+// the cursor of the first unread page when MaxListPages is reached.
+func listPages[P any](ctx context.Context, call CallFunc, method string, add func(page *P) string) (string, error) {
+	cursor := ""
+	for range MaxListPages {
+		var params any
+		if cursor != "" {
+			params = PaginatedParams{Cursor: cursor}
+		}
+
+		var page P
+		if err := call(ctx, method, params, &page); err != nil {
+			return "", err
+		}
+		if cursor = add(&page); cursor == "" {
+			return "", nil
+		}
+	}
+	return cursor, nil
 }
 
 // notify sends a JSON-RPC 2.0 notification (no response expected)
@@ -471,6 +471,72 @@ func TestClient_ListTools(t *testing.T) {
 	}
 }
 
+func TestListAllTools_FollowsNextCursor(t *testing.T) {
+	var cursors []string
+	call := func(ctx context.Context, method string, params any, result any) error {
+		if method != MethodToolsList {
+			t.Fatalf("Expected method %s, got %s", MethodToolsList, method)
+		}
+
+		cursor := ""
+		if params != nil {
+			cursor = params.(PaginatedParams).Cursor
+		}
+		cursors = append(cursors, cursor)
+
+		page := result.(*ListToolsResult)
+		switch cursor {
+		case "":
+			*page = ListToolsResult{Tools: []Tool{{Name: "first"}}, NextCursor: "page-2"}
+		case "page-2":
+			*page = ListToolsResult{Tools: []Tool{{Name: "second"}}}
+		}
+		return nil
+	}
+
+	result, err := ListAllTools(context.Background(), call)
+	if err != nil {
+		t.Fatalf("ListAllTools failed: %v", err)
+	}
+
+	if len(result.Tools) != 2 || result.Tools[0].Name != "first" || result.Tools[1].Name != "second" {
+		t.Errorf("Expected tools first and second, got %v", result.Tools)
+	}
+	if result.NextCursor != "" {
+		t.Errorf("Expected no next cursor, got %q", result.NextCursor)
+	}
+	if len(cursors) != 2 || cursors[0] != "" || cursors[1] != "page-2" {
+		t.Errorf("Expected cursors [\"\" page-2], got %q", cursors)
+	}
+}
+
+func TestListAllPrompts_StopsAtMaxListPages(t *testing.T) {
+	calls := 0
+	call := func(ctx context.Context, method string, params any, result any) error {
+		calls++
+		*result.(*ListPromptsResult) = ListPromptsResult{
+			Prompts:    []Prompt{{Name: "prompt"}},
+			NextCursor: "again",
+		}
+		return nil
+	}
+
+	result, err := ListAllPrompts(context.Background(), call)
+	if err != nil {
+		t.Fatalf("ListAllPrompts failed: %v", err)
+	}
+
+	if calls != MaxListPages {
+		t.Errorf("Expected %d calls, got %d", MaxListPages, calls)
+	}
+	if len(result.Prompts) != MaxListPages {
+		t.Errorf("Expected %d prompts, got %d", MaxListPages, len(result.Prompts))
+	}
+	if result.NextCursor != "again" {
+		t.Errorf("Expected the unread cursor to be kept, got %q", result.NextCursor)
+	}
+}
+
 func TestClient_ListResources(t *testing.T) {
 	server := mockMCPServer(t, func(method string, params json.RawMessage) (interface{}, *RPCError) {
 		if method != MethodResourcesList {
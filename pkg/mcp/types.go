@@ -122,9 +122,15 @@ type PromptsCapability struct {
 // LoggingCapability represents logging capability (empty object means supported)
 type LoggingCapability struct{}
 
+// PaginatedParams are the params of a list request continuing at a cursor
+type PaginatedParams struct {
+	Cursor string `json:"cursor,omitempty"`
+}
+
 // ListToolsResult represents the result of a tools/list request
 type ListToolsResult struct {
-	Tools []Tool `json:"tools"`
+	Tools      []Tool `json:"tools"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 // Tool represents an MCP tool
@@ -136,7 +142,8 @@ type Tool struct {
 
 // ListResourcesResult represents the result of a resources/list request
 type ListResourcesResult struct {
-	Resources []Resource `json:"resources"`
+	Resources  []Resource `json:"resources"`
+	NextCursor string     `json:"nextCursor,omitempty"`
 }
 
 // Resource represents an MCP resource
@@ -150,7 +157,8 @@ type Resource struct {
 
 // ListPromptsResult represents the result of a prompts/list request
 type ListPromptsResult struct {
-	Prompts []Prompt `json:"prompts"`
+	Prompts    []Prompt `json:"prompts"`
+	NextCursor string   `json:"nextCursor,omitempty"`
 }
 
 // Prompt represents an MCP prompt
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// oauth2TokenTimeout bounds each request to the OAuth2 token endpoint
+const oauth2TokenTimeout = 10 * time.Second
+
+// Credentials configures authentication for validation requests.
+// All configured methods are applied; an OAuth2 access token takes
+// precedence over BearerToken for the Authorization header.
+//
+// Credentials must not be copied after first use.
+type Credentials struct {
+	// BearerToken is sent as "Authorization: Bearer <token>"
+	BearerToken string
+
+	// Headers are sent with every request (e.g., API keys)
+	Headers map[string]string
+
+	// OAuth2 enables the OAuth2 client credentials flow
+	OAuth2 *OAuth2ClientCredentials
+
+	once        sync.Once
+	tokenSource oauth2.TokenSource
+}
+
+// OAuth2ClientCredentials configures the OAuth2 client credentials grant
+type OAuth2ClientCredentials struct {
+	// TokenURL is the token endpoint of the authorization server
+	TokenURL string
+
+	// ClientID is the OAuth2 client identifier
+	ClientID string
+
+	// ClientSecret is the OAuth2 client secret
+	ClientSecret string
+
+	// Scopes are the requested scopes (optional)
+	Scopes []string
+}
+
+// apply adds the configured credentials to an outgoing request.
+// Nil credentials are a no-op so callers don't need to check.
+func (c *Credentials) apply(req *http.Request) error {
+	if c == nil {
+		return nil
+	}
+
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	}
+
+	if c.OAuth2 != nil {
+		token, err := c.getTokenSource().Token()
+		if err != nil {
+			return fmt.Errorf("failed to obtain OAuth2 access token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	}
+
+	return nil
+}
+
+// getTokenSource returns a token source that caches the access token
+// and only contacts the token endpoint when it expires
+func (c *Credentials) getTokenSource() oauth2.TokenSource {
+	c.once.Do(func() {
+		config := &clientcredentials.Config{
+			ClientID:     c.OAuth2.ClientID,
+			ClientSecret: c.OAuth2.ClientSecret,
+			TokenURL:     c.OAuth2.TokenURL,
+			Scopes:       c.OAuth2.Scopes,
+		}
+
+		// The token source outlives individual requests, so it can't use a request context
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+			Timeout: oauth2TokenTimeout,
+		})
+		c.tokenSource = config.TokenSource(ctx)
+	})
+	return c.tokenSource
+}
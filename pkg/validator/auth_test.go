@@ -0,0 +1,212 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// protectedMCPServer wraps the mock MCP server and rejects requests failing authorize
+func protectedMCPServer(t *testing.T, authorize func(r *http.Request) bool) *httptest.Server {
+	backend := mockMCPServer(t, validServerConfig())
+	t.Cleanup(backend.Close)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorize(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		backend.Config.Handler.ServeHTTP(w, r)
+	}))
+}
+
+func TestValidateWithoutCredentialsRequiresAuth(t *testing.T) {
+	server := protectedMCPServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer secret-token"
+	})
+	defer server.Close()
+
+	v := NewValidator(server.URL, WithMetricsEnabled(false))
+	result, err := v.Validate(context.Background(), ValidationOptions{Transport: TransportStreamableHTTP})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if !result.RequiresAuth {
+		t.Error("Expected RequiresAuth without credentials")
+	}
+}
+
+func TestValidateWithBearerToken(t *testing.T) {
+	server := protectedMCPServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer secret-token"
+	})
+	defer server.Close()
+
+	v := NewValidator(server.URL,
+		WithMetricsEnabled(false),
+		WithCredentials(&Credentials{BearerToken: "secret-token"}))
+
+	// Auto-detection must also send the credentials
+	result, err := v.Validate(context.Background(), ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if !result.Success || result.RequiresAuth {
+		t.Errorf("Expected successful validation, got success=%v requiresAuth=%v issues=%v",
+			result.Success, result.RequiresAuth, result.Issues)
+	}
+	if result.ServerInfo == nil || result.ServerInfo.Name != "test-server" {
+		t.Errorf("Expected server info from authenticated initialize, got %v", result.ServerInfo)
+	}
+}
+
+func TestValidateWithHeaders(t *testing.T) {
+	server := protectedMCPServer(t, func(r *http.Request) bool {
+		return r.Header.Get("X-API-Key") == "key123"
+	})
+	defer server.Close()
+
+	v := NewValidator(server.URL,
+		WithMetricsEnabled(false),
+		WithCredentials(&Credentials{Headers: map[string]string{"X-API-Key": "key123"}}))
+
+	result, err := v.Validate(context.Background(), ValidationOptions{Transport: TransportStreamableHTTP})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if !result.Success {
+		t.Errorf("Expected successful validation, got issues=%v", result.Issues)
+	}
+}
+
+func TestValidateWithRejectedCredentials(t *testing.T) {
+	server := protectedMCPServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer secret-token"
+	})
+	defer server.Close()
+
+	v := NewValidator(server.URL,
+		WithMetricsEnabled(false),
+		WithCredentials(&Credentials{BearerToken: "wrong-token"}))
+
+	result, err := v.Validate(context.Background(), ValidationOptions{Transport: TransportStreamableHTTP})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if result.Success {
+		t.Error("Expected validation to fail with rejected credentials")
+	}
+
+	found := false
+	for _, issue := range result.Issues {
+		if issue.Code == CodeAuthFailed {
+			found = true
+		}
+		if issue.Code == CodeAuthRequired {
+			t.Errorf("Rejected credentials should not be reported as %s", CodeAuthRequired)
+		}
+	}
+	if !found {
+		t.Errorf("Expected %s issue, got %v", CodeAuthFailed, result.Issues)
+	}
+}
+
+func TestValidateWithOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID = r.PostForm.Get("client_id")
+			clientSecret = r.PostForm.Get("client_secret")
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" ||
+			clientID != "validator" || clientSecret != "s3cret" ||
+			r.PostForm.Get("scope") != "mcp:read" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "oauth-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	server := protectedMCPServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer oauth-token"
+	})
+	defer server.Close()
+
+	v := NewValidator(server.URL,
+		WithMetricsEnabled(false),
+		WithTimeout(10*time.Second),
+		WithCredentials(&Credentials{
+			OAuth2: &OAuth2ClientCredentials{
+				TokenURL:     tokenServer.URL,
+				ClientID:     "validator",
+				ClientSecret: "s3cret",
+				Scopes:       []string{"mcp:read"},
+			},
+		}))
+
+	result, err := v.Validate(context.Background(), ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if !result.Success || result.RequiresAuth {
+		t.Errorf("Expected successful validation, got success=%v requiresAuth=%v issues=%v",
+			result.Success, result.RequiresAuth, result.Issues)
+	}
+
+	// The token is cached across detection, initialize and list requests
+	if got := tokenRequests.Load(); got != 1 {
+		t.Errorf("Expected 1 token request, got %d", got)
+	}
+}
+
+func TestCredentialsApplyNil(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	var creds *Credentials
+	if err := creds.apply(req); err != nil {
+		t.Fatalf("apply on nil credentials returned error: %v", err)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Expected no Authorization header for nil credentials")
+	}
+}
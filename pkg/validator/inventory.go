@@ -0,0 +1,84 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/vitorbari/mcp-operator/pkg/mcp"
+)
+
+// checkRequiredInventory reports required tools, resources and prompts that the
+// server did not list. Missing items are errors in strict mode and warnings
+// otherwise, so a server that drops a tool can keep serving while it is fixed.
+// Items are missing whether the list call failed, the capability is not
+// advertised, or the list simply lacks them.
+func checkRequiredInventory(opts ValidationOptions, result *ValidationResult) {
+	level := LevelWarning
+	if opts.StrictMode {
+		level = LevelError
+	}
+
+	report := func(code, kind string, required, discovered []string) {
+		for _, name := range required {
+			if slices.Contains(discovered, name) {
+				continue
+			}
+			if level == LevelError {
+				result.Success = false
+			}
+			result.Issues = append(result.Issues, newIssue(
+				level,
+				code,
+				fmt.Sprintf("Required %s '%s' is not exposed by the server", kind, name),
+			))
+		}
+	}
+
+	report(CodeMissingTool, "tool", opts.RequiredTools, result.Tools)
+	report(CodeMissingResource, "resource", opts.RequiredResources, result.Resources)
+	report(CodeMissingPrompt, "prompt", opts.RequiredPrompts, result.Prompts)
+}
+
+// toolNames returns the names of the listed tools in server order
+func toolNames(tools []mcp.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// resourceURIs returns the URIs of the listed resources in server order.
+// URIs identify resources; names are only display labels and need not be unique.
+func resourceURIs(resources []mcp.Resource) []string {
+	uris := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		uris = append(uris, resource.URI)
+	}
+	return uris
+}
+
+// promptNames returns the names of the listed prompts in server order
+func promptNames(prompts []mcp.Prompt) []string {
+	names := make([]string, 0, len(prompts))
+	for _, prompt := range prompts {
+		names = append(names, prompt.Name)
+	}
+	return names
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/pkg/mcp"
+)
+
+func inventoryServerConfig() mockServerConfig {
+	config := validServerConfig()
+	config.tools = []mcp.Tool{
+		{Name: "search", Description: "Searches documents", InputSchema: map[string]any{"type": "object"}},
+		{Name: "fetch", Description: "Fetches a document", InputSchema: map[string]any{"type": "object"}},
+	}
+	config.resources = []mcp.Resource{
+		{URI: "file:///docs/readme.md", Name: "readme"},
+	}
+	config.prompts = []mcp.Prompt{
+		{Name: "summarize"},
+	}
+	return config
+}
+
+func TestValidator_RecordsInventory(t *testing.T) {
+	server := mockMCPServer(t, inventoryServerConfig())
+	defer server.Close()
+
+	validator := NewValidator(server.URL, WithTimeout(5*time.Second))
+	result, err := validator.Validate(context.Background(), ValidationOptions{
+		Transport: TransportStreamableHTTP,
+	})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if !slices.Equal(result.Tools, []string{"search", "fetch"}) {
+		t.Errorf("Tools = %v, want [search fetch]", result.Tools)
+	}
+	if !slices.Equal(result.Resources, []string{"file:///docs/readme.md"}) {
+		t.Errorf("Resources = %v, want the resource URI", result.Resources)
+	}
+	if !slices.Equal(result.Prompts, []string{"summarize"}) {
+		t.Errorf("Prompts = %v, want [summarize]", result.Prompts)
+	}
+}
+
+func TestValidator_RequiredInventory(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          ValidationOptions
+		wantSuccess   bool
+		wantCodes     []string
+		wantLevel     string
+		wantNoMissing bool
+	}{
+		{
+			name: "all present",
+			opts: ValidationOptions{
+				RequiredTools:     []string{"search"},
+				RequiredResources: []string{"file:///docs/readme.md"},
+				RequiredPrompts:   []string{"summarize"},
+			},
+			wantSuccess:   true,
+			wantNoMissing: true,
+		},
+		{
+			name: "missing items warn without strict mode",
+			opts: ValidationOptions{
+				RequiredTools:     []string{"search", "delete"},
+				RequiredResources: []string{"readme"},
+				RequiredPrompts:   []string{"translate"},
+			},
+			wantSuccess: true,
+			wantCodes:   []string{CodeMissingTool, CodeMissingResource, CodeMissingPrompt},
+			wantLevel:   LevelWarning,
+		},
+		{
+			name: "missing items fail in strict mode",
+			opts: ValidationOptions{
+				StrictMode:    true,
+				RequiredTools: []string{"delete"},
+			},
+			wantSuccess: false,
+			wantCodes:   []string{CodeMissingTool},
+			wantLevel:   LevelError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := mockMCPServer(t, inventoryServerConfig())
+			defer server.Close()
+
+			tt.opts.Transport = TransportStreamableHTTP
+			validator := NewValidator(server.URL, WithTimeout(5*time.Second))
+			result, err := validator.Validate(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("Validate returned error: %v", err)
+			}
+
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (issues: %+v)", result.Success, tt.wantSuccess, result.Issues)
+			}
+
+			var missing []ValidationIssue
+			for _, issue := range result.Issues {
+				switch issue.Code {
+				case CodeMissingTool, CodeMissingResource, CodeMissingPrompt:
+					missing = append(missing, issue)
+				}
+			}
+
+			if tt.wantNoMissing {
+				if len(missing) > 0 {
+					t.Errorf("unexpected missing inventory issues: %+v", missing)
+				}
+				return
+			}
+
+			if len(missing) != len(tt.wantCodes) {
+				t.Fatalf("got %d missing inventory issues, want %d: %+v", len(missing), len(tt.wantCodes), missing)
+			}
+			for i, issue := range missing {
+				if issue.Code != tt.wantCodes[i] {
+					t.Errorf("issue %d code = %s, want %s", i, issue.Code, tt.wantCodes[i])
+				}
+				if issue.Level != tt.wantLevel {
+					t.Errorf("issue %d level = %s, want %s", i, issue.Level, tt.wantLevel)
+				}
+				if len(issue.Suggestions) == 0 {
+					t.Errorf("issue %d has no suggestions from the catalog", i)
+				}
+			}
+		})
+	}
+}
+
+func TestValidator_RequiredToolWithoutToolsCapability(t *testing.T) {
+	config := validServerConfig()
+	config.capabilities.Tools = nil
+	server := mockMCPServer(t, config)
+	defer server.Close()
+
+	validator := NewValidator(server.URL, WithTimeout(5*time.Second))
+	result, err := validator.Validate(context.Background(), ValidationOptions{
+		Transport:     TransportStreamableHTTP,
+		StrictMode:    true,
+		RequiredTools: []string{"search"},
+	})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if result.Success {
+		t.Error("Expected validation to fail when the tools capability is not advertised")
+	}
+	if result.Tools != nil {
+		t.Errorf("Tools = %v, want nil when tools/list was not called", result.Tools)
+	}
+}
@@ -226,6 +226,95 @@ func (c *IssueCatalog) registerDefaultIssues() {
 		RelatedIssues:    []string{CodeToolsListFailed, CodeResourcesListFailed},
 	}
 
+	c.issues[CodeToolInvalidSchema] = IssueTemplate{
+		Code:        CodeToolInvalidSchema,
+		Title:       "Tool input schema is not valid JSON Schema",
+		Description: "A tool returned by tools/list has an inputSchema that clients cannot use to build arguments",
+		Suggestions: []string{
+			"Ensure inputSchema is a JSON object with \"type\": \"object\"",
+			"Validate the schema against the JSON Schema draft 2020-12 metaschema",
+			"Check keyword value types, e.g. \"required\" must be an array of strings",
+			"Inline external $ref targets; the validator does not fetch remote schemas",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-06-18/server/tools#tool",
+		RelatedIssues:    []string{CodeToolInvalidName, CodeToolsListFailed},
+	}
+
+	c.issues[CodeToolInvalidName] = IssueTemplate{
+		Code:        CodeToolInvalidName,
+		Title:       "Tool name contains invalid characters",
+		Description: "A tool name is empty, too long or uses characters outside the allowed set",
+		Suggestions: []string{
+			"Use 1 to 128 characters",
+			"Only use ASCII letters, digits, underscores, hyphens and dots",
+			"Avoid spaces and slashes, which many clients reject",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-06-18/server/tools#tool",
+		RelatedIssues:    []string{CodeToolDuplicateName},
+	}
+
+	c.issues[CodeToolDuplicateName] = IssueTemplate{
+		Code:        CodeToolDuplicateName,
+		Title:       "Duplicate tool name",
+		Description: "More than one tool returned by tools/list has the same name, so tools/call is ambiguous",
+		Suggestions: []string{
+			"Give every tool a unique name within the server",
+			"Check for tools registered twice by plugins or middleware",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-06-18/server/tools#tool",
+		RelatedIssues:    []string{CodeToolInvalidName},
+	}
+
+	c.issues[CodeToolMissingDescription] = IssueTemplate{
+		Code:        CodeToolMissingDescription,
+		Title:       "Tool has no description",
+		Description: "A tool returned by tools/list has an empty description",
+		Suggestions: []string{
+			"Add a description explaining what the tool does and when to use it",
+			"Models rely on descriptions to select tools; undocumented tools are often ignored or misused",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-06-18/server/tools#tool",
+		RelatedIssues:    []string{CodeToolInvalidSchema},
+	}
+
+	c.issues[CodeMissingTool] = IssueTemplate{
+		Code:        CodeMissingTool,
+		Title:       "Required tool not exposed",
+		Description: "A tool listed in the required tools is not returned by tools/list",
+		Suggestions: []string{
+			"Check whether the tool was renamed or removed in the current server version",
+			"Verify the server image tag; a mutable tag may have pulled a different release",
+			"Update the required tools if the tool is no longer needed",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-06-18/server/tools#listing-tools",
+		RelatedIssues:    []string{CodeToolsListFailed, CodeMissingCapability},
+	}
+
+	c.issues[CodeMissingResource] = IssueTemplate{
+		Code:        CodeMissingResource,
+		Title:       "Required resource not exposed",
+		Description: "A resource URI listed in the required resources is not returned by resources/list",
+		Suggestions: []string{
+			"Check whether the resource URI changed in the current server version",
+			"Required resources are matched by URI, not by display name",
+			"Update the required resources if the resource is no longer needed",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-06-18/server/resources#listing-resources",
+		RelatedIssues:    []string{CodeResourcesListFailed, CodeMissingCapability},
+	}
+
+	c.issues[CodeMissingPrompt] = IssueTemplate{
+		Code:        CodeMissingPrompt,
+		Title:       "Required prompt not exposed",
+		Description: "A prompt listed in the required prompts is not returned by prompts/list",
+		Suggestions: []string{
+			"Check whether the prompt was renamed or removed in the current server version",
+			"Update the required prompts if the prompt is no longer needed",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-06-18/server/prompts#listing-prompts",
+		RelatedIssues:    []string{CodePromptsListFailed, CodeMissingCapability},
+	}
+
 	c.issues["RETRIES_EXHAUSTED"] = IssueTemplate{
 		Code:        "RETRIES_EXHAUSTED",
 		Title:       "Validation failed after multiple retries",
@@ -269,6 +358,20 @@ func (c *IssueCatalog) registerDefaultIssues() {
 		RelatedIssues:    []string{"AUTH_REQUIRED"},
 	}
 
+	c.issues[CodeAuthFailed] = IssueTemplate{
+		Code:        CodeAuthFailed,
+		Title:       "Authentication failed",
+		Description: "Credentials were configured but the server rejected them or they could not be loaded",
+		Suggestions: []string{
+			"Verify the Secret referenced by spec.validation.auth.secretRef exists and contains the expected keys",
+			"Check that the token or API key has not expired or been revoked",
+			"For OAuth2, verify the token URL, client ID, client secret and scopes",
+			"Confirm the server expects the configured header names",
+		},
+		DocumentationURL: "https://modelcontextprotocol.io/specification/2025-03-26/basic/authorization",
+		RelatedIssues:    []string{"AUTH_REQUIRED"},
+	}
+
 	c.issues[CodeProtocolMismatch] = IssueTemplate{
 		Code:        CodeProtocolMismatch,
 		Title:       "Protocol mismatch detected",
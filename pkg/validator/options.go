@@ -255,6 +255,24 @@ func (opts ValidationOptions) WithRequiredCapabilities(capabilities ...string) V
 	return opts
 }
 
+// WithRequiredTools returns a copy with required tool names set
+func (opts ValidationOptions) WithRequiredTools(names ...string) ValidationOptions {
+	opts.RequiredTools = names
+	return opts
+}
+
+// WithRequiredResources returns a copy with required resource URIs set
+func (opts ValidationOptions) WithRequiredResources(uris ...string) ValidationOptions {
+	opts.RequiredResources = uris
+	return opts
+}
+
+// WithRequiredPrompts returns a copy with required prompt names set
+func (opts ValidationOptions) WithRequiredPrompts(names ...string) ValidationOptions {
+	opts.RequiredPrompts = names
+	return opts
+}
+
 // WithTransport returns a copy with explicit transport specified
 func (opts ValidationOptions) WithTransport(transport TransportType) ValidationOptions {
 	opts.Transport = transport
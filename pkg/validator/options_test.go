@@ -223,6 +223,29 @@ func TestValidationOptions_WithRequiredCapabilities(t *testing.T) {
 	}
 }
 
+func TestValidationOptions_WithRequiredInventory(t *testing.T) {
+	opts := ValidationOptions{}
+
+	newOpts := opts.
+		WithRequiredTools("search", "fetch").
+		WithRequiredResources("file:///readme.md").
+		WithRequiredPrompts("summarize")
+
+	if len(newOpts.RequiredTools) != 2 || newOpts.RequiredTools[0] != "search" {
+		t.Errorf("Expected required tools [search fetch], got %v", newOpts.RequiredTools)
+	}
+	if len(newOpts.RequiredResources) != 1 {
+		t.Errorf("Expected 1 required resource, got %v", newOpts.RequiredResources)
+	}
+	if len(newOpts.RequiredPrompts) != 1 {
+		t.Errorf("Expected 1 required prompt, got %v", newOpts.RequiredPrompts)
+	}
+
+	if opts.RequiredTools != nil || opts.RequiredResources != nil || opts.RequiredPrompts != nil {
+		t.Error("Original ValidationOptions should not be modified")
+	}
+}
+
 func TestValidationOptions_WithTransport(t *testing.T) {
 	opts := ValidationOptions{}
 
@@ -18,28 +18,61 @@ package validator
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/vitorbari/mcp-operator/pkg/mcp"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
-// SSEClient handles SSE-based MCP communication
+const (
+	// sseEndpointTimeout bounds how long Connect waits for the endpoint event
+	sseEndpointTimeout = 1 * time.Second
+
+	// defaultSSEResponseTimeout is used when the client was created without a timeout
+	defaultSSEResponseTimeout = 30 * time.Second
+
+	// maxSSEEventSize caps a single SSE line; tools/list responses can be large
+	maxSSEEventSize = 4 * 1024 * 1024
+)
+
+// SSEClient handles SSE-based MCP communication.
+//
+// Requests are POSTed to the URL announced by the server's endpoint event and
+// their responses arrive asynchronously on the SSE stream. A single read loop
+// owns the stream and hands each response to the request waiting for its ID.
 type SSEClient struct {
 	httpClient  *http.Client
 	sseEndpoint string
 	messagesURL string
 	sseReader   io.ReadCloser
-	requestID   int64
+	timeout     time.Duration
+	credentials *Credentials // Optional credentials sent with every request
+
+	mu        sync.Mutex
+	requestID int64
+	pending   map[int64]chan sseResponse
+	done      chan struct{} // closed when the read loop exits
+	streamErr error         // why the read loop exited, valid once done is closed
 }
 
-// NewSSEClient creates a new SSE client
+// sseResponse is a JSON-RPC response received on the SSE stream
+type sseResponse struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *mcp.RPCError   `json:"error,omitempty"`
+}
+
+// NewSSEClient creates a new SSE client.
+// The timeout bounds how long each request waits for its response on the stream.
 func NewSSEClient(sseEndpoint string, timeout time.Duration) *SSEClient {
 	return &SSEClient{
 		httpClient: &http.Client{
@@ -48,10 +81,16 @@ func NewSSEClient(sseEndpoint string, timeout time.Duration) *SSEClient {
 			Timeout: 0,
 		},
 		sseEndpoint: sseEndpoint,
+		timeout:     timeout,
 		requestID:   1,
 	}
 }
 
+// SetCredentials sets the credentials sent with every request
+func (c *SSEClient) SetCredentials(creds *Credentials) {
+	c.credentials = creds
+}
+
 // Connect establishes SSE connection and discovers the messages endpoint
 func (c *SSEClient) Connect(ctx context.Context) error {
 	logger := log.FromContext(ctx)
@@ -64,6 +103,9 @@ func (c *SSEClient) Connect(ctx context.Context) error {
 
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
+	if err := c.credentials.apply(req); err != nil {
+		return err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -86,11 +128,19 @@ func (c *SSEClient) Connect(ctx context.Context) error {
 		return fmt.Errorf("SSE endpoint returned wrong content type: %s", contentType)
 	}
 
+	c.mu.Lock()
 	c.sseReader = resp.Body
+	c.pending = make(map[int64]chan sseResponse)
+	c.done = make(chan struct{})
+	c.mu.Unlock()
+
+	// The read loop delivers the endpoint event first, then dispatches responses
+	endpointChan := make(chan string, 1)
+	go c.readLoop(logger, endpointChan)
 
 	// Read the endpoint event to get messages URL
 	logger.V(1).Info("Reading SSE endpoint event")
-	messagesPath, err := c.readEndpointEvent(ctx)
+	messagesPath, err := c.waitForEndpoint(ctx, endpointChan)
 	if err != nil {
 		_ = resp.Body.Close()
 		logger.Error(err, "Failed to read endpoint event")
@@ -108,204 +158,295 @@ func (c *SSEClient) Connect(ctx context.Context) error {
 	return nil
 }
 
-// readEndpointEvent reads SSE stream until it finds the endpoint event
-func (c *SSEClient) readEndpointEvent(ctx context.Context) (string, error) {
-	scanner := bufio.NewScanner(c.sseReader)
-	timeout := time.After(1 * time.Second)
-
-	var currentEvent string
-	var currentData strings.Builder
-
-	eventChan := make(chan string, 1)
-	errChan := make(chan error, 1)
-
-	go func() {
-		logger := log.FromContext(ctx)
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if strings.HasPrefix(line, "event:") {
-				currentEvent = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
-			} else if strings.HasPrefix(line, "data:") {
-				data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
-				currentData.WriteString(data)
-			} else if line == "" {
-				// Empty line marks end of event
-				if currentEvent == "endpoint" {
-					// The endpoint data is just a plain string (the URI path), not JSON
-					uri := strings.TrimSpace(currentData.String())
-					logger.V(1).Info("Found SSE endpoint URI", "uri", uri)
-					if uri != "" {
-						eventChan <- uri
-						return
-					}
-				}
-				// Reset for next event
-				currentEvent = ""
-				currentData.Reset()
-			}
-		}
-		if err := scanner.Err(); err != nil {
-			errChan <- fmt.Errorf("scanner error: %w", err)
-		} else {
-			errChan <- fmt.Errorf("SSE stream ended without endpoint event")
-		}
-	}()
+// waitForEndpoint waits for the read loop to report the endpoint event
+func (c *SSEClient) waitForEndpoint(ctx context.Context, endpointChan <-chan string) (string, error) {
+	timeout := time.NewTimer(sseEndpointTimeout)
+	defer timeout.Stop()
 
 	select {
-	case uri := <-eventChan:
+	case uri := <-endpointChan:
 		return uri, nil
-	case err := <-errChan:
-		return "", err
-	case <-timeout:
+	case <-c.done:
+		if c.streamErr != nil {
+			return "", c.streamErr
+		}
+		return "", fmt.Errorf("SSE stream ended without endpoint event")
+	case <-timeout.C:
 		return "", fmt.Errorf("timeout waiting for endpoint event")
 	case <-ctx.Done():
 		return "", ctx.Err()
 	}
 }
 
-// Initialize sends an initialize request via SSE transport
-func (c *SSEClient) Initialize(ctx context.Context) (*mcp.InitializeResult, error) {
-	logger := log.FromContext(ctx)
+// readLoop reads events from the SSE stream until it ends.
+// The first endpoint event is sent on endpointChan; JSON-RPC responses are
+// routed to the pending request with the matching ID.
+func (c *SSEClient) readLoop(logger logr.Logger, endpointChan chan<- string) {
+	scanner := bufio.NewScanner(c.sseReader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSEEventSize)
 
-	if c.messagesURL == "" {
-		return nil, fmt.Errorf("not connected: call Connect() first")
+	var currentEvent string
+	var currentData []string
+	endpointSent := false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "event:") {
+			currentEvent = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			continue
+		}
+		if strings.HasPrefix(line, "data:") {
+			currentData = append(currentData, strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+			continue
+		}
+		if line != "" || len(currentData) == 0 {
+			// Comments, id/retry fields and keep-alives carry nothing we need
+			continue
+		}
+
+		// Empty line marks end of event
+		data := strings.Join(currentData, "\n")
+		switch {
+		case currentEvent == "endpoint":
+			// The endpoint data is just a plain string (the URI path), not JSON
+			if uri := strings.TrimSpace(data); uri != "" && !endpointSent {
+				logger.V(1).Info("Found SSE endpoint URI", "uri", uri)
+				endpointChan <- uri
+				endpointSent = true
+			}
+		case currentEvent == "" || currentEvent == "message":
+			c.dispatch(logger, data)
+		}
+
+		// Reset for next event
+		currentEvent = ""
+		currentData = nil
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		err = fmt.Errorf("scanner error: %w", err)
 	}
 
+	c.mu.Lock()
+	c.streamErr = err
+	close(c.done)
+	c.mu.Unlock()
+}
+
+// dispatch hands a JSON-RPC response to the request waiting for it.
+// Server-initiated requests, notifications and unknown IDs are ignored.
+func (c *SSEClient) dispatch(logger logr.Logger, data string) {
+	var message struct {
+		ID     *int64  `json:"id"`
+		Method *string `json:"method"`
+		sseResponse
+	}
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		logger.V(1).Info("Ignoring non JSON-RPC SSE message", "error", err.Error())
+		return
+	}
+	if message.ID == nil || message.Method != nil {
+		return
+	}
+
+	c.mu.Lock()
+	responseChan, ok := c.pending[*message.ID]
+	delete(c.pending, *message.ID)
+	c.mu.Unlock()
+
+	if !ok {
+		logger.V(1).Info("Ignoring SSE response for unknown request", "id", *message.ID)
+		return
+	}
+	responseChan <- message.sseResponse
+}
+
+// Initialize sends an initialize request via SSE transport and sends the
+// initialized notification to complete the handshake
+func (c *SSEClient) Initialize(ctx context.Context) (*mcp.InitializeResult, error) {
+	logger := log.FromContext(ctx)
 	logger.V(1).Info("Sending SSE initialize request", "messagesURL", c.messagesURL)
 
-	// Build initialize request
-	request := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"id":      c.requestID,
-		"method":  mcp.MethodInitialize,
-		"params": map[string]interface{}{
-			"protocolVersion": ProtocolVersion20241105, // SSE uses legacy protocol version
-			"capabilities":    map[string]interface{}{},
-			"clientInfo": map[string]string{
-				"name":    "mcp-operator-validator",
-				"version": "1.0.0",
-			},
+	params := mcp.InitializeParams{
+		ProtocolVersion: ProtocolVersion20241105, // SSE uses legacy protocol version
+		Capabilities:    mcp.ClientCapabilities{},
+		ClientInfo: mcp.Implementation{
+			Name:    "mcp-operator-validator",
+			Version: "1.0.0",
 		},
 	}
-	c.requestID++
 
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	var result mcp.InitializeResult
+	if err := c.call(ctx, mcp.MethodInitialize, params, &result); err != nil {
+		logger.Error(err, "SSE initialize failed")
+		return nil, fmt.Errorf("initialize failed: %w", err)
 	}
 
-	// POST to messages URL
-	req, err := http.NewRequestWithContext(ctx, "POST", c.messagesURL, strings.NewReader(string(requestBody)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if err := c.notify(ctx, mcp.MethodNotificationInitialized); err != nil {
+		return nil, fmt.Errorf("initialized notification failed: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	logger.Info(
+		"SSE initialize successful",
+		"serverName", result.ServerInfo.Name,
+		"protocolVersion", result.ProtocolVersion,
+	)
+	return &result, nil
+}
 
-	resp, err := c.httpClient.Do(req)
+// ListTools lists available tools from the MCP server, following every page
+func (c *SSEClient) ListTools(ctx context.Context) (*mcp.ListToolsResult, error) {
+	result, err := mcp.ListAllTools(ctx, c.call)
 	if err != nil {
-		logger.Error(err, "SSE initialize POST failed")
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("list tools failed: %w", err)
 	}
-	defer func() {
-		_ = resp.Body.Close()
-	}()
 
-	logger.V(1).Info("SSE initialize POST completed", "status", resp.StatusCode)
+	return result, nil
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+// ListResources lists available resources from the MCP server
+func (c *SSEClient) ListResources(ctx context.Context) (*mcp.ListResourcesResult, error) {
+	result, err := mcp.ListAllResources(ctx, c.call)
+	if err != nil {
+		return nil, fmt.Errorf("list resources failed: %w", err)
 	}
 
-	// Read response from SSE stream
-	logger.V(1).Info("Reading initialize response from SSE stream")
-	response, err := c.readInitializeResponse(ctx)
+	return result, nil
+}
+
+// ListPrompts lists available prompts from the MCP server
+func (c *SSEClient) ListPrompts(ctx context.Context) (*mcp.ListPromptsResult, error) {
+	result, err := mcp.ListAllPrompts(ctx, c.call)
 	if err != nil {
-		logger.Error(err, "Failed to read SSE initialize response")
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("list prompts failed: %w", err)
 	}
 
-	logger.Info(
-		"SSE initialize successful",
-		"serverName", response.ServerInfo.Name,
-		"protocolVersion", response.ProtocolVersion,
-	)
-	return response, nil
+	return result, nil
 }
 
-// readInitializeResponse reads the SSE stream to find the initialize response
-func (c *SSEClient) readInitializeResponse(ctx context.Context) (*mcp.InitializeResult, error) {
-	scanner := bufio.NewScanner(c.sseReader)
-	timeout := time.After(2 * time.Second)
-
-	var currentData strings.Builder
-
-	responseChan := make(chan *mcp.InitializeResult, 1)
-	errChan := make(chan error, 1)
-
-	go func() {
-		for scanner.Scan() {
-			line := scanner.Text()
-
-			if strings.HasPrefix(line, "data:") {
-				data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
-				currentData.WriteString(data)
-			} else if line == "" {
-				// Empty line marks end of event
-				if currentData.Len() > 0 {
-					// Try to parse as JSON-RPC response
-					var jsonRPCResp struct {
-						JSONRPC string                  `json:"jsonrpc"`
-						ID      int64                   `json:"id"`
-						Result  *mcp.InitializeResult   `json:"result,omitempty"`
-						Error   *map[string]interface{} `json:"error,omitempty"`
-					}
-
-					dataStr := currentData.String()
-					if err := json.Unmarshal([]byte(dataStr), &jsonRPCResp); err != nil {
-						// Not a JSON-RPC message, skip
-						currentData.Reset()
-						continue
-					}
-
-					// Check if this is the initialize response
-					if jsonRPCResp.Result != nil {
-						responseChan <- jsonRPCResp.Result
-						return
-					} else if jsonRPCResp.Error != nil {
-						errChan <- fmt.Errorf("server returned error: %v", jsonRPCResp.Error)
-						return
-					}
-
-					currentData.Reset()
-				}
-			}
-		}
-		if err := scanner.Err(); err != nil {
-			errChan <- fmt.Errorf("scanner error: %w", err)
-		} else {
-			errChan <- fmt.Errorf("SSE stream ended without response")
-		}
+// Ping sends an initialize request to check if the server is responsive
+// This is a convenience method for quick connectivity checks
+func (c *SSEClient) Ping(ctx context.Context) error {
+	_, err := c.Initialize(ctx)
+	return err
+}
+
+// call sends a JSON-RPC 2.0 request to the messages URL and waits for the
+// response with the same ID to arrive on the SSE stream
+func (c *SSEClient) call(ctx context.Context, method string, params any, result any) error {
+	if c.messagesURL == "" {
+		return fmt.Errorf("not connected: call Connect() first")
+	}
+
+	// Register before sending so a fast response cannot be missed
+	responseChan := make(chan sseResponse, 1)
+	c.mu.Lock()
+	requestID := c.requestID
+	c.requestID++
+	c.pending[requestID] = responseChan
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, requestID)
+		c.mu.Unlock()
 	}()
 
+	request := mcp.JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      int(requestID),
+		Method:  method,
+		Params:  params,
+	}
+	if err := c.post(ctx, request); err != nil {
+		return err
+	}
+
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = defaultSSEResponseTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
 	select {
 	case response := <-responseChan:
-		return response, nil
-	case err := <-errChan:
-		return nil, err
-	case <-timeout:
-		return nil, fmt.Errorf("timeout waiting for initialize response")
+		if response.Error != nil {
+			return fmt.Errorf("JSON-RPC error %d: %s", response.Error.Code, response.Error.Message)
+		}
+		if result != nil && len(response.Result) > 0 {
+			if err := json.Unmarshal(response.Result, result); err != nil {
+				return fmt.Errorf("failed to decode result: %w", err)
+			}
+		}
+		return nil
+	case <-c.done:
+		if c.streamErr != nil {
+			return fmt.Errorf("SSE stream closed before response: %w", c.streamErr)
+		}
+		return errors.New("SSE stream ended without response")
+	case <-timer.C:
+		return fmt.Errorf("timeout waiting for %s response", method)
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return ctx.Err()
+	}
+}
+
+// notify sends a JSON-RPC 2.0 notification (no response expected)
+func (c *SSEClient) notify(ctx context.Context, method string) error {
+	return c.post(ctx, map[string]any{
+		"jsonrpc": "2.0",
+		"method":  method,
+	})
+}
+
+// post sends a JSON-RPC message to the messages URL.
+// The server acknowledges the POST; any response is delivered on the SSE stream.
+func (c *SSEClient) post(ctx context.Context, message any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.messagesURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if err := c.credentials.apply(req); err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK &&
+		resp.StatusCode != http.StatusAccepted &&
+		resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
 	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
 }
 
-// Close closes the SSE connection
+// Close closes the SSE connection, which also stops the read loop
 func (c *SSEClient) Close() error {
-	if c.sseReader != nil {
-		return c.sseReader.Close()
+	c.mu.Lock()
+	reader := c.sseReader
+	c.mu.Unlock()
+
+	if reader != nil {
+		return reader.Close()
 	}
 	return nil
 }
@@ -0,0 +1,271 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/pkg/mcp"
+)
+
+// mockSSEServer creates a test server speaking the 2024-11-05 SSE transport.
+// Responses to POSTed requests are written to the open SSE stream. When
+// reverseBatch is set, responses are held until that many are pending and then
+// sent in reverse order, so clients must correlate them by ID.
+func mockSSEServer(t *testing.T, config mockServerConfig, reverseBatch int) *httptest.Server {
+	events := make(chan string, 16)
+
+	var mu sync.Mutex
+	var held []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("response writer does not support flushing")
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprint(w, ": connected\n\nevent: endpoint\ndata: /messages?sessionId=test\n\n")
+		flusher.Flush()
+
+		for {
+			select {
+			case event := <-events:
+				_, _ = fmt.Fprint(w, event)
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+	mux.HandleFunc("/messages", func(w http.ResponseWriter, r *http.Request) {
+		var request mcp.JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+
+		if strings.HasPrefix(request.Method, "notifications/") {
+			return
+		}
+
+		response, err := json.Marshal(mockRPCResponse(config, request))
+		if err != nil {
+			t.Errorf("Failed to encode response: %v", err)
+			return
+		}
+		event := fmt.Sprintf("event: message\ndata: %s\n\n", response)
+
+		if reverseBatch == 0 {
+			events <- event
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		held = append(held, event)
+		if len(held) == reverseBatch {
+			for i := len(held) - 1; i >= 0; i-- {
+				events <- held[i]
+			}
+			held = nil
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	// Registered before any client cleanup, so clients disconnect first and
+	// the open SSE handler returns before the server waits for it
+	t.Cleanup(server.Close)
+	return server
+}
+
+func connectSSEClient(t *testing.T, server *httptest.Server) *SSEClient {
+	t.Helper()
+
+	client := NewSSEClient(server.URL+"/sse", 5*time.Second)
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	return client
+}
+
+func TestSSEClient_ListCapabilities(t *testing.T) {
+	config := validServerConfig()
+	config.tools = []mcp.Tool{{
+		Name:        "echo",
+		Description: "Echoes its input",
+		InputSchema: map[string]any{"type": "object"},
+	}}
+	server := mockSSEServer(t, config, 0)
+
+	client := connectSSEClient(t, server)
+	ctx := context.Background()
+
+	initResult, err := client.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if initResult.ServerInfo.Name != "test-server" {
+		t.Errorf("ServerInfo.Name = %v, want test-server", initResult.ServerInfo.Name)
+	}
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "echo" {
+		t.Errorf("ListTools() = %+v, want the echo tool", tools.Tools)
+	}
+
+	if _, err := client.ListResources(ctx); err != nil {
+		t.Errorf("ListResources() error = %v", err)
+	}
+	if _, err := client.ListPrompts(ctx); err != nil {
+		t.Errorf("ListPrompts() error = %v", err)
+	}
+	if err := client.Ping(ctx); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
+
+func TestSSEClient_CorrelatesOutOfOrderResponses(t *testing.T) {
+	server := mockSSEServer(t, validServerConfig(), 3)
+
+	client := connectSSEClient(t, server)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	calls := []func() error{
+		func() error { _, err := client.ListTools(ctx); return err },
+		func() error { _, err := client.ListResources(ctx); return err },
+		func() error { _, err := client.ListPrompts(ctx); return err },
+	}
+	for _, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- call()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent call failed: %v", err)
+		}
+	}
+}
+
+func TestSSEClient_JSONRPCError(t *testing.T) {
+	config := validServerConfig()
+	config.toolsListFails = true
+	server := mockSSEServer(t, config, 0)
+
+	client := connectSSEClient(t, server)
+
+	_, err := client.ListTools(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "Tools list failed") {
+		t.Errorf("ListTools() error = %v, want the server's JSON-RPC error", err)
+	}
+}
+
+func TestSSEClient_StreamClosedFailsPendingRequest(t *testing.T) {
+	// Responses are held until a second request arrives, which never happens
+	server := mockSSEServer(t, validServerConfig(), 2)
+
+	client := connectSSEClient(t, server)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.ListTools(context.Background())
+		errs <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	_ = client.Close()
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("ListTools() succeeded after the stream was closed")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("pending request was not released when the stream closed")
+	}
+}
+
+func TestSSEClient_NotConnected(t *testing.T) {
+	client := NewSSEClient("http://localhost/sse", time.Second)
+
+	if _, err := client.ListTools(context.Background()); err == nil {
+		t.Error("ListTools() before Connect() should fail")
+	}
+}
+
+func TestValidator_SSECapabilityTesting(t *testing.T) {
+	config := validServerConfig()
+	config.resourcesListFails = true
+	config.tools = []mcp.Tool{{
+		Name:        "bad tool",
+		Description: "Has an invalid name",
+		InputSchema: map[string]any{"type": "object"},
+	}}
+	server := mockSSEServer(t, config, 0)
+
+	validator := NewValidator(server.URL)
+	result, err := validator.Validate(context.Background(), ValidationOptions{
+		Transport: TransportSSE,
+	})
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if result.DetectedTransport != TransportSSE {
+		t.Errorf("DetectedTransport = %v, want %v", result.DetectedTransport, TransportSSE)
+	}
+	if result.Success {
+		t.Error("Expected validation to fail for an invalid tool name")
+	}
+
+	codes := make(map[string]bool)
+	for _, issue := range result.Issues {
+		codes[issue.Code] = true
+	}
+	if !codes[CodeToolInvalidName] {
+		t.Errorf("Expected %s issue, got %+v", CodeToolInvalidName, result.Issues)
+	}
+	if !codes[CodeResourcesListFailed] {
+		t.Errorf("Expected %s issue, got %+v", CodeResourcesListFailed, result.Issues)
+	}
+}
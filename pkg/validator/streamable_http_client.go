@@ -34,11 +34,12 @@ import (
 // StreamableHTTPClient is a standalone client for MCP Streamable HTTP transport
 // This client implements the MCP protocol over HTTP POST requests with JSON-RPC 2.0
 type StreamableHTTPClient struct {
-	endpoint   string
-	httpClient *http.Client
-	requestID  atomic.Int32
-	timeout    time.Duration
-	sessionID  string // MCP session ID from initialize response
+	endpoint    string
+	httpClient  *http.Client
+	requestID   atomic.Int32
+	timeout     time.Duration
+	sessionID   string       // MCP session ID from initialize response
+	credentials *Credentials // Optional credentials sent with every request
 }
 
 // NewStreamableHTTPClient creates a new Streamable HTTP client for the given endpoint
@@ -52,6 +53,11 @@ func NewStreamableHTTPClient(endpoint string, timeout time.Duration) *Streamable
 	}
 }
 
+// SetCredentials sets the credentials sent with every request
+func (c *StreamableHTTPClient) SetCredentials(creds *Credentials) {
+	c.credentials = creds
+}
+
 // Initialize sends an initialize request to the MCP server and sends the
 // initialized notification to complete the handshake.
 //
@@ -94,34 +100,34 @@ func (c *StreamableHTTPClient) Initialize(ctx context.Context) (*mcp.InitializeR
 	return &result, nil
 }
 
-// ListTools lists available tools from the MCP server
+// ListTools lists available tools from the MCP server, following every page
 func (c *StreamableHTTPClient) ListTools(ctx context.Context) (*mcp.ListToolsResult, error) {
-	var result mcp.ListToolsResult
-	if err := c.call(ctx, mcp.MethodToolsList, nil, &result); err != nil {
+	result, err := mcp.ListAllTools(ctx, c.call)
+	if err != nil {
 		return nil, fmt.Errorf("list tools failed: %w", err)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // ListResources lists available resources from the MCP server
 func (c *StreamableHTTPClient) ListResources(ctx context.Context) (*mcp.ListResourcesResult, error) {
-	var result mcp.ListResourcesResult
-	if err := c.call(ctx, mcp.MethodResourcesList, nil, &result); err != nil {
+	result, err := mcp.ListAllResources(ctx, c.call)
+	if err != nil {
 		return nil, fmt.Errorf("list resources failed: %w", err)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // ListPrompts lists available prompts from the MCP server
 func (c *StreamableHTTPClient) ListPrompts(ctx context.Context) (*mcp.ListPromptsResult, error) {
-	var result mcp.ListPromptsResult
-	if err := c.call(ctx, mcp.MethodPromptsList, nil, &result); err != nil {
+	result, err := mcp.ListAllPrompts(ctx, c.call)
+	if err != nil {
 		return nil, fmt.Errorf("list prompts failed: %w", err)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 // Ping sends an initialize request to check if the server is responsive
@@ -165,6 +171,10 @@ func (c *StreamableHTTPClient) notify(ctx context.Context, method string) error
 		httpReq.Header.Set(mcp.HeaderSessionID, c.sessionID)
 	}
 
+	if err := c.credentials.apply(httpReq); err != nil {
+		return err
+	}
+
 	// Send HTTP request
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -233,6 +243,10 @@ func (c *StreamableHTTPClient) callWithResponse(
 		httpReq.Header.Set(mcp.HeaderSessionID, c.sessionID)
 	}
 
+	if err := c.credentials.apply(httpReq); err != nil {
+		return err
+	}
+
 	// Send HTTP request
 	httpResp, err := c.httpClient.Do(httpReq)
 	if err != nil {
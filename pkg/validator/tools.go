@@ -0,0 +1,170 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/vitorbari/mcp-operator/pkg/mcp"
+)
+
+// toolNamePattern matches the characters allowed in tool names by the MCP specification:
+// 1 to 128 ASCII letters, digits, underscores, hyphens and dots
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// maxSchemaErrorLength caps schema error messages so a single tool cannot flood the status
+const maxSchemaErrorLength = 300
+
+// checkTools runs conformance checks on the tools returned by tools/list.
+// Invalid input schemas and invalid or duplicate names are errors, since clients
+// cannot call such tools reliably. Missing descriptions are warnings because the
+// specification allows them, but models rely on them to pick the right tool.
+func checkTools(tools []mcp.Tool) []ValidationIssue {
+	var issues []ValidationIssue
+	seen := make(map[string]bool, len(tools))
+
+	for i, tool := range tools {
+		label := fmt.Sprintf("'%s'", tool.Name)
+		if tool.Name == "" {
+			label = fmt.Sprintf("at index %d", i)
+		}
+
+		if !toolNamePattern.MatchString(tool.Name) {
+			issues = append(issues, newErrorIssue(
+				CodeToolInvalidName,
+				fmt.Sprintf("Tool %s has an invalid name: must be 1-128 characters of A-Z, a-z, 0-9, '_', '-' or '.'", label),
+			))
+		}
+
+		if tool.Name != "" {
+			if seen[tool.Name] {
+				issues = append(issues, newErrorIssue(
+					CodeToolDuplicateName,
+					fmt.Sprintf("Tool name '%s' is used by more than one tool", tool.Name),
+				))
+			}
+			seen[tool.Name] = true
+		}
+
+		if err := checkToolInputSchema(tool.InputSchema); err != nil {
+			issues = append(issues, newErrorIssue(
+				CodeToolInvalidSchema,
+				fmt.Sprintf("Tool %s has an invalid inputSchema: %v", label, err),
+			))
+		}
+
+		if strings.TrimSpace(tool.Description) == "" {
+			issues = append(issues, newWarningIssue(
+				CodeToolMissingDescription,
+				fmt.Sprintf("Tool %s has no description", label),
+			))
+		}
+	}
+
+	return issues
+}
+
+// checkToolInputSchema verifies that a tool's inputSchema is a valid JSON Schema
+// describing an object, as required by the MCP specification.
+// Schemas without $schema are compiled as draft 2020-12, and external $refs are
+// not followed, so they are reported as errors.
+func checkToolInputSchema(inputSchema any) error {
+	if inputSchema == nil {
+		return errors.New("inputSchema is required")
+	}
+
+	schemaObject, ok := inputSchema.(map[string]any)
+	if !ok {
+		return fmt.Errorf("must be a JSON object, got %T", inputSchema)
+	}
+	if schemaType, _ := schemaObject["type"].(string); schemaType != "object" {
+		return fmt.Errorf(`type must be "object", got %v`, schemaObject["type"])
+	}
+
+	// Re-decode with json.Number so the compiler sees numbers the way it expects
+	raw, err := json.Marshal(inputSchema)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("failed to decode schema: %w", err)
+	}
+
+	const schemaURL = "mcp://tool/inputSchema.json"
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.UseLoader(jsonschema.SchemeURLLoader{})
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return err
+	}
+	if _, err := compiler.Compile(schemaURL); err != nil {
+		return errors.New(formatSchemaError(err))
+	}
+
+	return nil
+}
+
+// formatSchemaError flattens a compiler error into a single bounded line.
+// Metaschema violations are reduced to their leaf errors, which point at the
+// offending keyword instead of the chain of allOf/anyOf wrappers above it.
+func formatSchemaError(err error) string {
+	var formatted string
+
+	var schemaErr *jsonschema.SchemaValidationError
+	var validationErr *jsonschema.ValidationError
+	var loadErr *jsonschema.LoadURLError
+	if errors.As(err, &loadErr) {
+		formatted = fmt.Sprintf("external $ref %q cannot be resolved", loadErr.URL)
+	} else if errors.As(err, &schemaErr) && errors.As(schemaErr.Err, &validationErr) {
+		printer := message.NewPrinter(language.English)
+		var leaves []string
+		collectSchemaErrorLeaves(validationErr, printer, &leaves)
+		formatted = strings.Join(leaves, "; ")
+	} else {
+		formatted = strings.Join(strings.Fields(err.Error()), " ")
+	}
+
+	if len(formatted) > maxSchemaErrorLength {
+		formatted = formatted[:maxSchemaErrorLength] + "..."
+	}
+	return formatted
+}
+
+// collectSchemaErrorLeaves appends the unique leaf messages of a validation error tree
+func collectSchemaErrorLeaves(err *jsonschema.ValidationError, printer *message.Printer, leaves *[]string) {
+	if len(err.Causes) == 0 {
+		leaf := fmt.Sprintf("at '/%s': %s", strings.Join(err.InstanceLocation, "/"), err.ErrorKind.LocalizedString(printer))
+		if !slices.Contains(*leaves, leaf) {
+			*leaves = append(*leaves, leaf)
+		}
+		return
+	}
+	for _, cause := range err.Causes {
+		collectSchemaErrorLeaves(cause, printer, leaves)
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2025 Vitor Bari.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vitorbari/mcp-operator/pkg/mcp"
+)
+
+// decodeSchema parses a JSON schema the same way the client decodes tools/list results
+func decodeSchema(t *testing.T, schema string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(schema), &v); err != nil {
+		t.Fatalf("invalid test schema: %v", err)
+	}
+	return v
+}
+
+func issueCodes(issues []ValidationIssue) []string {
+	codes := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	return codes
+}
+
+func TestCheckToolInputSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{
+			name:   "minimal object schema",
+			schema: `{"type": "object"}`,
+		},
+		{
+			name: "object schema with properties",
+			schema: `{
+				"type": "object",
+				"properties": {
+					"query": {"type": "string", "description": "Search query"},
+					"limit": {"type": "integer", "minimum": 1, "maximum": 100}
+				},
+				"required": ["query"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name:   "draft-07 schema with definitions",
+			schema: `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object", "definitions": {"id": {"type": "string"}}, "properties": {"id": {"$ref": "#/definitions/id"}}}`,
+		},
+		{
+			name:    "missing schema",
+			schema:  `null`,
+			wantErr: "inputSchema is required",
+		},
+		{
+			name:    "not an object",
+			schema:  `"object"`,
+			wantErr: "must be a JSON object",
+		},
+		{
+			name:    "wrong root type",
+			schema:  `{"type": "string"}`,
+			wantErr: `type must be "object"`,
+		},
+		{
+			name:    "required is not an array",
+			schema:  `{"type": "object", "required": "query"}`,
+			wantErr: "required",
+		},
+		{
+			name:    "unknown property type",
+			schema:  `{"type": "object", "properties": {"q": {"type": "text"}}}`,
+			wantErr: "properties/q/type",
+		},
+		{
+			name:    "external reference",
+			schema:  `{"type": "object", "properties": {"q": {"$ref": "https://example.com/schema.json"}}}`,
+			wantErr: "example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkToolInputSchema(decodeSchema(t, tt.schema))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected valid schema, got error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestCheckTools(t *testing.T) {
+	validSchema := map[string]any{"type": "object"}
+
+	tests := []struct {
+		name      string
+		tools     []mcp.Tool
+		wantCodes []string
+	}{
+		{
+			name: "conformant tools",
+			tools: []mcp.Tool{
+				{Name: "search", Description: "Search the web", InputSchema: validSchema},
+				{Name: "files.read_v2", Description: "Read a file", InputSchema: validSchema},
+			},
+			wantCodes: []string{},
+		},
+		{
+			name: "duplicate names",
+			tools: []mcp.Tool{
+				{Name: "search", Description: "Search", InputSchema: validSchema},
+				{Name: "search", Description: "Search again", InputSchema: validSchema},
+			},
+			wantCodes: []string{CodeToolDuplicateName},
+		},
+		{
+			name: "invalid names",
+			tools: []mcp.Tool{
+				{Name: "search web", Description: "Search", InputSchema: validSchema},
+				{Name: "", Description: "Unnamed", InputSchema: validSchema},
+				{Name: strings.Repeat("a", 129), Description: "Too long", InputSchema: validSchema},
+			},
+			wantCodes: []string{CodeToolInvalidName, CodeToolInvalidName, CodeToolInvalidName},
+		},
+		{
+			name: "missing description",
+			tools: []mcp.Tool{
+				{Name: "search", Description: "  ", InputSchema: validSchema},
+			},
+			wantCodes: []string{CodeToolMissingDescription},
+		},
+		{
+			name: "invalid schema",
+			tools: []mcp.Tool{
+				{Name: "search", Description: "Search"},
+			},
+			wantCodes: []string{CodeToolInvalidSchema},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := issueCodes(checkTools(tt.tools))
+			if strings.Join(got, ",") != strings.Join(tt.wantCodes, ",") {
+				t.Errorf("Expected issue codes %v, got %v", tt.wantCodes, got)
+			}
+		})
+	}
+}
+
+func TestCheckToolsIssueLevels(t *testing.T) {
+	issues := checkTools([]mcp.Tool{
+		{Name: "search", InputSchema: map[string]any{"type": "object"}},
+		{Name: "bad name", Description: "Bad", InputSchema: map[string]any{"type": "object"}},
+	})
+
+	for _, issue := range issues {
+		wantLevel := LevelError
+		if issue.Code == CodeToolMissingDescription {
+			wantLevel = LevelWarning
+		}
+		if issue.Level != wantLevel {
+			t.Errorf("Expected %s to be %s, got %s", issue.Code, wantLevel, issue.Level)
+		}
+		if len(issue.Suggestions) == 0 {
+			t.Errorf("Expected catalog suggestions for %s", issue.Code)
+		}
+	}
+}
+
+func TestValidator_MalformedToolSchema(t *testing.T) {
+	config := validServerConfig()
+	config.tools = []mcp.Tool{
+		{Name: "search", Description: "Search the web", InputSchema: map[string]any{"type": "object"}},
+		{Name: "broken", Description: "Broken schema", InputSchema: map[string]any{
+			"type":     "object",
+			"required": "query",
+		}},
+	}
+	server := mockMCPServer(t, config)
+	defer server.Close()
+
+	v := NewValidator(server.URL, WithMetricsEnabled(false))
+	result, err := v.Validate(context.Background(), ValidationOptions{Transport: TransportStreamableHTTP})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if result.IsCompliant() {
+		t.Error("Expected server with a malformed tool schema to be non-compliant")
+	}
+
+	found := false
+	for _, issue := range result.Issues {
+		if issue.Code == CodeToolInvalidSchema && strings.Contains(issue.Message, "'broken'") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected %s issue for tool 'broken', got %v", CodeToolInvalidSchema, result.Issues)
+	}
+}
+
+func TestValidator_MissingToolDescriptionStaysCompliant(t *testing.T) {
+	config := validServerConfig()
+	config.tools = []mcp.Tool{
+		{Name: "search", InputSchema: map[string]any{"type": "object"}},
+	}
+	server := mockMCPServer(t, config)
+	defer server.Close()
+
+	v := NewValidator(server.URL, WithMetricsEnabled(false))
+	result, err := v.Validate(context.Background(), ValidationOptions{Transport: TransportStreamableHTTP})
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if !result.IsCompliant() {
+		t.Errorf("Expected missing description to only warn, got issues %v", result.Issues)
+	}
+}
@@ -49,7 +49,8 @@ const (
 
 // TransportDetector detects which MCP transport protocol a server supports
 type TransportDetector struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	credentials *Credentials
 }
 
 // NewTransportDetector creates a new transport detector
@@ -71,6 +72,11 @@ func NewTransportDetector(timeout time.Duration) *TransportDetector {
 	}
 }
 
+// SetCredentials sets the credentials sent with detection probes
+func (d *TransportDetector) SetCredentials(creds *Credentials) {
+	d.credentials = creds
+}
+
 // DetectTransport attempts to detect which transport protocol the server supports
 // It tries Streamable HTTP first (preferred), then falls back to SSE
 func (d *TransportDetector) DetectTransport(
@@ -162,6 +168,12 @@ func (d *TransportDetector) tryStreamableHTTP(ctx context.Context, endpoint stri
 	req.Header.Set("Content-Type", "application/json")
 	// MCP Streamable HTTP requires accepting both JSON and SSE formats
 	req.Header.Set("Accept", "application/json, text/event-stream")
+	if err := d.credentials.apply(req); err != nil {
+		log.Info("Failed to apply credentials to Streamable HTTP request",
+			"endpoint", endpoint,
+			"error", err)
+		return false
+	}
 
 	log.V(1).Info("Sending Streamable HTTP detection request",
 		"endpoint", endpoint,
@@ -240,6 +252,12 @@ func (d *TransportDetector) trySSE(ctx context.Context, endpoint string) bool {
 	}
 
 	req.Header.Set("Accept", "text/event-stream")
+	if err := d.credentials.apply(req); err != nil {
+		log.Info("Failed to apply credentials to SSE request",
+			"endpoint", endpoint,
+			"error", err)
+		return false
+	}
 
 	log.V(1).Info("Sending SSE detection request",
 		"endpoint", endpoint,
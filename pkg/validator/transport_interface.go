@@ -41,6 +41,45 @@ type Transport interface {
 	Close() error
 }
 
+// Client is the request/response surface shared by the MCP clients of every
+// transport. Capability testing and ping go through it, so they behave the
+// same regardless of how messages reach the server.
+type Client interface {
+	// Initialize performs the MCP initialization handshake
+	Initialize(ctx context.Context) (*mcp.InitializeResult, error)
+
+	// ListTools calls tools/list
+	ListTools(ctx context.Context) (*mcp.ListToolsResult, error)
+
+	// ListResources calls resources/list
+	ListResources(ctx context.Context) (*mcp.ListResourcesResult, error)
+
+	// ListPrompts calls prompts/list
+	ListPrompts(ctx context.Context) (*mcp.ListPromptsResult, error)
+
+	// Ping checks that the server is responsive
+	Ping(ctx context.Context) error
+
+	// Close cleans up any resources
+	Close() error
+}
+
+// Both clients must satisfy the shared interface
+var (
+	_ Client = (*StreamableHTTPClient)(nil)
+	_ Client = (*SSEClient)(nil)
+)
+
+// ClientTransport is implemented by transports that expose their underlying
+// client. The validator uses it to test advertised capabilities after a
+// successful Initialize; transports without it are only checked for the handshake.
+type ClientTransport interface {
+	Transport
+
+	// Client returns the client used by the transport
+	Client() Client
+}
+
 // TransportOptions contains configuration for creating transports
 type TransportOptions struct {
 	// Timeout for transport operations
@@ -51,6 +90,9 @@ type TransportOptions struct {
 
 	// EnableSessionManagement enables session support if available
 	EnableSessionManagement bool
+
+	// Credentials are sent with every request (optional)
+	Credentials *Credentials
 }
 
 // DefaultTransportOptions returns sensible defaults for transport creation
@@ -129,9 +171,10 @@ type streamableHTTPTransport struct {
 func newStreamableHTTPTransport(endpoint string, httpClient *http.Client, opts TransportOptions) Transport {
 	// Create a new client with the provided HTTP client
 	client := &StreamableHTTPClient{
-		endpoint:   endpoint,
-		httpClient: httpClient,
-		timeout:    opts.Timeout,
+		endpoint:    endpoint,
+		httpClient:  httpClient,
+		timeout:     opts.Timeout,
+		credentials: opts.Credentials,
 	}
 
 	return &streamableHTTPTransport{
@@ -144,6 +187,10 @@ func (t *streamableHTTPTransport) Initialize(ctx context.Context) (*mcp.Initiali
 	return t.client.Initialize(ctx)
 }
 
+func (t *streamableHTTPTransport) Client() Client {
+	return t.client
+}
+
 func (t *streamableHTTPTransport) Name() TransportType {
 	return t.transport
 }
@@ -169,14 +216,15 @@ func newSSETransport(endpoint string, httpClient *http.Client, opts TransportOpt
 	// Create SSE client - note: SSE client manages its own HTTP client configuration
 	// We don't use the provided httpClient here because SSE requires special timeout handling
 	_ = httpClient
-	_ = opts
 
 	client := &SSEClient{
 		httpClient: &http.Client{
 			Timeout: 0, // SSE needs long-lived connections
 		},
 		sseEndpoint: endpoint,
+		timeout:     opts.Timeout,
 		requestID:   1,
+		credentials: opts.Credentials,
 	}
 
 	return &sseTransport{
@@ -199,6 +247,10 @@ func (t *sseTransport) Initialize(ctx context.Context) (*mcp.InitializeResult, e
 	return t.client.Initialize(ctx)
 }
 
+func (t *sseTransport) Client() Client {
+	return t.client
+}
+
 func (t *sseTransport) Name() TransportType {
 	return t.transport
 }
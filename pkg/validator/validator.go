@@ -34,6 +34,7 @@ type Validator struct {
 	transportFactory TransportFactory
 	versionDetector  *ProtocolVersionDetector
 	metricsRecorder  MetricsRecorder
+	credentials      *Credentials
 }
 
 // ValidationOptions configures validation behavior
@@ -56,6 +57,15 @@ type ValidationOptions struct {
 	// If set, skips auto-detection and uses the specified transport
 	// Valid values: TransportStreamableHTTP, TransportSSE, or empty for auto-detection
 	Transport TransportType
+
+	// RequiredTools are tool names that must be returned by tools/list
+	RequiredTools []string
+
+	// RequiredResources are resource URIs that must be returned by resources/list
+	RequiredResources []string
+
+	// RequiredPrompts are prompt names that must be returned by prompts/list
+	RequiredPrompts []string
 }
 
 // ValidationResult contains the results of protocol validation
@@ -72,6 +82,15 @@ type ValidationResult struct {
 	// ServerInfo contains server implementation details
 	ServerInfo *ServerInfo
 
+	// Tools lists the tool names returned by tools/list
+	Tools []string
+
+	// Resources lists the resource URIs returned by resources/list
+	Resources []string
+
+	// Prompts lists the prompt names returned by prompts/list
+	Prompts []string
+
 	// Issues contains any validation problems found
 	Issues []ValidationIssue
 
@@ -137,7 +156,15 @@ const (
 	CodePromptsListFailed      = "PROMPTS_LIST_FAILED"
 	CodeAuthRequired           = "AUTH_REQUIRED"
 	CodeAuthOnInitialize       = "AUTH_ON_INITIALIZE"
+	CodeAuthFailed             = "AUTH_FAILED"
 	CodeProtocolMismatch       = "PROTOCOL_MISMATCH"
+	CodeToolInvalidSchema      = "TOOL_INVALID_SCHEMA"
+	CodeToolInvalidName        = "TOOL_INVALID_NAME"
+	CodeToolDuplicateName      = "TOOL_DUPLICATE_NAME"
+	CodeToolMissingDescription = "TOOL_MISSING_DESCRIPTION"
+	CodeMissingTool            = "MISSING_TOOL"
+	CodeMissingResource        = "MISSING_RESOURCE"
+	CodeMissingPrompt          = "MISSING_PROMPT"
 )
 
 // Option configures a Validator during creation
@@ -168,6 +195,15 @@ func WithHTTPClient(client *http.Client) Option {
 	}
 }
 
+// WithCredentials sets the credentials sent with every validation request
+// When set, an authentication error means the credentials were rejected
+// and is reported as a failure instead of AuthRequired
+func WithCredentials(creds *Credentials) Option {
+	return func(v *Validator) {
+		v.credentials = creds
+	}
+}
+
 // WithMetricsRecorder sets a custom metrics recorder
 // This is primarily useful for testing or custom metrics collection
 func WithMetricsRecorder(m MetricsRecorder) Option {
@@ -260,6 +296,9 @@ func NewValidator(baseURL string, opts ...Option) *Validator {
 		opt(v)
 	}
 
+	// Detection probes need credentials too, and WithTimeout replaces the detector
+	v.detector.SetCredentials(v.credentials)
+
 	return v
 }
 
@@ -364,6 +403,7 @@ func (v *Validator) Validate(ctx context.Context, opts ValidationOptions) (*Vali
 		Timeout:                 v.timeout,
 		HTTPClient:              nil,
 		EnableSessionManagement: false,
+		Credentials:             v.credentials,
 	}
 
 	transport, err := v.transportFactory.CreateTransport(transportType, endpoint, transportOpts)
@@ -385,7 +425,10 @@ func (v *Validator) Validate(ctx context.Context, opts ValidationOptions) (*Vali
 	validationErr := v.validateWithTransport(ctx, transport, opts, result)
 	if validationErr != nil {
 		// Check if this is an auth error
-		if isAuthError(validationErr) {
+		if isAuthError(validationErr) && v.credentials != nil {
+			// Rejected credentials were already reported by validateWithTransport
+			result.Success = false
+		} else if isAuthError(validationErr) {
 			result.RequiresAuth = true
 			result.AuthMethod = extractAuthMethod(validationErr, nil)
 			// IMPORTANT: AuthRequired is NOT a failure - we successfully detected that auth is needed
@@ -434,6 +477,18 @@ func (v *Validator) validateWithTransport(
 	// Step 1: Initialize transport
 	initResult, err := transport.Initialize(ctx)
 	if err != nil {
+		// With credentials configured, an auth error means they were rejected
+		if isAuthError(err) && v.credentials != nil {
+			result.Success = false
+			result.RequiresAuth = true
+			result.AuthMethod = extractAuthMethod(err, nil)
+			result.Issues = append(result.Issues, newErrorIssue(
+				CodeAuthFailed,
+				fmt.Sprintf("Server rejected the configured credentials: %v", err),
+			))
+			return err
+		}
+
 		// Check if this is an auth error during initialization
 		if isAuthError(err) {
 			result.RequiresAuth = true
@@ -504,15 +559,14 @@ func (v *Validator) validateWithTransport(
 		}
 	}
 
-	// Step 6: Test capability endpoints (only for transports that support it)
-	// Currently only Streamable HTTP has the methods for testing capabilities
-	if transport.Name() == TransportStreamableHTTP {
-		// We need to cast to the concrete type to access ListTools, ListResources, ListPrompts
-		if httpTransport, ok := transport.(*streamableHTTPTransport); ok {
-			testCapabilityEndpoints(ctx, httpTransport.client, initResult.Capabilities, result)
-		}
+	// Step 6: Test capability endpoints (only for transports that expose a client)
+	if clientTransport, ok := transport.(ClientTransport); ok {
+		testCapabilityEndpoints(ctx, clientTransport.Client(), initResult.Capabilities, result)
 	}
 
+	// Step 7: Check the expected inventory against what the server listed
+	checkRequiredInventory(opts, result)
+
 	return nil
 }
 
@@ -545,37 +599,52 @@ func discoverCapabilities(caps mcp.ServerCapabilities) []string {
 // testCapabilityEndpoints tests that advertised capabilities actually work
 func testCapabilityEndpoints(
 	ctx context.Context,
-	client *StreamableHTTPClient,
+	client Client,
 	caps mcp.ServerCapabilities,
 	result *ValidationResult,
 ) {
-	// Test tools/list if tools capability is advertised
+	// Test tools/list if tools capability is advertised, then check each tool
 	if caps.Tools != nil {
-		if _, err := client.ListTools(ctx); err != nil {
+		tools, err := client.ListTools(ctx)
+		if err != nil {
 			result.Issues = append(result.Issues, newWarningIssue(
 				CodeToolsListFailed,
 				fmt.Sprintf("Tools capability advertised but tools/list failed: %v", err),
 			))
+		} else {
+			result.Tools = toolNames(tools.Tools)
+			for _, issue := range checkTools(tools.Tools) {
+				if issue.Level == LevelError {
+					result.Success = false
+				}
+				result.Issues = append(result.Issues, issue)
+			}
 		}
 	}
 
 	// Test resources/list if resources capability is advertised
 	if caps.Resources != nil {
-		if _, err := client.ListResources(ctx); err != nil {
+		resources, err := client.ListResources(ctx)
+		if err != nil {
 			result.Issues = append(result.Issues, newWarningIssue(
 				CodeResourcesListFailed,
 				fmt.Sprintf("Resources capability advertised but resources/list failed: %v", err),
 			))
+		} else {
+			result.Resources = resourceURIs(resources.Resources)
 		}
 	}
 
 	// Test prompts/list if prompts capability is advertised
 	if caps.Prompts != nil {
-		if _, err := client.ListPrompts(ctx); err != nil {
+		prompts, err := client.ListPrompts(ctx)
+		if err != nil {
 			result.Issues = append(result.Issues, newWarningIssue(
 				CodePromptsListFailed,
 				fmt.Sprintf("Prompts capability advertised but prompts/list failed: %v", err),
 			))
+		} else {
+			result.Prompts = promptNames(prompts.Prompts)
 		}
 	}
 }
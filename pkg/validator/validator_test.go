@@ -37,48 +37,7 @@ func mockMCPServer(t *testing.T, config mockServerConfig) *httptest.Server {
 			return
 		}
 
-		var result any
-		var rpcErr *mcp.RPCError
-
-		switch request.Method {
-		case mcp.MethodInitialize:
-			if config.initializeFails {
-				rpcErr = &mcp.RPCError{Code: -32603, Message: "Internal error"}
-			} else {
-				result = mcp.InitializeResult{
-					ProtocolVersion: config.protocolVersion,
-					Capabilities:    config.capabilities,
-					ServerInfo:      config.serverInfo,
-				}
-			}
-		case mcp.MethodToolsList:
-			if config.toolsListFails {
-				rpcErr = &mcp.RPCError{Code: -32603, Message: "Tools list failed"}
-			} else {
-				result = mcp.ListToolsResult{Tools: []mcp.Tool{}}
-			}
-		case mcp.MethodResourcesList:
-			if config.resourcesListFails {
-				rpcErr = &mcp.RPCError{Code: -32603, Message: "Resources list failed"}
-			} else {
-				result = mcp.ListResourcesResult{Resources: []mcp.Resource{}}
-			}
-		case mcp.MethodPromptsList:
-			if config.promptsListFails {
-				rpcErr = &mcp.RPCError{Code: -32603, Message: "Prompts list failed"}
-			} else {
-				result = mcp.ListPromptsResult{Prompts: []mcp.Prompt{}}
-			}
-		default:
-			rpcErr = &mcp.RPCError{Code: -32601, Message: "Method not found"}
-		}
-
-		response := mcp.JSONRPCResponse{
-			JSONRPC: "2.0",
-			ID:      request.ID,
-			Result:  result,
-			Error:   rpcErr,
-		}
+		response := mockRPCResponse(config, request)
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -87,10 +46,71 @@ func mockMCPServer(t *testing.T, config mockServerConfig) *httptest.Server {
 	}))
 }
 
+// mockRPCResponse builds the response of the mock server for a JSON-RPC request
+func mockRPCResponse(config mockServerConfig, request mcp.JSONRPCRequest) mcp.JSONRPCResponse {
+	var result any
+	var rpcErr *mcp.RPCError
+
+	switch request.Method {
+	case mcp.MethodInitialize:
+		if config.initializeFails {
+			rpcErr = &mcp.RPCError{Code: -32603, Message: "Internal error"}
+		} else {
+			result = mcp.InitializeResult{
+				ProtocolVersion: config.protocolVersion,
+				Capabilities:    config.capabilities,
+				ServerInfo:      config.serverInfo,
+			}
+		}
+	case mcp.MethodToolsList:
+		if config.toolsListFails {
+			rpcErr = &mcp.RPCError{Code: -32603, Message: "Tools list failed"}
+		} else {
+			tools := config.tools
+			if tools == nil {
+				tools = []mcp.Tool{}
+			}
+			result = mcp.ListToolsResult{Tools: tools}
+		}
+	case mcp.MethodResourcesList:
+		if config.resourcesListFails {
+			rpcErr = &mcp.RPCError{Code: -32603, Message: "Resources list failed"}
+		} else {
+			resources := config.resources
+			if resources == nil {
+				resources = []mcp.Resource{}
+			}
+			result = mcp.ListResourcesResult{Resources: resources}
+		}
+	case mcp.MethodPromptsList:
+		if config.promptsListFails {
+			rpcErr = &mcp.RPCError{Code: -32603, Message: "Prompts list failed"}
+		} else {
+			prompts := config.prompts
+			if prompts == nil {
+				prompts = []mcp.Prompt{}
+			}
+			result = mcp.ListPromptsResult{Prompts: prompts}
+		}
+	default:
+		rpcErr = &mcp.RPCError{Code: -32601, Message: "Method not found"}
+	}
+
+	return mcp.JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  result,
+		Error:   rpcErr,
+	}
+}
+
 type mockServerConfig struct {
 	protocolVersion    string
 	serverInfo         mcp.Implementation
 	capabilities       mcp.ServerCapabilities
+	tools              []mcp.Tool
+	resources          []mcp.Resource
+	prompts            []mcp.Prompt
 	initializeFails    bool
 	toolsListFails     bool
 	resourcesListFails bool
@@ -4,18 +4,26 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/audit"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/bridge"
 	"github.com/vitorbari/mcp-operator/sidecar/pkg/config"
 	"github.com/vitorbari/mcp-operator/sidecar/pkg/health"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
 	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
 	"github.com/vitorbari/mcp-operator/sidecar/pkg/proxy"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/tracing"
 )
 
 // Version is set at build time via -ldflags.
@@ -28,6 +36,32 @@ func main() {
 	// Setup structured logging
 	logger := cfg.SetupLogger()
 
+	switch cfg.Mode {
+	case config.ModeProxy:
+		// Continue below with the metrics proxy.
+	case config.ModeStdioBridge:
+		runStdioBridge(cfg, logger)
+		return
+	case config.ModeInstall:
+		runInstall(cfg, logger)
+		return
+	case config.ModeGateway:
+		runGateway(cfg, logger)
+		return
+	case config.ModeSessionRouter:
+		runSessionRouter(cfg, logger)
+		return
+	case config.ModeActivator:
+		runActivator(cfg, logger)
+		return
+	case config.ModeDrain:
+		runDrain(cfg, logger)
+		return
+	default:
+		logger.Error("unknown mode", slog.String("mode", cfg.Mode))
+		os.Exit(1)
+	}
+
 	// Log startup configuration
 	logger.Info("starting MCP metrics sidecar proxy",
 		slog.String("version", Version),
@@ -37,6 +71,11 @@ func main() {
 		slog.String("log_level", cfg.LogLevel),
 		slog.Duration("health_check_interval", cfg.HealthCheckInterval),
 		slog.Bool("tls_enabled", cfg.TLSEnabled),
+		slog.Bool("policy_enabled", cfg.Policy != ""),
+		slog.Bool("auth_enabled", cfg.AuthEnabled()),
+		slog.Bool("rate_limit_enabled", cfg.RateLimit != ""),
+		slog.Bool("tracing_enabled", cfg.TracingEnabled()),
+		slog.Bool("audit_enabled", cfg.AuditEnabled()),
 	)
 
 	// Validate and load TLS configuration if enabled
@@ -87,6 +126,7 @@ func main() {
 		logger.Error("failed to create metrics recorder", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+	recorder.SetMaxToolLabels(cfg.MaxToolLabels)
 
 	// Create the proxy with metrics recorder
 	p, err := proxy.NewWithRecorder(cfg.ListenAddr, cfg.TargetAddr, logger, recorder)
@@ -95,8 +135,122 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Enable policy enforcement if configured
+	if cfg.Policy != "" {
+		policy, err := proxy.ParsePolicy([]byte(cfg.Policy))
+		if err != nil {
+			logger.Error("failed to load policy", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		p.SetPolicy(policy)
+		logger.Info("policy enforcement enabled",
+			slog.String("identity_header", policy.IdentityHeader),
+			slog.Int("identities", len(policy.Identities)),
+		)
+	}
+
+	// Enable rate limiting if configured
+	if cfg.RateLimit != "" {
+		limits, err := proxy.ParseRateLimits([]byte(cfg.RateLimit))
+		if err != nil {
+			logger.Error("failed to load rate limits", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		p.SetRateLimits(limits)
+		logger.Info("rate limiting enabled", slog.Int("rules", len(limits.Rules)))
+	}
+
+	// Enable authentication if configured
+	if cfg.AuthEnabled() {
+		auth, err := proxy.NewAuthenticator(proxy.AuthConfig{
+			APIKeysDir:           cfg.AuthAPIKeysDir,
+			JWKSURL:              cfg.AuthJWKSURL,
+			JWKSFile:             cfg.AuthJWKSFile,
+			Issuer:               cfg.AuthIssuer,
+			Audiences:            config.SplitList(cfg.AuthAudiences),
+			IdentityClaim:        cfg.AuthIdentityClaim,
+			AuthorizationServers: config.SplitList(cfg.AuthAuthorizationServers),
+			Resource:             cfg.AuthResource,
+		}, logger)
+		if err != nil {
+			logger.Error("failed to configure authentication", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		p.SetAuthenticator(auth)
+		logger.Info("authentication enabled",
+			slog.Bool("api_keys", cfg.AuthAPIKeysDir != ""),
+			slog.Bool("jwt", cfg.AuthJWKSURL != "" || cfg.AuthJWKSFile != ""),
+			slog.String("issuer", cfg.AuthIssuer),
+		)
+	}
+
+	// Enable tracing if configured
+	var tracerProvider *tracing.Provider
+	if cfg.TracingEnabled() {
+		tracerProvider, err = tracing.NewProvider(context.Background(), tracing.Config{
+			Endpoint:    cfg.TracingEndpoint,
+			File:        cfg.TracingFile,
+			SampleRatio: cfg.TracingSampleRatio,
+			ServiceName: cfg.TracingServiceName,
+			Version:     Version,
+		})
+		if err != nil {
+			logger.Error("failed to configure tracing", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		p.SetTracer(tracerProvider.Tracer())
+		logger.Info("tracing enabled",
+			slog.String("endpoint", cfg.TracingEndpoint),
+			slog.String("file", cfg.TracingFile),
+			slog.Float64("sample_ratio", cfg.TracingSampleRatio),
+		)
+	}
+
+	// Enable audit records if configured
+	var auditLogger *audit.Logger
+	if cfg.AuditEnabled() {
+		auditLogger, err = audit.NewLogger(audit.Config{
+			File:             cfg.AuditFile,
+			RedactPaths:      config.SplitList(cfg.AuditRedact),
+			MaxArgumentBytes: cfg.AuditMaxArgumentBytes,
+		})
+		if err != nil {
+			logger.Error("failed to configure audit logging", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		p.SetAuditLogger(auditLogger)
+		logger.Info("audit logging enabled",
+			slog.String("file", cfg.AuditFile),
+			slog.Int("redact_paths", len(config.SplitList(cfg.AuditRedact))),
+			slog.Int("max_argument_bytes", cfg.AuditMaxArgumentBytes),
+		)
+	}
+
 	// Create the health checker for target connectivity
 	healthChecker := health.NewHealthChecker(cfg.TargetAddr, cfg.HealthCheckInterval)
+	healthChecker.SetFailureThreshold(cfg.HealthCheckFailureThreshold)
+	switch cfg.HealthCheckMode {
+	case health.CheckTCP:
+	case health.CheckMCP:
+		if cfg.HealthCheckMethod != mcp.MethodInitialize && cfg.HealthCheckMethod != mcp.MethodPing {
+			logger.Error("invalid health check method", slog.String("method", cfg.HealthCheckMethod))
+			os.Exit(1)
+		}
+		healthChecker.SetMCPCheck(health.MCPCheck{
+			Path:    cfg.HealthCheckPath,
+			Method:  cfg.HealthCheckMethod,
+			Timeout: cfg.HealthCheckTimeout,
+		})
+		logger.Info("MCP health check enabled",
+			slog.String("method", cfg.HealthCheckMethod),
+			slog.String("path", cfg.HealthCheckPath),
+			slog.Duration("timeout", cfg.HealthCheckTimeout),
+			slog.Int("failure_threshold", cfg.HealthCheckFailureThreshold),
+		)
+	default:
+		logger.Error("invalid health check mode", slog.String("mode", cfg.HealthCheckMode))
+		os.Exit(1)
+	}
 
 	// Setup context with signal handling for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -109,14 +263,38 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	// Drain once, from the preStop hook or on the shutdown signal: fail
+	// readiness, end the event streams and wait for the requests in flight
+	var drainOnce sync.Once
+	drain := func() {
+		drainOnce.Do(func() {
+			logger.Info("draining proxy", slog.Duration("timeout", cfg.DrainTimeout))
+			healthChecker.SetDraining()
+
+			drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.DrainTimeout)
+			defer drainCancel()
+			if err := p.Drain(drainCtx); err != nil {
+				logger.Warn("drain incomplete", slog.String("error", err.Error()))
+				return
+			}
+			logger.Info("proxy drained")
+		})
+	}
+
 	go func() {
 		sig := <-sigChan
 		logger.Info("received shutdown signal", slog.String("signal", sig.String()))
+		drain()
 		cancel()
 	}()
 
 	// Start the metrics server with health endpoints
-	metricsServer := startMetricsServer(cfg.MetricsAddr, recorder, healthChecker, logger)
+	metricsServer := startMetricsServer(cfg.MetricsAddr, recorder,
+		healthChecker.LivenessHandler(), healthChecker.ReadinessHandler(),
+		map[string]http.HandlerFunc{
+			"/drain":   drainHandler(drain),
+			"/targetz": healthChecker.TargetHandler(),
+		}, logger)
 
 	// Start the proxy (blocking)
 	logger.Info("proxy configured",
@@ -154,19 +332,341 @@ func main() {
 		logger.Error("metrics recorder shutdown error", slog.String("error", err.Error()))
 	}
 
+	// Export the remaining spans
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			logger.Error("tracer provider shutdown error", slog.String("error", err.Error()))
+		}
+	}
+
+	// Close the audit file
+	if auditLogger != nil {
+		if err := auditLogger.Close(); err != nil {
+			logger.Error("audit file close error", slog.String("error", err.Error()))
+		}
+	}
+
 	logger.Info("proxy shutdown complete")
 }
 
-// startMetricsServer starts the Prometheus metrics HTTP server with health endpoints.
-func startMetricsServer(addr string, recorder *metrics.Recorder, healthChecker *health.HealthChecker, logger *slog.Logger) *http.Server {
+// runStdioBridge runs the configured command as a stdio MCP server and exposes
+// it over Streamable HTTP until a shutdown signal is received or the process exits.
+func runStdioBridge(cfg *config.Config, logger *slog.Logger) {
+	logger.Info("starting MCP stdio bridge",
+		slog.String("version", Version),
+		slog.String("listen_addr", cfg.ListenAddr),
+		slog.String("path", cfg.BridgePath),
+		slog.Any("command", cfg.Command),
+	)
+
+	b, err := bridge.New(cfg.ListenAddr, cfg.BridgePath, cfg.Command, logger)
+	if err != nil {
+		logger.Error("failed to create stdio bridge", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := b.Run(ctx); err != nil {
+		logger.Error("stdio bridge error", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	logger.Info("stdio bridge shutdown complete")
+}
+
+// runInstall copies the binary to the install path so other containers in the
+// pod can run it.
+func runInstall(cfg *config.Config, logger *slog.Logger) {
+	if err := bridge.Install(cfg.InstallPath); err != nil {
+		logger.Error("failed to install binary", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	logger.Info("installed binary", slog.String("path", cfg.InstallPath))
+}
+
+// runGateway serves the backends listed in the gateway configuration behind
+// one endpoint until a shutdown signal is received.
+func runGateway(cfg *config.Config, logger *slog.Logger) {
+	gatewayConfig, err := proxy.LoadGatewayConfig(cfg.GatewayConfigFile)
+	if err != nil {
+		logger.Error("failed to load gateway configuration", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	logger.Info("starting MCP gateway",
+		slog.String("version", Version),
+		slog.String("listen_addr", cfg.ListenAddr),
+		slog.String("path", gatewayConfig.Path),
+		slog.Int("backends", len(gatewayConfig.Backends)),
+		slog.Bool("prefix_tool_names", gatewayConfig.PrefixToolNames),
+	)
+
+	recorder, err := metrics.NewRecorder(Version, "gateway")
+	if err != nil {
+		logger.Error("failed to create metrics recorder", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	recorder.SetMaxToolLabels(cfg.MaxToolLabels)
+
+	p, err := proxy.NewGateway(cfg.ListenAddr, gatewayConfig, logger, recorder)
+	if err != nil {
+		logger.Error("failed to create gateway", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The gateway answers on its own, so it is ready as soon as it serves
+	startTime := time.Now()
+	healthy := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(health.HealthResponse{
+			Status:        "healthy",
+			UptimeSeconds: time.Since(startTime).Seconds(),
+		})
+	}
+	metricsServer := startMetricsServer(cfg.MetricsAddr, recorder, healthy, healthy, nil, logger)
+
+	if err := p.Start(ctx); err != nil && err != context.Canceled {
+		logger.Error("gateway error", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics server shutdown error", slog.String("error", err.Error()))
+	}
+	if err := recorder.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics recorder shutdown error", slog.String("error", err.Error()))
+	}
+
+	logger.Info("gateway shutdown complete")
+}
+
+// runSessionRouter routes the sessions of the pods behind a Service until a
+// shutdown signal is received.
+func runSessionRouter(cfg *config.Config, logger *slog.Logger) {
+	if cfg.RouterService == "" {
+		logger.Error("--router-service is required in session-router mode")
+		os.Exit(1)
+	}
+
+	source, err := proxy.NewInClusterEndpointSource(cfg.RouterNamespace, cfg.RouterService, cfg.RouterPortName)
+	if err != nil {
+		logger.Error("failed to create endpoint source", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	logger.Info("starting MCP session router",
+		slog.String("version", Version),
+		slog.String("listen_addr", cfg.ListenAddr),
+		slog.String("service", cfg.RouterService),
+		slog.String("port_name", cfg.RouterPortName),
+		slog.Duration("resync_interval", cfg.RouterResyncInterval),
+		slog.Duration("session_idle_timeout", cfg.RouterSessionIdleTimeout),
+	)
+
+	recorder, err := metrics.NewRecorder(Version, cfg.RouterService)
+	if err != nil {
+		logger.Error("failed to create metrics recorder", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	router := proxy.NewSessionRouter(proxy.SessionRouterConfig{
+		Source:             source,
+		ResyncInterval:     cfg.RouterResyncInterval,
+		SessionIdleTimeout: cfg.RouterSessionIdleTimeout,
+	}, logger, recorder)
+	p := proxy.NewRouterProxy(cfg.ListenAddr, router, logger, recorder)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go router.Run(ctx)
+
+	// The router is ready once it knows at least one ready pod
+	startTime := time.Now()
+	healthy := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(health.HealthResponse{
+			Status:        "healthy",
+			UptimeSeconds: time.Since(startTime).Seconds(),
+		})
+	}
+	ready := func(w http.ResponseWriter, r *http.Request) {
+		if router.Ready() {
+			healthy(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(health.HealthResponse{
+			Status:        "unhealthy",
+			UptimeSeconds: time.Since(startTime).Seconds(),
+		})
+	}
+	metricsServer := startMetricsServer(cfg.MetricsAddr, recorder, healthy, ready, nil, logger)
+
+	if err := p.Start(ctx); err != nil && err != context.Canceled {
+		logger.Error("session router error", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics server shutdown error", slog.String("error", err.Error()))
+	}
+	if err := recorder.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics recorder shutdown error", slog.String("error", err.Error()))
+	}
+
+	logger.Info("session router shutdown complete")
+}
+
+// runActivator answers for an MCP server scaled to zero until a shutdown
+// signal is received.
+func runActivator(cfg *config.Config, logger *slog.Logger) {
+	if cfg.ActivatorDeployment == "" {
+		logger.Error("--activator-deployment is required in activator mode")
+		os.Exit(1)
+	}
+	if cfg.ActivatorReplicas < 1 {
+		logger.Error("--activator-replicas must be at least 1")
+		os.Exit(1)
+	}
+	selector := cfg.ActivatorPodSelector
+	if selector == "" {
+		selector = "app=" + cfg.ActivatorDeployment
+	}
+
+	scaler, err := proxy.NewInClusterDeploymentScaler(cfg.ActivatorNamespace, cfg.ActivatorDeployment, selector,
+		cfg.ActivatorTargetPort, int32(cfg.ActivatorReplicas))
+	if err != nil {
+		logger.Error("failed to create deployment scaler", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	logger.Info("starting MCP activator",
+		slog.String("version", Version),
+		slog.String("listen_addr", cfg.ListenAddr),
+		slog.String("deployment", cfg.ActivatorDeployment),
+		slog.String("pod_selector", selector),
+		slog.Int("target_port", cfg.ActivatorTargetPort),
+		slog.Int("replicas", cfg.ActivatorReplicas),
+		slog.Duration("timeout", cfg.ActivatorTimeout),
+	)
+
+	recorder, err := metrics.NewRecorder(Version, cfg.ActivatorDeployment)
+	if err != nil {
+		logger.Error("failed to create metrics recorder", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	activator := proxy.NewActivator(proxy.ActivatorConfig{
+		Scaler:            scaler,
+		ActivationTimeout: cfg.ActivatorTimeout,
+	}, logger)
+	p := proxy.NewActivatorProxy(cfg.ListenAddr, activator, logger, recorder)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The activator can always accept requests, whether the server runs or not
+	startTime := time.Now()
+	healthy := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(health.HealthResponse{
+			Status:        "healthy",
+			UptimeSeconds: time.Since(startTime).Seconds(),
+		})
+	}
+	metricsServer := startMetricsServer(cfg.MetricsAddr, recorder, healthy, healthy, nil, logger)
+
+	if err := p.Start(ctx); err != nil && err != context.Canceled {
+		logger.Error("activator error", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics server shutdown error", slog.String("error", err.Error()))
+	}
+	if err := recorder.Shutdown(shutdownCtx); err != nil {
+		logger.Error("metrics recorder shutdown error", slog.String("error", err.Error()))
+	}
+
+	logger.Info("activator shutdown complete")
+}
+
+// runDrain asks the proxy listening on the metrics address to drain, and
+// waits until it is done, so Kubernetes sends SIGTERM after the streams ended.
+func runDrain(cfg *config.Config, logger *slog.Logger) {
+	_, port, err := net.SplitHostPort(cfg.MetricsAddr)
+	if err != nil {
+		logger.Error("invalid metrics address", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Leave the proxy time to answer once its own drain timed out
+	client := &http.Client{Timeout: cfg.DrainTimeout + 5*time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://%s/drain", net.JoinHostPort("localhost", port)))
+	if err != nil {
+		logger.Error("failed to drain proxy", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logger.Error("failed to drain proxy", slog.Int("status", resp.StatusCode))
+		os.Exit(1)
+	}
+	logger.Info("proxy drained")
+}
+
+// drainHandler returns the handler of the /drain endpoint called by the drain
+// mode. It answers once the proxy drained. Only callers in the pod may drain
+// it: the metrics port is reachable by Prometheus and other clients.
+func drainHandler(drain func()) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			http.Error(w, "drain is only accepted from localhost", http.StatusForbidden)
+			return
+		}
+
+		// The drain outlasts the write timeout of the metrics server
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		drain()
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// startMetricsServer starts the Prometheus metrics HTTP server with health
+// endpoints, and the endpoints of the mode by path.
+func startMetricsServer(addr string, recorder *metrics.Recorder, liveness, readiness http.HandlerFunc,
+	endpoints map[string]http.HandlerFunc, logger *slog.Logger) *http.Server {
 	mux := http.NewServeMux()
 
 	// Use the recorder's handler which serves Prometheus format metrics
 	mux.Handle("/metrics", recorder.Handler())
 
-	// Health check endpoints using the health checker
-	mux.HandleFunc("/healthz", healthChecker.LivenessHandler())
-	mux.HandleFunc("/readyz", healthChecker.ReadinessHandler())
+	// Health check endpoints
+	mux.HandleFunc("/healthz", liveness)
+	mux.HandleFunc("/readyz", readiness)
+
+	for path, handler := range endpoints {
+		mux.HandleFunc(path, handler)
+	}
 
 	server := &http.Server{
 		Addr:         addr,
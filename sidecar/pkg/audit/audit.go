@@ -0,0 +1,188 @@
+// Package audit writes a structured record of the tool calls and resource
+// reads clients send through the sidecar proxy.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// StdoutFile is the File value writing records to standard output.
+const StdoutFile = "-"
+
+// RecordType is the type field of every record, telling audit records apart
+// from log lines when both are written to standard output.
+const RecordType = "audit"
+
+// Redacted replaces the argument values matched by a redaction rule.
+const Redacted = "[REDACTED]"
+
+// DefaultMaxArgumentBytes is the default size above which arguments are truncated.
+const DefaultMaxArgumentBytes = 4096
+
+// Statuses of the request a record describes.
+const (
+	// StatusSuccess is a request answered with a result.
+	StatusSuccess = "success"
+
+	// StatusError is a request answered with a JSON-RPC error or an HTTP error status.
+	StatusError = "error"
+
+	// StatusToolError is a tool call whose result reports a failure (isError: true).
+	StatusToolError = "tool_error"
+
+	// StatusAccepted is a request whose response is delivered on another
+	// stream, as with the SSE transport.
+	StatusAccepted = "accepted"
+
+	// StatusUnknown is a request whose response could not be read.
+	StatusUnknown = "unknown"
+)
+
+// Config configures where records are written and what they contain.
+type Config struct {
+	// File is the JSON-lines file records are appended to, or StdoutFile.
+	File string
+
+	// RedactPaths are JSONPath expressions evaluated against the arguments
+	// of every tool call. Matched values are replaced with Redacted.
+	RedactPaths []string
+
+	// MaxArgumentBytes is the size of the encoded arguments above which they
+	// are truncated. Zero keeps arguments whole.
+	MaxArgumentBytes int
+}
+
+// Record describes one tools/call or resources/read request.
+// ArgumentsBytes is the size of arguments that were truncated.
+type Record struct {
+	Time               time.Time       `json:"timestamp"`
+	Type               string          `json:"type"`
+	Identity           string          `json:"identity,omitempty"`
+	ClientIP           string          `json:"client_ip,omitempty"`
+	SessionID          string          `json:"session_id,omitempty"`
+	Method             string          `json:"method"`
+	RequestID          any             `json:"request_id,omitempty"`
+	Tool               string          `json:"tool,omitempty"`
+	Resource           string          `json:"resource_uri,omitempty"`
+	Arguments          json.RawMessage `json:"arguments,omitempty"`
+	ArgumentsTruncated bool            `json:"arguments_truncated,omitempty"`
+	ArgumentsBytes     int             `json:"arguments_bytes,omitempty"`
+	Status             string          `json:"status"`
+	HTTPStatus         int             `json:"http_status"`
+	ErrorCode          int             `json:"error_code,omitempty"`
+	DurationMS         float64         `json:"duration_ms"`
+}
+
+// Logger writes audit records as JSON lines.
+type Logger struct {
+	mu               sync.Mutex
+	out              io.Writer
+	file             io.Closer
+	redact           []jsonPath
+	maxArgumentBytes int
+}
+
+// NewLogger creates a Logger writing records as configured.
+func NewLogger(cfg Config) (*Logger, error) {
+	if cfg.File == "" {
+		return nil, errors.New("audit logging needs a file or standard output")
+	}
+	if cfg.MaxArgumentBytes < 0 {
+		return nil, fmt.Errorf("max argument bytes %d is negative", cfg.MaxArgumentBytes)
+	}
+
+	l := &Logger{maxArgumentBytes: cfg.MaxArgumentBytes}
+	for _, expr := range cfg.RedactPaths {
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction path %q: %w", expr, err)
+		}
+		l.redact = append(l.redact, path)
+	}
+
+	l.out = os.Stdout
+	if cfg.File != StdoutFile {
+		file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit file: %w", err)
+		}
+		l.out = file
+		l.file = file
+	}
+
+	return l, nil
+}
+
+// Log redacts and truncates the arguments of the record and writes it.
+func (l *Logger) Log(rec Record) error {
+	rec.Type = RecordType
+	if len(rec.Arguments) > 0 {
+		rec.Arguments, rec.ArgumentsTruncated, rec.ArgumentsBytes = l.prepareArguments(rec.Arguments)
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	// Records are written in one call so concurrent requests never interleave
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.out.Write(line)
+	return err
+}
+
+// Close closes the audit file.
+func (l *Logger) Close() error {
+	if l.file != nil {
+		return l.file.Close()
+	}
+	return nil
+}
+
+// prepareArguments applies the redaction rules to the arguments, then
+// truncates them. Truncated arguments are recorded as a JSON string holding
+// the start of their encoding, along with their full size.
+func (l *Logger) prepareArguments(args json.RawMessage) (json.RawMessage, bool, int) {
+	if len(l.redact) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(args))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			// Unparseable arguments cannot be redacted, so none of them are kept
+			encoded, _ := json.Marshal(Redacted)
+			return encoded, false, 0
+		}
+		for _, path := range l.redact {
+			value = path.redact(value)
+		}
+		if redacted, err := json.Marshal(value); err == nil {
+			args = redacted
+		}
+	} else {
+		var compact bytes.Buffer
+		if json.Compact(&compact, args) == nil {
+			args = compact.Bytes()
+		}
+	}
+
+	if l.maxArgumentBytes == 0 || len(args) <= l.maxArgumentBytes {
+		return args, false, 0
+	}
+
+	prefix := args[:l.maxArgumentBytes]
+	for len(prefix) > 0 && !utf8.Valid(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	encoded, _ := json.Marshal(string(prefix))
+	return encoded, true, len(args)
+}
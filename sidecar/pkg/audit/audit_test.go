@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLogger(t *testing.T, redactPaths []string, maxArgumentBytes int) (*Logger, string) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := NewLogger(Config{File: file, RedactPaths: redactPaths, MaxArgumentBytes: maxArgumentBytes})
+	if err != nil {
+		t.Fatalf("NewLogger failed: %v", err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger, file
+}
+
+func readRecords(t *testing.T, file string) []map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read audit file: %v", err)
+	}
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("audit line %q is not JSON: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger_WritesJSONLines(t *testing.T) {
+	logger, file := newTestLogger(t, nil, 0)
+
+	for _, tool := range []string{"get_weather", "get_alerts"} {
+		err := logger.Log(Record{
+			Time:       time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+			Identity:   "alice",
+			SessionID:  "session-1",
+			Method:     "tools/call",
+			RequestID:  7,
+			Tool:       tool,
+			Arguments:  json.RawMessage(`{ "city": "Lisbon" }`),
+			Status:     StatusSuccess,
+			HTTPStatus: 200,
+			DurationMS: 12.5,
+		})
+		if err != nil {
+			t.Fatalf("Log failed: %v", err)
+		}
+	}
+
+	records := readRecords(t, file)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	record := records[0]
+	want := map[string]any{
+		"timestamp":   "2025-03-01T12:00:00Z",
+		"type":        RecordType,
+		"identity":    "alice",
+		"session_id":  "session-1",
+		"method":      "tools/call",
+		"request_id":  float64(7),
+		"tool":        "get_weather",
+		"status":      StatusSuccess,
+		"http_status": float64(200),
+		"duration_ms": 12.5,
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+	if args, _ := json.Marshal(record["arguments"]); string(args) != `{"city":"Lisbon"}` {
+		t.Errorf("arguments = %s, want the compacted arguments", args)
+	}
+	if records[1]["tool"] != "get_alerts" {
+		t.Errorf("second record tool = %v, want get_alerts", records[1]["tool"])
+	}
+}
+
+func TestLogger_RedactsArguments(t *testing.T) {
+	logger, file := newTestLogger(t, []string{
+		"$.password",
+		"$.headers['Authorization']",
+		"$.accounts[*].token",
+		"$..secret",
+		"$.items[1]",
+	}, 0)
+
+	args := `{
+		"user": "alice",
+		"password": "hunter2",
+		"headers": {"Authorization": "Bearer abc", "Accept": "text/plain"},
+		"accounts": [{"id": 1, "token": "t1"}, {"id": 2, "token": "t2"}],
+		"nested": {"deeper": {"secret": "s", "keep": 3}},
+		"items": ["a", "b", "c"]
+	}`
+	if err := logger.Log(Record{Method: "tools/call", Tool: "login", Arguments: json.RawMessage(args)}); err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	got, _ := json.Marshal(readRecords(t, file)[0]["arguments"])
+	want := `{"accounts":[{"id":1,"token":"[REDACTED]"},{"id":2,"token":"[REDACTED]"}],` +
+		`"headers":{"Accept":"text/plain","Authorization":"[REDACTED]"},` +
+		`"items":["a","[REDACTED]","c"],` +
+		`"nested":{"deeper":{"keep":3,"secret":"[REDACTED]"}},` +
+		`"password":"[REDACTED]","user":"alice"}`
+	if string(got) != want {
+		t.Errorf("arguments = %s\nwant %s", got, want)
+	}
+}
+
+func TestLogger_TruncatesArguments(t *testing.T) {
+	logger, file := newTestLogger(t, []string{"$.token"}, 32)
+
+	args := `{"token": "secret", "text": "` + strings.Repeat("x", 100) + `"}`
+	if err := logger.Log(Record{Method: "tools/call", Tool: "summarize", Arguments: json.RawMessage(args)}); err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	record := readRecords(t, file)[0]
+	if record["arguments_truncated"] != true {
+		t.Errorf("arguments_truncated = %v, want true", record["arguments_truncated"])
+	}
+	prefix, ok := record["arguments"].(string)
+	if !ok || len(prefix) != 32 {
+		t.Fatalf("arguments = %v, want a 32 byte string", record["arguments"])
+	}
+	if strings.Contains(prefix, "secret") {
+		t.Errorf("truncated arguments %q were not redacted first", prefix)
+	}
+	if record["arguments_bytes"] != float64(len(`{"text":"`+strings.Repeat("x", 100)+`","token":"[REDACTED]"}`)) {
+		t.Errorf("arguments_bytes = %v, want the size of the redacted arguments", record["arguments_bytes"])
+	}
+}
+
+func TestParseJSONPath_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "password", "$", "$.", "$..", "$.a[", "$.a[-1]", "$.a[x]", "$['']"} {
+		if _, err := parseJSONPath(expr); err == nil {
+			t.Errorf("parseJSONPath(%q) succeeded, want an error", expr)
+		}
+	}
+
+	if _, err := NewLogger(Config{File: StdoutFile, RedactPaths: []string{"password"}}); err == nil {
+		t.Error("NewLogger accepted an invalid redaction path")
+	}
+}
@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed JSONPath expression. The subset supported covers what
+// redaction rules need: $ for the root, .name and ['name'] for object members,
+// [n] for array elements, * and [*] for every member or element, and ..name
+// for members at any depth.
+type jsonPath []pathSegment
+
+// pathSegment selects children of the values matched so far.
+type pathSegment struct {
+	// name is the member selected; empty with wildcard or index set.
+	name string
+
+	// index is the array element selected, when not negative.
+	index int
+
+	// wildcard selects every member or element.
+	wildcard bool
+
+	// recursive also applies the segment to every descendant.
+	recursive bool
+}
+
+// parseJSONPath parses a JSONPath expression.
+func parseJSONPath(expr string) (jsonPath, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, errors.New("must start with $")
+	}
+
+	var path jsonPath
+	rest := expr[1:]
+	for rest != "" {
+		seg := pathSegment{index: -1}
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			seg.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			rest = parseMemberName(rest, &seg)
+		case strings.HasPrefix(rest, "."):
+			rest = parseMemberName(rest[1:], &seg)
+		case !strings.HasPrefix(rest, "["):
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+
+		if strings.HasPrefix(rest, "[") && seg.name == "" && !seg.wildcard {
+			var err error
+			if rest, err = parseBracket(rest, &seg); err != nil {
+				return nil, err
+			}
+		}
+
+		if seg.name == "" && !seg.wildcard && seg.index < 0 {
+			return nil, fmt.Errorf("empty segment in %q", expr)
+		}
+		path = append(path, seg)
+	}
+
+	if len(path) == 0 {
+		return nil, errors.New("selects the whole arguments object")
+	}
+	return path, nil
+}
+
+// parseMemberName parses a dot-notation member name into seg and returns the
+// rest of the expression.
+func parseMemberName(rest string, seg *pathSegment) string {
+	end := strings.IndexAny(rest, ".[")
+	if end < 0 {
+		end = len(rest)
+	}
+	if rest[:end] == "*" {
+		seg.wildcard = true
+	} else {
+		seg.name = rest[:end]
+	}
+	return rest[end:]
+}
+
+// parseBracket parses a bracket selector, [n], [*] or ['name'], into seg and
+// returns the rest of the expression.
+func parseBracket(rest string, seg *pathSegment) (string, error) {
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return "", errors.New("unterminated [")
+	}
+	selector := strings.TrimSpace(rest[1:end])
+
+	switch {
+	case selector == "*":
+		seg.wildcard = true
+	case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+		seg.name = selector[1 : len(selector)-1]
+		if seg.name == "" {
+			return "", errors.New("empty member name")
+		}
+	default:
+		index, err := strconv.Atoi(selector)
+		if err != nil || index < 0 {
+			return "", fmt.Errorf("unsupported selector [%s]", selector)
+		}
+		seg.index = index
+	}
+	return rest[end+1:], nil
+}
+
+// redact replaces the values the path matches in value with Redacted and
+// returns the result. Maps and slices are modified in place.
+func (p jsonPath) redact(value any) any {
+	if len(p) == 0 {
+		return Redacted
+	}
+	seg, rest := p[0], p[1:]
+
+	if seg.recursive {
+		// Descendants are visited before the segment itself applies, so a
+		// redacted member is not searched again
+		switch v := value.(type) {
+		case map[string]any:
+			for key, child := range v {
+				v[key] = p.redact(child)
+			}
+		case []any:
+			for i, child := range v {
+				v[i] = p.redact(child)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if seg.wildcard || (seg.name != "" && key == seg.name) {
+				v[key] = rest.redact(child)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if seg.wildcard || i == seg.index {
+				v[i] = rest.redact(child)
+			}
+		}
+	}
+	return value
+}
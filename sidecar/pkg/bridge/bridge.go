@@ -0,0 +1,656 @@
+// Package bridge exposes a stdio-based MCP server as a Streamable HTTP endpoint.
+//
+// The bridge spawns the server process, writes JSON-RPC messages to its stdin and
+// reads newline-delimited responses from its stdout. Request IDs are rewritten so
+// that concurrent HTTP clients can share the single stdio session without their
+// IDs colliding, and each initialize is answered with an Mcp-Session-Id that
+// tells the clients apart when they cancel requests.
+package bridge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultPath is the HTTP path the bridge serves MCP traffic on.
+	DefaultPath = "/mcp"
+
+	// DefaultRequestTimeout bounds how long a client waits for the process to answer.
+	DefaultRequestTimeout = 5 * time.Minute
+
+	// maxBodySize limits the size of a single POST body.
+	maxBodySize = 10 * 1024 * 1024
+
+	// subscriberBuffer is the number of server-initiated messages buffered per GET stream.
+	subscriberBuffer = 64
+
+	// processStopTimeout is how long the process has to exit after SIGTERM.
+	processStopTimeout = 5 * time.Second
+
+	methodInitialize              = "initialize"
+	methodNotificationInitialized = "notifications/initialized"
+	methodNotificationCancelled   = "notifications/cancelled"
+
+	headerSessionID = "Mcp-Session-Id"
+)
+
+// JSON-RPC error codes returned by the bridge itself.
+const (
+	codeParseError    = -32700
+	codeInternalError = -32603
+)
+
+// ErrProcessExited is returned for requests that were in flight when the server process exited.
+var ErrProcessExited = errors.New("mcp server process exited")
+
+// message is a JSON-RPC 2.0 message. Fields are kept raw so they can be
+// forwarded without altering their content.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// isRequest returns true for messages that expect a response.
+func (m *message) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0 && string(m.ID) != "null"
+}
+
+// isNotification returns true for messages without an ID.
+func (m *message) isNotification() bool {
+	return m.Method != "" && !m.isRequest()
+}
+
+// pendingRequest tracks a request forwarded to the process.
+type pendingRequest struct {
+	session    string
+	originalID json.RawMessage
+	response   chan *message
+}
+
+// Bridge runs a stdio MCP server and serves it over Streamable HTTP.
+type Bridge struct {
+	listenAddr     string
+	path           string
+	command        []string
+	logger         *slog.Logger
+	requestTimeout time.Duration
+
+	stdin   io.WriteCloser
+	writeMu sync.Mutex
+
+	nextID  atomic.Int64
+	mu      sync.Mutex
+	pending map[int64]*pendingRequest
+	// subscribers receive server-initiated messages (notifications and requests).
+	subscribers map[chan []byte]struct{}
+	// initResult caches the first successful initialize result. Later clients
+	// receive it directly since the process only supports one handshake.
+	initResult  json.RawMessage
+	initialized bool
+
+	exited  chan struct{}
+	exitErr error
+	server  *http.Server
+}
+
+// New creates a new Bridge that will run the given command.
+func New(listenAddr, path string, command []string, logger *slog.Logger) (*Bridge, error) {
+	if len(command) == 0 {
+		return nil, errors.New("no command specified for stdio bridge")
+	}
+	if path == "" {
+		path = DefaultPath
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return &Bridge{
+		listenAddr:     listenAddr,
+		path:           path,
+		command:        command,
+		logger:         logger,
+		requestTimeout: DefaultRequestTimeout,
+		pending:        make(map[int64]*pendingRequest),
+		subscribers:    make(map[chan []byte]struct{}),
+		exited:         make(chan struct{}),
+	}, nil
+}
+
+// Path returns the HTTP path the bridge serves MCP traffic on.
+func (b *Bridge) Path() string {
+	return b.path
+}
+
+// Run starts the server process and the HTTP listener, blocking until the
+// context is cancelled or the process exits.
+func (b *Bridge) Run(ctx context.Context) error {
+	cmd, err := b.startProcess(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.server = &http.Server{
+		Addr:              b.listenAddr,
+		Handler:           b.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		b.logger.Info("stdio bridge listening",
+			slog.String("addr", b.listenAddr),
+			slog.String("path", b.path),
+			slog.String("command", cmd.Path),
+		)
+		if err := b.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		b.logger.Info("shutting down stdio bridge")
+	case <-b.exited:
+		runErr = b.exitErr
+		if runErr == nil {
+			runErr = ErrProcessExited
+		}
+	case err := <-serverErr:
+		runErr = err
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := b.server.Shutdown(shutdownCtx); err != nil {
+		b.logger.Error("bridge server shutdown error", slog.String("error", err.Error()))
+	}
+
+	// Closing stdin is the conventional way to ask a stdio server to exit.
+	_ = b.stdin.Close()
+	<-b.exited
+
+	return runErr
+}
+
+// startProcess launches the server command and starts the stdout reader.
+func (b *Bridge) startProcess(ctx context.Context) (*exec.Cmd, error) {
+	cmd := exec.CommandContext(ctx, b.command[0], b.command[1:]...)
+	cmd.Stderr = os.Stderr
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = processStopTimeout
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %q: %w", b.command[0], err)
+	}
+	b.stdin = stdin
+
+	b.logger.Info("started mcp server process",
+		slog.String("command", strings.Join(b.command, " ")),
+		slog.Int("pid", cmd.Process.Pid),
+	)
+
+	go func() {
+		b.readLoop(stdout)
+		err := cmd.Wait()
+		b.mu.Lock()
+		b.exitErr = err
+		b.mu.Unlock()
+		if err != nil {
+			b.logger.Error("mcp server process exited", slog.String("error", err.Error()))
+		} else {
+			b.logger.Info("mcp server process exited")
+		}
+		b.failPending()
+		close(b.exited)
+	}()
+
+	return cmd, nil
+}
+
+// readLoop reads newline-delimited JSON-RPC messages from the process stdout.
+func (b *Bridge) readLoop(stdout io.Reader) {
+	reader := bufio.NewReader(stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			b.dispatch(line)
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				b.logger.Error("failed to read from mcp server", slog.String("error", err.Error()))
+			}
+			return
+		}
+	}
+}
+
+// dispatch routes a message received from the process to the waiting client
+// or, for server-initiated messages, to the GET stream subscribers.
+func (b *Bridge) dispatch(line []byte) {
+	var msg message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		// Servers sometimes print non-protocol output to stdout; skip it.
+		b.logger.Warn("ignoring non JSON-RPC output from mcp server", slog.String("line", truncate(string(line), 200)))
+		return
+	}
+
+	if msg.Method == "" && len(msg.ID) > 0 {
+		if id, err := strconv.ParseInt(string(msg.ID), 10, 64); err == nil {
+			b.mu.Lock()
+			req, ok := b.pending[id]
+			delete(b.pending, id)
+			b.mu.Unlock()
+			if ok {
+				msg.ID = req.originalID
+				req.response <- &msg
+				return
+			}
+		}
+		b.logger.Debug("dropping response with unknown id", slog.String("id", string(msg.ID)))
+		return
+	}
+
+	b.broadcast(line)
+}
+
+// broadcast delivers a server-initiated message to all GET stream subscribers.
+func (b *Bridge) broadcast(data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.subscribers) == 0 {
+		b.logger.Debug("dropping server message with no listening clients")
+		return
+	}
+	for ch := range b.subscribers {
+		select {
+		case ch <- data:
+		default:
+			b.logger.Warn("subscriber buffer full, dropping server message")
+		}
+	}
+}
+
+// failPending answers every in-flight request with an error after the process exits.
+func (b *Bridge) failPending() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, req := range b.pending {
+		req.response <- newErrorMessage(req.originalID, codeInternalError, ErrProcessExited.Error())
+		delete(b.pending, id)
+	}
+}
+
+// writeMessage writes a single message to the process stdin.
+func (b *Bridge) writeMessage(msg *message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	data = append(data, '\n')
+
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	select {
+	case <-b.exited:
+		return ErrProcessExited
+	default:
+	}
+
+	if _, err := b.stdin.Write(data); err != nil {
+		return fmt.Errorf("failed to write to mcp server: %w", err)
+	}
+	return nil
+}
+
+// Handler returns the HTTP handler serving the Streamable HTTP endpoint.
+func (b *Bridge) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(b.path, b.handleMCP)
+	mux.HandleFunc("/health", b.handleHealth)
+	return mux
+}
+
+// handleHealth reports whether the server process is still running.
+func (b *Bridge) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	select {
+	case <-b.exited:
+		http.Error(w, "mcp server process exited", http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}
+}
+
+// handleMCP implements the Streamable HTTP transport on top of the stdio session.
+func (b *Bridge) handleMCP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		b.handlePost(w, r)
+	case http.MethodGet:
+		b.handleGet(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost forwards one message or a batch to the process and writes back the responses.
+func (b *Bridge) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, newErrorMessage(nil, codeParseError, "failed to read request body"))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+
+	var msgs []*message
+	if batch {
+		err = json.Unmarshal(body, &msgs)
+	} else {
+		var msg message
+		err = json.Unmarshal(body, &msg)
+		msgs = []*message{&msg}
+	}
+	if err != nil || len(msgs) == 0 {
+		writeJSON(w, http.StatusBadRequest, newErrorMessage(nil, codeParseError, "invalid JSON-RPC message"))
+		return
+	}
+
+	session := r.Header.Get(headerSessionID)
+	for _, msg := range msgs {
+		if msg.isRequest() && msg.Method == methodInitialize {
+			session = newSessionID()
+			w.Header().Set(headerSessionID, session)
+			break
+		}
+	}
+
+	// Requests in a batch are independent, so forward them concurrently.
+	responses := make([]*message, len(msgs))
+	var wg sync.WaitGroup
+	for i, msg := range msgs {
+		if msg.isRequest() {
+			wg.Add(1)
+			go func(i int, msg *message) {
+				defer wg.Done()
+				responses[i] = b.forwardRequest(r.Context(), session, msg)
+			}(i, msg)
+			continue
+		}
+		if err := b.forwardOther(session, msg); err != nil {
+			b.logger.Error("failed to forward message", slog.String("error", err.Error()))
+		}
+	}
+	wg.Wait()
+
+	var results []*message
+	for _, resp := range responses {
+		if resp != nil {
+			results = append(results, resp)
+		}
+	}
+
+	// Notifications and responses alone are acknowledged without a body.
+	if len(results) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if batch {
+		writeJSON(w, http.StatusOK, results)
+		return
+	}
+	writeJSON(w, http.StatusOK, results[0])
+}
+
+// forwardRequest sends a request to the process and waits for its response.
+func (b *Bridge) forwardRequest(ctx context.Context, session string, msg *message) *message {
+	if msg.Method == methodInitialize {
+		b.mu.Lock()
+		cached := b.initResult
+		b.mu.Unlock()
+		if cached != nil {
+			return &message{JSONRPC: "2.0", ID: msg.ID, Result: cached}
+		}
+	}
+
+	id := b.nextID.Add(1)
+	req := &pendingRequest{
+		session:    session,
+		originalID: msg.ID,
+		response:   make(chan *message, 1),
+	}
+
+	b.mu.Lock()
+	b.pending[id] = req
+	b.mu.Unlock()
+
+	forwarded := *msg
+	forwarded.ID = json.RawMessage(strconv.FormatInt(id, 10))
+	if err := b.writeMessage(&forwarded); err != nil {
+		b.removePending(id)
+		return newErrorMessage(msg.ID, codeInternalError, err.Error())
+	}
+
+	timer := time.NewTimer(b.requestTimeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-req.response:
+		if msg.Method == methodInitialize && len(resp.Result) > 0 && len(resp.Error) == 0 {
+			b.mu.Lock()
+			if b.initResult == nil {
+				b.initResult = resp.Result
+			}
+			b.mu.Unlock()
+		}
+		return resp
+	case <-ctx.Done():
+		b.cancelRequest(id, "client disconnected")
+		return nil
+	case <-timer.C:
+		b.cancelRequest(id, "request timed out")
+		return newErrorMessage(msg.ID, codeInternalError, "timed out waiting for mcp server response")
+	}
+}
+
+// forwardOther sends notifications and client responses to the process.
+func (b *Bridge) forwardOther(session string, msg *message) error {
+	if msg.Method == "" && len(msg.ID) == 0 {
+		return errors.New("message is neither a request, notification nor response")
+	}
+
+	if msg.isNotification() && msg.Method == methodNotificationInitialized {
+		b.mu.Lock()
+		seen := b.initialized
+		b.initialized = true
+		b.mu.Unlock()
+		if seen {
+			// The process has already completed its handshake.
+			return nil
+		}
+	}
+
+	if msg.isNotification() && msg.Method == methodNotificationCancelled {
+		return b.forwardCancel(session, msg)
+	}
+
+	return b.writeMessage(msg)
+}
+
+// forwardCancel rewrites the request ID of a client cancellation to the ID the
+// bridge forwarded the request with. Cancellations matching no pending request
+// of the session are dropped, since the client's ID could otherwise name the
+// request of another client.
+func (b *Bridge) forwardCancel(session string, msg *message) error {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return fmt.Errorf("invalid cancellation params: %w", err)
+	}
+
+	matches := 0
+	var id int64
+	b.mu.Lock()
+	for pendingID, req := range b.pending {
+		if req.session == session && bytes.Equal(req.originalID, params["requestId"]) {
+			id = pendingID
+			matches++
+		}
+	}
+	b.mu.Unlock()
+
+	if matches != 1 {
+		b.logger.Debug("dropping cancellation with no single pending request",
+			slog.String("requestId", string(params["requestId"])))
+		return nil
+	}
+
+	params["requestId"] = json.RawMessage(strconv.FormatInt(id, 10))
+	rewritten, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cancellation params: %w", err)
+	}
+
+	forwarded := *msg
+	forwarded.Params = rewritten
+	return b.writeMessage(&forwarded)
+}
+
+// cancelRequest abandons a pending request and tells the process to stop working on it.
+func (b *Bridge) cancelRequest(id int64, reason string) {
+	b.removePending(id)
+
+	params, _ := json.Marshal(map[string]any{
+		"requestId": id,
+		"reason":    reason,
+	})
+	if err := b.writeMessage(&message{JSONRPC: "2.0", Method: methodNotificationCancelled, Params: params}); err != nil {
+		b.logger.Debug("failed to send cancellation", slog.String("error", err.Error()))
+	}
+}
+
+// removePending drops a pending request without answering it.
+func (b *Bridge) removePending(id int64) {
+	b.mu.Lock()
+	delete(b.pending, id)
+	b.mu.Unlock()
+}
+
+// handleGet opens an SSE stream carrying server-initiated messages.
+func (b *Bridge) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "GET requires Accept: text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan []byte, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-b.exited:
+			return
+		case data := <-ch:
+			if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// newSessionID returns a random session ID for a client that initializes.
+func newSessionID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// newErrorMessage builds a JSON-RPC error response.
+func newErrorMessage(id json.RawMessage, code int, msg string) *message {
+	errData, _ := json.Marshal(map[string]any{
+		"code":    code,
+		"message": msg,
+	})
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &message{JSONRPC: "2.0", ID: id, Error: errData}
+}
+
+// writeJSON writes v as a JSON response body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// truncate shortens s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,453 @@
+package bridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is not a real test. It is executed as a child process by
+// the bridge tests and behaves like a minimal stdio MCP server.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	initializeCount := 0
+	waiting := map[float64]bool{}
+	for scanner.Scan() {
+		var req map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			continue
+		}
+		id, hasID := req["id"]
+		if !hasID {
+			// Answer a cancelled "wait" request so that tests can see which one the
+			// cancellation reached.
+			params, _ := req["params"].(map[string]any)
+			if requestID, ok := params["requestId"].(float64); ok && req["method"] == "notifications/cancelled" && waiting[requestID] {
+				delete(waiting, requestID)
+				resp, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": requestID, "result": map[string]any{"cancelled": true}})
+				fmt.Println(string(resp))
+			}
+			continue
+		}
+
+		var result any
+		switch req["method"] {
+		case "initialize":
+			initializeCount++
+			result = map[string]any{
+				"protocolVersion": "2024-11-05",
+				"capabilities":    map[string]any{"tools": map[string]any{}},
+				"serverInfo":      map[string]any{"name": "helper", "version": "1.0.0"},
+				"count":           initializeCount,
+			}
+		case "tools/list":
+			// Emit noise and a server notification before the response.
+			fmt.Println("not json")
+			fmt.Println(`{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info"}}`)
+			result = map[string]any{"tools": []any{map[string]any{"name": "echo"}}}
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+			result = map[string]any{"slow": true}
+		case "wait":
+			waiting[id.(float64)] = true
+			continue
+		case "exit":
+			os.Exit(0)
+		default:
+			result = map[string]any{"echo": req["method"]}
+		}
+
+		resp, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "result": result})
+		fmt.Println(string(resp))
+	}
+	os.Exit(0)
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// startTestBridge starts a bridge running the helper process and returns an
+// httptest server serving its handler.
+func startTestBridge(t *testing.T) (*Bridge, *httptest.Server) {
+	t.Helper()
+	t.Setenv("GO_WANT_HELPER_PROCESS", "1")
+
+	b, err := New(":0", "", []string{os.Args[0], "-test.run=TestHelperProcess"}, newTestLogger())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := b.startProcess(ctx); err != nil {
+		cancel()
+		t.Fatalf("startProcess() error = %v", err)
+	}
+
+	server := httptest.NewServer(b.Handler())
+	t.Cleanup(func() {
+		server.Close()
+		_ = b.stdin.Close()
+		cancel()
+		<-b.exited
+	})
+
+	return b, server
+}
+
+func postJSON(t *testing.T, url, body string) (*http.Response, []byte) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return resp, data
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(":8080", "", nil, newTestLogger()); err == nil {
+		t.Error("expected error for empty command")
+	}
+
+	b, err := New(":8080", "rpc", []string{"server"}, newTestLogger())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if b.Path() != "/rpc" {
+		t.Errorf("expected path /rpc, got %s", b.Path())
+	}
+
+	b, err = New(":8080", "", []string{"server"}, newTestLogger())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if b.Path() != DefaultPath {
+		t.Errorf("expected default path %s, got %s", DefaultPath, b.Path())
+	}
+}
+
+func TestBridge_RequestResponse(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	resp, body := postJSON(t, server.URL+"/mcp", `{"jsonrpc":"2.0","id":"abc","method":"tools/list"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %s", ct)
+	}
+
+	var msg map[string]any
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if msg["id"] != "abc" {
+		t.Errorf("expected original id abc to be restored, got %v", msg["id"])
+	}
+	result, _ := msg["result"].(map[string]any)
+	if tools, _ := result["tools"].([]any); len(tools) != 1 {
+		t.Errorf("expected 1 tool, got %v", result)
+	}
+}
+
+func TestBridge_Notification(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	resp, _ := postJSON(t, server.URL+"/mcp", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("expected 202, got %d", resp.StatusCode)
+	}
+}
+
+func TestBridge_InitializeCached(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	for i, id := range []string{"1", "2"} {
+		_, body := postJSON(t, server.URL+"/mcp", `{"jsonrpc":"2.0","id":`+id+`,"method":"initialize","params":{}}`)
+
+		var msg struct {
+			ID     int            `json:"id"`
+			Result map[string]any `json:"result"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+		if msg.ID != i+1 {
+			t.Errorf("expected id %d, got %d", i+1, msg.ID)
+		}
+		// The process must only see the first initialize.
+		if msg.Result["count"] != float64(1) {
+			t.Errorf("expected cached initialize result, got count %v", msg.Result["count"])
+		}
+	}
+}
+
+func TestBridge_ConcurrentRequestsWithSameID(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			method := fmt.Sprintf("method-%d", i)
+			resp, err := http.Post(server.URL+"/mcp", "application/json",
+				strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"`+method+`"}`))
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			var msg struct {
+				ID     int               `json:"id"`
+				Result map[string]string `json:"result"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+				errs <- err
+				return
+			}
+			if msg.ID != 1 || msg.Result["echo"] != method {
+				errs <- fmt.Errorf("got id=%d echo=%s, want id=1 echo=%s", msg.ID, msg.Result["echo"], method)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestBridge_CancelRewritesRequestID(t *testing.T) {
+	b, server := startTestBridge(t)
+
+	// Two clients each start a session and send a request with the same ID.
+	sessions := make([]string, 2)
+	responses := make([]chan map[string]any, 2)
+	for i := range sessions {
+		resp, _ := postJSON(t, server.URL+"/mcp", `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`)
+		sessions[i] = resp.Header.Get(headerSessionID)
+		if sessions[i] == "" {
+			t.Fatal("expected initialize to return a session ID")
+		}
+
+		responses[i] = make(chan map[string]any, 1)
+		go func(i int) {
+			req, _ := http.NewRequest(http.MethodPost, server.URL+"/mcp",
+				strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"wait"}`))
+			req.Header.Set(headerSessionID, sessions[i])
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return
+			}
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			var msg map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&msg)
+			responses[i] <- msg
+		}(i)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		b.mu.Lock()
+		inFlight := len(b.pending)
+		b.mu.Unlock()
+		if inFlight == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 pending requests, got %d", inFlight)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel := func(session, requestID string) {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/mcp",
+			strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":`+requestID+`}}`))
+		req.Header.Set(headerSessionID, session)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d", resp.StatusCode)
+		}
+	}
+
+	// A cancellation naming no pending request of the session is dropped.
+	cancel(sessions[1], "7")
+	cancel("unknown", "1")
+
+	cancel(sessions[1], "1")
+	select {
+	case msg := <-responses[1]:
+		if msg["id"] != float64(1) || msg["result"].(map[string]any)["cancelled"] != true {
+			t.Errorf("expected the second client's request to be cancelled, got %v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cancellation did not reach the second client's request")
+	}
+
+	select {
+	case msg := <-responses[0]:
+		t.Fatalf("the first client's request was cancelled: %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel(sessions[0], "1")
+	select {
+	case <-responses[0]:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the cancellation did not reach the first client's request")
+	}
+}
+
+func TestBridge_Batch(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	resp, body := postJSON(t, server.URL+"/mcp",
+		`[{"jsonrpc":"2.0","id":1,"method":"slow"},{"jsonrpc":"2.0","method":"notifications/progress"},{"jsonrpc":"2.0","id":2,"method":"ping"}]`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var msgs []map[string]any
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		t.Fatalf("expected batch response: %v (%s)", err, body)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(msgs))
+	}
+	if msgs[0]["id"] != float64(1) || msgs[1]["id"] != float64(2) {
+		t.Errorf("unexpected response ids: %v, %v", msgs[0]["id"], msgs[1]["id"])
+	}
+}
+
+func TestBridge_InvalidJSON(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	resp, _ := postJSON(t, server.URL+"/mcp", `{not json`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestBridge_MethodNotAllowed(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/mcp", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", resp.StatusCode)
+	}
+}
+
+func TestBridge_ServerNotificationsStream(t *testing.T) {
+	_, server := startTestBridge(t)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/mcp", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %s", ct)
+	}
+
+	// tools/list makes the helper emit a notification.
+	postJSON(t, server.URL+"/mcp", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatal("stream closed before notification was received")
+			}
+			if strings.HasPrefix(line, "data: ") && strings.Contains(line, "notifications/message") {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for server notification")
+		}
+	}
+}
+
+func TestBridge_ProcessExit(t *testing.T) {
+	b, server := startTestBridge(t)
+
+	_, body := postJSON(t, server.URL+"/mcp", `{"jsonrpc":"2.0","id":7,"method":"exit"}`)
+
+	var msg struct {
+		ID    int `json:"id"`
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if msg.ID != 7 || msg.Error == nil || msg.Error.Code != codeInternalError {
+		t.Errorf("expected internal error for id 7, got %s", body)
+	}
+
+	select {
+	case <-b.exited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("bridge did not observe process exit")
+	}
+
+	resp, err := http.Get(server.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 after exit, got %d", resp.StatusCode)
+	}
+}
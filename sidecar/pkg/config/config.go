@@ -9,8 +9,38 @@ import (
 	"time"
 )
 
+// Run modes for the mcp-proxy binary.
+const (
+	// ModeProxy runs the metrics sidecar proxy in front of an HTTP MCP server.
+	ModeProxy = "proxy"
+
+	// ModeStdioBridge runs a stdio MCP server and exposes it as Streamable HTTP.
+	ModeStdioBridge = "stdio-bridge"
+
+	// ModeInstall copies the binary to InstallPath and exits.
+	ModeInstall = "install"
+
+	// ModeGateway fronts several MCP servers behind one Streamable HTTP endpoint.
+	ModeGateway = "gateway"
+
+	// ModeSessionRouter routes each Streamable HTTP session to the pod that owns it.
+	ModeSessionRouter = "session-router"
+
+	// ModeActivator answers for an MCP server scaled to zero and scales it
+	// up on the first request.
+	ModeActivator = "activator"
+
+	// ModeDrain asks the proxy running in the pod to drain, and waits until
+	// it is done. It is run by the preStop hook of the sidecar.
+	ModeDrain = "drain"
+)
+
 // Config holds the configuration for the MCP proxy sidecar.
 type Config struct {
+	// Mode selects what the binary runs: proxy, stdio-bridge, install,
+	// gateway, session-router, activator or drain.
+	Mode string
+
 	// ListenAddr is the address the proxy listens on for incoming requests.
 	ListenAddr string
 
@@ -23,9 +53,34 @@ type Config struct {
 	// LogLevel controls the logging verbosity (debug, info, warn, error).
 	LogLevel string
 
+	// MaxToolLabels is the number of most called tools given their own
+	// tool_name metric label. Other tools are recorded as "other".
+	MaxToolLabels int
+
 	// HealthCheckInterval is the interval between health checks of the target.
 	HealthCheckInterval time.Duration
 
+	// HealthCheckMode selects how the target is checked: tcp dials it, mcp
+	// sends it MCP requests.
+	HealthCheckMode string
+
+	// HealthCheckMethod is the MCP request of the mcp check: initialize or ping.
+	HealthCheckMethod string
+
+	// HealthCheckPath is the MCP endpoint of the target checked by the mcp check.
+	HealthCheckPath string
+
+	// HealthCheckTimeout bounds every mcp check.
+	HealthCheckTimeout time.Duration
+
+	// HealthCheckFailureThreshold is the number of consecutive failed checks
+	// after which the target is reported unready.
+	HealthCheckFailureThreshold int
+
+	// DrainTimeout is how long the proxy waits for requests in flight when
+	// shutting down, after asking the clients of event streams to reconnect.
+	DrainTimeout time.Duration
+
 	// TLSEnabled enables TLS termination for incoming connections.
 	TLSEnabled bool
 
@@ -37,20 +92,153 @@ type Config struct {
 
 	// TLSMinVersion is the minimum TLS version to accept (1.2 or 1.3).
 	TLSMinVersion string
+
+	// BridgePath is the HTTP path the stdio bridge serves MCP traffic on.
+	BridgePath string
+
+	// InstallPath is the destination the binary is copied to in install mode.
+	InstallPath string
+
+	// GatewayConfigFile is the path to the JSON backend configuration in gateway mode.
+	GatewayConfigFile string
+
+	// Policy is the JSON tool and resource policy enforced in proxy mode.
+	// Empty disables enforcement.
+	Policy string
+
+	// RateLimit is the JSON rate limit configuration enforced in proxy mode.
+	// Empty disables rate limiting.
+	RateLimit string
+
+	// AuthAPIKeysDir is a directory of API keys, one file per client named
+	// after its identity. Setting it or a JWKS enables authentication.
+	AuthAPIKeysDir string
+
+	// AuthJWKSURL is the URL of the JSON Web Key Set JWTs are verified against.
+	AuthJWKSURL string
+
+	// AuthJWKSFile is a file holding the JSON Web Key Set JWTs are verified against.
+	AuthJWKSFile string
+
+	// AuthIssuer is the required issuer of JWTs.
+	AuthIssuer string
+
+	// AuthAudiences is a comma-separated list of accepted JWT audiences.
+	AuthAudiences string
+
+	// AuthIdentityClaim is the JWT claim used as the client identity.
+	AuthIdentityClaim string
+
+	// AuthAuthorizationServers is a comma-separated list of authorization
+	// servers published in the OAuth protected resource metadata.
+	AuthAuthorizationServers string
+
+	// AuthResource is the resource identifier published in the OAuth
+	// protected resource metadata.
+	AuthResource string
+
+	// TracingEndpoint is the URL of the OTLP/HTTP collector spans are exported to.
+	TracingEndpoint string
+
+	// TracingFile is a file spans are written to as JSON lines, "-" for stdout.
+	// Setting it or TracingEndpoint enables tracing.
+	TracingFile string
+
+	// TracingSampleRatio is the fraction of new traces sampled.
+	TracingSampleRatio float64
+
+	// TracingServiceName is the service.name attribute of the spans.
+	TracingServiceName string
+
+	// AuditFile is a JSON-lines file audit records of tool calls and resource
+	// reads are appended to, "-" for stdout. Setting it enables auditing.
+	AuditFile string
+
+	// AuditRedact is a comma-separated list of JSONPath expressions whose
+	// values are redacted from the audited tool arguments.
+	AuditRedact string
+
+	// AuditMaxArgumentBytes is the size above which audited arguments are truncated.
+	AuditMaxArgumentBytes int
+
+	// RouterService is the Service whose pods the session router routes to.
+	RouterService string
+
+	// RouterNamespace is the namespace of RouterService. Empty selects the
+	// namespace of the pod.
+	RouterNamespace string
+
+	// RouterPortName is the name of the RouterService port requests are sent to.
+	RouterPortName string
+
+	// RouterResyncInterval is the interval between EndpointSlice lists.
+	RouterResyncInterval time.Duration
+
+	// RouterSessionIdleTimeout is how long an unused session is remembered.
+	RouterSessionIdleTimeout time.Duration
+
+	// ActivatorDeployment is the Deployment the activator scales up.
+	ActivatorDeployment string
+
+	// ActivatorNamespace is the namespace of ActivatorDeployment. Empty
+	// selects the namespace of the pod.
+	ActivatorNamespace string
+
+	// ActivatorPodSelector is the label selector of the Deployment pods.
+	// Empty selects app=<ActivatorDeployment>.
+	ActivatorPodSelector string
+
+	// ActivatorTargetPort is the port of the Deployment pods requests are sent to.
+	ActivatorTargetPort int
+
+	// ActivatorReplicas is the number of replicas the Deployment is scaled up to.
+	ActivatorReplicas int
+
+	// ActivatorTimeout is how long a request waits for a ready pod.
+	ActivatorTimeout time.Duration
+
+	// Command is the stdio server command and arguments, taken from the
+	// positional arguments after the flags (e.g. "-- npx my-server").
+	Command []string
 }
 
 // DefaultConfig returns a Config with default values.
 func DefaultConfig() *Config {
 	return &Config{
+		Mode:                ModeProxy,
 		ListenAddr:          ":8080",
 		TargetAddr:          "localhost:3001",
 		MetricsAddr:         ":9090",
 		LogLevel:            "info",
+		MaxToolLabels:       100,
 		HealthCheckInterval: 10 * time.Second,
-		TLSEnabled:          false,
-		TLSCertFile:         "",
-		TLSKeyFile:          "",
-		TLSMinVersion:       "1.2",
+		HealthCheckMode:     "tcp",
+		HealthCheckMethod:   "ping",
+		HealthCheckPath:     "/mcp",
+		HealthCheckTimeout:  5 * time.Second,
+		DrainTimeout:        25 * time.Second,
+
+		HealthCheckFailureThreshold: 1,
+		TLSEnabled:                  false,
+		TLSCertFile:                 "",
+		TLSKeyFile:                  "",
+		TLSMinVersion:               "1.2",
+		BridgePath:                  "/mcp",
+		InstallPath:                 "/mcp-bridge/mcp-proxy",
+		GatewayConfigFile:           "/etc/mcp-gateway/gateway.json",
+		AuthIdentityClaim:           "sub",
+		TracingSampleRatio:          1,
+		TracingServiceName:          "mcp-proxy",
+
+		AuditMaxArgumentBytes: 4096,
+
+		RouterPortName:           "http",
+		RouterResyncInterval:     5 * time.Second,
+		RouterSessionIdleTimeout: time.Hour,
+
+		ActivatorTargetPort: 8080,
+		ActivatorReplicas:   1,
+		ActivatorTimeout:    2 * time.Minute,
 	}
 }
 
@@ -58,21 +246,100 @@ func DefaultConfig() *Config {
 func ParseFlags() *Config {
 	cfg := DefaultConfig()
 
+	flag.StringVar(&cfg.Mode, "mode", cfg.Mode, "Run mode (proxy, stdio-bridge, install, gateway, session-router, activator, drain)")
 	flag.StringVar(&cfg.ListenAddr, "listen-addr", cfg.ListenAddr, "Address to listen on for incoming requests")
 	flag.StringVar(&cfg.TargetAddr, "target-addr", cfg.TargetAddr, "Address of the MCP server to proxy to")
 	flag.StringVar(&cfg.MetricsAddr, "metrics-addr", cfg.MetricsAddr, "Address to expose Prometheus metrics on")
+	flag.IntVar(&cfg.MaxToolLabels, "max-tool-labels", cfg.MaxToolLabels, "Number of most called tools given their own metric label; others are recorded as \"other\"")
 	flag.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "Log level (debug, info, warn, error)")
 	flag.DurationVar(&cfg.HealthCheckInterval, "health-check-interval", cfg.HealthCheckInterval, "Interval between health checks of the target")
+	flag.StringVar(&cfg.HealthCheckMode, "health-check-mode", cfg.HealthCheckMode, "How the target is checked: tcp dials it, mcp sends it MCP requests")
+	flag.StringVar(&cfg.HealthCheckMethod, "health-check-method", cfg.HealthCheckMethod, "MCP request of the mcp health check (initialize, ping)")
+	flag.StringVar(&cfg.HealthCheckPath, "health-check-path", cfg.HealthCheckPath, "MCP endpoint of the target checked by the mcp health check")
+	flag.DurationVar(&cfg.HealthCheckTimeout, "health-check-timeout", cfg.HealthCheckTimeout, "Timeout of every mcp health check")
+	flag.IntVar(&cfg.HealthCheckFailureThreshold, "health-check-failure-threshold", cfg.HealthCheckFailureThreshold,
+		"Consecutive failed health checks after which the target is reported unready")
+	flag.DurationVar(&cfg.DrainTimeout, "drain-timeout", cfg.DrainTimeout, "How long to wait for requests in flight when shutting down")
 	flag.BoolVar(&cfg.TLSEnabled, "tls-enabled", cfg.TLSEnabled, "Enable TLS termination for incoming connections")
 	flag.StringVar(&cfg.TLSCertFile, "tls-cert-file", cfg.TLSCertFile, "Path to TLS certificate file")
 	flag.StringVar(&cfg.TLSKeyFile, "tls-key-file", cfg.TLSKeyFile, "Path to TLS private key file")
 	flag.StringVar(&cfg.TLSMinVersion, "tls-min-version", cfg.TLSMinVersion, "Minimum TLS version (1.2 or 1.3)")
 
+	flag.StringVar(&cfg.BridgePath, "bridge-path", cfg.BridgePath, "HTTP path served by the stdio bridge")
+	flag.StringVar(&cfg.InstallPath, "install-path", cfg.InstallPath, "Destination path for the binary in install mode")
+	flag.StringVar(&cfg.GatewayConfigFile, "gateway-config", cfg.GatewayConfigFile, "Path to the backend configuration in gateway mode")
+	flag.StringVar(&cfg.Policy, "policy", cfg.Policy, "JSON tool and resource policy to enforce (empty disables enforcement)")
+
+	flag.StringVar(&cfg.RateLimit, "rate-limit", cfg.RateLimit, "JSON rate limits to enforce (empty disables rate limiting)")
+	flag.StringVar(&cfg.AuthAPIKeysDir, "auth-api-keys-dir", cfg.AuthAPIKeysDir, "Directory of API keys, one file per client identity")
+	flag.StringVar(&cfg.AuthJWKSURL, "auth-jwks-url", cfg.AuthJWKSURL, "URL of the JWKS used to verify JWTs")
+	flag.StringVar(&cfg.AuthJWKSFile, "auth-jwks-file", cfg.AuthJWKSFile, "Path to the JWKS used to verify JWTs")
+	flag.StringVar(&cfg.AuthIssuer, "auth-issuer", cfg.AuthIssuer, "Required JWT issuer")
+	flag.StringVar(&cfg.AuthAudiences, "auth-audiences", cfg.AuthAudiences, "Comma-separated list of accepted JWT audiences")
+	flag.StringVar(&cfg.AuthIdentityClaim, "auth-identity-claim", cfg.AuthIdentityClaim, "JWT claim used as the client identity")
+	flag.StringVar(&cfg.AuthAuthorizationServers, "auth-authorization-servers", cfg.AuthAuthorizationServers,
+		"Comma-separated list of authorization servers published in the protected resource metadata")
+	flag.StringVar(&cfg.AuthResource, "auth-resource", cfg.AuthResource, "Resource identifier published in the protected resource metadata")
+
+	flag.StringVar(&cfg.TracingEndpoint, "tracing-endpoint", cfg.TracingEndpoint,
+		"URL of the OTLP/HTTP collector to export spans to (e.g. http://otel-collector:4318)")
+	flag.StringVar(&cfg.TracingFile, "tracing-file", cfg.TracingFile, "File to write spans to as JSON lines, - for stdout")
+	flag.Float64Var(&cfg.TracingSampleRatio, "tracing-sample-ratio", cfg.TracingSampleRatio, "Fraction of new traces to sample (0 to 1)")
+	flag.StringVar(&cfg.TracingServiceName, "tracing-service-name", cfg.TracingServiceName, "Service name reported in spans")
+
+	flag.StringVar(&cfg.AuditFile, "audit-file", cfg.AuditFile, "File to append audit records to as JSON lines, - for stdout")
+	flag.StringVar(&cfg.AuditRedact, "audit-redact", cfg.AuditRedact, "Comma-separated JSONPath expressions redacted from audited tool arguments")
+	flag.IntVar(&cfg.AuditMaxArgumentBytes, "audit-max-argument-bytes", cfg.AuditMaxArgumentBytes,
+		"Size above which audited tool arguments are truncated (0 for no limit)")
+
+	flag.StringVar(&cfg.RouterService, "router-service", cfg.RouterService, "Service whose pods sessions are routed to in session-router mode")
+	flag.StringVar(&cfg.RouterNamespace, "router-namespace", cfg.RouterNamespace, "Namespace of the routed Service (default: the pod's namespace)")
+	flag.StringVar(&cfg.RouterPortName, "router-port-name", cfg.RouterPortName, "Name of the routed Service port")
+	flag.DurationVar(&cfg.RouterResyncInterval, "router-resync-interval", cfg.RouterResyncInterval, "Interval between EndpointSlice lists")
+	flag.DurationVar(&cfg.RouterSessionIdleTimeout, "router-session-idle-timeout", cfg.RouterSessionIdleTimeout,
+		"How long an unused session is remembered")
+
+	flag.StringVar(&cfg.ActivatorDeployment, "activator-deployment", cfg.ActivatorDeployment, "Deployment scaled up by the activator")
+	flag.StringVar(&cfg.ActivatorNamespace, "activator-namespace", cfg.ActivatorNamespace, "Namespace of the activated Deployment (default: the pod's namespace)")
+	flag.StringVar(&cfg.ActivatorPodSelector, "activator-pod-selector", cfg.ActivatorPodSelector,
+		"Label selector of the activated Deployment pods (default: app=<deployment>)")
+	flag.IntVar(&cfg.ActivatorTargetPort, "activator-target-port", cfg.ActivatorTargetPort, "Port of the activated pods requests are sent to")
+	flag.IntVar(&cfg.ActivatorReplicas, "activator-replicas", cfg.ActivatorReplicas, "Replicas the activated Deployment is scaled up to")
+	flag.DurationVar(&cfg.ActivatorTimeout, "activator-timeout", cfg.ActivatorTimeout, "How long a request waits for a ready pod")
+
 	flag.Parse()
 
+	cfg.Command = flag.Args()
+
 	return cfg
 }
 
+// AuthEnabled reports whether incoming requests must be authenticated.
+func (c *Config) AuthEnabled() bool {
+	return c.AuthAPIKeysDir != "" || c.AuthJWKSURL != "" || c.AuthJWKSFile != ""
+}
+
+// TracingEnabled reports whether spans are exported.
+func (c *Config) TracingEnabled() bool {
+	return c.TracingEndpoint != "" || c.TracingFile != ""
+}
+
+// AuditEnabled reports whether tool calls and resource reads are audited.
+func (c *Config) AuditEnabled() bool {
+	return c.AuditFile != ""
+}
+
+// SplitList splits a comma-separated flag value, dropping empty entries.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // LogLevel returns the slog.Level corresponding to the configured log level string.
 func (c *Config) GetLogLevel() slog.Level {
 	switch strings.ToLower(c.LogLevel) {
@@ -7,22 +7,63 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
 )
 
+// Check modes of the target.
+const (
+	// CheckTCP dials the target.
+	CheckTCP = "tcp"
+
+	// CheckMCP sends MCP requests to the target.
+	CheckMCP = "mcp"
+)
+
+// MCPCheck configures checking the target with MCP requests rather than a
+// TCP dial, so a server that accepts connections but no longer answers is
+// reported unready.
+type MCPCheck struct {
+	// Path is the MCP endpoint of the target.
+	Path string
+
+	// Method is the request sent on every check: mcp.MethodInitialize starts
+	// and ends a session each time, mcp.MethodPing pings a session started
+	// once.
+	Method string
+
+	// Timeout bounds every check.
+	Timeout time.Duration
+}
+
 // HealthChecker manages health checks against a target server.
 type HealthChecker struct {
 	targetAddr    string
 	healthy       atomic.Bool
 	ready         atomic.Bool
+	draining      atomic.Bool
 	lastCheck     time.Time
 	lastError     error
 	lastLatency   time.Duration
 	checkInterval time.Duration
 	startTime     time.Time
 
+	// mcpCheck and client check the target with MCP requests (optional, can be nil).
+	mcpCheck *MCPCheck
+	client   *mcp.Client
+	// sessionStarted is set once the ping check initialized its session.
+	sessionStarted bool
+
+	// failureThreshold is the number of consecutive failed checks after
+	// which the target is reported unready.
+	failureThreshold    int
+	consecutiveFailures int
+	protocolVersion     string
+
 	mu     sync.RWMutex
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
@@ -38,17 +79,23 @@ type HealthResponse struct {
 // CheckResult represents the result of a single health check.
 type CheckResult struct {
 	Status    string  `json:"status"`
+	Check     string  `json:"check,omitempty"`
 	LatencyMs float64 `json:"latency_ms,omitempty"`
 	LastCheck string  `json:"last_check,omitempty"`
 	Error     string  `json:"error,omitempty"`
+	// ProtocolVersion is the MCP protocol version the target negotiated, with the MCP check.
+	ProtocolVersion string `json:"protocol_version,omitempty"`
+	// ConsecutiveFailures is the number of failed checks since the last success.
+	ConsecutiveFailures int `json:"consecutive_failures,omitempty"`
 }
 
 // NewHealthChecker creates a new HealthChecker for the given target.
 func NewHealthChecker(targetAddr string, checkInterval time.Duration) *HealthChecker {
 	hc := &HealthChecker{
-		targetAddr:    targetAddr,
-		checkInterval: checkInterval,
-		startTime:     time.Now(),
+		targetAddr:       targetAddr,
+		checkInterval:    checkInterval,
+		startTime:        time.Now(),
+		failureThreshold: 1,
 	}
 	// Process is always considered healthy (liveness)
 	hc.healthy.Store(true)
@@ -57,6 +104,28 @@ func NewHealthChecker(targetAddr string, checkInterval time.Duration) *HealthChe
 	return hc
 }
 
+// SetMCPCheck checks the target with MCP requests instead of a TCP dial.
+// It must be called before Start.
+func (hc *HealthChecker) SetMCPCheck(check MCPCheck) {
+	endpoint := hc.targetAddr
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	if !strings.HasPrefix(check.Path, "/") {
+		check.Path = "/" + check.Path
+	}
+
+	hc.mcpCheck = &check
+	hc.client = mcp.NewClient(endpoint+check.Path, &http.Client{Timeout: check.Timeout},
+		mcp.Implementation{Name: "mcp-proxy-health", Version: "1.0.0"})
+}
+
+// SetFailureThreshold sets the number of consecutive failed checks after
+// which the target is reported unready. It must be called before Start.
+func (hc *HealthChecker) SetFailureThreshold(threshold int) {
+	hc.failureThreshold = max(threshold, 1)
+}
+
 // Start begins the background health checking routine.
 func (hc *HealthChecker) Start(ctx context.Context) {
 	ctx, hc.cancel = context.WithCancel(ctx)
@@ -94,12 +163,18 @@ func (hc *HealthChecker) runChecks(ctx context.Context) {
 	}
 }
 
-// checkTarget attempts to connect to the target and updates the ready state.
+// checkTarget checks the target and updates the ready state. The target is
+// reported unready after failureThreshold consecutive failures.
 func (hc *HealthChecker) checkTarget() {
 	start := time.Now()
 
-	// Attempt TCP connection to target
-	conn, err := net.DialTimeout("tcp", hc.targetAddr, 5*time.Second)
+	var protocolVersion string
+	var err error
+	if hc.mcpCheck != nil {
+		protocolVersion, err = hc.checkMCP()
+	} else {
+		err = hc.dialTarget()
+	}
 
 	latency := time.Since(start)
 
@@ -109,23 +184,84 @@ func (hc *HealthChecker) checkTarget() {
 
 	if err != nil {
 		hc.lastError = err
-		hc.ready.Store(false)
+		hc.consecutiveFailures++
+		if hc.consecutiveFailures >= hc.failureThreshold {
+			hc.ready.Store(false)
+		}
 	} else {
-		conn.Close()
 		hc.lastError = nil
+		hc.consecutiveFailures = 0
+		if protocolVersion != "" {
+			hc.protocolVersion = protocolVersion
+		}
 		hc.ready.Store(true)
 	}
 	hc.mu.Unlock()
 }
 
+// dialTarget attempts a TCP connection to the target.
+func (hc *HealthChecker) dialTarget() error {
+	conn, err := net.DialTimeout("tcp", hc.targetAddr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkMCP sends the configured MCP request to the target, and returns the
+// protocol version negotiated when the check initialized a session.
+func (hc *HealthChecker) checkMCP() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), hc.mcpCheck.Timeout)
+	defer cancel()
+
+	if hc.mcpCheck.Method == mcp.MethodPing && hc.sessionStarted {
+		err := hc.client.Ping(ctx)
+		if err != nil {
+			// Start a new session on the next check, in case the server
+			// restarted or expired the session
+			hc.sessionStarted = false
+		}
+		return "", err
+	}
+
+	result, err := hc.client.Initialize(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if hc.mcpCheck.Method == mcp.MethodPing {
+		hc.sessionStarted = true
+		if err := hc.client.Ping(ctx); err != nil {
+			hc.sessionStarted = false
+			return "", err
+		}
+		return result.ProtocolVersion, nil
+	}
+
+	// Do not leave a session behind on every check
+	_ = hc.client.Close(ctx)
+	return result.ProtocolVersion, nil
+}
+
 // IsHealthy returns the liveness status.
 func (hc *HealthChecker) IsHealthy() bool {
 	return hc.healthy.Load()
 }
 
-// IsReady returns the readiness status.
+// IsReady returns the readiness status. A draining sidecar is never ready.
 func (hc *HealthChecker) IsReady() bool {
-	return hc.ready.Load()
+	return hc.ready.Load() && !hc.draining.Load()
+}
+
+// SetDraining marks the sidecar as shutting down, so readiness fails and
+// Kubernetes stops sending new connections to the pod.
+func (hc *HealthChecker) SetDraining() {
+	hc.draining.Store(true)
+}
+
+// IsDraining reports whether the sidecar is shutting down.
+func (hc *HealthChecker) IsDraining() bool {
+	return hc.draining.Load()
 }
 
 // LivenessHandler returns an HTTP handler for the /healthz endpoint.
@@ -145,31 +281,42 @@ func (hc *HealthChecker) LivenessHandler() http.HandlerFunc {
 // ReadinessHandler returns an HTTP handler for the /readyz endpoint.
 func (hc *HealthChecker) ReadinessHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		hc.mu.RLock()
-		lastCheck := hc.lastCheck
-		lastError := hc.lastError
-		lastLatency := hc.lastLatency
-		hc.mu.RUnlock()
-
-		isReady := hc.ready.Load()
+		checkResult := hc.targetCheckResult()
+		isReady := checkResult.Status == "up"
 
-		checkResult := CheckResult{
-			LatencyMs: float64(lastLatency.Milliseconds()),
-		}
-		if !lastCheck.IsZero() {
-			checkResult.LastCheck = lastCheck.Format(time.RFC3339)
+		response := HealthResponse{
+			UptimeSeconds: time.Since(hc.startTime).Seconds(),
+			Checks: map[string]CheckResult{
+				"target": checkResult,
+			},
 		}
 
-		if isReady {
-			checkResult.Status = "up"
-		} else {
-			checkResult.Status = "down"
-			if lastError != nil {
-				checkResult.Error = lastError.Error()
-			}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case hc.draining.Load():
+			response.Status = "draining"
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case isReady:
+			response.Status = "healthy"
+			w.WriteHeader(http.StatusOK)
+		default:
+			response.Status = "unhealthy"
+			w.WriteHeader(http.StatusServiceUnavailable)
 		}
 
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// TargetHandler returns an HTTP handler reporting the check of the target
+// alone, unaffected by draining. Kubernetes probes of the MCP server
+// container use it with the MCP check.
+func (hc *HealthChecker) TargetHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checkResult := hc.targetCheckResult()
 		response := HealthResponse{
+			Status:        "healthy",
 			UptimeSeconds: time.Since(hc.startTime).Seconds(),
 			Checks: map[string]CheckResult{
 				"target": checkResult,
@@ -177,19 +324,43 @@ func (hc *HealthChecker) ReadinessHandler() http.HandlerFunc {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-
-		if isReady {
-			response.Status = "healthy"
+		if checkResult.Status == "up" {
 			w.WriteHeader(http.StatusOK)
 		} else {
 			response.Status = "unhealthy"
 			w.WriteHeader(http.StatusServiceUnavailable)
 		}
-
 		json.NewEncoder(w).Encode(response)
 	}
 }
 
+// targetCheckResult returns the result of the last checks of the target.
+func (hc *HealthChecker) targetCheckResult() CheckResult {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	checkResult := CheckResult{
+		Status:              "down",
+		Check:               CheckTCP,
+		LatencyMs:           float64(hc.lastLatency.Milliseconds()),
+		ProtocolVersion:     hc.protocolVersion,
+		ConsecutiveFailures: hc.consecutiveFailures,
+	}
+	if hc.mcpCheck != nil {
+		checkResult.Check = hc.mcpCheck.Method
+	}
+	if !hc.lastCheck.IsZero() {
+		checkResult.LastCheck = hc.lastCheck.Format(time.RFC3339)
+	}
+	if hc.ready.Load() {
+		checkResult.Status = "up"
+	}
+	if hc.lastError != nil {
+		checkResult.Error = hc.lastError.Error()
+	}
+	return checkResult
+}
+
 // TargetAddr returns the target address being checked.
 func (hc *HealthChecker) TargetAddr() string {
 	return hc.targetAddr
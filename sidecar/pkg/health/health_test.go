@@ -7,6 +7,9 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -111,6 +114,46 @@ func TestHealthChecker_ReadinessReturns200WhenTargetUp(t *testing.T) {
 	hc.Stop()
 }
 
+func TestHealthChecker_ReadinessReturns503WhileDraining(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test listener: %v", err)
+	}
+	defer listener.Close()
+
+	hc := NewHealthChecker(listener.Addr().String(), 100*time.Millisecond)
+	hc.checkTarget()
+	if !hc.IsReady() {
+		t.Fatal("expected IsReady to be true with the target up")
+	}
+
+	hc.SetDraining()
+	if hc.IsReady() {
+		t.Error("expected IsReady to be false while draining")
+	}
+	if !hc.IsHealthy() {
+		t.Error("expected IsHealthy to stay true while draining")
+	}
+
+	rr := httptest.NewRecorder()
+	hc.ReadinessHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rr.Code)
+	}
+
+	var response HealthResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Status != "draining" {
+		t.Errorf("expected status 'draining', got %s", response.Status)
+	}
+	if response.Checks["target"].Status != "up" {
+		t.Errorf("expected target status 'up', got %s", response.Checks["target"].Status)
+	}
+}
+
 func TestHealthChecker_ReadinessReturns503WhenTargetDown(t *testing.T) {
 	// Use a port that nothing is listening on
 	hc := NewHealthChecker("127.0.0.1:59999", 100*time.Millisecond)
@@ -327,3 +370,92 @@ func TestHealthChecker_TargetRecovery(t *testing.T) {
 		t.Error("should be ready when target is up")
 	}
 }
+
+func TestHealthChecker_MCPCheck(t *testing.T) {
+	var hung atomic.Bool
+	release := make(chan struct{})
+	var methods []string
+	var mu sync.Mutex
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hung.Load() {
+			<-release
+			return
+		}
+
+		var req struct {
+			Method string      `json:"method"`
+			ID     interface{} `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		methods = append(methods, req.Method)
+		mu.Unlock()
+
+		switch req.Method {
+		case "initialize":
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"jsonrpc": "2.0", "id": req.ID,
+				"result": map[string]interface{}{"protocolVersion": "2025-03-26"},
+			})
+		case "ping":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": map[string]interface{}{}})
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer target.Close()
+	defer close(release)
+
+	hc := NewHealthChecker(strings.TrimPrefix(target.URL, "http://"), time.Second)
+	hc.SetMCPCheck(MCPCheck{Path: "/mcp", Method: "ping", Timeout: 200 * time.Millisecond})
+	hc.SetFailureThreshold(2)
+
+	hc.checkTarget()
+	hc.checkTarget()
+	if !hc.IsReady() {
+		t.Fatalf("expected IsReady to be true, last error: %v", hc.lastError)
+	}
+	mu.Lock()
+	wantMethods := []string{"initialize", "notifications/initialized", "ping", "ping"}
+	if strings.Join(methods, ",") != strings.Join(wantMethods, ",") {
+		t.Errorf("expected requests %v, got %v", wantMethods, methods)
+	}
+	mu.Unlock()
+
+	rr := httptest.NewRecorder()
+	hc.ReadinessHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+	var response HealthResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if check := response.Checks["target"]; check.ProtocolVersion != "2025-03-26" || check.Check != "ping" {
+		t.Errorf("expected protocol version 2025-03-26 with the ping check, got %+v", check)
+	}
+
+	// A hung server still accepts connections, but fails the check
+	hung.Store(true)
+	hc.checkTarget()
+	if !hc.IsReady() {
+		t.Error("expected IsReady to stay true below the failure threshold")
+	}
+	hc.checkTarget()
+	if hc.IsReady() {
+		t.Error("expected IsReady to be false at the failure threshold")
+	}
+
+	rr = httptest.NewRecorder()
+	hc.TargetHandler().ServeHTTP(rr, httptest.NewRequest("GET", "/targetz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", rr.Code)
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if check := response.Checks["target"]; check.ConsecutiveFailures != 2 || check.Error == "" {
+		t.Errorf("expected 2 consecutive failures with an error, got %+v", check)
+	}
+}
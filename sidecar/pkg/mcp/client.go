@@ -0,0 +1,300 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// ProtocolVersion is the MCP protocol version the client requests.
+	ProtocolVersion = "2025-03-26"
+
+	// HeaderSessionID carries the session of the Streamable HTTP transport.
+	HeaderSessionID = "Mcp-Session-Id"
+
+	// HeaderProtocolVersion carries the negotiated protocol version on the
+	// requests following initialization.
+	HeaderProtocolVersion = "Mcp-Protocol-Version"
+
+	// MaxListPages bounds how many pages of a paginated list are fetched.
+	MaxListPages = 20
+
+	// maxResponseBytes bounds the responses the client reads, which include
+	// the tool results the gateway forwards.
+	maxResponseBytes = 10 << 20
+)
+
+// ErrSessionExpired is returned when the server no longer knows the session.
+var ErrSessionExpired = errors.New("session expired")
+
+// Implementation identifies an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeResult is the part of the initialize result the client reads.
+type InitializeResult struct {
+	// ProtocolVersion is the protocol version the server chose.
+	ProtocolVersion string `json:"protocolVersion"`
+
+	// Capabilities are the capabilities the server offers, by name.
+	Capabilities map[string]json.RawMessage `json:"capabilities"`
+
+	// ServerInfo identifies the server.
+	ServerInfo Implementation `json:"serverInfo"`
+}
+
+// Client is a minimal MCP client over the Streamable HTTP transport. The
+// sidecar uses it to check that the server it fronts answers MCP requests,
+// and the gateway to reach its backends.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+	clientInfo Implementation
+	requestID  atomic.Int64
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+}
+
+// NewClient creates a client for the MCP endpoint of a server.
+func NewClient(endpoint string, httpClient *http.Client, clientInfo Implementation) *Client {
+	return &Client{
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		clientInfo: clientInfo,
+	}
+}
+
+// Initialize performs the initialization handshake, starting a new session.
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	c.Reset()
+
+	params := map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      c.clientInfo,
+	}
+
+	var result InitializeResult
+	if err := c.call(ctx, MethodInitialize, params, &result); err != nil {
+		return nil, fmt.Errorf("initialize failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.protocolVersion = result.ProtocolVersion
+	c.mu.Unlock()
+
+	if err := c.send(ctx, http.MethodPost, &JSONRPCRequest{JSONRPC: "2.0", Method: MethodNotificationInitialized}, nil); err != nil {
+		return nil, fmt.Errorf("initialized notification failed: %w", err)
+	}
+	return &result, nil
+}
+
+// Ping sends a ping request in the current session.
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.call(ctx, MethodPing, nil, nil); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// ListTools returns the tools of every page of tools/list, following
+// nextCursor for at most MaxListPages pages. Tools are kept as raw JSON
+// fields so that they can be forwarded unchanged.
+func (c *Client) ListTools(ctx context.Context) ([]map[string]json.RawMessage, error) {
+	var tools []map[string]json.RawMessage
+	cursor := ""
+	for range MaxListPages {
+		var params any
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+
+		var result struct {
+			Tools      []map[string]json.RawMessage `json:"tools"`
+			NextCursor string                       `json:"nextCursor"`
+		}
+		if err := c.call(ctx, MethodToolsList, params, &result); err != nil {
+			return nil, fmt.Errorf("tools/list failed: %w", err)
+		}
+		tools = append(tools, result.Tools...)
+
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	return tools, nil
+}
+
+// Call sends a request in the current session and returns its response,
+// including a JSON-RPC error the server answered with.
+func (c *Client) Call(ctx context.Context, method string, params json.RawMessage) (*JSONRPCResponse, error) {
+	request := JSONRPCRequest{JSONRPC: "2.0", Method: method, Params: params, ID: c.requestID.Add(1)}
+
+	var response JSONRPCResponse
+	if err := c.send(ctx, http.MethodPost, &request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Close ends the current session, if the server started one.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	sessionID := c.sessionID
+	c.mu.Unlock()
+	defer c.Reset()
+
+	if sessionID == "" {
+		return nil
+	}
+	return c.send(ctx, http.MethodDelete, nil, nil)
+}
+
+// Reset forgets the current session without ending it on the server.
+func (c *Client) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessionID = ""
+	c.protocolVersion = ""
+}
+
+// call sends a request and decodes the result of its response into result,
+// when not nil. A JSON-RPC error is returned as an error.
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	var encoded json.RawMessage
+	if params != nil {
+		var err error
+		if encoded, err = json.Marshal(params); err != nil {
+			return fmt.Errorf("failed to marshal params: %w", err)
+		}
+	}
+
+	response, err := c.Call(ctx, method, encoded)
+	if err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return fmt.Errorf("JSON-RPC error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("failed to decode result: %w", err)
+		}
+	}
+	return nil
+}
+
+// send sends a message, or no body for a DELETE, and reads the response
+// answering it into response, when not nil. Responses are accepted as JSON
+// or as the events of a stream.
+func (c *Client) send(ctx context.Context, method string, message *JSONRPCRequest, response *JSONRPCResponse) error {
+	var body io.Reader
+	if message != nil {
+		encoded, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	c.mu.Lock()
+	if c.sessionID != "" {
+		req.Header.Set(HeaderSessionID, c.sessionID)
+	}
+	if c.protocolVersion != "" {
+		req.Header.Set(HeaderProtocolVersion, c.protocolVersion)
+	}
+	c.mu.Unlock()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if sessionID := resp.Header.Get(HeaderSessionID); sessionID != "" {
+		c.mu.Lock()
+		c.sessionID = sessionID
+		c.mu.Unlock()
+	}
+
+	limited := io.LimitReader(resp.Body, maxResponseBytes)
+	switch {
+	case resp.StatusCode == http.StatusNotFound && req.Header.Get(HeaderSessionID) != "":
+		return ErrSessionExpired
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		data, _ := io.ReadAll(limited)
+		return fmt.Errorf("HTTP error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	case response == nil:
+		return nil
+	case strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"):
+		return readEventResponse(limited, message.ID, response)
+	}
+
+	data, err := io.ReadAll(limited)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(data, response); err != nil || fmt.Sprint(response.ID) != fmt.Sprint(message.ID) {
+		return fmt.Errorf("no response to request %v", message.ID)
+	}
+	return nil
+}
+
+// readEventResponse reads the events of a stream until one carries the
+// response to the request with the given ID, skipping the notifications and
+// requests the server sends before it. The stream is not read further, since
+// servers may keep it open.
+func readEventResponse(r io.Reader, id any, response *JSONRPCResponse) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxResponseBytes)
+
+	var data []string
+	matches := func() bool {
+		var candidate JSONRPCResponse
+		payload := strings.Join(data, "\n")
+		data = nil
+		if err := json.Unmarshal([]byte(payload), &candidate); err != nil || fmt.Sprint(candidate.ID) != fmt.Sprint(id) {
+			return false
+		}
+		*response = candidate
+		return true
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		} else if line == "" && len(data) > 0 && matches() {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(data) > 0 && matches() {
+		return nil
+	}
+	return fmt.Errorf("no response to request %v", id)
+}
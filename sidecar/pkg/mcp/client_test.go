@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestServer returns a Streamable HTTP MCP server answering initialize
+// with a session, and ping and tools/list in that session as event streams.
+// tools/list returns one tool per page over two pages and keeps its stream open.
+func newTestServer(t *testing.T, sessions map[string]bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := r.Header.Get(HeaderSessionID)
+		if r.Method == http.MethodDelete {
+			delete(sessions, session)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var req JSONRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch req.Method {
+		case MethodInitialize:
+			session = fmt.Sprintf("session-%d", len(sessions)+1)
+			sessions[session] = true
+			w.Header().Set(HeaderSessionID, session)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%v,"result":{"protocolVersion":"2025-03-26","serverInfo":{"name":"test","version":"1.0"}}}`, req.ID)
+		case MethodNotificationInitialized:
+			w.WriteHeader(http.StatusAccepted)
+		case MethodPing:
+			if !sessions[session] {
+				http.Error(w, "unknown session", http.StatusNotFound)
+				return
+			}
+			if r.Header.Get(HeaderProtocolVersion) != "2025-03-26" {
+				http.Error(w, "missing protocol version", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":%v,\"result\":{}}\n\n", req.ID)
+		case MethodToolsList:
+			result := `{"tools":[{"name":"forecast"}],"nextCursor":"page-2"}`
+			if bytes.Contains(req.Params, []byte(`"page-2"`)) {
+				result = `{"tools":[{"name":"alerts","annotations":{"readOnlyHint":true}}]}`
+			}
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\n")
+			fmt.Fprintf(w, "data: {\"jsonrpc\":\"2.0\",\"id\":%v,\"result\":%s}\n\n", req.ID, result)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%v,"error":{"code":-32601,"message":"method not found"}}`, req.ID)
+		}
+	}))
+}
+
+func TestClient_InitializeAndPing(t *testing.T) {
+	sessions := map[string]bool{}
+	server := newTestServer(t, sessions)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL+"/mcp", server.Client(), Implementation{Name: "test-client", Version: "1.0"})
+	result, err := client.Initialize(ctx)
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if result.ProtocolVersion != "2025-03-26" {
+		t.Errorf("ProtocolVersion = %q, want %q", result.ProtocolVersion, "2025-03-26")
+	}
+	if result.ServerInfo.Name != "test" {
+		t.Errorf("ServerInfo.Name = %q, want %q", result.ServerInfo.Name, "test")
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	if err := client.Close(ctx); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("Sessions left on the server = %v, want none", sessions)
+	}
+}
+
+func TestClient_PingExpiredSession(t *testing.T) {
+	sessions := map[string]bool{}
+	server := newTestServer(t, sessions)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL, server.Client(), Implementation{Name: "test-client", Version: "1.0"})
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// The server restarted and forgot the session
+	clear(sessions)
+	if err := client.Ping(ctx); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Ping() error = %v, want %v", err, ErrSessionExpired)
+	}
+}
+
+func TestClient_ListToolsFollowsNextCursor(t *testing.T) {
+	server := newTestServer(t, map[string]bool{})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL, server.Client(), Implementation{Name: "test-client", Version: "1.0"})
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools() error = %v", err)
+	}
+	if len(tools) != 2 || string(tools[0]["name"]) != `"forecast"` || string(tools[1]["name"]) != `"alerts"` {
+		t.Fatalf("ListTools() = %v, want forecast and alerts", tools)
+	}
+	if got := string(tools[1]["annotations"]); got != `{"readOnlyHint":true}` {
+		t.Errorf("annotations = %s, want the raw field of the server", got)
+	}
+}
+
+func TestClient_CallReturnsJSONRPCError(t *testing.T) {
+	server := newTestServer(t, map[string]bool{})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(server.URL, server.Client(), Implementation{Name: "test-client", Version: "1.0"})
+	response, err := client.Call(ctx, MethodToolsCall, json.RawMessage(`{"name":"forecast"}`))
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if response.Error == nil || response.Error.Code != -32601 {
+		t.Errorf("Call() error response = %+v, want code -32601", response.Error)
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	client := NewClient(server.URL, server.Client(), Implementation{Name: "test-client", Version: "1.0"})
+	if _, err := client.Initialize(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Initialize() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
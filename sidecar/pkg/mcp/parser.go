@@ -26,6 +26,9 @@ type ParsedRequest struct {
 	// ToolName is the tool name extracted from tools/call params.
 	ToolName string
 
+	// Arguments are the raw tool arguments extracted from tools/call params.
+	Arguments json.RawMessage
+
 	// ResourceURI is the resource URI extracted from resources/read params.
 	ResourceURI string
 }
@@ -43,6 +46,9 @@ type ParsedResponse struct {
 
 	// ErrorMessage is the error message if IsError is true.
 	ErrorMessage string
+
+	// IsToolError is true if the result reports a failed tool call (isError: true).
+	IsToolError bool
 }
 
 // ParseRequest parses a JSON-RPC request body and extracts relevant information.
@@ -69,7 +75,7 @@ func ParseRequest(body []byte) (*ParsedRequest, error) {
 	// Extract additional info based on method
 	switch req.Method {
 	case MethodToolsCall:
-		parsed.ToolName = extractToolName(req.Params)
+		parsed.ToolName, parsed.Arguments = extractToolCall(req.Params)
 	case MethodResourcesRead:
 		parsed.ResourceURI = extractResourceURI(req.Params)
 	}
@@ -98,21 +104,28 @@ func ParseResponse(body []byte) (*ParsedResponse, error) {
 		parsed.ErrorMessage = resp.Error.Message
 	}
 
+	if len(resp.Result) > 0 {
+		var result ToolCallResult
+		if json.Unmarshal(resp.Result, &result) == nil {
+			parsed.IsToolError = result.IsError
+		}
+	}
+
 	return parsed, nil
 }
 
-// extractToolName extracts the tool name from tools/call params.
-func extractToolName(params json.RawMessage) string {
+// extractToolCall extracts the tool name and arguments from tools/call params.
+func extractToolCall(params json.RawMessage) (string, json.RawMessage) {
 	if len(params) == 0 {
-		return ""
+		return "", nil
 	}
 
 	var toolParams ToolCallParams
 	if err := json.Unmarshal(params, &toolParams); err != nil {
-		return ""
+		return "", nil
 	}
 
-	return toolParams.Name
+	return toolParams.Name, toolParams.Arguments
 }
 
 // extractResourceURI extracts the resource URI from resources/read params.
@@ -75,6 +75,10 @@ func TestParseRequest_ToolsCall(t *testing.T) {
 	if parsed.ToolName != "get_weather" {
 		t.Errorf("ToolName = %q, want 'get_weather'", parsed.ToolName)
 	}
+
+	if string(parsed.Arguments) != `{"location": "San Francisco"}` {
+		t.Errorf("Arguments = %s, want the raw arguments object", parsed.Arguments)
+	}
 }
 
 func TestParseRequest_ResourcesRead(t *testing.T) {
@@ -202,6 +206,27 @@ func TestParseResponse_Success(t *testing.T) {
 	}
 }
 
+func TestParseResponse_ToolError(t *testing.T) {
+	body := []byte(`{
+		"jsonrpc": "2.0",
+		"result": {"content": [{"type": "text", "text": "city not found"}], "isError": true},
+		"id": 7
+	}`)
+
+	parsed, err := ParseResponse(body)
+	if err != nil {
+		t.Fatalf("ParseResponse failed: %v", err)
+	}
+
+	if parsed.IsError {
+		t.Error("Expected IsError to be false for a tool error")
+	}
+
+	if !parsed.IsToolError {
+		t.Error("Expected IsToolError to be true")
+	}
+}
+
 func TestParseResponse_Error(t *testing.T) {
 	body := []byte(`{
 		"jsonrpc": "2.0",
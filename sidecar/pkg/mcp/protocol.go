@@ -1,4 +1,5 @@
-// Package mcp provides types and utilities for parsing MCP (Model Context Protocol) messages.
+// Package mcp provides types and utilities for parsing MCP (Model Context Protocol) messages,
+// and a minimal client used to check MCP servers.
 package mcp
 
 import "encoding/json"
@@ -12,6 +13,10 @@ const (
 	MethodResourcesRead = "resources/read"
 	MethodPromptsList   = "prompts/list"
 	MethodPromptsGet    = "prompts/get"
+	MethodPing          = "ping"
+
+	// MethodNotificationInitialized completes the initialization handshake.
+	MethodNotificationInitialized = "notifications/initialized"
 )
 
 // JSONRPCRequest represents a JSON-RPC 2.0 request message.
@@ -66,6 +71,13 @@ type ToolCallParams struct {
 	Arguments json.RawMessage `json:"arguments,omitempty"`
 }
 
+// ToolCallResult represents the part of a tools/call result the proxy inspects.
+type ToolCallResult struct {
+	// IsError is true if the tool call failed. Tool failures are reported in
+	// the result rather than as a JSON-RPC error.
+	IsError bool `json:"isError,omitempty"`
+}
+
 // ResourceReadParams represents the parameters for a resources/read request.
 type ResourceReadParams struct {
 	// URI is the URI of the resource to read.
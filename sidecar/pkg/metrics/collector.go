@@ -44,12 +44,21 @@ type Instruments struct {
 	// ToolCallsTotal counts total tool call requests by tool name.
 	ToolCallsTotal metric.Int64Counter
 
+	// ToolCallDuration tracks the duration of answered tool calls in seconds by tool name.
+	ToolCallDuration metric.Float64Histogram
+
+	// ToolErrorsTotal counts failed tool calls by tool name and error type.
+	ToolErrorsTotal metric.Int64Counter
+
 	// ResourceReadsTotal counts total resource read requests by resource URI.
 	ResourceReadsTotal metric.Int64Counter
 
 	// RequestErrorsTotal counts total JSON-RPC error responses by method and error code.
 	RequestErrorsTotal metric.Int64Counter
 
+	// RPCDuration tracks the time from a JSON-RPC request to its response in seconds by method.
+	RPCDuration metric.Float64Histogram
+
 	// SSEConnectionsTotal counts total SSE connections opened.
 	SSEConnectionsTotal metric.Int64Counter
 
@@ -61,6 +70,27 @@ type Instruments struct {
 
 	// SSEConnectionDuration tracks SSE connection duration in seconds.
 	SSEConnectionDuration metric.Float64Histogram
+
+	// PolicyDenialsTotal counts requests denied by policy by MCP method.
+	PolicyDenialsTotal metric.Int64Counter
+
+	// AuthRequestsTotal counts authenticated requests by client identity and MCP method.
+	AuthRequestsTotal metric.Int64Counter
+
+	// AuthFailuresTotal counts requests rejected by authentication by reason.
+	AuthFailuresTotal metric.Int64Counter
+
+	// RateLimitedTotal counts requests rejected by a rate limit by rule and MCP method.
+	RateLimitedTotal metric.Int64Counter
+
+	// RouterBackends tracks the number of ready pods the session router routes to.
+	RouterBackends metric.Int64Gauge
+
+	// RouterSessions tracks the number of sessions the session router has pinned to a pod.
+	RouterSessions metric.Int64Gauge
+
+	// RouterSessionsDroppedTotal counts sessions forgotten because their pod went away.
+	RouterSessionsDroppedTotal metric.Int64Counter
 }
 
 // NewInstruments creates all metric instruments using the provided meter.
@@ -122,6 +152,25 @@ func NewInstruments(meter metric.Meter) (*Instruments, error) {
 		return nil, err
 	}
 
+	toolCallDuration, err := meter.Float64Histogram(
+		"mcp.tool_call.duration",
+		metric.WithDescription("Duration of answered tool calls in seconds by tool name."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(DefaultDurationBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	toolErrorsTotal, err := meter.Int64Counter(
+		"mcp.tool_errors.total",
+		metric.WithDescription("Total number of failed tool calls by tool name and error type."),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	resourceReadsTotal, err := meter.Int64Counter(
 		"mcp.resource_reads.total",
 		metric.WithDescription("Total number of resource read requests by resource URI."),
@@ -140,6 +189,16 @@ func NewInstruments(meter metric.Meter) (*Instruments, error) {
 		return nil, err
 	}
 
+	rpcDuration, err := meter.Float64Histogram(
+		"mcp.rpc.duration",
+		metric.WithDescription("Duration from a JSON-RPC request to its response in seconds by MCP method."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(DefaultDurationBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	sseConnectionsTotal, err := meter.Int64Counter(
 		"mcp.sse.connections.total",
 		metric.WithDescription("Total number of SSE connections opened."),
@@ -177,6 +236,69 @@ func NewInstruments(meter metric.Meter) (*Instruments, error) {
 		return nil, err
 	}
 
+	policyDenialsTotal, err := meter.Int64Counter(
+		"mcp.policy.denials.total",
+		metric.WithDescription("Total number of requests denied by policy by MCP method."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	authRequestsTotal, err := meter.Int64Counter(
+		"mcp.auth.requests.total",
+		metric.WithDescription("Total number of authenticated requests by client identity and MCP method."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	authFailuresTotal, err := meter.Int64Counter(
+		"mcp.auth.failures.total",
+		metric.WithDescription("Total number of requests rejected by authentication by reason."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimitedTotal, err := meter.Int64Counter(
+		"mcp.ratelimit.rejections.total",
+		metric.WithDescription("Total number of requests rejected by a rate limit by rule and MCP method."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routerBackends, err := meter.Int64Gauge(
+		"mcp.router.backends",
+		metric.WithDescription("Number of ready pods the session router routes to."),
+		metric.WithUnit("{pod}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routerSessions, err := meter.Int64Gauge(
+		"mcp.router.sessions",
+		metric.WithDescription("Number of sessions the session router has pinned to a pod."),
+		metric.WithUnit("{session}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routerSessionsDroppedTotal, err := meter.Int64Counter(
+		"mcp.router.sessions.dropped.total",
+		metric.WithDescription("Total number of sessions forgotten because their pod went away."),
+		metric.WithUnit("{session}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Instruments{
 		RequestsTotal:         requestsTotal,
 		RequestDuration:       requestDuration,
@@ -184,11 +306,22 @@ func NewInstruments(meter metric.Meter) (*Instruments, error) {
 		ResponseSize:          responseSize,
 		ActiveConnections:     activeConnections,
 		ToolCallsTotal:        toolCallsTotal,
+		ToolCallDuration:      toolCallDuration,
+		ToolErrorsTotal:       toolErrorsTotal,
 		ResourceReadsTotal:    resourceReadsTotal,
 		RequestErrorsTotal:    requestErrorsTotal,
+		RPCDuration:           rpcDuration,
 		SSEConnectionsTotal:   sseConnectionsTotal,
 		SSEConnectionsActive:  sseConnectionsActive,
 		SSEEventsTotal:        sseEventsTotal,
 		SSEConnectionDuration: sseConnectionDuration,
+		PolicyDenialsTotal:    policyDenialsTotal,
+		AuthRequestsTotal:     authRequestsTotal,
+		AuthFailuresTotal:     authFailuresTotal,
+		RateLimitedTotal:      rateLimitedTotal,
+
+		RouterBackends:             routerBackends,
+		RouterSessions:             routerSessions,
+		RouterSessionsDroppedTotal: routerSessionsDroppedTotal,
 	}, nil
 }
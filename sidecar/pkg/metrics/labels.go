@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// labelRecomputeInterval is how often a labelLimiter recomputes its busiest values.
+const labelRecomputeInterval = 5 * time.Minute
+
+// labelTrackingFactor bounds the values a labelLimiter counts to this many
+// times its label limit.
+const labelTrackingFactor = 10
+
+// labelLimiter bounds the number of distinct values of a label. It counts how
+// often each value is recorded and, every labelRecomputeInterval, gives the
+// maxLabels busiest values their own label. Other values share the other label,
+// so a server with thousands of dynamic tools cannot create thousands of series.
+//
+// Counts are halved at every recomputation, so values that become busy later
+// take over from values that are no longer used. Until the first recomputation
+// fills the set, new values get their own label straight away.
+type labelLimiter struct {
+	mu        sync.Mutex
+	maxLabels int
+	other     string
+	now       func() time.Time
+
+	// top holds the values recorded with their own label
+	top map[string]struct{}
+	// counts holds the recent number of records of up to
+	// labelTrackingFactor*maxLabels values
+	counts        map[string]uint64
+	lastRecompute time.Time
+}
+
+// newLabelLimiter creates a labelLimiter allowing maxLabels distinct values.
+// Further values are recorded as other.
+func newLabelLimiter(maxLabels int, other string) *labelLimiter {
+	return &labelLimiter{
+		maxLabels:     maxLabels,
+		other:         other,
+		now:           time.Now,
+		top:           make(map[string]struct{}),
+		counts:        make(map[string]uint64),
+		lastRecompute: time.Now(),
+	}
+}
+
+// label counts a record of value and returns the label to record it as.
+func (l *labelLimiter) label(value string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Once the tracked values are full, new values are counted after the
+	// next recomputation has dropped idle ones
+	if _, ok := l.counts[value]; ok || len(l.counts) < labelTrackingFactor*l.maxLabels {
+		l.counts[value]++
+	}
+
+	if now := l.now(); now.Sub(l.lastRecompute) >= labelRecomputeInterval {
+		l.recompute()
+		l.lastRecompute = now
+	}
+
+	if _, ok := l.top[value]; ok {
+		return value
+	}
+	if len(l.top) < l.maxLabels {
+		l.top[value] = struct{}{}
+		return value
+	}
+	return l.other
+}
+
+// recompute gives the busiest values their own label and halves all counts,
+// dropping values that were not recorded recently. Callers hold l.mu.
+func (l *labelLimiter) recompute() {
+	values := make([]string, 0, len(l.counts))
+	for value := range l.counts {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if l.counts[values[i]] != l.counts[values[j]] {
+			return l.counts[values[i]] > l.counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	l.top = make(map[string]struct{}, l.maxLabels)
+	for _, value := range values[:min(len(values), l.maxLabels)] {
+		l.top[value] = struct{}{}
+	}
+
+	for value, count := range l.counts {
+		if count/2 == 0 {
+			delete(l.counts, value)
+			continue
+		}
+		l.counts[value] = count / 2
+	}
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLabelLimiter_KeepsBusiestValues(t *testing.T) {
+	limiter := newLabelLimiter(2, OtherToolLabel)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	// The first values get their own label until the limit is reached
+	for _, value := range []string{"early_1", "early_2"} {
+		if got := limiter.label(value); got != value {
+			t.Errorf("label(%q) = %q, want its own label", value, got)
+		}
+	}
+	if got := limiter.label("busy"); got != OtherToolLabel {
+		t.Errorf("label(busy) = %q, want %q before the recomputation", got, OtherToolLabel)
+	}
+
+	// A value that becomes busy takes over from the idle ones
+	for range 10 {
+		limiter.label("busy")
+	}
+	limiter.label("early_2")
+	now = now.Add(labelRecomputeInterval)
+
+	if got := limiter.label("busy"); got != "busy" {
+		t.Errorf("label(busy) = %q, want its own label after the recomputation", got)
+	}
+	if got := limiter.label("early_2"); got != "early_2" {
+		t.Errorf("label(early_2) = %q, want its own label", got)
+	}
+	if got := limiter.label("early_1"); got != OtherToolLabel {
+		t.Errorf("label(early_1) = %q, want %q once idle", got, OtherToolLabel)
+	}
+}
+
+func TestLabelLimiter_BoundsTrackedValues(t *testing.T) {
+	limiter := newLabelLimiter(1, OtherToolLabel)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	for i := range 100 {
+		limiter.label(fmt.Sprintf("tool_%d", i))
+	}
+	if len(limiter.counts) != labelTrackingFactor {
+		t.Errorf("tracked values = %d, want %d", len(limiter.counts), labelTrackingFactor)
+	}
+
+	// Recomputing drops the values recorded once
+	now = now.Add(labelRecomputeInterval)
+	limiter.label("tool_0")
+	if len(limiter.counts) != 1 {
+		t.Errorf("tracked values = %d after the recomputation, want 1", len(limiter.counts))
+	}
+}
@@ -14,6 +14,30 @@ import (
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
+// DefaultMaxToolLabels is the default number of tools given their own tool_name label.
+const DefaultMaxToolLabels = 100
+
+// OtherToolLabel is the tool_name label of the tools beyond the label limit.
+const OtherToolLabel = "other"
+
+// DefaultMaxIdentityLabels is the number of client identities given their own identity label.
+const DefaultMaxIdentityLabels = 100
+
+// OtherIdentityLabel is the identity label of the clients beyond the label limit.
+const OtherIdentityLabel = "other"
+
+// Types of tool call errors.
+const (
+	// ToolErrorJSONRPC is a tool call answered with a JSON-RPC error.
+	ToolErrorJSONRPC = "jsonrpc"
+
+	// ToolErrorResult is a tool call whose result reports a failure (isError: true).
+	ToolErrorResult = "tool"
+
+	// ToolErrorHTTP is a tool call that failed with an HTTP error status.
+	ToolErrorHTTP = "http"
+)
+
 // Recorder provides methods to record metrics for the MCP proxy.
 type Recorder struct {
 	instruments   *Instruments
@@ -21,6 +45,8 @@ type Recorder struct {
 	registry      *prom.Registry
 	versionAttr   attribute.KeyValue
 	targetAttr    attribute.KeyValue
+	tools         *labelLimiter
+	identities    *labelLimiter
 }
 
 // NewRecorder creates a new Recorder with the given version and target.
@@ -73,9 +99,18 @@ func NewRecorder(version, target string) (*Recorder, error) {
 		registry:      registry,
 		versionAttr:   attribute.String("version", version),
 		targetAttr:    attribute.String("target", target),
+		tools:         newLabelLimiter(DefaultMaxToolLabels, OtherToolLabel),
+		identities:    newLabelLimiter(DefaultMaxIdentityLabels, OtherIdentityLabel),
 	}, nil
 }
 
+// SetMaxToolLabels sets how many of the most called tools are given their own
+// tool_name label. Other tools are recorded as OtherToolLabel. It must be called before
+// any tool is recorded.
+func (r *Recorder) SetMaxToolLabels(maxLabels int) {
+	r.tools = newLabelLimiter(maxLabels, OtherToolLabel)
+}
+
 // Handler returns an http.Handler that serves the Prometheus metrics endpoint.
 func (r *Recorder) Handler() http.Handler {
 	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{
@@ -127,7 +162,29 @@ func (r *Recorder) RecordRequest(ctx context.Context, method string, status int,
 // RecordToolCall records a tool call request.
 func (r *Recorder) RecordToolCall(ctx context.Context, toolName string) {
 	r.instruments.ToolCallsTotal.Add(ctx, 1, metric.WithAttributes(
-		attribute.String("tool_name", toolName),
+		attribute.String("tool_name", r.tools.label(toolName)),
+	))
+}
+
+// RecordToolResult records the duration of an answered tool call and, when
+// errorType is not empty, its failure.
+func (r *Recorder) RecordToolResult(ctx context.Context, toolName string, duration time.Duration, errorType string) {
+	toolAttr := attribute.String("tool_name", r.tools.label(toolName))
+	r.instruments.ToolCallDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(toolAttr))
+
+	if errorType != "" {
+		r.instruments.ToolErrorsTotal.Add(ctx, 1, metric.WithAttributes(
+			toolAttr,
+			attribute.String("error_type", errorType),
+		))
+	}
+}
+
+// RecordRPCDuration records the time from a JSON-RPC request to its response,
+// whether the response came in the same exchange or later on a stream.
+func (r *Recorder) RecordRPCDuration(ctx context.Context, method string, duration time.Duration) {
+	r.instruments.RPCDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		attribute.String("method", method),
 	))
 }
 
@@ -146,6 +203,49 @@ func (r *Recorder) RecordError(ctx context.Context, method string, errorCode int
 	))
 }
 
+// RecordPolicyDenial records a request denied by policy.
+func (r *Recorder) RecordPolicyDenial(ctx context.Context, method string) {
+	r.instruments.PolicyDenialsTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("method", method),
+	))
+}
+
+// RecordAuthenticatedRequest records a request from an authenticated client.
+// Identities beyond the DefaultMaxIdentityLabels busiest are recorded as OtherIdentityLabel,
+// since JWT subjects can be unbounded; logs and spans keep the full identity.
+func (r *Recorder) RecordAuthenticatedRequest(ctx context.Context, identity, method string) {
+	r.instruments.AuthRequestsTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("identity", r.identities.label(identity)),
+		attribute.String("method", method),
+	))
+}
+
+// RecordAuthFailure records a request rejected by authentication.
+func (r *Recorder) RecordAuthFailure(ctx context.Context, reason string) {
+	r.instruments.AuthFailuresTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("reason", reason),
+	))
+}
+
+// RecordRateLimited records a request rejected by a rate limit.
+func (r *Recorder) RecordRateLimited(ctx context.Context, rule, method string) {
+	r.instruments.RateLimitedTotal.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("rule", rule),
+		attribute.String("method", method),
+	))
+}
+
+// RecordRouterState records the pods and sessions known to the session router.
+func (r *Recorder) RecordRouterState(ctx context.Context, backends, sessions int) {
+	r.instruments.RouterBackends.Record(ctx, int64(backends))
+	r.instruments.RouterSessions.Record(ctx, int64(sessions))
+}
+
+// RecordRouterSessionsDropped records sessions forgotten because their pod went away.
+func (r *Recorder) RecordRouterSessionsDropped(ctx context.Context, count int) {
+	r.instruments.RouterSessionsDroppedTotal.Add(ctx, int64(count))
+}
+
 // IncrementConnections increments the active connections counter.
 func (r *Recorder) IncrementConnections(ctx context.Context) {
 	r.instruments.ActiveConnections.Add(ctx, 1)
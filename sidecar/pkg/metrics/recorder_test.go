@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http/httptest"
 	"strings"
@@ -201,6 +202,39 @@ func TestRecorder_ActiveConnections(t *testing.T) {
 	}
 }
 
+func TestRecorder_RouterState(t *testing.T) {
+	recorder, err := NewRecorder("1.0.0", "my-server")
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+
+	ctx := context.Background()
+	recorder.RecordRouterState(ctx, 3, 12)
+	recorder.RecordRouterState(ctx, 2, 8)
+	recorder.RecordRouterSessionsDropped(ctx, 4)
+
+	rr := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	metrics := rr.Body.String()
+
+	for _, want := range []string{"mcp_router_backends", "mcp_router_sessions", "mcp_router_sessions_dropped_total"} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("%s metric not found:\n%s", want, metrics)
+		}
+	}
+	for _, line := range strings.Split(metrics, "\n") {
+		switch {
+		case strings.HasPrefix(line, "mcp_router_backends{") && !strings.HasSuffix(line, "} 2"):
+			t.Errorf("mcp_router_backends is not the last recorded value: %s", line)
+		case strings.HasPrefix(line, "mcp_router_sessions{") && !strings.HasSuffix(line, "} 8"):
+			t.Errorf("mcp_router_sessions is not the last recorded value: %s", line)
+		case strings.HasPrefix(line, "mcp_router_sessions_dropped_total{") && !strings.HasSuffix(line, "} 4"):
+			t.Errorf("mcp_router_sessions_dropped_total = %s, want 4", line)
+		}
+	}
+}
+
 func TestRecorder_HistogramBuckets(t *testing.T) {
 	recorder, err := NewRecorder("1.0.0", "http://localhost:3001")
 	if err != nil {
@@ -334,3 +368,88 @@ func TestRecorder_Shutdown(t *testing.T) {
 		t.Errorf("Shutdown failed: %v", err)
 	}
 }
+
+func TestRecorder_ToolResults(t *testing.T) {
+	recorder, err := NewRecorder("1.0.0", "my-server")
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+	recorder.SetMaxToolLabels(2)
+
+	ctx := context.Background()
+	recorder.RecordToolResult(ctx, "forecast", 20*time.Millisecond, "")
+	recorder.RecordToolResult(ctx, "forecast", 30*time.Millisecond, ToolErrorResult)
+	recorder.RecordToolResult(ctx, "alerts", 5*time.Millisecond, ToolErrorJSONRPC)
+	for _, tool := range []string{"dynamic_1", "dynamic_2", "dynamic_3"} {
+		recorder.RecordToolCall(ctx, tool)
+		recorder.RecordToolResult(ctx, tool, time.Millisecond, ToolErrorHTTP)
+	}
+
+	rr := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	metrics := rr.Body.String()
+
+	want := map[string]string{
+		`mcp_tool_call_duration_seconds_count{`:       `tool_name="forecast"`,
+		`mcp_tool_errors_total{error_type="tool",`:    `tool_name="forecast"`,
+		`mcp_tool_errors_total{error_type="jsonrpc",`: `tool_name="alerts"`,
+		`mcp_tool_errors_total{error_type="http",`:    `tool_name="other"`,
+		`mcp_tool_calls_total{`:                       `tool_name="other"`,
+	}
+	for prefix, label := range want {
+		found := false
+		for _, line := range strings.Split(metrics, "\n") {
+			if strings.HasPrefix(line, prefix) && strings.Contains(line, label) {
+				found = true
+				if prefix == `mcp_tool_calls_total{` && !strings.HasSuffix(line, " 3") {
+					t.Errorf("tools beyond the limit were not all recorded as other: %s", line)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("no %s line with %s:\n%s", prefix, label, metrics)
+		}
+	}
+
+	if strings.Contains(metrics, `tool_name="dynamic_`) {
+		t.Error("tools beyond the label limit got their own label")
+	}
+}
+
+func TestRecorder_AuthenticatedRequestIdentities(t *testing.T) {
+	recorder, err := NewRecorder("1.0.0", "my-server")
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+
+	ctx := context.Background()
+	for i := range DefaultMaxIdentityLabels + 5 {
+		recorder.RecordAuthenticatedRequest(ctx, fmt.Sprintf("user-%03d", i), "tools/call")
+	}
+
+	rr := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	metrics := rr.Body.String()
+
+	if !strings.Contains(metrics, `identity="user-000"`) {
+		t.Errorf("first identity has no label of its own:\n%s", metrics)
+	}
+	if strings.Contains(metrics, fmt.Sprintf(`identity="user-%03d"`, DefaultMaxIdentityLabels)) {
+		t.Error("identities beyond the label limit got their own label")
+	}
+
+	found := false
+	for _, line := range strings.Split(metrics, "\n") {
+		if strings.HasPrefix(line, "mcp_auth_requests_total{") && strings.Contains(line, `identity="other"`) {
+			found = true
+			if !strings.HasSuffix(line, " 5") {
+				t.Errorf("identities beyond the limit were not all recorded as other: %s", line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no mcp_auth_requests_total line with identity=\"other\":\n%s", metrics)
+	}
+}
@@ -0,0 +1,389 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
+)
+
+const (
+	// DefaultActivationTimeout is the default time a request waits for the MCP server to become ready.
+	DefaultActivationTimeout = 2 * time.Minute
+
+	// DefaultActivatorPollInterval is the default interval between pod lists while the MCP server starts.
+	DefaultActivatorPollInterval = time.Second
+
+	// activatorBackendTTL is how long a ready pod is used before the pods are listed again.
+	activatorBackendTTL = 5 * time.Second
+)
+
+// Scaler starts the pods of an MCP server scaled to zero.
+type Scaler interface {
+	EndpointSource
+
+	// ScaleUp scales the MCP server up if it has no replicas.
+	ScaleUp(ctx context.Context) error
+}
+
+// ActivatorConfig configures the activator mode of the proxy.
+type ActivatorConfig struct {
+	// Scaler scales the MCP server up and lists its ready pods.
+	Scaler Scaler
+
+	// ActivationTimeout is how long a request waits for a ready pod.
+	ActivationTimeout time.Duration
+
+	// PollInterval is the interval between pod lists while the MCP server starts.
+	PollInterval time.Duration
+}
+
+// Activator answers for an MCP server scaled to zero.
+//
+// Requests starting a session, such as initialize, are held while the
+// activator scales the server up, and forwarded to the first pod that
+// becomes ready. Concurrent requests wait for the same activation. Requests
+// of an existing session are answered 404 while no pod runs: sessions do not
+// outlive the pods that created them, so the client initializes again.
+//
+// The operator points the server Service back at the server pods once they
+// are ready. Requests still reaching the activator are forwarded to the
+// first ready pod, so the sessions it created keep working.
+type Activator struct {
+	cfg          ActivatorConfig
+	logger       *slog.Logger
+	reverseProxy *httputil.ReverseProxy
+
+	mu         sync.Mutex
+	backend    string
+	checked    time.Time
+	activation *activation
+}
+
+// activation is a scale up requests wait for.
+type activation struct {
+	done    chan struct{}
+	backend string
+	err     error
+}
+
+type activatorBackendKey struct{}
+
+// NewActivator creates an Activator.
+func NewActivator(cfg ActivatorConfig, logger *slog.Logger) *Activator {
+	if cfg.ActivationTimeout <= 0 {
+		cfg.ActivationTimeout = DefaultActivationTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultActivatorPollInterval
+	}
+
+	a := &Activator{cfg: cfg, logger: logger}
+
+	a.reverseProxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			backend := pr.In.Context().Value(activatorBackendKey{}).(string)
+			pr.SetURL(&url.URL{Scheme: "http", Host: backend})
+			pr.SetXForwarded()
+		},
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 20,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// Flush immediately so SSE responses stream through
+		FlushInterval: -1,
+		ErrorHandler:  a.errorHandler,
+	}
+
+	return a
+}
+
+// NewActivatorProxy creates a Proxy serving the activator, recording
+// metrics around it like any other mode.
+func NewActivatorProxy(listenAddr string, activator *Activator, logger *slog.Logger, recorder *metrics.Recorder) *Proxy {
+	return &Proxy{
+		listenAddr: listenAddr,
+		handler:    activator,
+		logger:     logger,
+		recorder:   recorder,
+	}
+}
+
+// ServeHTTP forwards the request to a ready pod, scaling the MCP server up first if needed.
+func (a *Activator) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var backend string
+	var err error
+	if req.Header.Get(headerSessionID) != "" {
+		backend, err = a.readyBackend(req.Context())
+		if err == nil && backend == "" {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+	} else {
+		backend, err = a.activate(req.Context())
+	}
+	if err != nil {
+		a.logger.Warn("MCP server is not available",
+			slog.String("method", req.Method),
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "MCP server is not available: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := context.WithValue(req.Context(), activatorBackendKey{}, backend)
+	a.reverseProxy.ServeHTTP(w, req.WithContext(ctx))
+}
+
+// cachedBackend returns the last ready pod, if it was seen recently.
+// It must be called with the lock held.
+func (a *Activator) cachedBackend() string {
+	if a.backend != "" && time.Since(a.checked) < activatorBackendTTL {
+		return a.backend
+	}
+	return ""
+}
+
+// readyBackend returns a ready pod without scaling the MCP server, or an
+// empty address when none runs.
+func (a *Activator) readyBackend(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	backend := a.cachedBackend()
+	a.mu.Unlock()
+	if backend != "" {
+		return backend, nil
+	}
+
+	endpoints, err := a.cfg.Scaler.Endpoints(ctx)
+	if err != nil || len(endpoints) == 0 {
+		return "", err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.backend, a.checked = endpoints[0].Address, time.Now()
+	return a.backend, nil
+}
+
+// activate returns a ready pod, waiting for the running activation or
+// starting one when there is none.
+func (a *Activator) activate(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	if backend := a.cachedBackend(); backend != "" {
+		a.mu.Unlock()
+		return backend, nil
+	}
+	act := a.activation
+	if act == nil {
+		act = &activation{done: make(chan struct{})}
+		a.activation = act
+		go a.run(act)
+	}
+	a.mu.Unlock()
+
+	select {
+	case <-act.done:
+		return act.backend, act.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// run completes an activation. It outlives the request that started it, so
+// the pods keep starting when that client gives up.
+func (a *Activator) run(act *activation) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.cfg.ActivationTimeout)
+	defer cancel()
+
+	act.backend, act.err = a.waitForPod(ctx)
+
+	a.mu.Lock()
+	a.activation = nil
+	if act.backend != "" {
+		a.backend, a.checked = act.backend, time.Now()
+	}
+	a.mu.Unlock()
+	close(act.done)
+}
+
+// waitForPod scales the MCP server up if no pod is ready, and waits until one is.
+func (a *Activator) waitForPod(ctx context.Context) (string, error) {
+	ticker := time.NewTicker(a.cfg.PollInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	scaled := false
+	for {
+		endpoints, err := a.cfg.Scaler.Endpoints(ctx)
+		if err == nil && len(endpoints) > 0 {
+			if scaled {
+				a.logger.Info("MCP server activated",
+					slog.String("pod", endpoints[0].Pod),
+					slog.Duration("duration", time.Since(start)),
+				)
+			}
+			return endpoints[0].Address, nil
+		}
+		if err != nil && ctx.Err() == nil {
+			a.logger.Warn("failed to list MCP server pods", slog.String("error", err.Error()))
+		}
+
+		if !scaled && ctx.Err() == nil {
+			if err := a.cfg.Scaler.ScaleUp(ctx); err != nil {
+				a.logger.Warn("failed to scale MCP server up", slog.String("error", err.Error()))
+			} else {
+				scaled = true
+				a.logger.Info("scaling MCP server up")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("no pod became ready within %s", a.cfg.ActivationTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// errorHandler handles pods that cannot be reached. The pod is forgotten,
+// so the next request lists the pods again.
+func (a *Activator) errorHandler(w http.ResponseWriter, req *http.Request, err error) {
+	backend := req.Context().Value(activatorBackendKey{}).(string)
+
+	a.mu.Lock()
+	if a.backend == backend {
+		a.backend = ""
+	}
+	a.mu.Unlock()
+
+	a.logger.Error("activator error",
+		slog.String("method", req.Method),
+		slog.String("pod", backend),
+		slog.String("error", err.Error()),
+	)
+	http.Error(w, "activator error: "+err.Error(), http.StatusBadGateway)
+}
+
+// DeploymentScaler scales a Deployment through its scale subresource and
+// lists its ready pods, using the Kubernetes API with the pod's service account.
+type DeploymentScaler struct {
+	apiURL    string
+	tokenFile string
+	client    *http.Client
+
+	namespace  string
+	deployment string
+	selector   string
+	port       int
+	replicas   int32
+}
+
+// NewInClusterDeploymentScaler creates a DeploymentScaler setting replicas on
+// the Deployment, whose pods are selected by the label selector and serve
+// on port. An empty namespace selects the pod's own.
+func NewInClusterDeploymentScaler(namespace, deployment, selector string, port int, replicas int32) (*DeploymentScaler, error) {
+	api, err := loadInClusterAPI(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeploymentScaler{
+		apiURL:     api.apiURL,
+		tokenFile:  api.tokenFile,
+		client:     api.client,
+		namespace:  api.namespace,
+		deployment: deployment,
+		selector:   selector,
+		port:       port,
+		replicas:   replicas,
+	}, nil
+}
+
+// deploymentScale is the part of an autoscaling/v1 Scale the activator reads.
+type deploymentScale struct {
+	Spec struct {
+		Replicas int32 `json:"replicas"`
+	} `json:"spec"`
+}
+
+// ScaleUp sets the replicas of the Deployment if it has none. A Deployment
+// already scaling, by another activator replica or an autoscaler, is left alone.
+func (s *DeploymentScaler) ScaleUp(ctx context.Context) error {
+	scaleURL := fmt.Sprintf("%s/apis/apps/v1/namespaces/%s/deployments/%s/scale",
+		s.apiURL, url.PathEscape(s.namespace), url.PathEscape(s.deployment))
+
+	var current deploymentScale
+	if err := kubeRequest(ctx, s.client, s.tokenFile, http.MethodGet, scaleURL, nil, &current); err != nil {
+		return fmt.Errorf("failed to get Deployment scale: %w", err)
+	}
+	if current.Spec.Replicas > 0 {
+		return nil
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, s.replicas))
+	if err := kubeRequest(ctx, s.client, s.tokenFile, http.MethodPatch, scaleURL, patch, &current); err != nil {
+		return fmt.Errorf("failed to scale Deployment: %w", err)
+	}
+	return nil
+}
+
+// podList is the part of a v1 PodList the activator reads.
+type podList struct {
+	Items []struct {
+		Metadata struct {
+			Name              string  `json:"name"`
+			DeletionTimestamp *string `json:"deletionTimestamp"`
+		} `json:"metadata"`
+		Status struct {
+			PodIP      string `json:"podIP"`
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// Endpoints lists the ready pods of the Deployment, sorted by address.
+func (s *DeploymentScaler) Endpoints(ctx context.Context) ([]Endpoint, error) {
+	query := url.Values{"labelSelector": {s.selector}}
+	listURL := fmt.Sprintf("%s/api/v1/namespaces/%s/pods?%s", s.apiURL, url.PathEscape(s.namespace), query.Encode())
+
+	var list podList
+	if err := kubeRequest(ctx, s.client, s.tokenFile, http.MethodGet, listURL, nil, &list); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var endpoints []Endpoint
+	for _, pod := range list.Items {
+		if pod.Metadata.DeletionTimestamp != nil || pod.Status.PodIP == "" {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == "Ready" && condition.Status == "True" {
+				endpoints = append(endpoints, Endpoint{
+					Address: net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(s.port)),
+					Pod:     pod.Metadata.Name,
+				})
+				break
+			}
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Address < endpoints[j].Address })
+	return endpoints, nil
+}
@@ -0,0 +1,205 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScaler is a Scaler whose pod becomes ready after a few lists once scaled up.
+type fakeScaler struct {
+	mu       sync.Mutex
+	pod      Endpoint
+	scaleUps int
+	lists    int
+	// readyAfter is the number of lists after the scale up before the pod is ready, -1 for never
+	readyAfter int
+}
+
+func (s *fakeScaler) Endpoints(ctx context.Context) ([]Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scaleUps == 0 {
+		return nil, nil
+	}
+	s.lists++
+	if s.readyAfter < 0 || s.lists <= s.readyAfter {
+		return nil, nil
+	}
+	return []Endpoint{s.pod}, nil
+}
+
+func (s *fakeScaler) ScaleUp(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scaleUps++
+	return nil
+}
+
+func (s *fakeScaler) scaleUpCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scaleUps
+}
+
+func TestActivator_HoldsRequestsUntilReady(t *testing.T) {
+	pod := newSessionPod(t, "server-a")
+	scaler := &fakeScaler{pod: pod.endpoint(), readyAfter: 2}
+	activator := NewActivator(ActivatorConfig{
+		Scaler:       scaler,
+		PollInterval: 10 * time.Millisecond,
+	}, newTestLogger())
+
+	var wg sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 3)
+	for i := range responses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = routerRequest(activator, http.MethodPost, "", `{"method":"initialize"}`)
+		}()
+	}
+	wg.Wait()
+
+	for i, rec := range responses {
+		if rec.Code != http.StatusOK || rec.Body.String() != "server-a" {
+			t.Errorf("response %d = %d %q, want 200 %q", i, rec.Code, rec.Body.String(), "server-a")
+		}
+	}
+	if got := scaler.scaleUpCount(); got != 1 {
+		t.Errorf("scale ups = %d, want 1", got)
+	}
+
+	// Requests of the sessions created through the activator reach their pod
+	sessionID := responses[0].Header().Get(headerSessionID)
+	rec := routerRequest(activator, http.MethodPost, sessionID, "ping")
+	if rec.Code != http.StatusOK || rec.Body.String() != "server-a:ping" {
+		t.Errorf("session request = %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestActivator_SessionRequestWhileScaledToZero(t *testing.T) {
+	scaler := &fakeScaler{readyAfter: 0}
+	activator := NewActivator(ActivatorConfig{Scaler: scaler}, newTestLogger())
+
+	rec := routerRequest(activator, http.MethodPost, "expired-session", "ping")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if got := scaler.scaleUpCount(); got != 0 {
+		t.Errorf("scale ups = %d, want 0", got)
+	}
+}
+
+func TestActivator_Timeout(t *testing.T) {
+	scaler := &fakeScaler{readyAfter: -1}
+	activator := NewActivator(ActivatorConfig{
+		Scaler:            scaler,
+		ActivationTimeout: 50 * time.Millisecond,
+		PollInterval:      10 * time.Millisecond,
+	}, newTestLogger())
+
+	rec := routerRequest(activator, http.MethodPost, "", `{"method":"initialize"}`)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(rec.Body.String(), "no pod became ready") {
+		t.Errorf("body = %q", rec.Body.String())
+	}
+}
+
+func TestDeploymentScaler(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		replicas int32
+		patches  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer pod-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/apis/apps/v1/namespaces/default/deployments/weather/scale":
+			if r.Method == http.MethodPatch {
+				if r.Header.Get("Content-Type") != "application/merge-patch+json" {
+					http.Error(w, "unsupported patch", http.StatusUnsupportedMediaType)
+					return
+				}
+				body, _ := io.ReadAll(r.Body)
+				patches = append(patches, string(body))
+				var scale deploymentScale
+				_ = json.Unmarshal(body, &scale)
+				replicas = scale.Spec.Replicas
+			}
+			_, _ = fmt.Fprintf(w, `{"spec":{"replicas":%d}}`, replicas)
+		case r.URL.Path == "/api/v1/namespaces/default/pods" && r.URL.Query().Get("labelSelector") == "app=weather":
+			_, _ = io.WriteString(w, `{"items":[
+				{"metadata":{"name":"weather-b"},"status":{"podIP":"10.0.0.2","conditions":[{"type":"Ready","status":"True"}]}},
+				{"metadata":{"name":"weather-a"},"status":{"podIP":"10.0.0.1","conditions":[{"type":"Ready","status":"True"}]}},
+				{"metadata":{"name":"weather-c"},"status":{"podIP":"10.0.0.3","conditions":[{"type":"Ready","status":"False"}]}},
+				{"metadata":{"name":"weather-d","deletionTimestamp":"2025-01-01T00:00:00Z"},
+				 "status":{"podIP":"10.0.0.4","conditions":[{"type":"Ready","status":"True"}]}},
+				{"metadata":{"name":"weather-e"},"status":{}}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("pod-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	scaler := &DeploymentScaler{
+		apiURL:     server.URL,
+		tokenFile:  tokenFile,
+		client:     server.Client(),
+		namespace:  "default",
+		deployment: "weather",
+		selector:   "app=weather",
+		port:       8080,
+		replicas:   2,
+	}
+
+	ctx := context.Background()
+	if err := scaler.ScaleUp(ctx); err != nil {
+		t.Fatalf("ScaleUp() error = %v", err)
+	}
+	// A Deployment with replicas is not scaled again
+	if err := scaler.ScaleUp(ctx); err != nil {
+		t.Fatalf("ScaleUp() error = %v", err)
+	}
+	if want := []string{`{"spec":{"replicas":2}}`}; !reflect.DeepEqual(patches, want) {
+		t.Errorf("patches = %v, want %v", patches, want)
+	}
+
+	endpoints, err := scaler.Endpoints(ctx)
+	if err != nil {
+		t.Fatalf("Endpoints() error = %v", err)
+	}
+	want := []Endpoint{
+		{Address: "10.0.0.1:8080", Pod: "weather-a"},
+		{Address: "10.0.0.2:8080", Pod: "weather-b"},
+	}
+	if !reflect.DeepEqual(endpoints, want) {
+		t.Errorf("endpoints = %v, want %v", endpoints, want)
+	}
+}
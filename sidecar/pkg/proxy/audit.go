@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/audit"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+)
+
+// SetAuditLogger enables audit records of tool calls and resource reads.
+// It must be called before Start.
+func (p *Proxy) SetAuditLogger(auditLogger *audit.Logger) {
+	p.auditLogger = auditLogger
+}
+
+// auditRequests writes an audit record for every tools/call and
+// resources/read request of a message or batch, with the status of the
+// response the server sent it.
+func (p *Proxy) auditRequests(req *http.Request, sw *sseAwareWriter, reqBody []byte, identity string,
+	start time.Time, duration time.Duration) {
+	if p.auditLogger == nil || len(reqBody) == 0 {
+		return
+	}
+
+	messages, _ := splitBatch(reqBody)
+	for _, message := range messages {
+		parsed, err := mcp.ParseRequest(message)
+		if err != nil || parsed.IsNotification {
+			continue
+		}
+		if parsed.Method != mcp.MethodToolsCall && parsed.Method != mcp.MethodResourcesRead {
+			continue
+		}
+
+		record := audit.Record{
+			Time:       start.UTC(),
+			Identity:   identity,
+			ClientIP:   getClientIP(req),
+			SessionID:  requestSessionID(req, sw),
+			Method:     parsed.Method,
+			RequestID:  parsed.ID,
+			Tool:       parsed.ToolName,
+			Resource:   parsed.ResourceURI,
+			Arguments:  parsed.Arguments,
+			HTTPStatus: sw.statusCode,
+			DurationMS: float64(duration.Microseconds()) / 1000,
+		}
+		record.Status, record.ErrorCode = auditStatus(sw.Responses()[responseKey(parsed.ID)], sw.statusCode)
+
+		if err := p.auditLogger.Log(record); err != nil {
+			p.logger.Warn("failed to write audit record",
+				slog.String("method", parsed.Method),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// auditStatus returns the status of an audited request and its JSON-RPC
+// error code, from its response or, without one, the HTTP status.
+func auditStatus(resp *mcp.ParsedResponse, httpStatus int) (string, int) {
+	switch {
+	case resp != nil && resp.IsError:
+		return audit.StatusError, resp.ErrorCode
+	case resp != nil && resp.IsToolError:
+		return audit.StatusToolError, 0
+	case resp != nil:
+		return audit.StatusSuccess, 0
+	case httpStatus >= http.StatusBadRequest:
+		return audit.StatusError, 0
+	case httpStatus == http.StatusAccepted:
+		return audit.StatusAccepted, 0
+	}
+	return audit.StatusUnknown, 0
+}
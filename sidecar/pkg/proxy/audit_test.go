@@ -0,0 +1,193 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/audit"
+)
+
+// newAuditedProxy returns an audited handler in front of target and the file
+// its records are written to.
+func newAuditedProxy(t *testing.T, target http.Handler, redactPaths ...string) (http.Handler, string) {
+	t.Helper()
+
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	p, err := New(":0", server.URL, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "audit.jsonl")
+	auditLogger, err := audit.NewLogger(audit.Config{File: file, RedactPaths: redactPaths})
+	if err != nil {
+		t.Fatalf("failed to create audit logger: %v", err)
+	}
+	t.Cleanup(func() { _ = auditLogger.Close() })
+	p.SetAuditLogger(auditLogger)
+
+	return p.metricsMiddleware(p.handler), file
+}
+
+// auditRecords returns the records written to an audit file.
+func auditRecords(t *testing.T, file string) []map[string]any {
+	t.Helper()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read audit file: %v", err)
+	}
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("audit line %q is not JSON: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func postMCP(handler http.Handler, body string, headers map[string]string) {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestAudit_ToolCall(t *testing.T) {
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":3,"result":{"content":[{"type":"text","text":"sunny"}]}}`))
+	})
+	handler, file := newAuditedProxy(t, target, "$.apiKey")
+
+	postMCP(handler,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"forecast","arguments":{"city":"Porto","apiKey":"k"}}}`,
+		map[string]string{headerSessionID: "session-1"})
+
+	records := auditRecords(t, file)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	record := records[0]
+
+	want := map[string]any{
+		"method":      "tools/call",
+		"tool":        "forecast",
+		"session_id":  "session-1",
+		"request_id":  float64(3),
+		"status":      audit.StatusSuccess,
+		"http_status": float64(http.StatusOK),
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+	if args, _ := json.Marshal(record["arguments"]); string(args) != `{"apiKey":"[REDACTED]","city":"Porto"}` {
+		t.Errorf("arguments = %s, want the redacted arguments", args)
+	}
+	if _, ok := record["timestamp"]; !ok {
+		t.Error("record has no timestamp")
+	}
+	if _, ok := record["duration_ms"]; !ok {
+		t.Error("record has no duration")
+	}
+}
+
+func TestAudit_StreamedToolError(t *testing.T) {
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n"))
+		_, _ = w.Write([]byte("event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":\"a\",\"result\":{\"isError\":true}}\n\n"))
+	})
+	handler, file := newAuditedProxy(t, target)
+
+	postMCP(handler, `{"jsonrpc":"2.0","id":"a","method":"tools/call","params":{"name":"forecast"}}`, nil)
+
+	records := auditRecords(t, file)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if records[0]["status"] != audit.StatusToolError {
+		t.Errorf("status = %v, want %s", records[0]["status"], audit.StatusToolError)
+	}
+}
+
+func TestAudit_BatchAndOtherMethods(t *testing.T) {
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[` +
+			`{"jsonrpc":"2.0","id":1,"result":{"tools":[]}},` +
+			`{"jsonrpc":"2.0","id":2,"error":{"code":-32002,"message":"Resource not found"}}]`))
+	})
+	handler, file := newAuditedProxy(t, target)
+
+	postMCP(handler, `[`+
+		`{"jsonrpc":"2.0","id":1,"method":"tools/list"},`+
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"file:///notes.txt"}}]`,
+		nil)
+
+	records := auditRecords(t, file)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want only the resource read", len(records))
+	}
+	record := records[0]
+	if record["resource_uri"] != "file:///notes.txt" {
+		t.Errorf("resource_uri = %v, want file:///notes.txt", record["resource_uri"])
+	}
+	if record["status"] != audit.StatusError || record["error_code"] != float64(-32002) {
+		t.Errorf("status = %v, error_code = %v, want error -32002", record["status"], record["error_code"])
+	}
+}
+
+func TestAudit_ToolCallWithOtherContentType(t *testing.T) {
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[]}}`))
+	})
+	handler, file := newAuditedProxy(t, target)
+
+	postMCP(handler,
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"forecast","arguments":{}}}`,
+		map[string]string{"Content-Type": "text/plain"})
+
+	records := auditRecords(t, file)
+	if len(records) != 1 || records[0]["tool"] != "forecast" {
+		t.Fatalf("records = %v, want the tool call whatever its Content-Type", records)
+	}
+}
+
+func TestAudit_AcceptedOnSSETransport(t *testing.T) {
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler, file := newAuditedProxy(t, target)
+
+	req := httptest.NewRequest(http.MethodPost, "/message?sessionId=abc",
+		strings.NewReader(`{"jsonrpc":"2.0","id":9,"method":"tools/call","params":{"name":"forecast"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	record := auditRecords(t, file)[0]
+	if record["status"] != audit.StatusAccepted {
+		t.Errorf("status = %v, want %s", record["status"], audit.StatusAccepted)
+	}
+	if record["session_id"] != "abc" {
+		t.Errorf("session_id = %v, want the sessionId query parameter", record["session_id"])
+	}
+}
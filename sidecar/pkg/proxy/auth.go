@@ -0,0 +1,385 @@
+package proxy
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ProtectedResourceMetadataPath is the path of the OAuth 2.0 protected
+	// resource metadata document (RFC 9728).
+	ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+	// APIKeyHeader is the request header API keys may be sent in, as an
+	// alternative to "Authorization: Bearer <key>".
+	APIKeyHeader = "X-API-Key"
+
+	// DefaultIdentityClaim is the JWT claim used as the client identity.
+	DefaultIdentityClaim = "sub"
+
+	// apiKeysReloadInterval is how often the API key directory is read again,
+	// so keys rotated in the mounted Secret are picked up.
+	apiKeysReloadInterval = 30 * time.Second
+)
+
+// Reasons a request fails authentication, used as the reason metric label.
+const (
+	authFailureMissingCredentials = "missing_credentials"
+	authFailureInvalidAPIKey      = "invalid_api_key"
+	authFailureInvalidToken       = "invalid_token"
+)
+
+// AuthConfig configures client authentication at the proxy.
+// At least one of APIKeysDir, JWKSURL or JWKSFile must be set.
+type AuthConfig struct {
+	// APIKeysDir is a directory holding one file per client, named after the
+	// client identity and containing its API key, such as a mounted Secret.
+	APIKeysDir string
+
+	// JWKSURL is the URL of the JSON Web Key Set JWTs are verified against.
+	JWKSURL string
+
+	// JWKSFile is a file holding the JSON Web Key Set JWTs are verified against.
+	JWKSFile string
+
+	// Issuer is the required iss claim of JWTs (empty skips the check).
+	Issuer string
+
+	// Audiences are the accepted aud claims of JWTs (empty skips the check).
+	Audiences []string
+
+	// IdentityClaim is the JWT claim used as the client identity.
+	IdentityClaim string
+
+	// AuthorizationServers are the authorization servers published in the
+	// protected resource metadata. The metadata endpoint is served when set.
+	AuthorizationServers []string
+
+	// Resource is the resource identifier published in the protected resource
+	// metadata. When empty it is derived from the request URL.
+	Resource string
+}
+
+// Authenticator verifies the credentials of incoming requests: static API
+// keys, JWTs signed by a key of a JSON Web Key Set, or both.
+type Authenticator struct {
+	config  AuthConfig
+	apiKeys *apiKeyStore
+	jwt     *jwtVerifier
+}
+
+// authError is an authentication failure and its metric reason.
+type authError struct {
+	reason string
+	err    error
+}
+
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+// identityContextKey is the context key of the verified client identity.
+type identityContextKey struct{}
+
+// IdentityFromContext returns the client identity verified by the
+// authenticator, or an empty string if the request was not authenticated.
+func IdentityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(identityContextKey{}).(string)
+	return identity
+}
+
+// NewAuthenticator creates an Authenticator and loads its API keys and JWKS.
+// A JWKS URL that cannot be fetched yet is retried when tokens arrive.
+func NewAuthenticator(cfg AuthConfig, logger *slog.Logger) (*Authenticator, error) {
+	if cfg.APIKeysDir == "" && cfg.JWKSURL == "" && cfg.JWKSFile == "" {
+		return nil, errors.New("authentication requires an API key directory or a JWKS")
+	}
+	if cfg.JWKSURL != "" && cfg.JWKSFile != "" {
+		return nil, errors.New("only one of JWKS URL and JWKS file can be set")
+	}
+	if cfg.IdentityClaim == "" {
+		cfg.IdentityClaim = DefaultIdentityClaim
+	}
+
+	a := &Authenticator{config: cfg}
+
+	if cfg.APIKeysDir != "" {
+		a.apiKeys = &apiKeyStore{dir: cfg.APIKeysDir, now: time.Now}
+		if err := a.apiKeys.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.JWKSURL != "" || cfg.JWKSFile != "" {
+		keys := &keySet{
+			url:    cfg.JWKSURL,
+			file:   cfg.JWKSFile,
+			client: &http.Client{Timeout: 10 * time.Second},
+			now:    time.Now,
+		}
+		if err := keys.load(); err != nil {
+			if cfg.JWKSFile != "" {
+				return nil, err
+			}
+			logger.Warn("failed to fetch JWKS, retrying when tokens arrive",
+				slog.String("jwks_url", cfg.JWKSURL),
+				slog.String("error", err.Error()),
+			)
+		}
+		a.jwt = &jwtVerifier{
+			keys:      keys,
+			issuer:    cfg.Issuer,
+			audiences: cfg.Audiences,
+			now:       time.Now,
+		}
+	}
+
+	return a, nil
+}
+
+// Authenticate verifies the credentials of a request and returns the client
+// identity. API keys are accepted in the X-API-Key header or as a bearer
+// token; other bearer tokens are verified as JWTs.
+func (a *Authenticator) Authenticate(req *http.Request) (string, error) {
+	if key := req.Header.Get(APIKeyHeader); key != "" {
+		if a.apiKeys != nil {
+			if identity, ok := a.apiKeys.lookup(key); ok {
+				return identity, nil
+			}
+		}
+		return "", &authError{reason: authFailureInvalidAPIKey, err: errors.New("invalid API key")}
+	}
+
+	token, ok := bearerToken(req)
+	if !ok {
+		return "", &authError{reason: authFailureMissingCredentials, err: errors.New("missing credentials")}
+	}
+
+	if a.apiKeys != nil {
+		if identity, ok := a.apiKeys.lookup(token); ok {
+			return identity, nil
+		}
+	}
+	if a.jwt == nil {
+		return "", &authError{reason: authFailureInvalidAPIKey, err: errors.New("invalid API key")}
+	}
+
+	claims, err := a.jwt.verify(token)
+	if err != nil {
+		return "", &authError{reason: authFailureInvalidToken, err: err}
+	}
+	identity, _ := claims[a.config.IdentityClaim].(string)
+	if identity == "" {
+		return "", &authError{
+			reason: authFailureInvalidToken,
+			err:    fmt.Errorf("token has no %q claim", a.config.IdentityClaim),
+		}
+	}
+	return identity, nil
+}
+
+// servesMetadata reports whether the protected resource metadata is served.
+func (a *Authenticator) servesMetadata() bool {
+	return len(a.config.AuthorizationServers) > 0
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header.
+func bearerToken(req *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// SetAuthenticator enables authentication of incoming requests.
+func (p *Proxy) SetAuthenticator(a *Authenticator) {
+	p.auth = a
+}
+
+// authMiddleware rejects requests without valid credentials and serves the
+// protected resource metadata. Authenticated requests carry the identity in
+// their context and in the identity header, and their credentials are
+// removed before they are forwarded.
+func (p *Proxy) authMiddleware(next http.Handler) http.Handler {
+	if p.auth == nil {
+		return next
+	}
+
+	identityHeader := DefaultIdentityHeader
+	if p.policy != nil {
+		identityHeader = p.policy.IdentityHeader
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if p.auth.servesMetadata() && strings.HasPrefix(req.URL.Path, ProtectedResourceMetadataPath) {
+			p.serveResourceMetadata(w, req)
+			return
+		}
+
+		identity, err := p.auth.Authenticate(req)
+		if err != nil {
+			reason := authFailureInvalidToken
+			var authErr *authError
+			if errors.As(err, &authErr) {
+				reason = authErr.reason
+			}
+			p.logger.Warn("request rejected by authentication",
+				slog.String("reason", reason),
+				slog.String("error", err.Error()),
+				slog.String("path", req.URL.Path),
+				slog.String("client_ip", getClientIP(req)),
+			)
+			if p.recorder != nil {
+				p.recorder.RecordAuthFailure(req.Context(), reason)
+			}
+			p.writeUnauthorized(w, req, reason)
+			return
+		}
+
+		req.Header.Del("Authorization")
+		req.Header.Del(APIKeyHeader)
+		req.Header.Set(identityHeader, identity)
+
+		next.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), identityContextKey{}, identity)))
+	})
+}
+
+// writeUnauthorized sends a 401 response with a WWW-Authenticate challenge
+// pointing clients at the protected resource metadata.
+func (p *Proxy) writeUnauthorized(w http.ResponseWriter, req *http.Request, reason string) {
+	challenge := "Bearer"
+	var params []string
+	if p.auth.servesMetadata() {
+		params = append(params, fmt.Sprintf("resource_metadata=%q", metadataURL(req)))
+	}
+	if reason == authFailureInvalidToken {
+		params = append(params, `error="invalid_token"`)
+	}
+	if len(params) > 0 {
+		challenge += " " + strings.Join(params, ", ")
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+// serveResourceMetadata serves the OAuth 2.0 protected resource metadata,
+// telling MCP clients which authorization servers issue tokens for the server.
+func (p *Proxy) serveResourceMetadata(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resource := p.auth.config.Resource
+	if resource == "" {
+		resource = requestOrigin(req) + strings.TrimPrefix(req.URL.Path, ProtectedResourceMetadataPath)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"resource":                 resource,
+		"authorization_servers":    p.auth.config.AuthorizationServers,
+		"bearer_methods_supported": []string{"header"},
+	})
+}
+
+// metadataURL returns the protected resource metadata URL for the resource
+// a request was sent to.
+func metadataURL(req *http.Request) string {
+	path := req.URL.Path
+	if path == "/" {
+		path = ""
+	}
+	return requestOrigin(req) + ProtectedResourceMetadataPath + path
+}
+
+// requestOrigin returns the scheme and host the client used to reach the proxy.
+func requestOrigin(req *http.Request) string {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+	if proto := req.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := req.Host
+	if forwarded := req.Header.Get("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	return scheme + "://" + host
+}
+
+// apiKeyStore holds the API keys read from a directory, such as a mounted
+// Secret, where each file is named after a client and holds its key.
+type apiKeyStore struct {
+	dir string
+	now func() time.Time
+
+	mu     sync.Mutex
+	keys   map[string]string
+	loaded time.Time
+}
+
+// load reads the API keys. Hidden entries, which the kubelet uses for its
+// own bookkeeping of mounted Secrets, and empty files are skipped.
+func (s *apiKeyStore) load() error {
+	s.loaded = s.now()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read API keys: %w", err)
+	}
+
+	keys := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			// Directories and unreadable entries are not keys
+			continue
+		}
+		if key := strings.TrimSpace(string(data)); key != "" {
+			keys[name] = key
+		}
+	}
+
+	s.keys = keys
+	return nil
+}
+
+// lookup returns the identity an API key belongs to. Every key is compared
+// in constant time so the timing does not reveal which keys exist.
+func (s *apiKeyStore) lookup(key string) (string, bool) {
+	s.mu.Lock()
+	if s.now().Sub(s.loaded) > apiKeysReloadInterval {
+		// Keep the current keys if the directory cannot be read
+		_ = s.load()
+	}
+	keys := s.keys
+	s.mu.Unlock()
+
+	var identity string
+	for name, candidate := range keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			identity = name
+		}
+	}
+	return identity, identity != ""
+}
@@ -0,0 +1,484 @@
+package proxy
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
+)
+
+// authTarget is an MCP server recording the headers of the last request it received.
+type authTarget struct {
+	mu      sync.Mutex
+	headers http.Header
+}
+
+func (t *authTarget) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.mu.Lock()
+	t.headers = r.Header.Clone()
+	t.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+}
+
+func (t *authTarget) header(name string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.headers.Get(name)
+}
+
+// newAuthProxy returns an authenticating handler in front of target.
+func newAuthProxy(t *testing.T, target http.Handler, cfg AuthConfig, recorder *metrics.Recorder) http.Handler {
+	t.Helper()
+
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	p, err := NewWithRecorder(":0", server.URL, newTestLogger(), recorder)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	auth, err := NewAuthenticator(cfg, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	p.SetAuthenticator(auth)
+
+	return p.authMiddleware(p.metricsMiddleware(p.policyMiddleware(p.reverseProxy)))
+}
+
+// authPost sends an initialize request with the given headers and returns the response.
+func authPost(handler http.Handler, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+// hasMetricLine reports whether a line of Prometheus output starts with prefix and contains label.
+func hasMetricLine(output, prefix, label string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, prefix) && strings.Contains(line, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAPIKeys writes one file per client into a new directory, as a mounted Secret would.
+func writeAPIKeys(t *testing.T, keys map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, key := range keys {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(key), 0o600); err != nil {
+			t.Fatalf("failed to write API key: %v", err)
+		}
+	}
+	return dir
+}
+
+// signToken signs claims as a JWT with an RSA or ECDSA key.
+func signToken(t *testing.T, key crypto.Signer, alg, kid string, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// jwks returns a JSON Web Key Set holding the public keys, by key ID.
+func jwks(t *testing.T, keys map[string]crypto.Signer) []byte {
+	t.Helper()
+
+	encode := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	var set []map[string]string
+	for kid, key := range keys {
+		switch pub := key.Public().(type) {
+		case *rsa.PublicKey:
+			set = append(set, map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig",
+				"n": encode(pub.N), "e": encode(big.NewInt(int64(pub.E))),
+			})
+		case *ecdsa.PublicKey:
+			set = append(set, map[string]string{
+				"kty": "EC", "kid": kid, "crv": "P-256",
+				"x": encode(pub.X), "y": encode(pub.Y),
+			})
+		}
+	}
+	data, _ := json.Marshal(map[string]any{"keys": set})
+	return data
+}
+
+// validClaims returns claims accepted by the test authenticators.
+func validClaims(subject string) map[string]any {
+	return map[string]any{
+		"sub": subject,
+		"iss": "https://auth.example.com",
+		"aud": []string{"https://mcp.example.com"},
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestAuth_APIKeys(t *testing.T) {
+	target := &authTarget{}
+	recorder, err := metrics.NewRecorder("test", "target")
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+
+	dir := writeAPIKeys(t, map[string]string{"alice": "alice-key\n", ".hidden": "hidden-key"})
+	handler := newAuthProxy(t, target, AuthConfig{APIKeysDir: dir}, recorder)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"X-API-Key header", map[string]string{APIKeyHeader: "alice-key"}, http.StatusOK},
+		{"bearer token", map[string]string{"Authorization": "Bearer alice-key"}, http.StatusOK},
+		{"unknown key", map[string]string{APIKeyHeader: "wrong"}, http.StatusUnauthorized},
+		{"hidden file", map[string]string{APIKeyHeader: "hidden-key"}, http.StatusUnauthorized},
+		{"no credentials", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := authPost(handler, tt.headers)
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && !strings.HasPrefix(rr.Header().Get("WWW-Authenticate"), "Bearer") {
+				t.Errorf("WWW-Authenticate = %q, want a Bearer challenge", rr.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	// Credentials are not passed on, the verified identity is
+	authPost(handler, map[string]string{APIKeyHeader: "alice-key", DefaultIdentityHeader: "admin"})
+	if got := target.header(DefaultIdentityHeader); got != "alice" {
+		t.Errorf("forwarded identity = %q, want alice", got)
+	}
+	if target.header(APIKeyHeader) != "" || target.header("Authorization") != "" {
+		t.Error("credentials were forwarded to the server")
+	}
+
+	metricsBody := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(metricsBody, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(metricsBody.Body.String(), `mcp_auth_requests_total{identity="alice",method="initialize"`) {
+		t.Error("expected mcp_auth_requests_total to be recorded for alice")
+	}
+	for _, reason := range []string{authFailureInvalidAPIKey, authFailureMissingCredentials} {
+		if !hasMetricLine(metricsBody.Body.String(), "mcp_auth_failures_total{", `reason="`+reason+`"`) {
+			t.Errorf("expected mcp_auth_failures_total to be recorded with reason %s", reason)
+		}
+	}
+}
+
+func TestAuth_APIKeysReloaded(t *testing.T) {
+	dir := writeAPIKeys(t, map[string]string{"alice": "old-key"})
+	auth, err := NewAuthenticator(AuthConfig{APIKeysDir: dir}, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+
+	now := time.Now()
+	auth.apiKeys.now = func() time.Time { return now }
+
+	if err := os.WriteFile(filepath.Join(dir, "alice"), []byte("new-key"), 0o600); err != nil {
+		t.Fatalf("failed to rotate API key: %v", err)
+	}
+	if _, ok := auth.apiKeys.lookup("old-key"); !ok {
+		t.Error("old key rejected before the reload interval passed")
+	}
+
+	now = now.Add(apiKeysReloadInterval + time.Second)
+	if _, ok := auth.apiKeys.lookup("old-key"); ok {
+		t.Error("old key still accepted after rotation")
+	}
+	if identity, ok := auth.apiKeys.lookup("new-key"); !ok || identity != "alice" {
+		t.Errorf("lookup(new-key) = %q, %v, want alice", identity, ok)
+	}
+}
+
+func TestAuth_JWT(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(jwks(t, map[string]crypto.Signer{"rsa-1": rsaKey}))
+	}))
+	defer jwksServer.Close()
+
+	target := &authTarget{}
+	handler := newAuthProxy(t, target, AuthConfig{
+		JWKSURL:   jwksServer.URL,
+		Issuer:    "https://auth.example.com",
+		Audiences: []string{"https://mcp.example.com"},
+	}, nil)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	with := func(change func(map[string]any)) map[string]any {
+		claims := validClaims("alice")
+		change(claims)
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"valid token", signToken(t, rsaKey, "RS256", "rsa-1", validClaims("alice")), http.StatusOK},
+		{"expired", signToken(t, rsaKey, "RS256", "rsa-1", with(func(c map[string]any) {
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+		})), http.StatusUnauthorized},
+		{"no expiry", signToken(t, rsaKey, "RS256", "rsa-1", with(func(c map[string]any) {
+			delete(c, "exp")
+		})), http.StatusUnauthorized},
+		{"wrong audience", signToken(t, rsaKey, "RS256", "rsa-1", with(func(c map[string]any) {
+			c["aud"] = "https://other.example.com"
+		})), http.StatusUnauthorized},
+		{"wrong issuer", signToken(t, rsaKey, "RS256", "rsa-1", with(func(c map[string]any) {
+			c["iss"] = "https://evil.example.com"
+		})), http.StatusUnauthorized},
+		{"unknown key", signToken(t, otherKey, "RS256", "rsa-2", validClaims("alice")), http.StatusUnauthorized},
+		{"wrong signature", signToken(t, otherKey, "RS256", "rsa-1", validClaims("alice")), http.StatusUnauthorized},
+		{"alg none", strings.Join([]string{
+			base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)),
+			base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice"}`)),
+			"",
+		}, "."), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := authPost(handler, map[string]string{"Authorization": "Bearer " + tt.token})
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && !strings.Contains(rr.Header().Get("WWW-Authenticate"), `error="invalid_token"`) {
+				t.Errorf("WWW-Authenticate = %q, want an invalid_token error", rr.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	authPost(handler, map[string]string{"Authorization": "Bearer " + signToken(t, rsaKey, "RS256", "rsa-1", validClaims("bob"))})
+	if got := target.header(DefaultIdentityHeader); got != "bob" {
+		t.Errorf("forwarded identity = %q, want bob", got)
+	}
+}
+
+func TestAuth_JWTFromFile(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, jwks(t, map[string]crypto.Signer{"ec-1": ecKey}), 0o600); err != nil {
+		t.Fatalf("failed to write JWKS: %v", err)
+	}
+
+	handler := newAuthProxy(t, &authTarget{}, AuthConfig{JWKSFile: file, IdentityClaim: "email"}, nil)
+
+	claims := validClaims("alice")
+	claims["email"] = "alice@example.com"
+	rr := authPost(handler, map[string]string{"Authorization": "Bearer " + signToken(t, ecKey, "ES256", "ec-1", claims)})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+
+	// Tokens without the identity claim are rejected
+	rr = authPost(handler, map[string]string{"Authorization": "Bearer " + signToken(t, ecKey, "ES256", "ec-1", validClaims("alice"))})
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", rr.Code)
+	}
+}
+
+func TestAuth_JWKSRefreshedForUnknownKey(t *testing.T) {
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	var mu sync.Mutex
+	current := map[string]crypto.Signer{"old": oldKey}
+	jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(jwks(t, current))
+	}))
+	defer jwksServer.Close()
+
+	auth, err := NewAuthenticator(AuthConfig{JWKSURL: jwksServer.URL}, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	now := time.Now()
+	auth.jwt.keys.now = func() time.Time { return now }
+
+	mu.Lock()
+	current = map[string]crypto.Signer{"old": oldKey, "new": newKey}
+	mu.Unlock()
+
+	token := signToken(t, newKey, "RS256", "new", validClaims("alice"))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	// Unknown keys do not trigger a reload more than once per interval
+	if _, err := auth.Authenticate(req); err == nil {
+		t.Fatal("expected the new key to be unknown right after loading")
+	}
+
+	now = now.Add(jwksMinRefreshInterval + time.Second)
+	identity, err := auth.Authenticate(req)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if identity != "alice" {
+		t.Errorf("identity = %q, want alice", identity)
+	}
+}
+
+func TestAuth_ResourceMetadata(t *testing.T) {
+	dir := writeAPIKeys(t, map[string]string{"alice": "alice-key"})
+	handler := newAuthProxy(t, &authTarget{}, AuthConfig{
+		APIKeysDir:           dir,
+		AuthorizationServers: []string{"https://auth.example.com"},
+	}, nil)
+
+	rr := authPost(handler, nil)
+	want := `Bearer resource_metadata="http://example.com/.well-known/oauth-protected-resource/mcp"`
+	if got := rr.Header().Get("WWW-Authenticate"); got != want {
+		t.Errorf("WWW-Authenticate = %q, want %q", got, want)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, ProtectedResourceMetadataPath+"/mcp", nil)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+
+	var metadata struct {
+		Resource             string   `json:"resource"`
+		AuthorizationServers []string `json:"authorization_servers"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &metadata); err != nil {
+		t.Fatalf("failed to decode metadata: %v", err)
+	}
+	if metadata.Resource != "http://example.com/mcp" {
+		t.Errorf("resource = %q, want http://example.com/mcp", metadata.Resource)
+	}
+	if len(metadata.AuthorizationServers) != 1 || metadata.AuthorizationServers[0] != "https://auth.example.com" {
+		t.Errorf("authorization_servers = %v", metadata.AuthorizationServers)
+	}
+}
+
+func TestAuth_IdentityUsedByPolicy(t *testing.T) {
+	target := &policyTarget{}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	p, err := NewWithRecorder(":0", server.URL, newTestLogger(), nil)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("failed to parse policy: %v", err)
+	}
+	p.SetPolicy(policy)
+	auth, err := NewAuthenticator(AuthConfig{
+		APIKeysDir: writeAPIKeys(t, map[string]string{"alice": "alice-key", "admin": "admin-key"}),
+	}, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	p.SetAuthenticator(auth)
+	handler := p.authMiddleware(p.metricsMiddleware(p.policyMiddleware(p.reverseProxy)))
+
+	call := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/mcp",
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_users"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(APIKeyHeader, key)
+		// A claimed identity is replaced by the verified one
+		req.Header.Set(DefaultIdentityHeader, "admin")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if rr := call("alice-key"); !strings.Contains(rr.Body.String(), `"error"`) {
+		t.Errorf("expected alice to be denied list_users, got %s", rr.Body.String())
+	}
+	if rr := call("admin-key"); strings.Contains(rr.Body.String(), `"error"`) {
+		t.Errorf("expected admin to be allowed list_users, got %s", rr.Body.String())
+	}
+}
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  AuthConfig
+	}{
+		{"nothing configured", AuthConfig{}},
+		{"both JWKS sources", AuthConfig{JWKSURL: "https://auth.example.com/jwks", JWKSFile: "/etc/jwks.json"}},
+		{"missing JWKS file", AuthConfig{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}},
+		{"missing API key directory", AuthConfig{APIKeysDir: filepath.Join(t.TempDir(), "missing")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(tt.cfg, newTestLogger()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+)
+
+const (
+	// drainPollInterval is how often Drain checks whether requests are
+	// still in flight.
+	drainPollInterval = 100 * time.Millisecond
+
+	// drainReconnectHint ends the open event streams when the proxy drains:
+	// a comment for people reading the stream, and a retry field asking
+	// clients to reconnect after a second, to another pod.
+	drainReconnectHint = ": shutting down, reconnect\nretry: 1000\n\n"
+
+	// maxEndpointEventSize bounds the start of a stream read to find the
+	// session named by the endpoint event of the SSE transport.
+	maxEndpointEventSize = 4096
+)
+
+// errStreamDrained is returned to writes on a stream the drain has closed.
+var errStreamDrained = errors.New("event stream closed by drain")
+
+// drainState tracks the requests in flight and the open event streams, so
+// the proxy can shut down without cutting them.
+type drainState struct {
+	draining atomic.Bool
+	once     sync.Once
+
+	// active counts the requests in flight, event streams included.
+	active atomic.Int64
+
+	mu      sync.Mutex
+	streams map[*drainWriter]struct{}
+}
+
+// start stops new sessions and closes the open event streams, except those
+// pending reports requests waiting on.
+func (d *drainState) start(pending func(session string) bool) {
+	d.once.Do(func() {
+		d.draining.Store(true)
+	})
+	d.closeStreams(pending)
+}
+
+// closeStreams closes the open event streams. With pending, the streams of
+// sessions whose requests still wait for their response on the stream are
+// kept open: with the SSE transport, the answers to requests already accepted
+// with a 202 arrive there.
+func (d *drainState) closeStreams(pending func(session string) bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for dw := range d.streams {
+		if pending != nil && pending(dw.sessionID()) {
+			continue
+		}
+		dw.close()
+	}
+}
+
+// track registers an event stream, closing it at once if the drain started.
+func (d *drainState) track(dw *drainWriter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.streams == nil {
+		d.streams = map[*drainWriter]struct{}{}
+	}
+	d.streams[dw] = struct{}{}
+	if d.draining.Load() {
+		dw.close()
+	}
+}
+
+// untrack forgets an event stream once its request completed.
+func (d *drainState) untrack(dw *drainWriter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.streams, dw)
+}
+
+// Drain stops the proxy from accepting new sessions, asks the clients of the
+// open event streams to reconnect once no request waits on them, and waits
+// until the requests in flight complete or ctx is done. It is safe to call
+// more than once.
+func (p *Proxy) Drain(ctx context.Context) error {
+	p.drain.start(p.streamPending)
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		active := p.drain.active.Load()
+		if active == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			p.drain.closeStreams(nil)
+			return fmt.Errorf("%d requests still in flight: %w", active, ctx.Err())
+		case <-ticker.C:
+			p.drain.closeStreams(p.streamPending)
+		}
+	}
+}
+
+// streamPending reports whether requests of a session wait for their
+// response on its event stream.
+func (p *Proxy) streamPending(session string) bool {
+	return p.inflight != nil && session != "" && p.inflight.hasSession(session)
+}
+
+// Draining reports whether the proxy is shutting down.
+func (p *Proxy) Draining() bool {
+	return p.drain.draining.Load()
+}
+
+// drainMiddleware counts the requests in flight and, once the proxy drains,
+// rejects new sessions so clients open them on another pod. Requests of
+// existing sessions are still served, with connections closed after them.
+func (p *Proxy) drainMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if p.drain.draining.Load() {
+			w.Header().Set("Connection", "close")
+			if isNewSession(req) {
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		p.drain.active.Add(1)
+		defer p.drain.active.Add(-1)
+
+		if !isEventStreamRequest(req) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+
+		dw := &drainWriter{ResponseWriter: w, cancel: cancel, session: req.Header.Get(headerSessionID)}
+		p.drain.track(dw)
+		defer p.drain.untrack(dw)
+
+		next.ServeHTTP(dw, req.WithContext(ctx))
+	})
+}
+
+// isEventStreamRequest reports whether a request opens a long-lived event
+// stream: the stream of the SSE transport, or the GET stream of Streamable HTTP.
+func isEventStreamRequest(req *http.Request) bool {
+	return req.Method == http.MethodGet && strings.Contains(req.Header.Get("Accept"), "text/event-stream")
+}
+
+// isNewSession reports whether a request starts a session: it opens an event
+// stream, or initializes a Streamable HTTP session. The body of an initialize
+// request is restored for the handlers after it.
+func isNewSession(req *http.Request) bool {
+	if isEventStreamRequest(req) {
+		return true
+	}
+	if req.Method != http.MethodPost || req.Body == nil ||
+		req.Header.Get(headerSessionID) != "" || req.URL.Query().Get(sseSessionParam) != "" {
+		return false
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	messages, _ := splitBatch(body)
+	for _, message := range messages {
+		if parsed, err := mcp.ParseRequest(message); err == nil && parsed.Method == mcp.MethodInitialize {
+			return true
+		}
+	}
+	return false
+}
+
+// drainWriter wraps the response of an event stream so the drain can end it
+// with the reconnect hint. The hint is only written between events, so
+// clients never see an event cut in half.
+type drainWriter struct {
+	http.ResponseWriter
+	cancel context.CancelFunc
+
+	mu          sync.Mutex
+	wroteHeader bool
+	isSSE       bool
+	// session is the MCP session of the stream, from the Mcp-Session-Id
+	// header or the endpoint event of the SSE transport
+	session string
+	// head holds the start of the stream until its first event is complete
+	head     []byte
+	readHead bool
+	// tail holds the last bytes written, to find whether an event just ended
+	tail []byte
+	// closing is set once the drain asked the stream to end
+	closing bool
+	// closed is set once the reconnect hint was written
+	closed bool
+}
+
+// WriteHeader writes the response headers, ending the stream right away if
+// the drain already started.
+func (dw *drainWriter) WriteHeader(code int) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.writeHeader(code)
+	dw.closeAtBoundary()
+}
+
+func (dw *drainWriter) writeHeader(code int) {
+	if dw.wroteHeader {
+		return
+	}
+	dw.wroteHeader = true
+	dw.isSSE = IsSSEContentType(dw.Header().Get("Content-Type"))
+	dw.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes stream data, ending the stream after it if the drain started
+// and the data completes an event.
+func (dw *drainWriter) Write(b []byte) (int, error) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if dw.closed {
+		return 0, errStreamDrained
+	}
+	dw.writeHeader(http.StatusOK)
+
+	n, err := dw.ResponseWriter.Write(b)
+	dw.readSession(b[:n])
+	dw.tail = append(dw.tail, b[:n]...)
+	if len(dw.tail) > 4 {
+		dw.tail = dw.tail[len(dw.tail)-4:]
+	}
+	if err == nil {
+		dw.closeAtBoundary()
+	}
+	return n, err
+}
+
+// Flush implements http.Flusher for SSE streaming support.
+func (dw *drainWriter) Flush() {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	if dw.closed {
+		return
+	}
+	if flusher, ok := dw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// readSession finds the session in the endpoint event of the SSE transport,
+// the first event of its stream.
+func (dw *drainWriter) readSession(b []byte) {
+	if dw.session != "" || dw.readHead || !dw.isSSE {
+		return
+	}
+	dw.head = append(dw.head, b...)
+
+	head := strings.ReplaceAll(string(dw.head), "\r\n", "\n")
+	event, _, complete := strings.Cut(head, "\n\n")
+	if !complete && len(dw.head) < maxEndpointEventSize {
+		return
+	}
+	dw.readHead = true
+	dw.head = nil
+
+	var eventType, data string
+	for _, line := range strings.Split(event, "\n") {
+		if value, ok := strings.CutPrefix(line, "event:"); ok {
+			eventType = strings.TrimSpace(value)
+		} else if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = strings.TrimSpace(value)
+		}
+	}
+	if eventType == sseEndpointEvent {
+		dw.session = endpointSessionID(data)
+	}
+}
+
+// sessionID returns the MCP session of the stream, if known.
+func (dw *drainWriter) sessionID() string {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	return dw.session
+}
+
+// close asks the stream to end with the reconnect hint at the next event boundary.
+func (dw *drainWriter) close() {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+	dw.closing = true
+	dw.closeAtBoundary()
+}
+
+// closeAtBoundary writes the reconnect hint and cancels the request to the
+// target, if the drain asked the stream to end and no event is half written.
+// Responses that are not event streams are left to complete.
+func (dw *drainWriter) closeAtBoundary() {
+	if !dw.closing || dw.closed || !dw.wroteHeader || !dw.isSSE {
+		return
+	}
+	if len(dw.tail) > 0 && !bytes.HasSuffix(dw.tail, []byte("\n\n")) && !bytes.HasSuffix(dw.tail, []byte("\r\n\r\n")) {
+		return
+	}
+
+	dw.closed = true
+	if _, err := io.WriteString(dw.ResponseWriter, drainReconnectHint); err == nil {
+		if flusher, ok := dw.ResponseWriter.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	dw.cancel()
+}
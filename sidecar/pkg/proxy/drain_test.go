@@ -0,0 +1,300 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
+)
+
+func TestDrain_EndsEventStreamsWithReconnectHint(t *testing.T) {
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "event: endpoint\ndata: /message?sessionId=abc\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer targetServer.Close()
+
+	p, err := New(":0", targetServer.URL, newTestLogger())
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+	proxyServer := httptest.NewServer(p.drainMiddleware(p.metricsMiddleware(p.reverseProxy)))
+	defer proxyServer.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, proxyServer.URL+"/sse", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Wait for the endpoint event before draining
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read endpoint event: %v", err)
+		}
+		if line == "\n" {
+			break
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	// The stream ends after the hint, possibly with an aborted connection
+	rest, _ := io.ReadAll(reader)
+	if string(rest) != drainReconnectHint {
+		t.Errorf("Stream data after drain = %q, want %q", rest, drainReconnectHint)
+	}
+}
+
+func TestDrain_KeepsSSEStreamsUntilAcceptedRequestsAreAnswered(t *testing.T) {
+	answer := make(chan struct{})
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "event: endpoint\ndata: /message?sessionId=abc\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-answer:
+			io.WriteString(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n\n")
+			w.(http.Flusher).Flush()
+		case <-r.Context().Done():
+		}
+		<-r.Context().Done()
+	}))
+	defer targetServer.Close()
+
+	recorder, err := metrics.NewRecorder("test", targetServer.URL)
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	p, err := NewWithRecorder(":0", targetServer.URL, newTestLogger(), recorder)
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+	proxyServer := httptest.NewServer(p.drainMiddleware(p.metricsMiddleware(p.reverseProxy)))
+	defer proxyServer.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, proxyServer.URL+"/sse", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read endpoint event: %v", err)
+		}
+		if line == "\n" {
+			break
+		}
+	}
+
+	post, err := http.Post(proxyServer.URL+"/message?sessionId=abc", "application/json",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`))
+	if err != nil {
+		t.Fatalf("Failed to post message: %v", err)
+	}
+	post.Body.Close()
+	if post.StatusCode != http.StatusAccepted {
+		t.Fatalf("Post status = %d, want %d", post.StatusCode, http.StatusAccepted)
+	}
+
+	drained := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		drained <- p.Drain(ctx)
+	}()
+
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain() returned before the accepted request was answered: %v", err)
+	case <-time.After(3 * drainPollInterval):
+	}
+
+	close(answer)
+	rest, _ := io.ReadAll(reader)
+	if want := "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n\n" + drainReconnectHint; string(rest) != want {
+		t.Errorf("Stream data after drain = %q, want %q", rest, want)
+	}
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+}
+
+func TestDrainWriter_WaitsForEventBoundary(t *testing.T) {
+	rr := httptest.NewRecorder()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dw := &drainWriter{ResponseWriter: rr, cancel: cancel}
+	dw.Header().Set("Content-Type", "text/event-stream")
+	dw.WriteHeader(http.StatusOK)
+	io.WriteString(dw, "data: {\"jsonrpc\":")
+
+	dw.close()
+	if strings.Contains(rr.Body.String(), drainReconnectHint) {
+		t.Fatal("Reconnect hint written in the middle of an event")
+	}
+	if ctx.Err() != nil {
+		t.Fatal("Stream cancelled in the middle of an event")
+	}
+
+	io.WriteString(dw, "\"2.0\"}\n\n")
+	if want := "data: {\"jsonrpc\":\"2.0\"}\n\n" + drainReconnectHint; rr.Body.String() != want {
+		t.Errorf("Body = %q, want %q", rr.Body.String(), want)
+	}
+	if ctx.Err() == nil {
+		t.Error("Stream not cancelled after the reconnect hint")
+	}
+
+	if _, err := io.WriteString(dw, "data: late\n\n"); !errors.Is(err, errStreamDrained) {
+		t.Errorf("Write after drain error = %v, want %v", err, errStreamDrained)
+	}
+}
+
+func TestDrain_RejectsNewSessions(t *testing.T) {
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{}}`)
+	}))
+	defer targetServer.Close()
+
+	p, err := New(":0", targetServer.URL, newTestLogger())
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+	handler := p.drainMiddleware(p.metricsMiddleware(p.reverseProxy))
+	p.drain.start(nil)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "event stream",
+			method:     http.MethodGet,
+			headers:    map[string]string{"Accept": "text/event-stream"},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "initialize",
+			method:     http.MethodPost,
+			body:       `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+			headers:    map[string]string{"Content-Type": "application/json"},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "request of an existing session",
+			method: http.MethodPost,
+			body:   `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+			headers: map[string]string{
+				"Content-Type":  "application/json",
+				headerSessionID: "session-1",
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/mcp", strings.NewReader(tt.body))
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if rr.Header().Get("Connection") != "close" {
+				t.Errorf("Connection header = %q, want close", rr.Header().Get("Connection"))
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable && rr.Header().Get("Retry-After") == "" {
+				t.Error("Expected a Retry-After header on rejected sessions")
+			}
+		})
+	}
+}
+
+func TestDrain_WaitsForRequestsInFlight(t *testing.T) {
+	release := make(chan struct{})
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"jsonrpc":"2.0","id":1,"result":{}}`)
+	}))
+	defer targetServer.Close()
+
+	p, err := New(":0", targetServer.URL, newTestLogger())
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+	handler := p.drainMiddleware(p.metricsMiddleware(p.reverseProxy))
+
+	done := make(chan int)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		done <- rr.Code
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for p.drain.active.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Request never reached the proxy")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := p.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Drain() with a request in flight error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !p.Draining() {
+		t.Error("Expected the proxy to be draining")
+	}
+
+	close(release)
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("Request in flight status = %d, want %d", code, http.StatusOK)
+	}
+}
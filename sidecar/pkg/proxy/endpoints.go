@@ -0,0 +1,238 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serviceAccountDir holds the credentials Kubernetes mounts into pods.
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// Endpoint is a ready pod behind a Service.
+type Endpoint struct {
+	// Address is the host:port requests are sent to.
+	Address string
+
+	// Pod is the name of the pod, when known.
+	Pod string
+}
+
+// EndpointSource lists the ready endpoints requests may be routed to.
+type EndpointSource interface {
+	Endpoints(ctx context.Context) ([]Endpoint, error)
+}
+
+// EndpointSliceSource lists the ready endpoints of a Service from its
+// EndpointSlices, using the Kubernetes API with the pod's service account.
+type EndpointSliceSource struct {
+	apiURL    string
+	tokenFile string
+	client    *http.Client
+
+	namespace string
+	service   string
+	portName  string
+}
+
+// NewInClusterEndpointSource creates an EndpointSliceSource for the port
+// named portName of a Service. An empty namespace selects the pod's own.
+func NewInClusterEndpointSource(namespace, service, portName string) (*EndpointSliceSource, error) {
+	api, err := loadInClusterAPI(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EndpointSliceSource{
+		apiURL:    api.apiURL,
+		tokenFile: api.tokenFile,
+		client:    api.client,
+		namespace: api.namespace,
+		service:   service,
+		portName:  portName,
+	}, nil
+}
+
+// inClusterAPI is what a pod needs to call the Kubernetes API with its
+// service account.
+type inClusterAPI struct {
+	apiURL    string
+	tokenFile string
+	client    *http.Client
+	namespace string
+}
+
+// loadInClusterAPI reads the API address and the service account of the
+// pod. An empty namespace selects the pod's own.
+func loadInClusterAPI(namespace string) (*inClusterAPI, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a Kubernetes pod: KUBERNETES_SERVICE_HOST is not set")
+	}
+
+	ca, err := os.ReadFile(filepath.Join(serviceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("cluster CA contains no certificates")
+	}
+
+	if namespace == "" {
+		data, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pod namespace: %w", err)
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	return &inClusterAPI{
+		apiURL:    "https://" + net.JoinHostPort(host, port),
+		tokenFile: filepath.Join(serviceAccountDir, "token"),
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}},
+		},
+		namespace: namespace,
+	}, nil
+}
+
+// kubeRequest sends a request to the Kubernetes API as the service account
+// whose token is in tokenFile, and decodes the response into out.
+func kubeRequest(ctx context.Context, client *http.Client, tokenFile, method, url string, patch []byte, out any) error {
+	var body io.Reader
+	if patch != nil {
+		body = bytes.NewReader(patch)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	// Projected service account tokens are rotated, so the file is read every time
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return fmt.Errorf("failed to read service account token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+	if patch != nil {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// endpointSliceList is the part of a discovery.k8s.io/v1 EndpointSliceList the router reads.
+type endpointSliceList struct {
+	Items []struct {
+		AddressType string `json:"addressType"`
+		Endpoints   []struct {
+			Addresses  []string `json:"addresses"`
+			Conditions struct {
+				Ready *bool `json:"ready"`
+			} `json:"conditions"`
+			TargetRef *struct {
+				Name string `json:"name"`
+			} `json:"targetRef"`
+		} `json:"endpoints"`
+		Ports []struct {
+			Name *string `json:"name"`
+			Port *int32  `json:"port"`
+		} `json:"ports"`
+	} `json:"items"`
+}
+
+// Endpoints lists the ready endpoints of the Service, sorted by address.
+func (s *EndpointSliceSource) Endpoints(ctx context.Context) ([]Endpoint, error) {
+	query := url.Values{"labelSelector": {"kubernetes.io/service-name=" + s.service}}
+	listURL := fmt.Sprintf("%s/apis/discovery.k8s.io/v1/namespaces/%s/endpointslices?%s",
+		s.apiURL, url.PathEscape(s.namespace), query.Encode())
+
+	var list endpointSliceList
+	if err := kubeRequest(ctx, s.client, s.tokenFile, http.MethodGet, listURL, nil, &list); err != nil {
+		return nil, fmt.Errorf("failed to list EndpointSlices: %w", err)
+	}
+	return s.readyEndpoints(&list), nil
+}
+
+// readyEndpoints extracts the ready endpoints serving the named port. A pod
+// listed in several slices, as on dual-stack clusters, is used once.
+func (s *EndpointSliceSource) readyEndpoints(list *endpointSliceList) []Endpoint {
+	var endpoints []Endpoint
+	seen := make(map[string]bool)
+
+	for _, slice := range list.Items {
+		if slice.AddressType != "IPv4" && slice.AddressType != "IPv6" {
+			continue
+		}
+
+		var port int32
+		for _, p := range slice.Ports {
+			name := ""
+			if p.Name != nil {
+				name = *p.Name
+			}
+			if name == s.portName && p.Port != nil {
+				port = *p.Port
+			}
+		}
+		if port == 0 {
+			continue
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			// A missing ready condition means ready
+			if len(endpoint.Addresses) == 0 || (endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready) {
+				continue
+			}
+
+			address := net.JoinHostPort(endpoint.Addresses[0], strconv.Itoa(int(port)))
+			pod := ""
+			if endpoint.TargetRef != nil {
+				pod = endpoint.TargetRef.Name
+			}
+
+			key := address
+			if pod != "" {
+				key = pod
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			endpoints = append(endpoints, Endpoint{Address: address, Pod: pod})
+		}
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Address < endpoints[j].Address })
+	return endpoints
+}
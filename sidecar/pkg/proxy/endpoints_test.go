@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testEndpointSlices = `{
+  "items": [
+    {
+      "addressType": "IPv4",
+      "ports": [{"name": "metrics", "port": 9090}, {"name": "http", "port": 8080}],
+      "endpoints": [
+        {"addresses": ["10.0.0.2"], "conditions": {"ready": true}, "targetRef": {"name": "server-b"}},
+        {"addresses": ["10.0.0.1"], "conditions": {}, "targetRef": {"name": "server-a"}},
+        {"addresses": ["10.0.0.3"], "conditions": {"ready": false}, "targetRef": {"name": "server-c"}}
+      ]
+    },
+    {
+      "addressType": "IPv6",
+      "ports": [{"name": "http", "port": 8080}],
+      "endpoints": [
+        {"addresses": ["fd00::1"], "conditions": {"ready": true}, "targetRef": {"name": "server-a"}},
+        {"addresses": ["fd00::4"], "conditions": {"ready": true}, "targetRef": {"name": "server-d"}}
+      ]
+    },
+    {
+      "addressType": "FQDN",
+      "ports": [{"name": "http", "port": 8080}],
+      "endpoints": [{"addresses": ["server.example.com"]}]
+    }
+  ]
+}`
+
+func TestEndpointSliceSource_Endpoints(t *testing.T) {
+	var gotPath, gotSelector, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotSelector = r.URL.Query().Get("labelSelector")
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(testEndpointSlices))
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("pod-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &EndpointSliceSource{
+		apiURL:    server.URL,
+		tokenFile: tokenFile,
+		client:    server.Client(),
+		namespace: "default",
+		service:   "my-server",
+		portName:  "http",
+	}
+
+	endpoints, err := source.Endpoints(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list endpoints: %v", err)
+	}
+
+	if gotPath != "/apis/discovery.k8s.io/v1/namespaces/default/endpointslices" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotSelector != "kubernetes.io/service-name=my-server" {
+		t.Errorf("labelSelector = %q", gotSelector)
+	}
+	if gotAuth != "Bearer pod-token" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+
+	want := []Endpoint{
+		{Address: "10.0.0.1:8080", Pod: "server-a"},
+		{Address: "10.0.0.2:8080", Pod: "server-b"},
+		{Address: "[fd00::4]:8080", Pod: "server-d"},
+	}
+	if !reflect.DeepEqual(endpoints, want) {
+		t.Errorf("endpoints = %v, want %v", endpoints, want)
+	}
+}
+
+func TestEndpointSliceSource_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "endpointslices is forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("pod-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &EndpointSliceSource{apiURL: server.URL, tokenFile: tokenFile, client: server.Client(), service: "my-server"}
+	if _, err := source.Endpoints(context.Background()); err == nil {
+		t.Error("expected an error for a forbidden list")
+	}
+}
@@ -0,0 +1,596 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
+)
+
+const (
+	// DefaultGatewayPath is the HTTP path the gateway serves MCP traffic on.
+	DefaultGatewayPath = "/mcp"
+
+	// DefaultToolSeparator joins a backend prefix and a tool name.
+	DefaultToolSeparator = "__"
+
+	// gatewayProtocolVersion is offered to clients that request an unknown
+	// version. Backends are initialized with the version of the MCP client.
+	gatewayProtocolVersion = "2025-03-26"
+
+	// gatewayRequestTimeout bounds a single backend call, including tool calls.
+	gatewayRequestTimeout = 5 * time.Minute
+
+	// maxGatewayBodySize limits the size of request and backend response bodies.
+	maxGatewayBodySize = 10 * 1024 * 1024
+
+	// minRouteRefreshInterval is how long calls to unknown tools wait after a
+	// refresh of the routes before they refresh them again, so clients calling
+	// tools that do not exist cannot make the gateway list every backend
+	// on each call.
+	minRouteRefreshInterval = 5 * time.Second
+
+	headerSessionID = "Mcp-Session-Id"
+
+	methodPing = "ping"
+)
+
+// JSON-RPC error codes returned by the gateway itself.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// supportedProtocolVersions are the MCP versions the gateway accepts from clients.
+var supportedProtocolVersions = map[string]bool{
+	"2024-11-05": true,
+	"2025-03-26": true,
+	"2025-06-18": true,
+}
+
+// GatewayConfig configures the gateway mode of the proxy.
+type GatewayConfig struct {
+	// Path is the HTTP path the gateway serves MCP traffic on.
+	Path string `json:"path,omitempty"`
+
+	// PrefixToolNames exposes each tool as "<prefix><separator><name>" so tools
+	// with the same name on different backends do not collide.
+	PrefixToolNames bool `json:"prefixToolNames,omitempty"`
+
+	// Separator joins the backend prefix and the tool name.
+	Separator string `json:"separator,omitempty"`
+
+	// Backends are the Streamable HTTP MCP servers behind the gateway.
+	Backends []GatewayBackend `json:"backends"`
+}
+
+// GatewayBackend is an MCP server behind the gateway.
+type GatewayBackend struct {
+	// Name identifies the backend in logs.
+	Name string `json:"name"`
+
+	// URL is the Streamable HTTP endpoint of the backend, including its path.
+	URL string `json:"url"`
+
+	// Prefix is prepended to the backend's tool names when prefixing is enabled.
+	// Defaults to Name.
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// LoadGatewayConfig reads and validates a gateway configuration file.
+func LoadGatewayConfig(path string) (*GatewayConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gateway config: %w", err)
+	}
+
+	cfg := &GatewayConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse gateway config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validate checks the configuration and fills in defaults.
+func (c *GatewayConfig) validate() error {
+	if c.Path == "" {
+		c.Path = DefaultGatewayPath
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("gateway path must start with '/': %q", c.Path)
+	}
+	if c.Separator == "" {
+		c.Separator = DefaultToolSeparator
+	}
+
+	names := make(map[string]bool, len(c.Backends))
+	for i := range c.Backends {
+		backend := &c.Backends[i]
+		if backend.Name == "" {
+			return fmt.Errorf("gateway backend %d has no name", i)
+		}
+		if names[backend.Name] {
+			return fmt.Errorf("duplicate gateway backend %q", backend.Name)
+		}
+		names[backend.Name] = true
+
+		u, err := url.Parse(backend.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("gateway backend %q has an invalid URL %q", backend.Name, backend.URL)
+		}
+		if backend.Prefix == "" {
+			backend.Prefix = backend.Name
+		}
+	}
+	return nil
+}
+
+// NewGateway creates a Proxy that fronts several MCP servers behind one
+// Streamable HTTP endpoint instead of forwarding to a single target.
+//
+// The gateway answers initialize itself, merges tools/list across backends
+// and routes each tools/call to the backend that owns the tool.
+func NewGateway(listenAddr string, cfg *GatewayConfig, logger *slog.Logger, recorder *metrics.Recorder) (*Proxy, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Proxy{
+		listenAddr: listenAddr,
+		handler:    newGateway(cfg, logger),
+		logger:     logger,
+		recorder:   recorder,
+	}, nil
+}
+
+// gateway is the HTTP handler implementing the gateway mode.
+type gateway struct {
+	cfg        *GatewayConfig
+	logger     *slog.Logger
+	httpClient *http.Client
+	backends   []*gatewayBackend
+
+	mu          sync.RWMutex
+	routes      map[string]toolRoute
+	lastRefresh time.Time
+
+	// refreshMu lets one call to an unknown tool refresh the routes at a time,
+	// the others using its result.
+	refreshMu sync.Mutex
+}
+
+// toolRoute maps an exposed tool name to the backend that owns it.
+type toolRoute struct {
+	backend *gatewayBackend
+	name    string
+}
+
+// gatewayBackend holds the session the gateway keeps with one backend.
+// The session is shared by all clients of the gateway.
+type gatewayBackend struct {
+	GatewayBackend
+
+	client *mcp.Client
+
+	mu           sync.Mutex
+	initialized  bool
+	capabilities map[string]json.RawMessage
+	// tools is the last successful listing of the backend's tools.
+	tools []map[string]json.RawMessage
+}
+
+func newGateway(cfg *GatewayConfig, logger *slog.Logger) *gateway {
+	g := &gateway{
+		cfg:        cfg,
+		logger:     logger,
+		httpClient: &http.Client{Timeout: gatewayRequestTimeout},
+		routes:     make(map[string]toolRoute),
+	}
+
+	clientInfo := mcp.Implementation{Name: "mcp-gateway", Version: "1.0.0"}
+	for _, backend := range cfg.Backends {
+		g.backends = append(g.backends, &gatewayBackend{
+			GatewayBackend: backend,
+			client:         mcp.NewClient(backend.URL, g.httpClient, clientInfo),
+		})
+	}
+	// Backends are consulted in name order so unprefixed name collisions
+	// resolve the same way on every replica
+	sort.Slice(g.backends, func(i, j int) bool { return g.backends[i].Name < g.backends[j].Name })
+
+	return g
+}
+
+// ServeHTTP implements the client side of the Streamable HTTP transport.
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != g.cfg.Path {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		g.handlePost(w, r)
+	case http.MethodDelete:
+		// Sessions hold no state on the gateway, so there is nothing to clean up
+		w.WriteHeader(http.StatusOK)
+	default:
+		// The gateway does not offer a server-initiated stream
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost answers one JSON-RPC message.
+func (g *gateway) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxGatewayBodySize))
+	if err != nil {
+		writeGatewayJSON(w, http.StatusBadRequest, newRPCError(nil, codeParseError, "failed to read request body"))
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		writeGatewayJSON(w, http.StatusBadRequest, newRPCError(nil, codeInvalidRequest, "batch requests are not supported by the gateway"))
+		return
+	}
+
+	var req rpcMessage
+	if err := json.Unmarshal(body, &req); err != nil || req.Method == "" {
+		writeGatewayJSON(w, http.StatusBadRequest, newRPCError(nil, codeParseError, "invalid JSON-RPC message"))
+		return
+	}
+
+	// Notifications, including notifications/initialized, need no answer
+	if !req.isRequest() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var resp *rpcMessage
+	switch req.Method {
+	case mcp.MethodInitialize:
+		resp = g.initialize(r.Context(), &req)
+		w.Header().Set(headerSessionID, newGatewaySessionID())
+	case methodPing:
+		resp = newRPCResult(req.ID, json.RawMessage("{}"))
+	case mcp.MethodToolsList:
+		resp = g.listTools(r.Context(), &req)
+	case mcp.MethodToolsCall:
+		resp = g.callTool(r.Context(), &req)
+	default:
+		resp = newRPCError(req.ID, codeMethodNotFound, fmt.Sprintf("method %q is not supported by the gateway", req.Method))
+	}
+
+	writeGatewayJSON(w, http.StatusOK, resp)
+}
+
+// initialize answers the client handshake with the capabilities merged from
+// all backends. Backends are initialized as part of the handshake so their
+// capabilities are known; unreachable backends are skipped.
+func (g *gateway) initialize(ctx context.Context, req *rpcMessage) *rpcMessage {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	_ = json.Unmarshal(req.Params, &params)
+
+	protocolVersion := params.ProtocolVersion
+	if !supportedProtocolVersions[protocolVersion] {
+		protocolVersion = gatewayProtocolVersion
+	}
+
+	hasTools := false
+	g.forEachBackend(func(backend *gatewayBackend) {
+		if err := g.ensureInitialized(ctx, backend); err != nil {
+			g.logger.Warn("failed to initialize gateway backend",
+				slog.String("backend", backend.Name),
+				slog.String("error", err.Error()),
+			)
+		}
+	})
+	for _, backend := range g.backends {
+		backend.mu.Lock()
+		if _, ok := backend.capabilities["tools"]; ok {
+			hasTools = true
+		}
+		backend.mu.Unlock()
+	}
+
+	capabilities := map[string]any{}
+	if hasTools {
+		capabilities["tools"] = map[string]any{}
+	}
+
+	result, _ := json.Marshal(map[string]any{
+		"protocolVersion": protocolVersion,
+		"capabilities":    capabilities,
+		"serverInfo": map[string]any{
+			"name":    "mcp-gateway",
+			"version": "1.0.0",
+		},
+	})
+	return newRPCResult(req.ID, result)
+}
+
+// listTools merges the tools of all backends and refreshes the routing table.
+func (g *gateway) listTools(ctx context.Context, req *rpcMessage) *rpcMessage {
+	result, err := json.Marshal(map[string]any{"tools": g.refreshRoutes(ctx)})
+	if err != nil {
+		return newRPCError(req.ID, codeInternalError, err.Error())
+	}
+	return newRPCResult(req.ID, result)
+}
+
+// refreshRoutes lists the tools of every backend, rebuilds the routing table
+// and returns the merged tool list with exposed names. Backends that cannot
+// be listed keep the tools of their last successful listing.
+func (g *gateway) refreshRoutes(ctx context.Context) []map[string]json.RawMessage {
+	listings := make([][]map[string]json.RawMessage, len(g.backends))
+	g.forEachBackendIndexed(func(i int, backend *gatewayBackend) {
+		tools, err := g.backendTools(ctx, backend)
+
+		backend.mu.Lock()
+		defer backend.mu.Unlock()
+		if err != nil {
+			g.logger.Warn("failed to list tools from gateway backend, keeping its previous tools",
+				slog.String("backend", backend.Name),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			backend.tools = tools
+		}
+		listings[i] = backend.tools
+	})
+
+	routes := make(map[string]toolRoute)
+	merged := []map[string]json.RawMessage{}
+	for i, backend := range g.backends {
+		for _, tool := range listings[i] {
+			var name string
+			if err := json.Unmarshal(tool["name"], &name); err != nil || name == "" {
+				continue
+			}
+
+			exposed := g.exposedToolName(backend, name)
+			if owner, exists := routes[exposed]; exists {
+				g.logger.Warn("duplicate tool name across gateway backends, keeping the first",
+					slog.String("tool", exposed),
+					slog.String("kept", owner.backend.Name),
+					slog.String("skipped", backend.Name),
+				)
+				continue
+			}
+			routes[exposed] = toolRoute{backend: backend, name: name}
+
+			// The listing is kept for the next refresh, so it is not renamed in place
+			if exposed != name {
+				tool = maps.Clone(tool)
+				tool["name"], _ = json.Marshal(exposed)
+			}
+			merged = append(merged, tool)
+		}
+	}
+
+	g.mu.Lock()
+	g.routes = routes
+	g.lastRefresh = time.Now()
+	g.mu.Unlock()
+
+	return merged
+}
+
+// refreshUnknownTool refreshes the routes for a call to a tool they do not
+// know, unless they were refreshed within minRouteRefreshInterval. Concurrent
+// calls wait for the refresh in progress instead of starting their own.
+func (g *gateway) refreshUnknownTool(ctx context.Context) {
+	g.refreshMu.Lock()
+	defer g.refreshMu.Unlock()
+
+	g.mu.RLock()
+	lastRefresh := g.lastRefresh
+	g.mu.RUnlock()
+	if time.Since(lastRefresh) < minRouteRefreshInterval {
+		return
+	}
+	g.refreshRoutes(ctx)
+}
+
+// backendTools fetches every page of a backend's tools/list.
+func (g *gateway) backendTools(ctx context.Context, backend *gatewayBackend) ([]map[string]json.RawMessage, error) {
+	var tools []map[string]json.RawMessage
+	err := g.withSession(ctx, backend, func(client *mcp.Client) error {
+		var err error
+		tools, err = client.ListTools(ctx)
+		return err
+	})
+	return tools, err
+}
+
+// exposedToolName returns the name a backend tool is listed under.
+func (g *gateway) exposedToolName(backend *gatewayBackend, name string) string {
+	if !g.cfg.PrefixToolNames {
+		return name
+	}
+	return backend.Prefix + g.cfg.Separator + name
+}
+
+// callTool forwards a tools/call to the backend that owns the tool.
+func (g *gateway) callTool(ctx context.Context, req *rpcMessage) *rpcMessage {
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return newRPCError(req.ID, codeInvalidParams, "invalid tools/call params")
+	}
+	var exposed string
+	if err := json.Unmarshal(params["name"], &exposed); err != nil || exposed == "" {
+		return newRPCError(req.ID, codeInvalidParams, "tools/call requires a tool name")
+	}
+
+	route, ok := g.lookupRoute(exposed)
+	if !ok {
+		// The client may call a tool without listing first, or a backend may
+		// have added tools since the last listing
+		g.refreshUnknownTool(ctx)
+		route, ok = g.lookupRoute(exposed)
+	}
+	if !ok {
+		return newRPCError(req.ID, codeInvalidParams, fmt.Sprintf("Unknown tool: %s", exposed))
+	}
+
+	params["name"], _ = json.Marshal(route.name)
+	forwarded, err := json.Marshal(params)
+	if err != nil {
+		return newRPCError(req.ID, codeInternalError, err.Error())
+	}
+
+	var resp *mcp.JSONRPCResponse
+	err = g.withSession(ctx, route.backend, func(client *mcp.Client) error {
+		var err error
+		resp, err = client.Call(ctx, mcp.MethodToolsCall, forwarded)
+		return err
+	})
+	if err != nil {
+		g.logger.Error("gateway tool call failed",
+			slog.String("backend", route.backend.Name),
+			slog.String("tool", route.name),
+			slog.String("error", err.Error()),
+		)
+		return newRPCError(req.ID, codeInternalError, fmt.Sprintf("backend %s unavailable: %v", route.backend.Name, err))
+	}
+
+	// Answer with the client's ID, not the one used towards the backend
+	answer := &rpcMessage{JSONRPC: "2.0", ID: req.ID, Result: resp.Result}
+	if resp.Error != nil {
+		answer.Error, _ = json.Marshal(resp.Error)
+	}
+	return answer
+}
+
+// lookupRoute returns the route for an exposed tool name.
+func (g *gateway) lookupRoute(name string) (toolRoute, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	route, ok := g.routes[name]
+	return route, ok
+}
+
+// forEachBackend runs fn for every backend concurrently and waits for all of them.
+func (g *gateway) forEachBackend(fn func(backend *gatewayBackend)) {
+	g.forEachBackendIndexed(func(_ int, backend *gatewayBackend) { fn(backend) })
+}
+
+// forEachBackendIndexed runs fn for every backend concurrently and waits for all of them.
+func (g *gateway) forEachBackendIndexed(fn func(i int, backend *gatewayBackend)) {
+	var wg sync.WaitGroup
+	for i, backend := range g.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(i, backend)
+		}()
+	}
+	wg.Wait()
+}
+
+// ensureInitialized performs the MCP handshake with a backend once.
+func (g *gateway) ensureInitialized(ctx context.Context, backend *gatewayBackend) error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	if backend.initialized {
+		return nil
+	}
+
+	result, err := backend.client.Initialize(ctx)
+	if err != nil {
+		return err
+	}
+
+	backend.initialized = true
+	backend.capabilities = result.Capabilities
+	return nil
+}
+
+// withSession runs fn in the backend session, initializing it first if needed.
+// An expired session is re-established once.
+func (g *gateway) withSession(ctx context.Context, backend *gatewayBackend, fn func(client *mcp.Client) error) error {
+	for attempt := 0; ; attempt++ {
+		if err := g.ensureInitialized(ctx, backend); err != nil {
+			return err
+		}
+
+		err := fn(backend.client)
+		if errors.Is(err, mcp.ErrSessionExpired) && attempt == 0 {
+			backend.mu.Lock()
+			backend.initialized = false
+			backend.mu.Unlock()
+			continue
+		}
+		return err
+	}
+}
+
+// rpcMessage is a JSON-RPC 2.0 message with raw fields, so results and
+// errors from backends are forwarded unchanged.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// isRequest returns true for messages that expect a response.
+func (m *rpcMessage) isRequest() bool {
+	return m.Method != "" && len(m.ID) > 0 && string(m.ID) != "null"
+}
+
+// newRPCResult builds a successful response.
+func newRPCResult(id json.RawMessage, result json.RawMessage) *rpcMessage {
+	return &rpcMessage{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+// newRPCError builds an error response.
+func newRPCError(id json.RawMessage, code int, message string) *rpcMessage {
+	errData, _ := json.Marshal(mcp.JSONRPCError{Code: code, Message: message})
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &rpcMessage{JSONRPC: "2.0", ID: id, Error: errData}
+}
+
+// newGatewaySessionID returns a random session ID for a client.
+func newGatewaySessionID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// writeGatewayJSON writes v as a JSON response body.
+func writeGatewayJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
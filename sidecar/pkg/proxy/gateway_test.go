@@ -0,0 +1,477 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+)
+
+// mockBackend is a Streamable HTTP MCP server for gateway tests.
+type mockBackend struct {
+	tools []string
+	// sse answers requests with a text/event-stream body
+	sse bool
+
+	mu       sync.Mutex
+	session  string
+	sessions atomic.Int32
+	calls    []string
+	// lists counts tools/list requests, and failList makes them fail
+	lists    atomic.Int32
+	failList atomic.Bool
+}
+
+func (b *mockBackend) serve(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcMessage
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		b.mu.Lock()
+		if req.Method == "initialize" {
+			b.session = fmt.Sprintf("session-%d", b.sessions.Add(1))
+			w.Header().Set("Mcp-Session-Id", b.session)
+		} else if r.Header.Get("Mcp-Session-Id") != b.session {
+			b.mu.Unlock()
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		b.mu.Unlock()
+
+		if !req.isRequest() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		var result any
+		switch req.Method {
+		case "initialize":
+			result = map[string]any{
+				"protocolVersion": "2025-03-26",
+				"capabilities":    map[string]any{"tools": map[string]any{}},
+				"serverInfo":      map[string]any{"name": "mock", "version": "1.0.0"},
+			}
+		case "tools/list":
+			b.lists.Add(1)
+			if b.failList.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var tools []map[string]any
+			for _, name := range b.tools {
+				tools = append(tools, map[string]any{"name": name, "inputSchema": map[string]any{"type": "object"}})
+			}
+			result = map[string]any{"tools": tools}
+		case "tools/call":
+			var params mcp.ToolCallParams
+			_ = json.Unmarshal(req.Params, &params)
+			b.mu.Lock()
+			b.calls = append(b.calls, params.Name)
+			b.mu.Unlock()
+			result = map[string]any{"content": []map[string]any{{"type": "text", "text": "called " + params.Name}}}
+		}
+
+		data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result})
+		if b.sse {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\"}\n\n")
+			_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// expireSession makes the backend forget its current session.
+func (b *mockBackend) expireSession() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.session = "expired"
+}
+
+func (b *mockBackend) calledTools() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.calls...)
+}
+
+// newTestGateway starts a gateway in front of the given backends.
+func newTestGateway(t *testing.T, prefix bool, backends map[string]*mockBackend) *httptest.Server {
+	t.Helper()
+
+	cfg := &GatewayConfig{PrefixToolNames: prefix}
+	for name, backend := range backends {
+		cfg.Backends = append(cfg.Backends, GatewayBackend{Name: name, URL: backend.serve(t).URL + "/mcp"})
+	}
+
+	p, err := NewGateway(":0", cfg, newTestLogger(), nil)
+	if err != nil {
+		t.Fatalf("NewGateway() error = %v", err)
+	}
+
+	server := httptest.NewServer(p.metricsMiddleware(p.handler))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// gatewayCall posts a JSON-RPC request to the gateway and decodes the response.
+func gatewayCall(t *testing.T, server *httptest.Server, id int, method string, params any) (*http.Response, *rpcMessage) {
+	t.Helper()
+
+	body := map[string]any{"jsonrpc": "2.0", "id": id, "method": method}
+	if params != nil {
+		body["params"] = params
+	}
+	data, _ := json.Marshal(body)
+
+	resp, err := http.Post(server.URL+"/mcp", "application/json", strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("POST error = %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	msg := &rpcMessage{}
+	if err := json.NewDecoder(resp.Body).Decode(msg); err != nil {
+		t.Fatalf("failed to decode gateway response: %v", err)
+	}
+	if string(msg.ID) != fmt.Sprint(id) {
+		t.Errorf("response id = %s, want %d", msg.ID, id)
+	}
+	return resp, msg
+}
+
+// toolNames extracts the tool names from a tools/list result.
+func toolNames(t *testing.T, msg *rpcMessage) []string {
+	t.Helper()
+
+	var result struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(msg.Result, &result); err != nil {
+		t.Fatalf("invalid tools/list result %s: %v", msg.Result, err)
+	}
+
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// rpcErrorCode extracts the error code from an error response.
+func rpcErrorCode(t *testing.T, msg *rpcMessage) int {
+	t.Helper()
+
+	var rpcErr struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(msg.Error, &rpcErr); err != nil {
+		t.Fatalf("expected a JSON-RPC error, got %+v", msg)
+	}
+	return rpcErr.Code
+}
+
+func TestGateway_Initialize(t *testing.T) {
+	server := newTestGateway(t, false, map[string]*mockBackend{
+		"weather": {tools: []string{"forecast"}},
+	})
+
+	resp, msg := gatewayCall(t, server, 1, "initialize", map[string]any{"protocolVersion": "2025-06-18"})
+
+	if resp.Header.Get("Mcp-Session-Id") == "" {
+		t.Error("initialize response has no Mcp-Session-Id header")
+	}
+
+	var result struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(msg.Result, &result); err != nil {
+		t.Fatalf("invalid initialize result: %v", err)
+	}
+	if result.ProtocolVersion != "2025-06-18" {
+		t.Errorf("protocolVersion = %q, want the requested 2025-06-18", result.ProtocolVersion)
+	}
+	if _, ok := result.Capabilities["tools"]; !ok {
+		t.Errorf("capabilities = %v, want tools", result.Capabilities)
+	}
+	if result.ServerInfo.Name != "mcp-gateway" {
+		t.Errorf("serverInfo.name = %q, want mcp-gateway", result.ServerInfo.Name)
+	}
+
+	_, msg = gatewayCall(t, server, 2, "initialize", map[string]any{"protocolVersion": "1999-01-01"})
+	_ = json.Unmarshal(msg.Result, &result)
+	if result.ProtocolVersion != gatewayProtocolVersion {
+		t.Errorf("protocolVersion = %q, want %q for an unknown version", result.ProtocolVersion, gatewayProtocolVersion)
+	}
+}
+
+func TestGateway_ListToolsWithPrefix(t *testing.T) {
+	server := newTestGateway(t, true, map[string]*mockBackend{
+		"weather": {tools: []string{"forecast", "search"}},
+		"wiki":    {tools: []string{"search"}},
+	})
+
+	_, msg := gatewayCall(t, server, 1, "tools/list", nil)
+
+	got := toolNames(t, msg)
+	want := []string{"weather__forecast", "weather__search", "wiki__search"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tools = %v, want %v", got, want)
+	}
+}
+
+func TestGateway_ListToolsWithoutPrefixKeepsFirstDuplicate(t *testing.T) {
+	weather := &mockBackend{tools: []string{"forecast", "search"}}
+	wiki := &mockBackend{tools: []string{"search", "summary"}}
+	server := newTestGateway(t, false, map[string]*mockBackend{"weather": weather, "wiki": wiki})
+
+	_, msg := gatewayCall(t, server, 1, "tools/list", nil)
+
+	got := toolNames(t, msg)
+	want := []string{"forecast", "search", "summary"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("tools = %v, want %v", got, want)
+	}
+
+	// The duplicate resolves to the backend that sorts first
+	gatewayCall(t, server, 2, "tools/call", map[string]any{"name": "search"})
+	if calls := weather.calledTools(); len(calls) != 1 || calls[0] != "search" {
+		t.Errorf("weather calls = %v, want [search]", calls)
+	}
+	if calls := wiki.calledTools(); len(calls) != 0 {
+		t.Errorf("wiki calls = %v, want none", calls)
+	}
+}
+
+func TestGateway_CallToolRoutesToOwner(t *testing.T) {
+	weather := &mockBackend{tools: []string{"forecast"}}
+	wiki := &mockBackend{tools: []string{"search"}, sse: true}
+	server := newTestGateway(t, true, map[string]*mockBackend{"weather": weather, "wiki": wiki})
+
+	// No tools/list first: the gateway builds its routes on demand
+	_, msg := gatewayCall(t, server, 7, "tools/call", map[string]any{
+		"name":      "wiki__search",
+		"arguments": map[string]any{"query": "mcp"},
+	})
+
+	if msg.Error != nil {
+		t.Fatalf("tools/call error = %s", msg.Error)
+	}
+	if !strings.Contains(string(msg.Result), "called search") {
+		t.Errorf("result = %s, want the wiki backend's answer", msg.Result)
+	}
+	if calls := wiki.calledTools(); len(calls) != 1 || calls[0] != "search" {
+		t.Errorf("wiki calls = %v, want the unprefixed tool name", calls)
+	}
+	if calls := weather.calledTools(); len(calls) != 0 {
+		t.Errorf("weather calls = %v, want none", calls)
+	}
+}
+
+func TestGateway_CallUnknownTool(t *testing.T) {
+	server := newTestGateway(t, true, map[string]*mockBackend{
+		"weather": {tools: []string{"forecast"}},
+	})
+
+	_, msg := gatewayCall(t, server, 1, "tools/call", map[string]any{"name": "forecast"})
+
+	if code := rpcErrorCode(t, msg); code != codeInvalidParams {
+		t.Errorf("error code = %d, want %d", code, codeInvalidParams)
+	}
+}
+
+func TestGateway_UnknownToolsRefreshRoutesAtMostOncePerInterval(t *testing.T) {
+	weather := &mockBackend{tools: []string{"forecast"}}
+	server := newTestGateway(t, false, map[string]*mockBackend{"weather": weather})
+
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gatewayCall(t, server, i, "tools/call", map[string]any{"name": "missing"})
+		}()
+	}
+	wg.Wait()
+
+	if lists := weather.lists.Load(); lists != 1 {
+		t.Errorf("backend tools/list requests = %d, want 1", lists)
+	}
+}
+
+func TestGateway_KeepsRoutesOfBackendThatFailedToList(t *testing.T) {
+	weather := &mockBackend{tools: []string{"forecast"}}
+	server := newTestGateway(t, true, map[string]*mockBackend{"weather": weather})
+
+	gatewayCall(t, server, 1, "tools/list", nil)
+	weather.failList.Store(true)
+
+	_, msg := gatewayCall(t, server, 2, "tools/list", nil)
+	if got := toolNames(t, msg); len(got) != 1 || got[0] != "weather__forecast" {
+		t.Errorf("tools = %v, want the tools of the previous listing", got)
+	}
+
+	_, msg = gatewayCall(t, server, 3, "tools/call", map[string]any{"name": "weather__forecast"})
+	if msg.Error != nil {
+		t.Fatalf("tools/call error = %s", msg.Error)
+	}
+	if got := weather.calledTools(); len(got) != 1 || got[0] != "forecast" {
+		t.Errorf("called tools = %v, want [forecast]", got)
+	}
+}
+
+func TestGateway_ReinitializesExpiredBackendSession(t *testing.T) {
+	weather := &mockBackend{tools: []string{"forecast"}}
+	server := newTestGateway(t, false, map[string]*mockBackend{"weather": weather})
+
+	gatewayCall(t, server, 1, "tools/list", nil)
+	weather.expireSession()
+
+	_, msg := gatewayCall(t, server, 2, "tools/call", map[string]any{"name": "forecast"})
+	if msg.Error != nil {
+		t.Fatalf("tools/call error = %s", msg.Error)
+	}
+	if sessions := weather.sessions.Load(); sessions != 2 {
+		t.Errorf("backend sessions = %d, want 2 after the session expired", sessions)
+	}
+}
+
+func TestGateway_UnreachableBackendIsSkipped(t *testing.T) {
+	cfg := &GatewayConfig{
+		PrefixToolNames: true,
+		Backends: []GatewayBackend{
+			{Name: "down", URL: "http://127.0.0.1:1/mcp"},
+			{Name: "weather", URL: (&mockBackend{tools: []string{"forecast"}}).serve(t).URL + "/mcp"},
+		},
+	}
+	p, err := NewGateway(":0", cfg, newTestLogger(), nil)
+	if err != nil {
+		t.Fatalf("NewGateway() error = %v", err)
+	}
+	server := httptest.NewServer(p.handler)
+	defer server.Close()
+
+	_, msg := gatewayCall(t, server, 1, "tools/list", nil)
+	if got := toolNames(t, msg); len(got) != 1 || got[0] != "weather__forecast" {
+		t.Errorf("tools = %v, want only the reachable backend's tools", got)
+	}
+}
+
+func TestGateway_ProtocolHandling(t *testing.T) {
+	server := newTestGateway(t, false, map[string]*mockBackend{
+		"weather": {tools: []string{"forecast"}},
+	})
+
+	_, msg := gatewayCall(t, server, 1, "ping", nil)
+	if string(msg.Result) != "{}" {
+		t.Errorf("ping result = %s, want {}", msg.Result)
+	}
+
+	_, msg = gatewayCall(t, server, 2, "resources/list", nil)
+	if code := rpcErrorCode(t, msg); code != codeMethodNotFound {
+		t.Errorf("resources/list error code = %d, want %d", code, codeMethodNotFound)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{name: "notification", method: http.MethodPost, body: `{"jsonrpc":"2.0","method":"notifications/initialized"}`, wantStatus: http.StatusAccepted},
+		{name: "batch", method: http.MethodPost, body: `[{"jsonrpc":"2.0","id":1,"method":"ping"}]`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", method: http.MethodPost, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "server stream", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+		{name: "end session", method: http.MethodDelete, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, server.URL+"/mcp", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request error = %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestLoadGatewayConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `{"prefixToolNames":true,"backends":[{"name":"weather","url":"http://weather:8080/mcp"}]}`,
+		},
+		{
+			name:    "duplicate backend",
+			content: `{"backends":[{"name":"a","url":"http://a/mcp"},{"name":"a","url":"http://b/mcp"}]}`,
+			wantErr: "duplicate gateway backend",
+		},
+		{
+			name:    "invalid URL",
+			content: `{"backends":[{"name":"a","url":"not-a-url"}]}`,
+			wantErr: "invalid URL",
+		},
+		{
+			name:    "invalid JSON",
+			content: `{`,
+			wantErr: "failed to parse",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "gateway.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadGatewayConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadGatewayConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadGatewayConfig() error = %v", err)
+			}
+			if cfg.Path != DefaultGatewayPath || cfg.Separator != DefaultToolSeparator {
+				t.Errorf("defaults not applied: path=%q separator=%q", cfg.Path, cfg.Separator)
+			}
+			if cfg.Backends[0].Prefix != "weather" {
+				t.Errorf("prefix = %q, want the backend name", cfg.Backends[0].Prefix)
+			}
+		})
+	}
+}
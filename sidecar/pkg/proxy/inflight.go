@@ -0,0 +1,123 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// inflightTimeout is how long a request waits for its response on a
+	// stream before it is forgotten.
+	inflightTimeout = 10 * time.Minute
+
+	// inflightSweepInterval is how often timed out requests are dropped.
+	inflightSweepInterval = time.Minute
+
+	// maxInflightRequests bounds the requests remembered at once, so clients
+	// that never read their streams cannot grow the table without limit.
+	maxInflightRequests = 10000
+
+	// sseEndpointEvent is the event of the 2024-11-05 SSE transport telling
+	// clients where to post their messages.
+	sseEndpointEvent = "endpoint"
+
+	// sseSessionParam is the query parameter carrying the session of the SSE
+	// transport in the endpoint URL.
+	sseSessionParam = "sessionId"
+)
+
+// inflightRequest is a request whose response is delivered on a stream.
+type inflightRequest struct {
+	method string
+	tool   string
+	start  time.Time
+}
+
+// inflightKey identifies a request by its MCP session and JSON-RPC id.
+type inflightKey struct {
+	session string
+	id      string
+}
+
+// inflightTable holds the requests the server accepted without answering in
+// the same exchange, as with the 2024-11-05 SSE transport, until their
+// response is seen on the session's stream.
+type inflightTable struct {
+	now       func() time.Time
+	mu        sync.Mutex
+	requests  map[inflightKey]inflightRequest
+	lastSweep time.Time
+}
+
+// newInflightTable creates an empty inflightTable.
+func newInflightTable() *inflightTable {
+	return &inflightTable{
+		now:      time.Now,
+		requests: make(map[inflightKey]inflightRequest),
+	}
+}
+
+// add remembers a request until its response is seen. Requests are dropped
+// when the table is full.
+func (t *inflightTable) add(session string, id interface{}, method, tool string, start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(t.now())
+	if len(t.requests) >= maxInflightRequests {
+		return
+	}
+	t.requests[inflightKey{session: session, id: responseKey(id)}] = inflightRequest{
+		method: method,
+		tool:   tool,
+		start:  start,
+	}
+}
+
+// complete removes and returns the request a response answers.
+func (t *inflightTable) complete(session string, id interface{}) (inflightRequest, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := inflightKey{session: session, id: responseKey(id)}
+	req, ok := t.requests[key]
+	if ok {
+		delete(t.requests, key)
+	}
+	return req, ok
+}
+
+// hasSession reports whether requests of a session wait for a response.
+func (t *inflightTable) hasSession(session string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(t.now())
+	for key := range t.requests {
+		if key.session == session {
+			return true
+		}
+	}
+	return false
+}
+
+// len returns the number of requests waiting for a response.
+func (t *inflightTable) len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.requests)
+}
+
+// sweep drops requests that waited longer than inflightTimeout. Callers hold t.mu.
+func (t *inflightTable) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < inflightSweepInterval {
+		return
+	}
+	t.lastSweep = now
+
+	for key, req := range t.requests {
+		if now.Sub(req.start) >= inflightTimeout {
+			delete(t.requests, key)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInflightTable_Complete(t *testing.T) {
+	table := newInflightTable()
+	start := time.Now()
+
+	table.add("session-1", float64(1), "tools/call", "forecast", start)
+	table.add("session-2", float64(1), "ping", "", start)
+
+	if _, ok := table.complete("session-1", float64(2)); ok {
+		t.Error("complete matched an unknown id")
+	}
+	if _, ok := table.complete("session-3", float64(1)); ok {
+		t.Error("complete matched an id of another session")
+	}
+
+	req, ok := table.complete("session-1", float64(1))
+	if !ok {
+		t.Fatal("complete did not match the request")
+	}
+	if req.method != "tools/call" || req.tool != "forecast" || !req.start.Equal(start) {
+		t.Errorf("complete returned %+v, want the tools/call of forecast", req)
+	}
+	if _, ok := table.complete("session-1", float64(1)); ok {
+		t.Error("complete matched a request twice")
+	}
+	if got := table.len(); got != 1 {
+		t.Errorf("len = %d, want 1", got)
+	}
+}
+
+func TestInflightTable_Sweep(t *testing.T) {
+	now := time.Now()
+	table := newInflightTable()
+	table.now = func() time.Time { return now }
+
+	table.add("session-1", "old", "tools/call", "forecast", now.Add(-inflightTimeout))
+	table.add("session-1", "new", "tools/call", "forecast", now)
+	if got := table.len(); got != 2 {
+		t.Fatalf("len = %d, want 2 before the next sweep", got)
+	}
+
+	now = now.Add(inflightSweepInterval)
+	table.add("session-1", "later", "ping", "", now)
+
+	if _, ok := table.complete("session-1", "old"); ok {
+		t.Error("a timed out request was not swept")
+	}
+	if _, ok := table.complete("session-1", "new"); !ok {
+		t.Error("a waiting request was swept")
+	}
+}
@@ -0,0 +1,412 @@
+package proxy
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for crypto.Hash
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for crypto.Hash
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksRefreshInterval is how long fetched signing keys are used before
+	// they are loaded again.
+	jwksRefreshInterval = 5 * time.Minute
+
+	// jwksMinRefreshInterval limits how often a token signed with an unknown
+	// key can trigger loading the keys again.
+	jwksMinRefreshInterval = 30 * time.Second
+
+	// jwtClockSkew is the leeway allowed when checking exp and nbf.
+	jwtClockSkew = time.Minute
+
+	// maxJWKSSize bounds the size of a key set read from a URL or file.
+	maxJWKSSize = 1 << 20
+)
+
+// jwtAlgorithms maps the supported JWS algorithms to their hash functions.
+// Symmetric algorithms and "none" are rejected.
+var jwtAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// jwtVerifier verifies signed JWTs against a JSON Web Key Set and checks
+// their issuer, audience and validity period.
+type jwtVerifier struct {
+	keys      *keySet
+	issuer    string
+	audiences []string
+	now       func() time.Time
+}
+
+// jwtHeader is the JOSE header of a token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// verify checks the token and returns its claims.
+func (v *jwtVerifier) verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	hash, ok := jwtAlgorithms[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	key, err := v.keys.key(header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	if err := verifySignature(header.Alg, hash, key, h.Sum(nil), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if err := v.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// checkClaims checks the validity period, issuer and audience of a token.
+// Tokens without an expiry are rejected.
+func (v *jwtVerifier) checkClaims(claims map[string]any) error {
+	now := v.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(jwtClockSkew)) {
+		return errors.New("token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(jwtClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token is not valid yet")
+	}
+
+	if v.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.issuer {
+			return fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+
+	if len(v.audiences) > 0 && !audienceMatches(claims["aud"], v.audiences) {
+		return errors.New("token audience does not match")
+	}
+	return nil
+}
+
+// audienceMatches reports whether the aud claim, a string or a list of
+// strings, contains one of the accepted audiences.
+func audienceMatches(aud any, accepted []string) bool {
+	var values []string
+	switch a := aud.(type) {
+	case string:
+		values = []string{a}
+	case []any:
+		for _, v := range a {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	for _, v := range values {
+		for _, want := range accepted {
+			if v == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifySignature checks a token signature with an RSA or ECDSA key.
+func verifySignature(alg string, hash crypto.Hash, key crypto.PublicKey, digest, signature []byte) error {
+	switch alg[:2] {
+	case "RS", "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key is not an RSA key for %s", alg)
+		}
+		var err error
+		if alg[:2] == "RS" {
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(pub, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		if err != nil {
+			return errors.New("invalid token signature")
+		}
+		return nil
+
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key is not an EC key for %s", alg)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported signing algorithm %q", alg)
+}
+
+// decodeSegment decodes a base64url encoded JSON token segment.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// keySet holds the signing keys of a JSON Web Key Set read from a URL or a
+// file. Keys are loaded again after jwksRefreshInterval, and sooner when a
+// token names a key the set does not have, so rotated keys are picked up.
+type keySet struct {
+	url    string
+	file   string
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	keys      []jsonWebKey
+	attempted time.Time
+}
+
+// jsonWebKey is a parsed signing key of a key set.
+type jsonWebKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// rawJSONWebKey is a key as it appears in a key set document.
+type rawJSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// load reads the key set, failing if it holds no usable signing key.
+// The current keys are kept when loading fails.
+func (s *keySet) load() error {
+	s.attempted = s.now()
+
+	data, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	var doc struct {
+		Keys []rawJSONWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	var keys []jsonWebKey
+	for _, raw := range doc.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := raw.publicKey()
+		if err != nil {
+			// Unsupported keys are skipped so one cannot break the others
+			continue
+		}
+		keys = append(keys, jsonWebKey{kid: raw.Kid, alg: raw.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return errors.New("JWKS contains no usable signing keys")
+	}
+
+	s.keys = keys
+	return nil
+}
+
+// read returns the key set document.
+func (s *keySet) read() ([]byte, error) {
+	if s.file != "" {
+		data, err := os.ReadFile(s.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		return data, nil
+	}
+
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	return data, nil
+}
+
+// key returns the key a token with the given key ID and algorithm was signed
+// with. A token without a key ID matches any key usable for its algorithm.
+func (s *keySet) key(kid, alg string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since := s.now().Sub(s.attempted)
+	if since > jwksRefreshInterval {
+		_ = s.load()
+	}
+	if key := s.find(kid, alg); key != nil {
+		return key, nil
+	}
+
+	if since > jwksMinRefreshInterval {
+		if err := s.load(); err == nil {
+			if key := s.find(kid, alg); key != nil {
+				return key, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no signing key found for key ID %q", kid)
+}
+
+// find looks up a key. Callers hold s.mu.
+func (s *keySet) find(kid, alg string) crypto.PublicKey {
+	for _, k := range s.keys {
+		if kid != "" && k.kid != kid {
+			continue
+		}
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+		if _, isRSA := k.key.(*rsa.PublicKey); isRSA != (alg[:2] != "ES") {
+			continue
+		}
+		return k.key
+	}
+	return nil
+}
+
+// publicKey converts a JWK into an RSA or ECDSA public key.
+func (k rawJSONWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var check ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, check = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, check = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, check = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		// Reject points that are not on the curve
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x.Bytes()) > size || len(y.Bytes()) > size {
+			return nil, errors.New("invalid EC point")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		x.FillBytes(point[1 : 1+size])
+		y.FillBytes(point[1+size:])
+		if _, err := check.NewPublicKey(point); err != nil {
+			return nil, errors.New("invalid EC point")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+// decodeBigInt decodes a base64url encoded big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,601 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+)
+
+// DefaultIdentityHeader is the request header the verified client identity is
+// forwarded in when the policy does not name one.
+const DefaultIdentityHeader = "X-Forwarded-User"
+
+// codePolicyDenied is the JSON-RPC error code returned for calls denied by policy.
+const codePolicyDenied = -32003
+
+// Policy restricts which tools and resources clients may use.
+//
+// A name is permitted by a set of rules when it matches no deny pattern and,
+// if allow patterns are given, matches at least one of them. The rules of the
+// first identity entry matching the identity verified by authentication
+// replace the top-level rules.
+type Policy struct {
+	// Tools allows or denies tools by name.
+	Tools *PolicyRules `json:"tools,omitempty"`
+
+	// Resources allows or denies resources by URI.
+	Resources *PolicyRules `json:"resources,omitempty"`
+
+	// IdentityHeader is the request header the verified client identity is
+	// forwarded to the server in.
+	IdentityHeader string `json:"identityHeader,omitempty"`
+
+	// Identities holds rules for specific clients.
+	Identities []IdentityPolicy `json:"identities,omitempty"`
+}
+
+// PolicyRules are allow and deny lists of glob patterns. "*" matches any
+// sequence of characters, including "/", and "?" matches one character.
+type PolicyRules struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// IdentityPolicy holds the rules for the clients whose identity matches Names.
+type IdentityPolicy struct {
+	Names     []string     `json:"names"`
+	Tools     *PolicyRules `json:"tools,omitempty"`
+	Resources *PolicyRules `json:"resources,omitempty"`
+
+	names []*regexp.Regexp
+}
+
+// ParsePolicy parses a JSON policy and compiles its patterns.
+func ParsePolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+
+	if policy.IdentityHeader == "" {
+		policy.IdentityHeader = DefaultIdentityHeader
+	}
+
+	policy.Tools.compile()
+	policy.Resources.compile()
+	for i := range policy.Identities {
+		identity := &policy.Identities[i]
+		if len(identity.Names) == 0 {
+			return nil, fmt.Errorf("identities[%d] has no names", i)
+		}
+		identity.names = compileGlobs(identity.Names)
+		identity.Tools.compile()
+		identity.Resources.compile()
+	}
+
+	return policy, nil
+}
+
+// AllowTool reports whether the client may see and call a tool.
+func (p *Policy) AllowTool(identity, name string) bool {
+	rules := p.Tools
+	if entry := p.identityPolicy(identity); entry != nil && entry.Tools != nil {
+		rules = entry.Tools
+	}
+	return rules.permits(name)
+}
+
+// AllowResource reports whether the client may see and read a resource.
+func (p *Policy) AllowResource(identity, uri string) bool {
+	rules := p.Resources
+	if entry := p.identityPolicy(identity); entry != nil && entry.Resources != nil {
+		rules = entry.Resources
+	}
+	return rules.permits(uri)
+}
+
+// identityPolicy returns the first identity entry matching the client, if any.
+func (p *Policy) identityPolicy(identity string) *IdentityPolicy {
+	if identity == "" {
+		return nil
+	}
+	for i := range p.Identities {
+		if matchesAny(p.Identities[i].names, identity) {
+			return &p.Identities[i]
+		}
+	}
+	return nil
+}
+
+// compile compiles the allow and deny patterns.
+func (r *PolicyRules) compile() {
+	if r == nil {
+		return
+	}
+	r.allow = compileGlobs(r.Allow)
+	r.deny = compileGlobs(r.Deny)
+}
+
+// permits reports whether the rules permit a name. Missing rules permit everything.
+func (r *PolicyRules) permits(name string) bool {
+	if r == nil {
+		return true
+	}
+	if matchesAny(r.deny, name) {
+		return false
+	}
+	return len(r.allow) == 0 || matchesAny(r.allow, name)
+}
+
+// compileGlobs turns glob patterns into anchored regular expressions.
+func compileGlobs(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		var expr strings.Builder
+		expr.WriteString("^")
+		for _, r := range pattern {
+			switch r {
+			case '*':
+				expr.WriteString(".*")
+			case '?':
+				expr.WriteString(".")
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		expr.WriteString("$")
+		compiled = append(compiled, regexp.MustCompile(expr.String()))
+	}
+	return compiled
+}
+
+// matchesAny reports whether s matches one of the patterns.
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPolicy enables policy enforcement. It must be called before Start.
+func (p *Proxy) SetPolicy(policy *Policy) {
+	p.policy = policy
+}
+
+// policyMiddleware rejects tool calls and resource reads the policy denies and
+// removes hidden tools and resources from list results.
+func (p *Proxy) policyMiddleware(next http.Handler) http.Handler {
+	if p.policy == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Only identities verified by sidecar authentication are matched, never
+		// the identity header a client sent itself
+		identity := IdentityFromContext(req.Context())
+
+		// Bodies are checked whatever their Content-Type, since servers may
+		// accept JSON sent with another one
+		var listRequest bool
+		if req.Method == http.MethodPost && req.Body != nil {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+
+			messages, batch := splitBatch(body)
+			if response, denied := p.checkPolicy(req, identity, messages, batch); denied {
+				w.Header().Set("Content-Type", "application/json")
+				if response == nil {
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+				_, _ = w.Write(response)
+				return
+			}
+			listRequest = batch || isListRequest(messages)
+		}
+
+		fw := newPolicyFilterWriter(w, p.policy, identity, listRequest)
+		next.ServeHTTP(fw, req)
+		fw.finish()
+	})
+}
+
+// checkPolicy checks every request in a message or batch. When one is denied
+// it returns the response to send instead of forwarding: the whole batch is
+// rejected, with an error for each request in it. The response is nil when
+// only notifications were sent.
+func (p *Proxy) checkPolicy(req *http.Request, identity string, messages []json.RawMessage, batch bool) ([]byte, bool) {
+	var errs []json.RawMessage
+	denied := false
+
+	for _, message := range messages {
+		parsed, err := mcp.ParseRequest(message)
+		if err != nil {
+			continue
+		}
+
+		reason := p.denyReason(identity, parsed)
+		if reason != "" {
+			denied = true
+			p.logger.Warn("request denied by policy",
+				slog.String("mcp_method", parsed.Method),
+				slog.String("identity", identity),
+				slog.String("reason", reason),
+				slog.String("client_ip", getClientIP(req)),
+			)
+			if p.recorder != nil {
+				p.recorder.RecordPolicyDenial(req.Context(), parsed.Method)
+			}
+		}
+
+		if parsed.IsNotification {
+			continue
+		}
+		if reason == "" {
+			reason = "Batch contains a request denied by policy"
+		}
+		errs = append(errs, requestError(message, codePolicyDenied, reason))
+	}
+
+	if !denied {
+		return nil, false
+	}
+	if len(errs) == 0 {
+		return nil, true
+	}
+	if !batch {
+		return errs[0], true
+	}
+	response, _ := json.Marshal(errs)
+	return response, true
+}
+
+// denyReason explains why the policy denies a request, or returns an empty
+// string if it is permitted.
+func (p *Proxy) denyReason(identity string, req *mcp.ParsedRequest) string {
+	switch req.Method {
+	case mcp.MethodToolsCall:
+		if !p.policy.AllowTool(identity, req.ToolName) {
+			return fmt.Sprintf("Tool %q is not allowed", req.ToolName)
+		}
+	case mcp.MethodResourcesRead:
+		if !p.policy.AllowResource(identity, req.ResourceURI) {
+			return fmt.Sprintf("Resource %q is not allowed", req.ResourceURI)
+		}
+	}
+	return ""
+}
+
+// requestError builds a JSON-RPC error response for a rejected request,
+// keeping the request ID as sent.
+func requestError(message json.RawMessage, code int, reason string) json.RawMessage {
+	var request struct {
+		ID json.RawMessage `json:"id"`
+	}
+	_ = json.Unmarshal(message, &request)
+
+	response, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      request.ID,
+		"error": map[string]any{
+			"code":    code,
+			"message": reason,
+		},
+	})
+	return response
+}
+
+// splitBatch returns the messages of a body and whether it was a batch.
+func splitBatch(body []byte) ([]json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var messages []json.RawMessage
+		if err := json.Unmarshal(trimmed, &messages); err == nil {
+			return messages, true
+		}
+	}
+	return []json.RawMessage{trimmed}, false
+}
+
+// isListRequest reports whether a request lists tools or resources.
+func isListRequest(messages []json.RawMessage) bool {
+	for _, message := range messages {
+		var request struct {
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(message, &request); err != nil {
+			continue
+		}
+		if request.Method == mcp.MethodToolsList || request.Method == mcp.MethodResourcesList {
+			return true
+		}
+	}
+	return false
+}
+
+// filterListResult removes hidden tools and resources from a JSON-RPC
+// response or batch of responses. It returns the message unchanged when
+// nothing is hidden.
+func (p *Policy) filterListResult(identity string, data []byte) []byte {
+	messages, batch := splitBatch(data)
+
+	changed := false
+	for i, message := range messages {
+		if filtered, ok := p.filterResponse(identity, message); ok {
+			messages[i] = filtered
+			changed = true
+		}
+	}
+	if !changed {
+		return data
+	}
+
+	if !batch {
+		return messages[0]
+	}
+	filtered, err := json.Marshal(messages)
+	if err != nil {
+		return data
+	}
+	return filtered
+}
+
+// filterResponse filters the tools and resources of one response. It returns
+// false when the response lists nothing hidden.
+func (p *Policy) filterResponse(identity string, message json.RawMessage) (json.RawMessage, bool) {
+	if !bytes.Contains(message, []byte(`"tools"`)) && !bytes.Contains(message, []byte(`"resources"`)) {
+		return nil, false
+	}
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(message, &response); err != nil || response["result"] == nil {
+		return nil, false
+	}
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(response["result"], &result); err != nil {
+		return nil, false
+	}
+
+	toolsChanged := filterEntries(result, "tools", "name", func(name string) bool {
+		return p.AllowTool(identity, name)
+	})
+	resourcesChanged := filterEntries(result, "resources", "uri", func(uri string) bool {
+		return p.AllowResource(identity, uri)
+	})
+	if !toolsChanged && !resourcesChanged {
+		return nil, false
+	}
+
+	var err error
+	if response["result"], err = json.Marshal(result); err != nil {
+		return nil, false
+	}
+	filtered, err := json.Marshal(response)
+	if err != nil {
+		return nil, false
+	}
+	return filtered, true
+}
+
+// filterEntries removes the entries of result[key] whose field is not
+// allowed, and reports whether any were removed.
+func filterEntries(result map[string]json.RawMessage, key, field string, allowed func(string) bool) bool {
+	raw, ok := result[key]
+	if !ok {
+		return false
+	}
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return false
+	}
+
+	kept := make([]map[string]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		var value string
+		_ = json.Unmarshal(entry[field], &value)
+		if allowed(value) {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == len(entries) {
+		return false
+	}
+
+	filtered, err := json.Marshal(kept)
+	if err != nil {
+		return false
+	}
+	result[key] = filtered
+	return true
+}
+
+// policyFilterWriter removes hidden tools and resources from responses.
+// JSON responses to list requests are buffered and filtered as a whole; SSE
+// responses are filtered one event at a time. Other responses pass through.
+type policyFilterWriter struct {
+	http.ResponseWriter
+	policy      *Policy
+	identity    string
+	listRequest bool
+
+	mode        filterMode
+	statusCode  int
+	wroteHeader bool
+
+	// buf holds a buffered JSON body, or the incomplete SSE event
+	buf bytes.Buffer
+}
+
+// filterMode is how a policyFilterWriter handles the response body.
+type filterMode int
+
+const (
+	filterPassthrough filterMode = iota
+	filterJSON
+	filterSSE
+)
+
+// newPolicyFilterWriter creates a policyFilterWriter.
+func newPolicyFilterWriter(w http.ResponseWriter, policy *Policy, identity string, listRequest bool) *policyFilterWriter {
+	return &policyFilterWriter{
+		ResponseWriter: w,
+		policy:         policy,
+		identity:       identity,
+		listRequest:    listRequest,
+		statusCode:     http.StatusOK,
+	}
+}
+
+// WriteHeader picks the filter mode from the Content-Type. The header of a
+// buffered JSON response is sent by finish, once its length is known.
+func (fw *policyFilterWriter) WriteHeader(code int) {
+	if fw.wroteHeader {
+		return
+	}
+	fw.wroteHeader = true
+	fw.statusCode = code
+
+	contentType := fw.Header().Get("Content-Type")
+	switch {
+	case IsSSEContentType(contentType):
+		fw.mode = filterSSE
+	case fw.listRequest && isJSONContentType(contentType):
+		fw.mode = filterJSON
+	}
+
+	if fw.mode == filterPassthrough {
+		fw.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	// Filtering changes the length of the body
+	fw.Header().Del("Content-Length")
+	if fw.mode == filterSSE {
+		fw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write buffers or filters the body depending on the mode.
+func (fw *policyFilterWriter) Write(b []byte) (int, error) {
+	if !fw.wroteHeader {
+		fw.WriteHeader(http.StatusOK)
+	}
+
+	switch fw.mode {
+	case filterJSON:
+		return fw.buf.Write(b)
+	case filterSSE:
+		fw.buf.Write(b)
+		if err := fw.writeCompleteEvents(); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	default:
+		return fw.ResponseWriter.Write(b)
+	}
+}
+
+// Flush sends complete SSE events on; buffered JSON is held until finish.
+func (fw *policyFilterWriter) Flush() {
+	if fw.mode == filterJSON {
+		return
+	}
+	if flusher, ok := fw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish writes whatever is still buffered once the handler returns.
+func (fw *policyFilterWriter) finish() {
+	switch fw.mode {
+	case filterJSON:
+		body := fw.policy.filterListResult(fw.identity, fw.buf.Bytes())
+		fw.ResponseWriter.WriteHeader(fw.statusCode)
+		_, _ = fw.ResponseWriter.Write(body)
+	case filterSSE:
+		if fw.buf.Len() > 0 {
+			_, _ = fw.ResponseWriter.Write(fw.filterEvent(fw.buf.Bytes()))
+		}
+	}
+	fw.buf.Reset()
+}
+
+// writeCompleteEvents filters and writes every complete event in the buffer,
+// keeping an incomplete trailing event for the next write.
+func (fw *policyFilterWriter) writeCompleteEvents() error {
+	for {
+		data := fw.buf.Bytes()
+		end, next := eventBoundary(data)
+		if end < 0 {
+			return nil
+		}
+
+		event := fw.filterEvent(data[:end])
+		if _, err := fw.ResponseWriter.Write(append(event, data[end:next]...)); err != nil {
+			return err
+		}
+		fw.buf.Next(next)
+	}
+}
+
+// eventBoundary finds the blank line ending the first event. It returns the
+// end of the event and the start of the next one, or -1 if the event is incomplete.
+func eventBoundary(data []byte) (int, int) {
+	lf := bytes.Index(data, []byte("\n\n"))
+	crlf := bytes.Index(data, []byte("\r\n\r\n"))
+	switch {
+	case crlf >= 0 && (lf < 0 || crlf < lf):
+		return crlf, crlf + 4
+	case lf >= 0:
+		return lf, lf + 2
+	}
+	return -1, -1
+}
+
+// filterEvent filters the data of one SSE event, keeping its other fields.
+func (fw *policyFilterWriter) filterEvent(event []byte) []byte {
+	lines := strings.Split(strings.ReplaceAll(string(event), "\r\n", "\n"), "\n")
+
+	var data []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "data:") {
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if len(data) == 0 {
+		return event
+	}
+
+	payload := []byte(strings.Join(data, "\n"))
+	filtered := fw.policy.filterListResult(fw.identity, payload)
+	if bytes.Equal(filtered, payload) {
+		return event
+	}
+
+	var out strings.Builder
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "data:") {
+			out.WriteString(line)
+			out.WriteString("\n")
+		}
+	}
+	out.WriteString("data: ")
+	out.Write(filtered)
+	return []byte(out.String())
+}
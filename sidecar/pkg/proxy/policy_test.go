@@ -0,0 +1,339 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
+)
+
+const testPolicy = `{
+	"tools": {"allow": ["get_*", "search"], "deny": ["get_secret"]},
+	"resources": {"deny": ["file:///etc/*"]},
+	"identities": [
+		{"names": ["admin", "ops-*"], "tools": {"deny": ["drop_*"]}}
+	]
+}`
+
+// policyTarget is an MCP server answering list calls and counting forwarded tools/call requests.
+type policyTarget struct {
+	calls atomic.Int32
+	sse   bool
+}
+
+func (t *policyTarget) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request mcp.JSONRPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var result any
+	switch request.Method {
+	case mcp.MethodToolsList:
+		result = map[string]any{"tools": []map[string]any{
+			{"name": "get_weather"},
+			{"name": "get_secret"},
+			{"name": "search"},
+			{"name": "drop_table"},
+		}}
+	case mcp.MethodResourcesList:
+		result = map[string]any{"resources": []map[string]any{
+			{"uri": "file:///etc/passwd"},
+			{"uri": "file:///data/report.csv"},
+		}}
+	case mcp.MethodToolsCall, mcp.MethodResourcesRead:
+		t.calls.Add(1)
+		result = map[string]any{"content": []any{}}
+	}
+
+	response, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	if t.sse {
+		w.Header().Set("Content-Type", "text/event-stream")
+		// Split the event across writes to exercise event reassembly
+		half := len(response) / 2
+		fmt.Fprintf(w, "event: message\ndata: %s", response[:half])
+		w.(http.Flusher).Flush()
+		fmt.Fprintf(w, "%s\n\n", response[half:])
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprint(len(response)))
+	_, _ = w.Write(response)
+}
+
+// newPolicyProxy returns a policy-enforcing handler in front of target.
+func newPolicyProxy(t *testing.T, target http.Handler, recorder *metrics.Recorder) http.Handler {
+	t.Helper()
+
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	p, err := NewWithRecorder(":0", server.URL, newTestLogger(), recorder)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("failed to parse policy: %v", err)
+	}
+	p.SetPolicy(policy)
+
+	return p.metricsMiddleware(p.policyMiddleware(p.reverseProxy))
+}
+
+// policyPost sends a JSON-RPC body as the given verified identity and returns the response.
+func policyPost(handler http.Handler, identity, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if identity != "" {
+		req = req.WithContext(context.WithValue(req.Context(), identityContextKey{}, identity))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+// listedNames returns the names listed under key in a JSON or SSE list response.
+func listedNames(t *testing.T, body, key, field string) []string {
+	t.Helper()
+
+	if idx := strings.Index(body, "data: "); idx >= 0 {
+		body = strings.TrimSpace(body[idx+len("data: "):])
+	}
+
+	var response struct {
+		Result map[string][]map[string]string `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("failed to decode response %q: %v", body, err)
+	}
+
+	var names []string
+	for _, entry := range response.Result[key] {
+		names = append(names, entry[field])
+	}
+	return names
+}
+
+// policyErrorCode returns the JSON-RPC error code of a response body.
+func policyErrorCode(t *testing.T, body []byte) int {
+	t.Helper()
+
+	var msg rpcMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		t.Fatalf("failed to decode response %q: %v", body, err)
+	}
+	return rpcErrorCode(t, &msg)
+}
+
+func TestPolicy_Rules(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("ParsePolicy failed: %v", err)
+	}
+
+	tests := []struct {
+		identity string
+		tool     string
+		want     bool
+	}{
+		{"", "get_weather", true},
+		{"", "search", true},
+		{"", "get_secret", false},
+		{"", "drop_table", false},
+		{"", "search_all", false},
+		{"alice", "drop_table", false},
+		{"admin", "drop_table", false},
+		{"admin", "get_secret", true},
+		{"ops-oncall", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := policy.AllowTool(tt.identity, tt.tool); got != tt.want {
+			t.Errorf("AllowTool(%q, %q) = %v, want %v", tt.identity, tt.tool, got, tt.want)
+		}
+	}
+
+	if policy.AllowResource("", "file:///etc/ssl/certs/ca.pem") {
+		t.Error("expected * to match across path separators")
+	}
+	if !policy.AllowResource("", "file:///data/report.csv") {
+		t.Error("expected resources outside the deny list to be allowed")
+	}
+	if policy.IdentityHeader != DefaultIdentityHeader {
+		t.Errorf("IdentityHeader = %q, want %q", policy.IdentityHeader, DefaultIdentityHeader)
+	}
+}
+
+func TestParsePolicy_Invalid(t *testing.T) {
+	for _, data := range []string{`{"tools": `, `{"identities": [{"names": []}]}`} {
+		if _, err := ParsePolicy([]byte(data)); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
+
+func TestPolicy_DeniedToolCall(t *testing.T) {
+	target := &policyTarget{}
+	recorder, err := metrics.NewRecorder("test", "target")
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+	handler := newPolicyProxy(t, target, recorder)
+
+	rr := policyPost(handler, "", `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"get_secret"}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	if code := policyErrorCode(t, rr.Body.Bytes()); code != codePolicyDenied {
+		t.Errorf("error code = %d, want %d", code, codePolicyDenied)
+	}
+	if !strings.Contains(rr.Body.String(), `"id":"call-1"`) {
+		t.Errorf("expected the request ID to be kept, got %s", rr.Body.String())
+	}
+	if target.calls.Load() != 0 {
+		t.Error("denied call was forwarded")
+	}
+
+	metricsBody := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(metricsBody, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(metricsBody.Body.String(), `mcp_policy_denials_total{method="tools/call"`) {
+		t.Error("expected mcp_policy_denials_total to be recorded")
+	}
+
+	rr = policyPost(handler, "", `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_weather"}}`)
+	if strings.Contains(rr.Body.String(), `"error"`) || target.calls.Load() != 1 {
+		t.Errorf("allowed call was not forwarded: %s", rr.Body.String())
+	}
+}
+
+func TestPolicy_IdentityRules(t *testing.T) {
+	target := &policyTarget{}
+	handler := newPolicyProxy(t, target, nil)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_secret"}}`
+	if rr := policyPost(handler, "admin", body); strings.Contains(rr.Body.String(), `"error"`) {
+		t.Errorf("admin should be allowed get_secret: %s", rr.Body.String())
+	}
+
+	body = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"drop_table"}}`
+	if rr := policyPost(handler, "ops-oncall", body); policyErrorCode(t, rr.Body.Bytes()) != codePolicyDenied {
+		t.Errorf("ops-oncall should be denied drop_table: %s", rr.Body.String())
+	}
+}
+
+func TestPolicy_IgnoresIdentityHeaderOfClients(t *testing.T) {
+	target := &policyTarget{}
+	handler := newPolicyProxy(t, target, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_secret"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DefaultIdentityHeader, "admin")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if policyErrorCode(t, rr.Body.Bytes()) != codePolicyDenied {
+		t.Errorf("a client claiming admin should be denied get_secret: %s", rr.Body.String())
+	}
+}
+
+func TestPolicy_DeniedResourceRead(t *testing.T) {
+	target := &policyTarget{}
+	handler := newPolicyProxy(t, target, nil)
+
+	rr := policyPost(handler, "", `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"file:///etc/passwd"}}`)
+	if policyErrorCode(t, rr.Body.Bytes()) != codePolicyDenied {
+		t.Errorf("expected a policy error, got %s", rr.Body.String())
+	}
+	if target.calls.Load() != 0 {
+		t.Error("denied read was forwarded")
+	}
+}
+
+func TestPolicy_BatchWithDeniedCall(t *testing.T) {
+	target := &policyTarget{}
+	handler := newPolicyProxy(t, target, nil)
+
+	rr := policyPost(handler, "", `[
+		{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search"}},
+		{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_secret"}},
+		{"jsonrpc":"2.0","method":"notifications/progress"}
+	]`)
+
+	var responses []json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("expected a batch response, got %s", rr.Body.String())
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected an error per request, got %d", len(responses))
+	}
+	for _, response := range responses {
+		if policyErrorCode(t, response) != codePolicyDenied {
+			t.Errorf("expected a policy error, got %s", response)
+		}
+	}
+	if target.calls.Load() != 0 {
+		t.Error("batch with a denied call was forwarded")
+	}
+}
+
+func TestPolicy_FiltersListResults(t *testing.T) {
+	for _, sse := range []bool{false, true} {
+		t.Run(fmt.Sprintf("sse=%v", sse), func(t *testing.T) {
+			handler := newPolicyProxy(t, &policyTarget{sse: sse}, nil)
+
+			rr := policyPost(handler, "", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+			tools := listedNames(t, rr.Body.String(), "tools", "name")
+			if strings.Join(tools, ",") != "get_weather,search" {
+				t.Errorf("tools = %v, want [get_weather search]", tools)
+			}
+			if length := rr.Header().Get("Content-Length"); length != "" && length != fmt.Sprint(rr.Body.Len()) {
+				t.Errorf("Content-Length = %s, body has %d bytes", length, rr.Body.Len())
+			}
+
+			rr = policyPost(handler, "admin", `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+			tools = listedNames(t, rr.Body.String(), "tools", "name")
+			if strings.Join(tools, ",") != "get_weather,get_secret,search" {
+				t.Errorf("admin tools = %v", tools)
+			}
+
+			rr = policyPost(handler, "", `{"jsonrpc":"2.0","id":3,"method":"resources/list"}`)
+			resources := listedNames(t, rr.Body.String(), "resources", "uri")
+			if strings.Join(resources, ",") != "file:///data/report.csv" {
+				t.Errorf("resources = %v", resources)
+			}
+		})
+	}
+}
+
+func TestPolicy_FiltersSSEStream(t *testing.T) {
+	// Legacy SSE servers answer on the GET stream rather than the POST
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: endpoint\ndata: /messages?sessionId=abc\n\n")
+		fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"tools\":[{\"name\":\"search\"},{\"name\":\"drop_table\"}]}}\n\n")
+	})
+	handler := newPolicyProxy(t, target, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sse", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	body, _ := io.ReadAll(rr.Body)
+	if !strings.Contains(string(body), "event: endpoint\ndata: /messages?sessionId=abc\n\n") {
+		t.Errorf("expected other events to pass through unchanged, got %q", body)
+	}
+	if strings.Contains(string(body), "drop_table") || !strings.Contains(string(body), "search") {
+		t.Errorf("expected drop_table to be filtered from the stream, got %q", body)
+	}
+}
@@ -16,6 +16,9 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/audit"
 	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
 	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
 )
@@ -31,6 +34,10 @@ type Proxy struct {
 	// reverseProxy is the underlying HTTP reverse proxy.
 	reverseProxy *httputil.ReverseProxy
 
+	// handler serves the proxied traffic: the reverse proxy, or the gateway
+	// in gateway mode. Metrics are recorded around it.
+	handler http.Handler
+
 	// server is the HTTP server.
 	server *http.Server
 
@@ -39,6 +46,28 @@ type Proxy struct {
 
 	// recorder is the metrics recorder (optional, can be nil).
 	recorder *metrics.Recorder
+
+	// policy restricts the tools and resources clients may use (optional, can be nil).
+	policy *Policy
+
+	// auth verifies the credentials of incoming requests (optional, can be nil).
+	auth *Authenticator
+
+	// rateLimits throttles clients and tools (optional, can be nil).
+	rateLimits *RateLimits
+
+	// tracer creates a span for every request (optional, can be nil).
+	tracer trace.Tracer
+
+	// auditLogger records tool calls and resource reads (optional, can be nil).
+	auditLogger *audit.Logger
+
+	// inflight holds requests answered on a stream until their response is
+	// seen (set when metrics are recorded).
+	inflight *inflightTable
+
+	// drain tracks the requests in flight for a graceful shutdown.
+	drain drainState
 }
 
 // New creates a new Proxy instance.
@@ -66,9 +95,13 @@ func NewWithRecorder(listenAddr, targetAddr string, logger *slog.Logger, recorde
 		logger:     logger,
 		recorder:   recorder,
 	}
+	if recorder != nil {
+		p.inflight = newInflightTable()
+	}
 
 	// Create the reverse proxy
 	p.reverseProxy = p.createReverseProxy()
+	p.handler = p.reverseProxy
 
 	return p, nil
 }
@@ -135,6 +168,9 @@ func (p *Proxy) modifyRequest(req *http.Request) {
 	if req.Header.Get("X-Forwarded-Host") == "" {
 		req.Header.Set("X-Forwarded-Host", req.Host)
 	}
+
+	// Continue the trace of the request at the target
+	p.injectTraceContext(req)
 }
 
 // errorHandler handles errors when proxying to the target.
@@ -167,6 +203,11 @@ func getClientIP(req *http.Request) string {
 	}
 
 	// Fall back to RemoteAddr
+	return remoteIP(req)
+}
+
+// remoteIP returns the IP of the connection a request arrived on.
+func remoteIP(req *http.Request) string {
 	ip, _, err := net.SplitHostPort(req.RemoteAddr)
 	if err != nil {
 		return req.RemoteAddr
@@ -178,6 +219,9 @@ func getClientIP(req *http.Request) string {
 func (p *Proxy) metricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		start := time.Now()
+
+		req, span := p.startSpan(req)
+		defer span.End()
 		ctx := req.Context()
 
 		// Track active connections
@@ -186,10 +230,12 @@ func (p *Proxy) metricsMiddleware(next http.Handler) http.Handler {
 			defer p.recorder.DecrementConnections(ctx)
 		}
 
-		// Capture request body for JSON parsing
+		// Capture request body for JSON parsing. POST bodies are captured
+		// whatever their Content-Type, since servers may accept JSON sent with
+		// another one, and their tool calls must still be audited
 		var reqBody []byte
 		var reqSize int64
-		if req.Body != nil && isJSONContentType(req.Header.Get("Content-Type")) {
+		if req.Body != nil && (req.Method == http.MethodPost || isJSONContentType(req.Header.Get("Content-Type"))) {
 			bodyBytes, err := io.ReadAll(req.Body)
 			if err == nil {
 				reqBody = bodyBytes
@@ -198,7 +244,7 @@ func (p *Proxy) metricsMiddleware(next http.Handler) http.Handler {
 				req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 			}
 		} else {
-			// Use Content-Length for non-JSON requests
+			// Use Content-Length for other requests
 			reqSize = req.ContentLength
 			if reqSize < 0 {
 				reqSize = 0
@@ -207,6 +253,8 @@ func (p *Proxy) metricsMiddleware(next http.Handler) http.Handler {
 
 		// Use SSE-aware response writer that can detect and handle SSE responses
 		sw := newSSEAwareWriter(w, p.recorder, req.Method)
+		sw.sessionID = req.Header.Get(headerSessionID)
+		sw.completeResponse = p.completeStreamedResponse
 
 		// Defer SSE connection close handling.
 		// IMPORTANT: This must be in a defer because for SSE connections,
@@ -256,6 +304,10 @@ func (p *Proxy) metricsMiddleware(next http.Handler) http.Handler {
 			}
 		}
 
+		identity := IdentityFromContext(ctx)
+		annotateSpan(span, parsedReq, parsedResp, sw.statusCode, identity)
+		p.auditRequests(req, sw, reqBody, identity, start, duration)
+
 		// Log the request
 		p.logger.Info("request",
 			slog.String("http_method", req.Method),
@@ -268,27 +320,30 @@ func (p *Proxy) metricsMiddleware(next http.Handler) http.Handler {
 			slog.Int64("request_bytes", reqSize),
 			slog.Int64("response_bytes", sw.bytesWritten),
 			slog.Bool("sse", sw.isSSE),
+			slog.String("identity", identity),
 		)
 
 		// Record metrics
 		if p.recorder != nil {
 			p.recorder.RecordRequest(ctx, mcpMethod, sw.statusCode, duration, reqSize, sw.bytesWritten)
+			if identity != "" {
+				p.recorder.RecordAuthenticatedRequest(ctx, identity, mcpMethod)
+			}
+
+			// Record tool calls, and the duration and errors of answered
+			// requests, for every request of a message or batch
+			p.recordRequests(ctx, req, sw, reqBody, start, duration)
 
 			// Record MCP-specific metrics
 			if parsedReq != nil {
-				// Record tool calls
-				if parsedReq.Method == mcp.MethodToolsCall && parsedReq.ToolName != "" {
-					p.recorder.RecordToolCall(ctx, parsedReq.ToolName)
-				}
-
 				// Record resource reads
 				if parsedReq.Method == mcp.MethodResourcesRead && parsedReq.ResourceURI != "" {
 					p.recorder.RecordResourceRead(ctx, parsedReq.ResourceURI)
 				}
 			}
 
-			// Record errors (from JSON response only, SSE errors are tracked separately)
-			if parsedResp != nil && parsedResp.IsError {
+			// Record errors answering requests that could not be parsed
+			if parsedReq == nil && parsedResp != nil && parsedResp.IsError {
 				p.recorder.RecordError(ctx, mcpMethod, parsedResp.ErrorCode)
 			}
 		}
@@ -310,6 +365,14 @@ type sseAwareWriter struct {
 	// SSE event accumulator (per-connection)
 	sseEventType string
 	sseEventData strings.Builder
+	// responses are the parsed JSON-RPC responses of the body, by id
+	responses map[string]*mcp.ParsedResponse
+	// sessionID is the MCP session of the stream, from the Mcp-Session-Id
+	// header or the endpoint event of the SSE transport
+	sessionID string
+	// completeResponse matches a response seen on the stream to the
+	// in-flight request it answers (optional, can be nil)
+	completeResponse func(ctx context.Context, session string, resp *mcp.ParsedResponse) bool
 }
 
 // newSSEAwareWriter creates a new SSE-aware response writer.
@@ -362,8 +425,8 @@ func (sw *sseAwareWriter) Write(b []byte) (int, error) {
 		// POST requests with SSE content type are Streamable HTTP request-responses,
 		// not long-lived SSE streams, so we don't track SSE event metrics for them.
 		sw.parseSSEData(b)
-	} else if !sw.isSSE {
-		// For non-SSE, capture body for later parsing
+	} else {
+		// For non-SSE and Streamable HTTP responses, capture body for later parsing
 		if sw.body.Len() < sw.maxCapture {
 			remaining := sw.maxCapture - sw.body.Len()
 			if len(b) <= remaining {
@@ -384,11 +447,155 @@ func (sw *sseAwareWriter) Flush() {
 	}
 }
 
-// Body returns the captured response body (for non-SSE responses).
+// Body returns the captured response body (for non-SSE responses and
+// Streamable HTTP event streams).
 func (sw *sseAwareWriter) Body() []byte {
 	return sw.body.Bytes()
 }
 
+// Responses returns the JSON-RPC responses of the captured body, sent as JSON
+// or as the events of a Streamable HTTP stream, keyed by their id.
+// They are parsed on first use.
+func (sw *sseAwareWriter) Responses() map[string]*mcp.ParsedResponse {
+	if sw.responses != nil {
+		return sw.responses
+	}
+	sw.responses = map[string]*mcp.ParsedResponse{}
+
+	var payloads [][]byte
+	body := sw.Body()
+	switch {
+	case sw.isSSE:
+		payloads = sseDataPayloads(body)
+	case isJSONContentType(sw.Header().Get("Content-Type")):
+		payloads = [][]byte{body}
+	}
+
+	for _, payload := range payloads {
+		messages, _ := splitBatch(payload)
+		for _, message := range messages {
+			if resp, err := mcp.ParseResponse(message); err == nil && resp.ID != nil {
+				sw.responses[responseKey(resp.ID)] = resp
+			}
+		}
+	}
+	return sw.responses
+}
+
+// responseKey returns the key of a JSON-RPC id in Responses.
+func responseKey(id interface{}) string {
+	return fmt.Sprint(id)
+}
+
+// sseDataPayloads returns the data of every complete event of an SSE body.
+func sseDataPayloads(body []byte) [][]byte {
+	var payloads [][]byte
+	for {
+		end, next := eventBoundary(body)
+		if end < 0 {
+			return payloads
+		}
+
+		var data []string
+		for _, line := range strings.Split(strings.ReplaceAll(string(body[:end]), "\r\n", "\n"), "\n") {
+			if strings.HasPrefix(line, "data:") {
+				data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			}
+		}
+		if len(data) > 0 {
+			payloads = append(payloads, []byte(strings.Join(data, "\n")))
+		}
+		body = body[next:]
+	}
+}
+
+// recordRequests records every request of a message or batch. Requests
+// answered in this exchange get their duration and errors recorded. Requests
+// the server accepted to answer on the session's stream, as with the SSE
+// transport, are remembered in the in-flight table until their response is seen.
+func (p *Proxy) recordRequests(ctx context.Context, req *http.Request, sw *sseAwareWriter, reqBody []byte,
+	start time.Time, duration time.Duration) {
+	if len(reqBody) == 0 {
+		return
+	}
+
+	messages, _ := splitBatch(reqBody)
+	for _, message := range messages {
+		parsed, err := mcp.ParseRequest(message)
+		if err != nil {
+			continue
+		}
+		if parsed.Method == mcp.MethodToolsCall && parsed.ToolName != "" {
+			p.recorder.RecordToolCall(ctx, parsed.ToolName)
+		}
+		if parsed.IsNotification {
+			continue
+		}
+
+		resp := sw.Responses()[responseKey(parsed.ID)]
+		switch {
+		case resp != nil:
+			p.recordResponse(ctx, parsed.Method, parsed.ToolName, resp, duration)
+		case sw.statusCode >= http.StatusBadRequest:
+			if parsed.Method == mcp.MethodToolsCall && parsed.ToolName != "" {
+				p.recorder.RecordToolResult(ctx, parsed.ToolName, duration, metrics.ToolErrorHTTP)
+			}
+		case sw.statusCode == http.StatusAccepted && p.inflight != nil:
+			if session := requestSessionID(req, sw); session != "" {
+				p.inflight.add(session, parsed.ID, parsed.Method, parsed.ToolName, start)
+			}
+		}
+	}
+}
+
+// recordResponse records the duration of an answered request and its error,
+// by method and, for tool calls, by tool.
+func (p *Proxy) recordResponse(ctx context.Context, method, tool string, resp *mcp.ParsedResponse, duration time.Duration) {
+	p.recorder.RecordRPCDuration(ctx, method, duration)
+	if resp.IsError {
+		p.recorder.RecordError(ctx, method, resp.ErrorCode)
+	}
+
+	if method != mcp.MethodToolsCall || tool == "" {
+		return
+	}
+	switch {
+	case resp.IsError:
+		p.recorder.RecordToolResult(ctx, tool, duration, metrics.ToolErrorJSONRPC)
+	case resp.IsToolError:
+		p.recorder.RecordToolResult(ctx, tool, duration, metrics.ToolErrorResult)
+	default:
+		p.recorder.RecordToolResult(ctx, tool, duration, "")
+	}
+}
+
+// completeStreamedResponse records a response seen on a session's stream
+// against the in-flight request it answers. It reports whether one was found.
+func (p *Proxy) completeStreamedResponse(ctx context.Context, session string, resp *mcp.ParsedResponse) bool {
+	if p.inflight == nil || session == "" || resp.ID == nil {
+		return false
+	}
+	call, ok := p.inflight.complete(session, resp.ID)
+	if !ok {
+		return false
+	}
+	p.recordResponse(ctx, call.method, call.tool, resp, time.Since(call.start))
+	return true
+}
+
+// requestSessionID returns the MCP session of a request: the Streamable HTTP
+// session header, the one assigned in the response, or the sessionId query
+// parameter of the SSE transport.
+func requestSessionID(req *http.Request, sw *sseAwareWriter) string {
+	if sessionID := req.Header.Get(headerSessionID); sessionID != "" {
+		return sessionID
+	}
+	if sessionID := sw.Header().Get(headerSessionID); sessionID != "" {
+		return sessionID
+	}
+	return req.URL.Query().Get(sseSessionParam)
+}
+
 // parseSSEData parses SSE events from the written data and records metrics.
 func (sw *sseAwareWriter) parseSSEData(data []byte) {
 	if sw.recorder == nil {
@@ -418,6 +625,11 @@ func (sw *sseAwareWriter) parseSSEData(data []byte) {
 			ctx := context.Background()
 			sw.recorder.SSEEventReceived(ctx, eventType)
 
+			// The endpoint event of the SSE transport names the session
+			if eventType == sseEndpointEvent && sw.sessionID == "" {
+				sw.sessionID = endpointSessionID(strings.TrimSpace(sw.sseEventData.String()))
+			}
+
 			// Try to parse MCP data
 			if sw.sseEventData.Len() > 0 {
 				dataStr := strings.TrimSpace(sw.sseEventData.String())
@@ -448,14 +660,32 @@ func (sw *sseAwareWriter) parseMCPFromSSE(ctx context.Context, data string) {
 		return
 	}
 
-	// Try parsing as response
-	if resp, err := mcp.ParseResponse([]byte(data)); err == nil {
+	// Try parsing as responses, matching them to the requests they answer
+	messages, _ := splitBatch([]byte(data))
+	for _, message := range messages {
+		resp, err := mcp.ParseResponse(message)
+		if err != nil {
+			continue
+		}
+		if sw.completeResponse != nil && sw.completeResponse(ctx, sw.sessionID, resp) {
+			continue
+		}
 		if resp.IsError {
 			sw.recorder.RecordError(ctx, "sse", resp.ErrorCode)
 		}
 	}
 }
 
+// endpointSessionID returns the session of an SSE transport endpoint event,
+// whose data is the URL clients post their messages to.
+func endpointSessionID(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get(sseSessionParam)
+}
+
 // recordSSEClose is called when the SSE connection is closed to record metrics.
 // Only records for GET requests (true long-lived SSE streams), matching the logic in WriteHeader.
 func (sw *sseAwareWriter) recordSSEClose() {
@@ -499,7 +729,7 @@ func (lrw *loggingResponseWriter) Flush() {
 // Start starts the proxy server and blocks until the context is cancelled.
 func (p *Proxy) Start(ctx context.Context) error {
 	// Create the HTTP handler with metrics middleware
-	handler := p.metricsMiddleware(p.reverseProxy)
+	handler := p.drainMiddleware(p.authMiddleware(p.metricsMiddleware(p.policyMiddleware(p.rateLimitMiddleware(p.handler)))))
 
 	// Create the HTTP server
 	// Note: WriteTimeout is set to 0 to support long-lived SSE connections.
@@ -518,7 +748,7 @@ func (p *Proxy) Start(ctx context.Context) error {
 	go func() {
 		p.logger.Info("starting HTTP server",
 			slog.String("listen_addr", p.listenAddr),
-			slog.String("target", p.target.String()),
+			slog.String("target", p.targetString()),
 		)
 		if err := p.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
@@ -552,7 +782,7 @@ func (p *Proxy) Start(ctx context.Context) error {
 // StartWithTLS starts the proxy server with TLS and blocks until the context is cancelled.
 func (p *Proxy) StartWithTLS(ctx context.Context, tlsConfig *tls.Config, certFile, keyFile string) error {
 	// Create the HTTP handler with metrics middleware
-	handler := p.metricsMiddleware(p.reverseProxy)
+	handler := p.drainMiddleware(p.authMiddleware(p.metricsMiddleware(p.policyMiddleware(p.rateLimitMiddleware(p.handler)))))
 
 	// Create the HTTPS server
 	// Note: WriteTimeout is set to 0 to support long-lived SSE connections.
@@ -572,7 +802,7 @@ func (p *Proxy) StartWithTLS(ctx context.Context, tlsConfig *tls.Config, certFil
 	go func() {
 		p.logger.Info("starting HTTPS server",
 			slog.String("listen_addr", p.listenAddr),
-			slog.String("target", p.target.String()),
+			slog.String("target", p.targetString()),
 		)
 		if err := p.server.ListenAndServeTLS(certFile, keyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			errChan <- err
@@ -609,6 +839,18 @@ func (p *Proxy) ListenAddr() string {
 }
 
 // TargetURL returns the target URL the proxy forwards requests to.
+// It is nil for a gateway or a session router, which have several backends.
 func (p *Proxy) TargetURL() *url.URL {
 	return p.target
 }
+
+// targetString describes the target for logging.
+func (p *Proxy) targetString() string {
+	if _, ok := p.handler.(*SessionRouter); ok {
+		return "session-router"
+	}
+	if p.target == nil {
+		return "gateway"
+	}
+	return p.target.String()
+}
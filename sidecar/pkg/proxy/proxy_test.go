@@ -619,6 +619,129 @@ func TestProxy_NonSSEResponseRecordsMetrics(t *testing.T) {
 	}
 }
 
+// TestProxy_ToolCallMetrics verifies that tool calls answered in the exchange,
+// here a batch answered on a Streamable HTTP stream, record their duration and
+// errors by tool, including results with isError set.
+func TestProxy_ToolCallMetrics(t *testing.T) {
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: [{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"content\":[]}}," +
+			"{\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{\"isError\":true}}," +
+			"{\"jsonrpc\":\"2.0\",\"id\":3,\"error\":{\"code\":-32602,\"message\":\"Unknown tool\"}}]\n\n"))
+	}))
+	defer targetServer.Close()
+
+	recorder, err := metrics.NewRecorder("test", targetServer.URL)
+	if err != nil {
+		t.Fatalf("Failed to create metrics recorder: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+
+	p, err := NewWithRecorder(":0", targetServer.URL, newTestLogger(), recorder)
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+
+	reqBody := []byte(`[` +
+		`{"jsonrpc":"2.0","method":"tools/call","params":{"name":"forecast"},"id":1},` +
+		`{"jsonrpc":"2.0","method":"tools/call","params":{"name":"alerts"},"id":2},` +
+		`{"jsonrpc":"2.0","method":"tools/call","params":{"name":"missing"},"id":3}]`)
+	req := httptest.NewRequest(http.MethodPost, "/mcp", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	p.metricsMiddleware(p.reverseProxy).ServeHTTP(httptest.NewRecorder(), req)
+
+	metricsRr := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(metricsRr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	metricsBody := metricsRr.Body.String()
+
+	for _, tool := range []string{"forecast", "alerts", "missing"} {
+		if !hasMetricLine(metricsBody, "mcp_tool_call_duration_seconds_count{", `tool_name="`+tool+`"`) {
+			t.Errorf("Expected a duration to be recorded for tool %s", tool)
+		}
+		if !hasMetricLine(metricsBody, "mcp_tool_calls_total{", `tool_name="`+tool+`"`) {
+			t.Errorf("Expected a call to be recorded for tool %s", tool)
+		}
+	}
+	if !hasMetricLine(metricsBody, `mcp_tool_errors_total{error_type="tool"`, `tool_name="alerts"`) {
+		t.Error("Expected the isError result of alerts to be recorded as a tool error")
+	}
+	if !hasMetricLine(metricsBody, `mcp_tool_errors_total{error_type="jsonrpc"`, `tool_name="missing"`) {
+		t.Error("Expected the JSON-RPC error of missing to be recorded")
+	}
+	if hasMetricLine(metricsBody, "mcp_tool_errors_total{", `tool_name="forecast"`) {
+		t.Error("Expected no error for the successful forecast call")
+	}
+}
+
+// TestProxy_SSETransportResponseMetrics verifies that responses sent on the
+// stream of the SSE transport are matched to the requests they answer.
+func TestProxy_SSETransportResponseMetrics(t *testing.T) {
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("event: endpoint\ndata: /message?sessionId=abc\n\n"))
+		w.Write([]byte("event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":7,\"result\":{\"isError\":true}}\n\n"))
+		w.Write([]byte("event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":8,\"error\":{\"code\":-32002,\"message\":\"Resource not found\"}}\n\n"))
+	}))
+	defer targetServer.Close()
+
+	recorder, err := metrics.NewRecorder("test", targetServer.URL)
+	if err != nil {
+		t.Fatalf("Failed to create metrics recorder: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+
+	p, err := NewWithRecorder(":0", targetServer.URL, newTestLogger(), recorder)
+	if err != nil {
+		t.Fatalf("Failed to create proxy: %v", err)
+	}
+	handler := p.metricsMiddleware(p.reverseProxy)
+
+	for _, body := range []string{
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"forecast"}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"resources/read","params":{"uri":"file:///missing"}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/message?sessionId=abc", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	if got := p.inflight.len(); got != 2 {
+		t.Fatalf("Expected 2 requests in flight after the accepted posts, got %d", got)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/sse", nil))
+	if got := p.inflight.len(); got != 0 {
+		t.Errorf("Expected the stream to answer every request in flight, %d left", got)
+	}
+
+	metricsRr := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(metricsRr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	metricsBody := metricsRr.Body.String()
+
+	for _, method := range []string{"tools/call", "resources/read"} {
+		if !hasMetricLine(metricsBody, "mcp_rpc_duration_seconds_count{", `method="`+method+`"`) {
+			t.Errorf("Expected a duration to be recorded for %s", method)
+		}
+	}
+	if !hasMetricLine(metricsBody, "mcp_tool_call_duration_seconds_count{", `tool_name="forecast"`) {
+		t.Error("Expected a duration to be recorded for tool forecast")
+	}
+	if !hasMetricLine(metricsBody, `mcp_tool_errors_total{error_type="tool"`, `tool_name="forecast"`) {
+		t.Error("Expected the isError result of forecast to be recorded as a tool error")
+	}
+	if !hasMetricLine(metricsBody, "mcp_request_errors_total{", `method="resources/read"`) {
+		t.Error("Expected the streamed error to be attributed to resources/read")
+	}
+	if hasMetricLine(metricsBody, "mcp_request_errors_total{", `method="sse"`) {
+		t.Error("Expected no unattributed SSE errors")
+	}
+}
+
 // TestProxy_GETSSERequestRecordsSSEConnectionMetrics verifies that GET requests
 // with SSE content type correctly record SSE connection metrics.
 // This is the expected behavior for true long-lived SSE streams (e.g., server notifications).
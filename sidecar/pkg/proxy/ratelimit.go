@@ -0,0 +1,342 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+)
+
+// Keys a rate limit rule can count requests by.
+const (
+	RateLimitByIP       = "ip"
+	RateLimitByIdentity = "identity"
+	RateLimitByTool     = "tool"
+)
+
+// codeRateLimited is the JSON-RPC error code returned for rate limited requests.
+const codeRateLimited = -32004
+
+// rateLimitSweepInterval is how often idle buckets are dropped.
+const rateLimitSweepInterval = time.Minute
+
+// maxRateLimitBuckets bounds the buckets kept across all rules. When it is
+// reached, the least recently used bucket is dropped to make room.
+const maxRateLimitBuckets = 10000
+
+// RateLimits are token-bucket limits on the requests clients send.
+// A request must pass every rule that applies to it.
+type RateLimits struct {
+	Rules []RateLimitRule `json:"rules"`
+
+	// TrustForwardedFor takes the client IP of ip keys from the X-Real-IP and
+	// X-Forwarded-For headers instead of the connection. Only set it when every
+	// client reaches the sidecar through a proxy that overwrites these headers,
+	// since clients can otherwise send any address to escape their limit.
+	TrustForwardedFor bool `json:"trustForwardedFor,omitempty"`
+
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// RateLimitRule limits requests to RequestsPerMinute, allowing bursts of
+// Burst requests, separately for each combination of the By keys.
+//
+// A rule counting by tool, or with Tools patterns, applies only to tools/call
+// requests (for the matching tools). Other rules apply to every request.
+type RateLimitRule struct {
+	// Name identifies the rule in metrics and logs.
+	Name string `json:"name,omitempty"`
+
+	// By lists the keys requests are counted by: ip, identity and tool.
+	By []string `json:"by"`
+
+	// RequestsPerMinute is the rate tokens are added at.
+	RequestsPerMinute int `json:"requestsPerMinute"`
+
+	// Burst is the bucket size. Defaults to RequestsPerMinute.
+	Burst int `json:"burst,omitempty"`
+
+	// Tools restricts the rule to calls of tools matching these glob patterns.
+	Tools []string `json:"tools,omitempty"`
+
+	tools []*regexp.Regexp
+}
+
+// tokenBucket holds the tokens left for one key of a rule.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimitRequest is what rules key a request by.
+type rateLimitRequest struct {
+	ip       string
+	identity string
+	tool     string
+}
+
+// ParseRateLimits parses JSON rate limits and compiles their patterns.
+func ParseRateLimits(data []byte) (*RateLimits, error) {
+	limits := &RateLimits{}
+	if err := json.Unmarshal(data, limits); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limits: %w", err)
+	}
+	if len(limits.Rules) == 0 {
+		return nil, errors.New("rate limits have no rules")
+	}
+
+	for i := range limits.Rules {
+		rule := &limits.Rules[i]
+		if len(rule.By) == 0 {
+			return nil, fmt.Errorf("rules[%d] has no keys", i)
+		}
+		for _, key := range rule.By {
+			if key != RateLimitByIP && key != RateLimitByIdentity && key != RateLimitByTool {
+				return nil, fmt.Errorf("rules[%d] has unknown key %q", i, key)
+			}
+		}
+		if rule.RequestsPerMinute <= 0 {
+			return nil, fmt.Errorf("rules[%d] must allow at least one request per minute", i)
+		}
+		if rule.Burst <= 0 {
+			rule.Burst = rule.RequestsPerMinute
+		}
+		if rule.Name == "" {
+			rule.Name = strings.Join(rule.By, "+")
+		}
+		rule.tools = compileGlobs(rule.Tools)
+	}
+
+	limits.now = time.Now
+	limits.buckets = make(map[string]*tokenBucket)
+	return limits, nil
+}
+
+// applies reports whether the rule counts a request.
+func (r *RateLimitRule) applies(method string, req rateLimitRequest) bool {
+	if len(r.tools) > 0 || r.countsBy(RateLimitByTool) {
+		if method != mcp.MethodToolsCall {
+			return false
+		}
+		return len(r.tools) == 0 || matchesAny(r.tools, req.tool)
+	}
+	return true
+}
+
+// countsBy reports whether the rule counts requests by a key.
+func (r *RateLimitRule) countsBy(key string) bool {
+	for _, k := range r.By {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketKey returns the bucket a request is counted in for rule i.
+func (r *RateLimitRule) bucketKey(i int, req rateLimitRequest) string {
+	var key strings.Builder
+	key.WriteString(strconv.Itoa(i))
+	for _, by := range r.By {
+		key.WriteByte(0)
+		switch by {
+		case RateLimitByIP:
+			key.WriteString(req.ip)
+		case RateLimitByIdentity:
+			key.WriteString(req.identity)
+		case RateLimitByTool:
+			key.WriteString(req.tool)
+		}
+	}
+	return key.String()
+}
+
+// allow takes a token from every rule applying to the request. It returns
+// the first rule without a token left and how long until one is added.
+// Tokens taken from other rules are not returned, so a rejected request
+// still counts against them.
+func (l *RateLimits) allow(method string, req rateLimitRequest) (*RateLimitRule, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	for i := range l.Rules {
+		rule := &l.Rules[i]
+		if !rule.applies(method, req) {
+			continue
+		}
+
+		rate := float64(rule.RequestsPerMinute) / 60
+		key := rule.bucketKey(i, req)
+		bucket, ok := l.buckets[key]
+		if !ok {
+			if len(l.buckets) >= maxRateLimitBuckets {
+				l.evictOldest()
+			}
+			bucket = &tokenBucket{tokens: float64(rule.Burst), last: now}
+			l.buckets[key] = bucket
+		}
+		bucket.tokens = math.Min(float64(rule.Burst), bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+		bucket.last = now
+
+		if bucket.tokens < 1 {
+			wait := time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+			return rule, wait
+		}
+		bucket.tokens--
+	}
+	return nil, 0
+}
+
+// sweep drops buckets that have refilled completely, since they behave like
+// new ones. Callers hold l.mu.
+func (l *RateLimits) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, bucket := range l.buckets {
+		i, _ := strconv.Atoi(key[:strings.IndexByte(key, 0)])
+		rule := &l.Rules[i]
+		refill := time.Duration(float64(rule.Burst) / float64(rule.RequestsPerMinute) * float64(time.Minute))
+		if now.Sub(bucket.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// evictOldest drops the least recently used bucket. Callers hold l.mu.
+func (l *RateLimits) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for key, bucket := range l.buckets {
+		if oldestKey == "" || bucket.last.Before(oldest) {
+			oldestKey, oldest = key, bucket.last
+		}
+	}
+	delete(l.buckets, oldestKey)
+}
+
+// clientIP returns the client IP ip keys count a request by.
+func (l *RateLimits) clientIP(req *http.Request) string {
+	if l.TrustForwardedFor {
+		return getClientIP(req)
+	}
+	return remoteIP(req)
+}
+
+// SetRateLimits enables rate limiting. It must be called before Start.
+func (p *Proxy) SetRateLimits(limits *RateLimits) {
+	p.rateLimits = limits
+}
+
+// rateLimitMiddleware rejects requests exceeding a rate limit with HTTP 429
+// and a JSON-RPC error. A batch is rejected as a whole when one of its
+// requests exceeds a limit.
+func (p *Proxy) rateLimitMiddleware(next http.Handler) http.Handler {
+	if p.rateLimits == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost || req.Body == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Unauthenticated requests are counted by client IP in identity rules
+		client := rateLimitRequest{ip: p.rateLimits.clientIP(req), identity: IdentityFromContext(req.Context())}
+		if client.identity == "" {
+			client.identity = client.ip
+		}
+
+		messages, batch := splitBatch(body)
+		if response, wait, limited := p.checkRateLimits(req, client, messages, batch); limited {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write(response)
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// checkRateLimits counts every request in a message or batch. When one
+// exceeds a limit it returns the response to send instead of forwarding,
+// with an error for each request, and the time until the limit allows it.
+func (p *Proxy) checkRateLimits(
+	req *http.Request, client rateLimitRequest, messages []json.RawMessage, batch bool,
+) ([]byte, time.Duration, bool) {
+	var errs []json.RawMessage
+	var wait time.Duration
+	limited := false
+
+	for _, message := range messages {
+		parsed, err := mcp.ParseRequest(message)
+		if err != nil {
+			continue
+		}
+
+		reason := "Batch contains a rate limited request"
+		call := client
+		call.tool = parsed.ToolName
+		if rule, retry := p.rateLimits.allow(parsed.Method, call); rule != nil {
+			limited = true
+			wait = max(wait, retry)
+			reason = fmt.Sprintf("Rate limit %q exceeded, retry in %s", rule.Name, retry.Round(time.Second))
+
+			p.logger.Debug("request rate limited",
+				slog.String("rule", rule.Name),
+				slog.String("mcp_method", parsed.Method),
+				slog.String("tool", parsed.ToolName),
+				slog.String("identity", IdentityFromContext(req.Context())),
+				slog.String("client_ip", client.ip),
+			)
+			if p.recorder != nil {
+				p.recorder.RecordRateLimited(req.Context(), rule.Name, parsed.Method)
+			}
+		}
+
+		if !parsed.IsNotification {
+			errs = append(errs, requestError(message, codeRateLimited, reason))
+		}
+	}
+
+	if !limited {
+		return nil, 0, false
+	}
+	if len(errs) == 0 {
+		return nil, wait, true
+	}
+	if !batch {
+		return errs[0], wait, true
+	}
+	response, _ := json.Marshal(errs)
+	return response, wait, true
+}
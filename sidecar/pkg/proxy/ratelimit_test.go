@@ -0,0 +1,277 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
+)
+
+// newTestRateLimits parses rate limits driven by a fake clock.
+func newTestRateLimits(t *testing.T, config string) (*RateLimits, *time.Time) {
+	t.Helper()
+
+	limits, err := ParseRateLimits([]byte(config))
+	if err != nil {
+		t.Fatalf("failed to parse rate limits: %v", err)
+	}
+	now := time.Now()
+	limits.now = func() time.Time { return now }
+	return limits, &now
+}
+
+// newRateLimitProxy returns a rate limited handler in front of target.
+func newRateLimitProxy(t *testing.T, target http.Handler, limits *RateLimits, recorder *metrics.Recorder) http.Handler {
+	t.Helper()
+
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	p, err := NewWithRecorder(":0", server.URL, newTestLogger(), recorder)
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	p.SetRateLimits(limits)
+
+	return p.metricsMiddleware(p.policyMiddleware(p.rateLimitMiddleware(p.reverseProxy)))
+}
+
+func TestParseRateLimits_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"no rules", `{"rules":[]}`},
+		{"no keys", `{"rules":[{"requestsPerMinute":10}]}`},
+		{"unknown key", `{"rules":[{"by":["session"],"requestsPerMinute":10}]}`},
+		{"no rate", `{"rules":[{"by":["ip"]}]}`},
+		{"not JSON", `rules`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRateLimits([]byte(tt.config)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRateLimits_TokenBucket(t *testing.T) {
+	limits, now := newTestRateLimits(t, `{"rules":[{"by":["ip"],"requestsPerMinute":60,"burst":2}]}`)
+	client := rateLimitRequest{ip: "10.0.0.1"}
+
+	for i := 0; i < 2; i++ {
+		if rule, _ := limits.allow(mcp.MethodToolsList, client); rule != nil {
+			t.Fatalf("request %d limited within the burst", i+1)
+		}
+	}
+
+	rule, wait := limits.allow(mcp.MethodToolsList, client)
+	if rule == nil {
+		t.Fatal("expected the request after the burst to be limited")
+	}
+	if rule.Name != "ip" {
+		t.Errorf("rule name = %q, want the default ip", rule.Name)
+	}
+	if wait != time.Second {
+		t.Errorf("wait = %v, want 1s", wait)
+	}
+
+	// Other clients have their own bucket
+	if rule, _ := limits.allow(mcp.MethodToolsList, rateLimitRequest{ip: "10.0.0.2"}); rule != nil {
+		t.Error("another client was limited")
+	}
+
+	*now = now.Add(time.Second)
+	if rule, _ := limits.allow(mcp.MethodToolsList, client); rule != nil {
+		t.Error("request limited after a token was added")
+	}
+}
+
+func TestRateLimits_ToolRules(t *testing.T) {
+	limits, _ := newTestRateLimits(t, `{"rules":[
+		{"name":"expensive","by":["identity","tool"],"requestsPerMinute":1,"tools":["expensive_*"]}
+	]}`)
+
+	alice := rateLimitRequest{identity: "alice", tool: "expensive_report"}
+	if rule, _ := limits.allow(mcp.MethodToolsCall, alice); rule != nil {
+		t.Fatal("first call limited")
+	}
+	if rule, _ := limits.allow(mcp.MethodToolsCall, alice); rule == nil || rule.Name != "expensive" {
+		t.Errorf("second call not limited by the expensive rule: %v", rule)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		req    rateLimitRequest
+	}{
+		{"another identity", mcp.MethodToolsCall, rateLimitRequest{identity: "bob", tool: "expensive_report"}},
+		{"another matching tool", mcp.MethodToolsCall, rateLimitRequest{identity: "alice", tool: "expensive_export"}},
+		{"a tool not matching", mcp.MethodToolsCall, rateLimitRequest{identity: "alice", tool: "cheap_lookup"}},
+		{"another method", mcp.MethodToolsList, rateLimitRequest{identity: "alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rule, _ := limits.allow(tt.method, tt.req); rule != nil {
+				t.Errorf("request limited by %q", rule.Name)
+			}
+		})
+	}
+}
+
+func TestRateLimits_SweepDropsRefilledBuckets(t *testing.T) {
+	limits, now := newTestRateLimits(t, `{"rules":[{"by":["ip"],"requestsPerMinute":60}]}`)
+	start := *now
+
+	limits.allow(mcp.MethodToolsList, rateLimitRequest{ip: "10.0.0.1"})
+	*now = start.Add(30 * time.Second)
+	limits.allow(mcp.MethodToolsList, rateLimitRequest{ip: "10.0.0.2"})
+
+	// A bucket of 60 tokens refills in a minute, so only the first one is dropped
+	*now = start.Add(65 * time.Second)
+	limits.allow(mcp.MethodToolsList, rateLimitRequest{ip: "10.0.0.3"})
+	if len(limits.buckets) != 2 {
+		t.Errorf("buckets = %d, want 2", len(limits.buckets))
+	}
+	for key := range limits.buckets {
+		if strings.HasSuffix(key, "10.0.0.1") {
+			t.Error("refilled bucket was kept")
+		}
+	}
+}
+
+func TestRateLimits_CapsBuckets(t *testing.T) {
+	limits, now := newTestRateLimits(t, `{"rules":[{"by":["ip"],"requestsPerMinute":60}]}`)
+	start := *now
+
+	for i := range maxRateLimitBuckets + 1 {
+		*now = start.Add(time.Duration(i) * time.Millisecond)
+		limits.allow(mcp.MethodToolsList, rateLimitRequest{ip: fmt.Sprintf("10.0.%d.%d", i/256, i%256)})
+	}
+	if len(limits.buckets) != maxRateLimitBuckets {
+		t.Errorf("buckets = %d, want %d", len(limits.buckets), maxRateLimitBuckets)
+	}
+	for key := range limits.buckets {
+		if strings.HasSuffix(key, "\x0010.0.0.0") {
+			t.Error("least recently used bucket was kept")
+		}
+	}
+}
+
+func TestRateLimit_IPKeyIgnoresForwardedHeadersUnlessTrusted(t *testing.T) {
+	post := func(handler http.Handler, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/mcp",
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	limits, _ := newTestRateLimits(t, `{"rules":[{"by":["ip"],"requestsPerMinute":1}]}`)
+	handler := newRateLimitProxy(t, &policyTarget{}, limits, nil)
+	if code := post(handler, "203.0.113.1"); code != http.StatusOK {
+		t.Fatalf("first request status = %d, want 200", code)
+	}
+	if code := post(handler, "203.0.113.2"); code != http.StatusTooManyRequests {
+		t.Errorf("request with a rotated X-Forwarded-For status = %d, want 429", code)
+	}
+
+	trusted, _ := newTestRateLimits(t, `{"rules":[{"by":["ip"],"requestsPerMinute":1}],"trustForwardedFor":true}`)
+	handler = newRateLimitProxy(t, &policyTarget{}, trusted, nil)
+	if code := post(handler, "203.0.113.1"); code != http.StatusOK {
+		t.Fatalf("first trusted request status = %d, want 200", code)
+	}
+	if code := post(handler, "203.0.113.2"); code != http.StatusOK {
+		t.Errorf("trusted request from another client status = %d, want 200", code)
+	}
+}
+
+func TestRateLimit_RejectsWith429(t *testing.T) {
+	target := &policyTarget{}
+	recorder, err := metrics.NewRecorder("test", "target")
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	defer recorder.Shutdown(context.Background())
+
+	limits, _ := newTestRateLimits(t, `{"rules":[{"name":"per-tool","by":["tool"],"requestsPerMinute":6,"burst":1}]}`)
+	handler := newRateLimitProxy(t, target, limits, recorder)
+
+	call := `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"search"}}`
+	if rr := policyPost(handler, "", call); rr.Code != http.StatusOK {
+		t.Fatalf("first call status = %d, want 200", rr.Code)
+	}
+
+	rr := policyPost(handler, "", call)
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rr.Code)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "10" {
+		t.Errorf("Retry-After = %q, want 10", got)
+	}
+	if code := policyErrorCode(t, rr.Body.Bytes()); code != codeRateLimited {
+		t.Errorf("error code = %d, want %d", code, codeRateLimited)
+	}
+	if !strings.Contains(rr.Body.String(), `"id":"call-1"`) {
+		t.Errorf("expected the request ID to be kept, got %s", rr.Body.String())
+	}
+	if target.calls.Load() != 1 {
+		t.Errorf("forwarded calls = %d, want 1", target.calls.Load())
+	}
+
+	// Requests other than tool calls are not counted by tool rules
+	if rr := policyPost(handler, "", `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`); rr.Code != http.StatusOK {
+		t.Errorf("tools/list status = %d, want 200", rr.Code)
+	}
+
+	metricsBody := httptest.NewRecorder()
+	recorder.Handler().ServeHTTP(metricsBody, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !hasMetricLine(metricsBody.Body.String(), `mcp_ratelimit_rejections_total{method="tools/call"`, `rule="per-tool"`) {
+		t.Error("expected mcp_ratelimit_rejections_total to be recorded")
+	}
+	if !strings.Contains(metricsBody.Body.String(), `status="429"`) {
+		t.Error("expected the rejection to be recorded as a 429 request")
+	}
+}
+
+func TestRateLimit_BatchRejectedAsWhole(t *testing.T) {
+	target := &policyTarget{}
+	limits, _ := newTestRateLimits(t, `{"rules":[{"by":["ip"],"requestsPerMinute":1}]}`)
+	handler := newRateLimitProxy(t, target, limits, nil)
+
+	rr := policyPost(handler, "", `[
+		{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search"}},
+		{"jsonrpc":"2.0","method":"notifications/progress"},
+		{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search"}}
+	]`)
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rr.Code)
+	}
+
+	var responses []json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &responses); err != nil {
+		t.Fatalf("failed to decode batch response: %v", err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("responses = %d, want one per request", len(responses))
+	}
+	for _, response := range responses {
+		if code := policyErrorCode(t, response); code != codeRateLimited {
+			t.Errorf("error code = %d, want %d", code, codeRateLimited)
+		}
+	}
+	if target.calls.Load() != 0 {
+		t.Error("rate limited batch was forwarded")
+	}
+}
@@ -0,0 +1,432 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/metrics"
+)
+
+const (
+	// DefaultRouterResyncInterval is the default interval between endpoint lists.
+	DefaultRouterResyncInterval = 5 * time.Second
+
+	// DefaultRouterSessionIdleTimeout is the default time an unused session is remembered.
+	DefaultRouterSessionIdleTimeout = time.Hour
+
+	// maxRouterBodySize limits the request bodies buffered to retry a request on another pod.
+	maxRouterBodySize = 10 * 1024 * 1024
+)
+
+// errSessionNotFound is returned for a pod that does not know the session of a request.
+var errSessionNotFound = errors.New("session not found on pod")
+
+// SessionRouterConfig configures the session-router mode of the proxy.
+type SessionRouterConfig struct {
+	// Source lists the ready pods of the MCP server.
+	Source EndpointSource
+
+	// ResyncInterval is the interval between endpoint lists.
+	ResyncInterval time.Duration
+
+	// SessionIdleTimeout is how long an unused session is remembered.
+	SessionIdleTimeout time.Duration
+}
+
+// SessionRouter routes every Streamable HTTP session to the pod that created
+// it, so MCP servers keeping sessions in memory can run several replicas.
+//
+// initialize requests, which have no Mcp-Session-Id yet, go to the pod with
+// the fewest sessions, and the session ID it returns is pinned to that pod.
+// A session the router has not seen, for example after the router restarted
+// or when it runs several replicas, is looked up by trying the pods in an
+// order hashed from the session ID: a pod answers 404 for a session it does
+// not know without processing the request, so the next pod is tried.
+type SessionRouter struct {
+	cfg          SessionRouterConfig
+	logger       *slog.Logger
+	recorder     *metrics.Recorder
+	reverseProxy *httputil.ReverseProxy
+
+	mu       sync.Mutex
+	synced   bool
+	backends []Endpoint
+	ready    map[string]bool
+	sessions map[string]*routedSession
+	load     map[string]int
+	next     int
+}
+
+// routedSession is a session pinned to a pod.
+type routedSession struct {
+	backend  string
+	lastUsed time.Time
+}
+
+// routeAttempt is the forwarding of a request to one pod.
+type routeAttempt struct {
+	backend   string
+	sessionID string
+
+	// retryNotFound is set when another pod is left to try if this one
+	// does not know the session.
+	retryNotFound bool
+
+	// notFound is set when the pod did not know the session and nothing
+	// was written to the client.
+	notFound bool
+}
+
+type routeAttemptKey struct{}
+
+// NewSessionRouter creates a SessionRouter. Run must be called to discover pods.
+func NewSessionRouter(cfg SessionRouterConfig, logger *slog.Logger, recorder *metrics.Recorder) *SessionRouter {
+	if cfg.ResyncInterval <= 0 {
+		cfg.ResyncInterval = DefaultRouterResyncInterval
+	}
+	if cfg.SessionIdleTimeout <= 0 {
+		cfg.SessionIdleTimeout = DefaultRouterSessionIdleTimeout
+	}
+
+	r := &SessionRouter{
+		cfg:      cfg,
+		logger:   logger,
+		recorder: recorder,
+		ready:    make(map[string]bool),
+		sessions: make(map[string]*routedSession),
+		load:     make(map[string]int),
+	}
+
+	r.reverseProxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			attempt := pr.In.Context().Value(routeAttemptKey{}).(*routeAttempt)
+			pr.SetURL(&url.URL{Scheme: "http", Host: attempt.backend})
+			pr.SetXForwarded()
+		},
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 20,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// Flush immediately so SSE responses stream through
+		FlushInterval:  -1,
+		ModifyResponse: r.modifyResponse,
+		ErrorHandler:   r.errorHandler,
+	}
+
+	return r
+}
+
+// NewRouterProxy creates a Proxy serving the session router, recording
+// metrics around it like any other mode.
+func NewRouterProxy(listenAddr string, router *SessionRouter, logger *slog.Logger, recorder *metrics.Recorder) *Proxy {
+	return &Proxy{
+		listenAddr: listenAddr,
+		handler:    router,
+		logger:     logger,
+		recorder:   recorder,
+	}
+}
+
+// Run lists the pods every resync interval until the context is cancelled.
+func (r *SessionRouter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.ResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Sync(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Warn("failed to list MCP server pods", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Ready reports whether at least one pod can receive requests.
+func (r *SessionRouter) Ready() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.synced && len(r.backends) > 0
+}
+
+// Sync refreshes the ready pods. Sessions of pods that went away are
+// forgotten, as are sessions unused for longer than the idle timeout.
+func (r *SessionRouter) Sync(ctx context.Context) error {
+	endpoints, err := r.cfg.Source.Endpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	ready := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		ready[endpoint.Address] = true
+	}
+
+	r.mu.Lock()
+	var added, removed []string
+	for address := range ready {
+		if !r.ready[address] {
+			added = append(added, address)
+		}
+	}
+	for address := range r.ready {
+		if !ready[address] {
+			removed = append(removed, address)
+		}
+	}
+
+	dropped := 0
+	idleSince := time.Now().Add(-r.cfg.SessionIdleTimeout)
+	for id, session := range r.sessions {
+		switch {
+		case !ready[session.backend]:
+			dropped++
+		case session.lastUsed.Before(idleSince):
+		default:
+			continue
+		}
+		delete(r.sessions, id)
+		r.load[session.backend]--
+	}
+	for address := range r.load {
+		if !ready[address] {
+			delete(r.load, address)
+		}
+	}
+
+	r.backends = endpoints
+	r.ready = ready
+	r.synced = true
+	sessions := len(r.sessions)
+	r.mu.Unlock()
+
+	if len(added) > 0 || len(removed) > 0 {
+		sort.Strings(added)
+		sort.Strings(removed)
+		r.logger.Info("MCP server pods changed",
+			slog.Int("pods", len(endpoints)),
+			slog.Any("added", added),
+			slog.Any("removed", removed),
+			slog.Int("dropped_sessions", dropped),
+		)
+	}
+
+	if r.recorder != nil {
+		r.recorder.RecordRouterState(ctx, len(endpoints), sessions)
+		if dropped > 0 {
+			r.recorder.RecordRouterSessionsDropped(ctx, dropped)
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP forwards the request to the pod owning its session.
+func (r *SessionRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	sessionID := req.Header.Get(headerSessionID)
+
+	candidates := r.candidates(sessionID)
+	if len(candidates) == 0 {
+		http.Error(w, "no ready MCP server pods", http.StatusServiceUnavailable)
+		return
+	}
+	if len(candidates) == 1 {
+		r.forward(w, req, &routeAttempt{backend: candidates[0], sessionID: sessionID})
+		return
+	}
+
+	// The session is unknown here and the request may be sent more than once
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRouterBodySize+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxRouterBodySize {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	for i, backend := range candidates {
+		attempt := &routeAttempt{
+			backend:       backend,
+			sessionID:     sessionID,
+			retryNotFound: i < len(candidates)-1,
+		}
+
+		out := req.Clone(req.Context())
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+
+		r.forward(w, out, attempt)
+		if !attempt.notFound {
+			return
+		}
+	}
+}
+
+// forward sends the request to the pod of the attempt.
+func (r *SessionRouter) forward(w http.ResponseWriter, req *http.Request, attempt *routeAttempt) {
+	ctx := context.WithValue(req.Context(), routeAttemptKey{}, attempt)
+	r.reverseProxy.ServeHTTP(w, req.WithContext(ctx))
+}
+
+// candidates returns the pods to try for a session, in order.
+func (r *SessionRouter) candidates(sessionID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.backends) == 0 {
+		return nil
+	}
+
+	if sessionID == "" {
+		return []string{r.leastLoaded()}
+	}
+
+	if session, ok := r.sessions[sessionID]; ok && r.ready[session.backend] {
+		session.lastUsed = time.Now()
+		return []string{session.backend}
+	}
+
+	return rendezvousOrder(sessionID, r.backends)
+}
+
+// leastLoaded returns the pod with the fewest sessions, rotating between
+// pods with as many. Pods added by a scale up receive new sessions until
+// they catch up with the others. Must be called with the lock held.
+func (r *SessionRouter) leastLoaded() string {
+	var least []string
+	for _, endpoint := range r.backends {
+		load := r.load[endpoint.Address]
+		if len(least) > 0 {
+			if fewest := r.load[least[0]]; load > fewest {
+				continue
+			} else if load < fewest {
+				least = least[:0]
+			}
+		}
+		least = append(least, endpoint.Address)
+	}
+
+	r.next++
+	return least[r.next%len(least)]
+}
+
+// rendezvousOrder orders the pods by their hash with the session ID, so all
+// router replicas look for a session on the same pods first.
+func rendezvousOrder(sessionID string, backends []Endpoint) []string {
+	type scored struct {
+		address string
+		score   uint64
+	}
+
+	scores := make([]scored, len(backends))
+	for i, endpoint := range backends {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(sessionID))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(endpoint.Address))
+		scores[i] = scored{address: endpoint.Address, score: h.Sum64()}
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].score > scores[j].score })
+
+	order := make([]string, len(scores))
+	for i, s := range scores {
+		order[i] = s.address
+	}
+	return order
+}
+
+// modifyResponse learns and forgets sessions from the responses of the pods.
+func (r *SessionRouter) modifyResponse(resp *http.Response) error {
+	attempt := resp.Request.Context().Value(routeAttemptKey{}).(*routeAttempt)
+
+	switch {
+	case attempt.sessionID == "":
+		// The response to initialize names the new session
+		if id := resp.Header.Get(headerSessionID); id != "" && resp.StatusCode < http.StatusMultipleChoices {
+			r.pin(id, attempt.backend)
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		if attempt.retryNotFound {
+			return errSessionNotFound
+		}
+		// The session expired on its pod; the client must initialize again
+		r.forget(attempt.sessionID)
+	case resp.Request.Method == http.MethodDelete && resp.StatusCode < http.StatusMultipleChoices:
+		r.forget(attempt.sessionID)
+	default:
+		r.pin(attempt.sessionID, attempt.backend)
+	}
+
+	return nil
+}
+
+// errorHandler handles pods that cannot be reached, and records pods that
+// did not know the session so the next one is tried.
+func (r *SessionRouter) errorHandler(w http.ResponseWriter, req *http.Request, err error) {
+	attempt := req.Context().Value(routeAttemptKey{}).(*routeAttempt)
+	if errors.Is(err, errSessionNotFound) {
+		attempt.notFound = true
+		return
+	}
+
+	r.logger.Error("router error",
+		slog.String("method", req.Method),
+		slog.String("pod", attempt.backend),
+		slog.String("error", err.Error()),
+	)
+	http.Error(w, "router error: "+err.Error(), http.StatusBadGateway)
+}
+
+// pin records the pod of a session.
+func (r *SessionRouter) pin(sessionID, backend string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.ready[backend] {
+		return
+	}
+
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		session = &routedSession{backend: backend}
+		r.sessions[sessionID] = session
+		r.load[backend]++
+	} else if session.backend != backend {
+		r.load[session.backend]--
+		r.load[backend]++
+		session.backend = backend
+	}
+	session.lastUsed = time.Now()
+}
+
+// forget removes a session.
+func (r *SessionRouter) forget(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session, ok := r.sessions[sessionID]; ok {
+		delete(r.sessions, sessionID)
+		r.load[session.backend]--
+	}
+}
@@ -0,0 +1,228 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// staticEndpoints is an EndpointSource with a fixed list of pods.
+type staticEndpoints struct {
+	mu        sync.Mutex
+	endpoints []Endpoint
+}
+
+func (s *staticEndpoints) Endpoints(ctx context.Context) ([]Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Endpoint(nil), s.endpoints...), nil
+}
+
+func (s *staticEndpoints) set(endpoints ...Endpoint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endpoints = endpoints
+}
+
+// sessionPod is an MCP server pod keeping its sessions in memory.
+type sessionPod struct {
+	name     string
+	server   *httptest.Server
+	mu       sync.Mutex
+	sessions map[string]bool
+	requests int
+}
+
+func newSessionPod(t *testing.T, name string) *sessionPod {
+	t.Helper()
+
+	pod := &sessionPod{name: name, sessions: make(map[string]bool)}
+	pod.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pod.mu.Lock()
+		defer pod.mu.Unlock()
+		pod.requests++
+
+		sessionID := r.Header.Get(headerSessionID)
+		if sessionID == "" {
+			sessionID = fmt.Sprintf("%s-%d", name, len(pod.sessions)+1)
+			pod.sessions[sessionID] = true
+			w.Header().Set(headerSessionID, sessionID)
+			_, _ = io.WriteString(w, name)
+			return
+		}
+		if !pod.sessions[sessionID] {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(pod.sessions, sessionID)
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, name+":"+string(body))
+	}))
+	t.Cleanup(pod.server.Close)
+	return pod
+}
+
+func (p *sessionPod) endpoint() Endpoint {
+	return Endpoint{Address: strings.TrimPrefix(p.server.URL, "http://"), Pod: p.name}
+}
+
+func (p *sessionPod) requestCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.requests
+}
+
+// newTestRouter returns a synced router in front of the pods.
+func newTestRouter(t *testing.T, pods ...*sessionPod) (*SessionRouter, *staticEndpoints) {
+	t.Helper()
+
+	source := &staticEndpoints{}
+	for _, pod := range pods {
+		source.endpoints = append(source.endpoints, pod.endpoint())
+	}
+
+	router := NewSessionRouter(SessionRouterConfig{Source: source}, newTestLogger(), nil)
+	if err := router.Sync(context.Background()); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	return router, source
+}
+
+// routerRequest sends a request through the router and returns the response.
+func routerRequest(router http.Handler, method, sessionID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+	if sessionID != "" {
+		req.Header.Set(headerSessionID, sessionID)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSessionRouter_PinsSessionsToTheirPod(t *testing.T) {
+	a, b := newSessionPod(t, "a"), newSessionPod(t, "b")
+	router, _ := newTestRouter(t, a, b)
+
+	// initialize requests are spread over the pods
+	var sessionIDs []string
+	for range 4 {
+		rec := routerRequest(router, http.MethodPost, "", "initialize")
+		sessionIDs = append(sessionIDs, rec.Header().Get(headerSessionID))
+	}
+	if a.requestCount() != 2 || b.requestCount() != 2 {
+		t.Fatalf("initialize requests = %d on a, %d on b, want 2 each", a.requestCount(), b.requestCount())
+	}
+
+	for _, sessionID := range sessionIDs {
+		for range 3 {
+			rec := routerRequest(router, http.MethodPost, sessionID, "tools/list")
+			pod := strings.SplitN(sessionID, "-", 2)[0]
+			if rec.Code != http.StatusOK || rec.Body.String() != pod+":tools/list" {
+				t.Fatalf("session %s: status %d, body %q, want its pod %s", sessionID, rec.Code, rec.Body.String(), pod)
+			}
+		}
+	}
+	// Pinned sessions never land on the wrong pod
+	if a.requestCount() != 8 || b.requestCount() != 8 {
+		t.Errorf("requests = %d on a, %d on b, want 8 each", a.requestCount(), b.requestCount())
+	}
+}
+
+func TestSessionRouter_FindsUnknownSession(t *testing.T) {
+	a, b, c := newSessionPod(t, "a"), newSessionPod(t, "b"), newSessionPod(t, "c")
+	first, _ := newTestRouter(t, a, b, c)
+
+	var sessionIDs []string
+	for range 3 {
+		sessionIDs = append(sessionIDs, routerRequest(first, http.MethodPost, "", "initialize").Header().Get(headerSessionID))
+	}
+
+	// A second router replica has never seen the sessions
+	second, _ := newTestRouter(t, a, b, c)
+	for _, sessionID := range sessionIDs {
+		pod := strings.SplitN(sessionID, "-", 2)[0]
+		rec := routerRequest(second, http.MethodPost, sessionID, "tools/call")
+		if rec.Code != http.StatusOK || rec.Body.String() != pod+":tools/call" {
+			t.Fatalf("session %s: status %d, body %q, want its pod %s", sessionID, rec.Code, rec.Body.String(), pod)
+		}
+	}
+
+	// Once found, sessions are pinned and go straight to their pod
+	before := a.requestCount() + b.requestCount() + c.requestCount()
+	for _, sessionID := range sessionIDs {
+		routerRequest(second, http.MethodPost, sessionID, "ping")
+	}
+	if after := a.requestCount() + b.requestCount() + c.requestCount(); after-before != len(sessionIDs) {
+		t.Errorf("pods received %d requests for %d pinned sessions", after-before, len(sessionIDs))
+	}
+
+	if rec := routerRequest(second, http.MethodPost, "unknown", "ping"); rec.Code != http.StatusNotFound {
+		t.Errorf("session no pod knows: status %d, want 404", rec.Code)
+	}
+}
+
+func TestSessionRouter_ScaleEvents(t *testing.T) {
+	a, b := newSessionPod(t, "a"), newSessionPod(t, "b")
+	router, source := newTestRouter(t, a)
+
+	sessionID := routerRequest(router, http.MethodPost, "", "initialize").Header().Get(headerSessionID)
+
+	// New sessions go to the pod added by a scale up until it catches up
+	source.set(a.endpoint(), b.endpoint())
+	if err := router.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := routerRequest(router, http.MethodPost, "", "initialize").Body.String(); got != "b" {
+		t.Errorf("initialize after scale up went to %q, want the new pod b", got)
+	}
+	if got := routerRequest(router, http.MethodPost, sessionID, "ping").Body.String(); got != "a:ping" {
+		t.Errorf("existing session went to %q, want its pod a", got)
+	}
+
+	// Sessions of a pod removed by a scale down are forgotten
+	source.set(b.endpoint())
+	if err := router.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rec := routerRequest(router, http.MethodPost, sessionID, "ping"); rec.Code != http.StatusNotFound {
+		t.Errorf("session of a removed pod: status %d, want 404 so the client initializes again", rec.Code)
+	}
+	if got := routerRequest(router, http.MethodPost, "", "initialize").Body.String(); got != "b" {
+		t.Errorf("initialize after scale down went to %q, want b", got)
+	}
+
+	source.set()
+	if err := router.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if router.Ready() {
+		t.Error("router without pods is ready")
+	}
+	if rec := routerRequest(router, http.MethodPost, "", "initialize"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status without pods = %d, want 503", rec.Code)
+	}
+}
+
+func TestSessionRouter_DeleteForgetsSession(t *testing.T) {
+	a, b := newSessionPod(t, "a"), newSessionPod(t, "b")
+	router, _ := newTestRouter(t, a, b)
+
+	sessionID := routerRequest(router, http.MethodPost, "", "initialize").Header().Get(headerSessionID)
+	if rec := routerRequest(router, http.MethodDelete, sessionID, ""); rec.Code != http.StatusOK {
+		t.Fatalf("DELETE status = %d", rec.Code)
+	}
+
+	router.mu.Lock()
+	_, pinned := router.sessions[sessionID]
+	router.mu.Unlock()
+	if pinned {
+		t.Error("deleted session is still pinned")
+	}
+}
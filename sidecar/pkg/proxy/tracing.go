@@ -0,0 +1,94 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/mcp"
+	"github.com/vitorbari/mcp-operator/sidecar/pkg/tracing"
+)
+
+// SetTracer enables tracing: every request becomes a span, continuing the
+// trace of an incoming traceparent header and propagated to the target.
+// It must be called before Start.
+func (p *Proxy) SetTracer(tracer trace.Tracer) {
+	p.tracer = tracer
+}
+
+// startSpan starts the span of a request and returns the request carrying it
+// in its context. Without a tracer the span does nothing.
+func (p *Proxy) startSpan(req *http.Request) (*http.Request, trace.Span) {
+	if p.tracer == nil {
+		return req, trace.SpanFromContext(req.Context())
+	}
+
+	ctx := tracing.Propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.path", req.URL.Path),
+		attribute.String("client.address", getClientIP(req)),
+	}
+	if sessionID := req.Header.Get(headerSessionID); sessionID != "" {
+		attrs = append(attrs, attribute.String("mcp.session.id", sessionID))
+	}
+
+	// The span is renamed after the MCP method once the body is parsed
+	ctx, span := p.tracer.Start(ctx, req.Method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+	return req.WithContext(ctx), span
+}
+
+// injectTraceContext sets the traceparent header of a request to the target
+// to the span of the request, so the MCP server continues the trace.
+func (p *Proxy) injectTraceContext(req *http.Request) {
+	if p.tracer != nil {
+		tracing.Propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+	}
+}
+
+// annotateSpan names a request span after its MCP method and records the
+// MCP attributes of the request and the outcome of the response.
+func annotateSpan(span trace.Span, req *mcp.ParsedRequest, resp *mcp.ParsedResponse, status int, identity string) {
+	if !span.IsRecording() {
+		return
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	if identity != "" {
+		span.SetAttributes(attribute.String("enduser.id", identity))
+	}
+
+	if req != nil {
+		name := req.Method
+		span.SetAttributes(attribute.String("mcp.method.name", req.Method))
+		if req.ToolName != "" {
+			name += " " + req.ToolName
+			span.SetAttributes(attribute.String("gen_ai.tool.name", req.ToolName))
+		}
+		if req.ResourceURI != "" {
+			span.SetAttributes(attribute.String("mcp.resource.uri", req.ResourceURI))
+		}
+		if !req.IsNotification {
+			span.SetAttributes(attribute.String("jsonrpc.request.id", fmt.Sprint(req.ID)))
+		}
+		span.SetName(name)
+	}
+
+	switch {
+	case resp != nil && resp.IsError:
+		span.SetAttributes(
+			attribute.Int("rpc.jsonrpc.error_code", resp.ErrorCode),
+			attribute.String("rpc.jsonrpc.error_message", resp.ErrorMessage),
+		)
+		span.SetStatus(codes.Error, resp.ErrorMessage)
+	case status >= http.StatusInternalServerError:
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
@@ -0,0 +1,156 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	testTraceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentSpanID = "00f067aa0ba902b7"
+)
+
+// newTracedProxy returns a traced handler in front of target and the recorder of its spans.
+func newTracedProxy(t *testing.T, target http.Handler) (http.Handler, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	server := httptest.NewServer(target)
+	t.Cleanup(server.Close)
+
+	p, err := New(":0", server.URL, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	spans := tracetest.NewSpanRecorder()
+	p.SetTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test"))
+
+	return p.metricsMiddleware(p.handler), spans
+}
+
+// spanAttributes returns the attributes of a span by key.
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestTracing_SpanPerRequest(t *testing.T) {
+	var upstreamTraceparent string
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":7,"error":{"code":-32602,"message":"Unknown tool"}}`))
+	})
+	handler, spans := newTracedProxy(t, target)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp",
+		strings.NewReader(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"search"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Traceparent", "00-"+testTraceID+"-"+testParentSpanID+"-01")
+	req.Header.Set(headerSessionID, "session-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("spans = %d, want 1", len(ended))
+	}
+	span := ended[0]
+
+	if span.Name() != "tools/call search" {
+		t.Errorf("span name = %q, want %q", span.Name(), "tools/call search")
+	}
+	if got := span.SpanContext().TraceID().String(); got != testTraceID {
+		t.Errorf("trace ID = %s, want the incoming %s", got, testTraceID)
+	}
+	if got := span.Parent().SpanID().String(); got != testParentSpanID {
+		t.Errorf("parent span ID = %s, want the incoming %s", got, testParentSpanID)
+	}
+
+	want := "00-" + testTraceID + "-" + span.SpanContext().SpanID().String() + "-01"
+	if upstreamTraceparent != want {
+		t.Errorf("upstream traceparent = %q, want %q", upstreamTraceparent, want)
+	}
+
+	attrs := spanAttributes(span)
+	for key, value := range map[attribute.Key]attribute.Value{
+		"mcp.method.name":           attribute.StringValue("tools/call"),
+		"gen_ai.tool.name":          attribute.StringValue("search"),
+		"jsonrpc.request.id":        attribute.StringValue("7"),
+		"mcp.session.id":            attribute.StringValue("session-1"),
+		"rpc.jsonrpc.error_code":    attribute.IntValue(-32602),
+		"http.response.status_code": attribute.IntValue(http.StatusOK),
+	} {
+		if attrs[key] != value {
+			t.Errorf("attribute %s = %v, want %v", key, attrs[key].Emit(), value.Emit())
+		}
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want Error", span.Status().Code)
+	}
+}
+
+func TestTracing_ResourceReadStartsNewTrace(t *testing.T) {
+	var upstreamTraceparent string
+	target := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get("Traceparent")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"r1","result":{"contents":[]}}`))
+	})
+	handler, spans := newTracedProxy(t, target)
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp",
+		strings.NewReader(`{"jsonrpc":"2.0","id":"r1","method":"resources/read","params":{"uri":"file:///notes.txt"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("spans = %d, want 1", len(ended))
+	}
+	span := ended[0]
+
+	if span.Parent().IsValid() {
+		t.Error("span without an incoming traceparent has a parent")
+	}
+	if !strings.Contains(upstreamTraceparent, span.SpanContext().TraceID().String()) {
+		t.Errorf("upstream traceparent %q does not continue trace %s", upstreamTraceparent, span.SpanContext().TraceID())
+	}
+	if got := spanAttributes(span)["mcp.resource.uri"].AsString(); got != "file:///notes.txt" {
+		t.Errorf("mcp.resource.uri = %q", got)
+	}
+	if span.Status().Code == codes.Error {
+		t.Error("successful request marked as an error")
+	}
+}
+
+func TestTracing_DisabledPassesTraceparentThrough(t *testing.T) {
+	var upstreamTraceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get("Traceparent")
+	}))
+	defer server.Close()
+
+	p, err := New(":0", server.URL, newTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	traceparent := "00-" + testTraceID + "-" + testParentSpanID + "-01"
+	req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+	req.Header.Set("Traceparent", traceparent)
+	p.metricsMiddleware(p.handler).ServeHTTP(httptest.NewRecorder(), req)
+
+	if upstreamTraceparent != traceparent {
+		t.Errorf("upstream traceparent = %q, want %q", upstreamTraceparent, traceparent)
+	}
+}
@@ -0,0 +1,125 @@
+// Package tracing configures OpenTelemetry tracing for the MCP sidecar proxy.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the spans created by the proxy.
+const TracerName = "github.com/vitorbari/mcp-operator/sidecar"
+
+// StdoutFile is the File value writing spans to standard output.
+const StdoutFile = "-"
+
+// Propagator reads and writes W3C trace context and baggage headers.
+var Propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// Config configures where spans are exported to.
+type Config struct {
+	// Endpoint is the URL of an OTLP/HTTP collector, e.g. http://otel-collector:4318.
+	// The http scheme sends spans without TLS.
+	Endpoint string
+
+	// File writes spans as JSON lines to a file instead of exporting them,
+	// or to standard output when set to StdoutFile.
+	File string
+
+	// SampleRatio is the fraction of traces sampled when the caller did not
+	// decide. Requests carrying a traceparent follow the caller's decision.
+	SampleRatio float64
+
+	// ServiceName is the service.name resource attribute of the spans.
+	ServiceName string
+
+	// Version is the service.version resource attribute of the spans.
+	Version string
+}
+
+// Enabled reports whether spans are exported anywhere.
+func (c Config) Enabled() bool {
+	return c.Endpoint != "" || c.File != ""
+}
+
+// Provider creates the tracers of the proxy and exports their spans.
+type Provider struct {
+	provider *sdktrace.TracerProvider
+	file     io.Closer
+}
+
+// NewProvider creates a Provider exporting spans as configured.
+// OTEL_EXPORTER_OTLP_* and OTEL_RESOURCE_ATTRIBUTES environment variables are honored.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("tracing needs an endpoint or a file")
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("sample ratio %v is not between 0 and 1", cfg.SampleRatio)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName), semconv.ServiceVersion(cfg.Version)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	p := &Provider{}
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	}
+
+	if cfg.File != "" {
+		// Spans are written as they end, so the file can be followed
+		var out io.Writer = os.Stdout
+		if cfg.File != StdoutFile {
+			file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open trace file: %w", err)
+			}
+			out = file
+			p.file = file
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(out))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+	} else {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	p.provider = sdktrace.NewTracerProvider(opts...)
+	return p, nil
+}
+
+// Tracer returns the tracer of the proxy.
+func (p *Provider) Tracer() trace.Tracer {
+	return p.provider.Tracer(TracerName)
+}
+
+// Shutdown exports the remaining spans and stops the provider.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	err := p.provider.Shutdown(ctx)
+	if p.file != nil {
+		err = errors.Join(err, p.file.Close())
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewProvider_WritesSpansToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.json")
+	provider, err := NewProvider(context.Background(), Config{
+		File:        path,
+		SampleRatio: 1,
+		ServiceName: "my-server",
+		Version:     "v1.2.3",
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+
+	_, span := provider.Tracer().Start(context.Background(), "tools/call search")
+	span.End()
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shut down provider: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read spans: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("lines = %d, want one per span", len(lines))
+	}
+
+	var exported struct {
+		Name     string
+		Resource []struct {
+			Key   string
+			Value struct{ Value any }
+		}
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &exported); err != nil {
+		t.Fatalf("span is not JSON: %v", err)
+	}
+	if exported.Name != "tools/call search" {
+		t.Errorf("span name = %q", exported.Name)
+	}
+
+	resource := make(map[string]any)
+	for _, attr := range exported.Resource {
+		resource[attr.Key] = attr.Value.Value
+	}
+	if resource["service.name"] != "my-server" || resource["service.version"] != "v1.2.3" {
+		t.Errorf("resource = %v, want the service name and version", resource)
+	}
+}
+
+func TestNewProvider_SampleRatio(t *testing.T) {
+	provider, err := NewProvider(context.Background(), Config{File: filepath.Join(t.TempDir(), "spans.json")})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Tracer().Start(context.Background(), "tools/list")
+	if span.SpanContext().IsSampled() {
+		t.Error("span sampled with a sample ratio of 0")
+	}
+	span.End()
+}
+
+func TestNewProvider_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"no exporter", Config{SampleRatio: 1}},
+		{"sample ratio above 1", Config{File: StdoutFile, SampleRatio: 1.5}},
+		{"missing directory", Config{File: "/nonexistent/spans.json", SampleRatio: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewProvider(context.Background(), tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}